@@ -13,6 +13,7 @@ import (
 
 	"auth/config"
 	grpc_server "auth/internal/adapters/grpc"
+	auditRepo "auth/internal/adapters/repository/audit"
 	authRepo "auth/internal/adapters/repository/auth"
 	"auth/internal/adapters/repository/intiter"
 	authService "auth/internal/services/auth"
@@ -70,7 +71,7 @@ func main() {
 
 	tokenProvider := jwt.New(cfg.JWT.Secret)
 
-	authSrv := authService.New(authRepo, tokenProvider, cfg.JWT.TTL)
+	authSrv := authService.New(authRepo, auditRepo.New(dbConn.Pool), tokenProvider, cfg.JWT.TTL)
 
 	grpcService := grpc_server.New(authSrv)
 
@@ -118,16 +119,21 @@ func main() {
 	}).Handler(mux)
 
 	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%d", cfg.HTTP.Port),
+		Addr:    ":" + cfg.HTTP.Port,
 		Handler: withCors,
 	}
 
 	// Start HTTP server (and proxy calls to gRPC server endpoint)
-	httpServer.ListenAndServe()
+	httpErrChan := make(chan error, 1)
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			httpErrChan <- err
+		}
+	}()
 
-	grpcErrChan := make(chan error)
+	grpcErrChan := make(chan error, 1)
 	go func() {
-		if err = grpcServer.Serve(lis); err != nil {
+		if err := grpcServer.Serve(lis); err != nil {
 			grpcErrChan <- err
 		}
 	}()
@@ -141,15 +147,20 @@ func main() {
 		logger.Info("app - Run - signal: " + s.String())
 	case err = <-grpcErrChan:
 		logger.Error(fmt.Errorf("app - Run - grpcServer.Serve: %w", err))
+	case err = <-httpErrChan:
+		logger.Error(fmt.Errorf("app - Run - httpServer.ListenAndServe: %w", err))
 	}
 
 	// Shutdown
+	if err := httpServer.Shutdown(context.WithoutCancel(ctx)); err != nil {
+		logger.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
+	}
 	grpcServer.GracefulStop()
 }
 
 // InterceptorLogger adapts logger to interceptor logger.
 func InterceptorLogger(l *logger.Logger) logging.Logger {
 	return logging.LoggerFunc(func(ctx context.Context, lvl logging.Level, msg string, fields ...any) {
-		l.Debug(fmt.Sprintf("%s: %s", lvl, msg), fields...)
+		l.Debug(fmt.Sprintf("%v: %s", lvl, msg), fields...)
 	})
 }
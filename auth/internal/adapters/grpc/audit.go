@@ -0,0 +1,151 @@
+package grpc_server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"auth/internal/entity"
+	desc "auth/pkg/api/auth"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+func (s *Service) ListAuditEvents(
+	ctx context.Context,
+	in *desc.ListAuditEventsRequest,
+) (*desc.ListAuditEventsResponse, error) {
+	token, ok := bearerToken(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	filter := entity.AuditFilter{
+		ActorID: in.GetActorId(),
+		Limit:   int(in.GetPageSize()),
+	}
+
+	switch {
+	case filter.Limit < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size cannot be negative")
+	case filter.Limit == 0:
+		filter.Limit = defaultAuditPageSize
+	case filter.Limit > maxAuditPageSize:
+		filter.Limit = maxAuditPageSize
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(in.GetFrom()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from must be in RFC 3339 format")
+	}
+	if filter.To, err = parseAuditTime(in.GetTo()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to must be in RFC 3339 format")
+	}
+
+	if pageToken := in.GetPageToken(); pageToken != "" {
+		beforeID, err := strconv.ParseInt(pageToken, 10, 64)
+		if err != nil || beforeID <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		filter.BeforeID = beforeID
+	}
+
+	events, err := s.auth.ListAuditEvents(withRequestMeta(ctx), token, filter)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		if errors.Is(err, entity.ErrPermissionDenied) {
+			return nil, status.Error(codes.PermissionDenied, "PERMISSION_AUDIT is required")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to list audit events")
+	}
+
+	result := make([]*desc.AuditEvent, len(events))
+	for i, event := range events {
+		result[i] = &desc.AuditEvent{
+			Id:        event.ID,
+			ActorId:   event.ActorID,
+			Action:    event.Action,
+			Resource:  event.Resource,
+			Ip:        event.IP,
+			UserAgent: event.UserAgent,
+			Outcome:   event.Outcome.String(),
+			Error:     event.Error,
+			CreatedAt: event.CreatedAt.Format(time.DateTime),
+		}
+	}
+
+	var nextPageToken string
+	if len(events) == filter.Limit {
+		nextPageToken = strconv.FormatInt(events[len(events)-1].ID, 10)
+	}
+
+	return &desc.ListAuditEventsResponse{Events: result, NextPageToken: nextPageToken}, nil
+}
+
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// bearerToken возвращает токен из заголовка Authorization. HTTP-шлюз передает
+// заголовок в метаданных под тем же именем.
+func bearerToken(ctx context.Context) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(firstMetadata(ctx, "authorization")), " ")
+	token = strings.TrimSpace(token)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// withRequestMeta кладет в контекст адрес и User-Agent клиента для журнала аудита.
+// Для запросов через HTTP-шлюз адрес берется из X-Forwarded-For, который шлюз
+// дополняет адресом клиента, а User-Agent - из заголовка, переданного шлюзом.
+func withRequestMeta(ctx context.Context) context.Context {
+	meta := entity.RequestMeta{
+		UserAgent: firstMetadata(ctx, "grpcgateway-user-agent"),
+	}
+	if meta.UserAgent == "" {
+		meta.UserAgent = firstMetadata(ctx, "user-agent")
+	}
+
+	if forwarded := firstMetadata(ctx, "x-forwarded-for"); forwarded != "" {
+		ip, _, _ := strings.Cut(forwarded, ",")
+		meta.IP = strings.TrimSpace(ip)
+	} else if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		meta.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(meta.IP); err == nil {
+			meta.IP = host
+		}
+	}
+
+	return entity.ContextWithRequestMeta(ctx, meta)
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
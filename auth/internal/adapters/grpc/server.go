@@ -15,7 +15,11 @@ type Auth interface {
 	Login(ctx context.Context, login, password string) (string, error)
 	Register(ctx context.Context, login, password string) (int64, error)
 	CheckPermission(ctx context.Context, userId int64, permission entity.Permission) (bool, error)
+	CheckPermissions(ctx context.Context, userID int64, permissions []entity.Permission) (map[entity.Permission]bool, int64, error)
+	PermissionsRevision(ctx context.Context) (int64, error)
+	ValidateToken(ctx context.Context, token string) (entity.User, error)
 	Logout(ctx context.Context, token string) error
+	ListAuditEvents(ctx context.Context, token string, filter entity.AuditFilter) ([]entity.AuditEvent, error)
 }
 
 type Service struct {
@@ -41,7 +45,7 @@ func (s *Service) Login(
 		return nil, status.Error(codes.InvalidArgument, "password is required")
 	}
 
-	token, err := s.auth.Login(ctx, in.GetLogin(), in.GetPassword())
+	token, err := s.auth.Login(withRequestMeta(ctx), in.GetLogin(), in.GetPassword())
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to login")
 	}
@@ -61,7 +65,7 @@ func (s *Service) Register(
 		return nil, status.Error(codes.InvalidArgument, "password is required")
 	}
 
-	uid, err := s.auth.Register(ctx, in.GetLogin(), in.GetPassword())
+	uid, err := s.auth.Register(withRequestMeta(ctx), in.GetLogin(), in.GetPassword())
 	if err != nil {
 		if errors.Is(err, entity.ErrLoginAlreadyExists) {
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
@@ -98,6 +102,74 @@ func (s *Service) CheckPermission(
 	return &desc.PermissionResponse{HavePermission: havePermission}, nil
 }
 
+func (s *Service) CheckPermissions(
+	ctx context.Context,
+	in *desc.CheckPermissionsRequest,
+) (*desc.CheckPermissionsResponse, error) {
+	if in.UserId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if len(in.Permissions) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "permissions are required")
+	}
+
+	permissions := make([]entity.Permission, len(in.GetPermissions()))
+	for i, perm := range in.GetPermissions() {
+		permissions[i] = convertToEntityPermission(perm)
+		if permissions[i] == entity.PermissionNone {
+			return nil, status.Error(codes.InvalidArgument, "invalid permission")
+		}
+	}
+
+	decisions, revision, err := s.auth.CheckPermissions(ctx, in.GetUserId(), permissions)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to check permissions")
+	}
+
+	result := make([]*desc.PermissionDecision, len(permissions))
+	for i, permission := range permissions {
+		result[i] = &desc.PermissionDecision{
+			Permission:     in.GetPermissions()[i],
+			HavePermission: decisions[permission],
+		}
+	}
+
+	return &desc.CheckPermissionsResponse{Decisions: result, Revision: revision}, nil
+}
+
+func (s *Service) GetPermissionsRevision(
+	ctx context.Context,
+	in *desc.GetPermissionsRevisionRequest,
+) (*desc.GetPermissionsRevisionResponse, error) {
+	revision, err := s.auth.PermissionsRevision(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get permissions revision")
+	}
+
+	return &desc.GetPermissionsRevisionResponse{Revision: revision}, nil
+}
+
+func (s *Service) ValidateToken(
+	ctx context.Context,
+	in *desc.ValidateTokenRequest,
+) (*desc.ValidateTokenResponse, error) {
+	if in.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	user, err := s.auth.ValidateToken(ctx, in.GetToken())
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to validate token")
+	}
+
+	return &desc.ValidateTokenResponse{UserId: user.ID, Login: user.Login}, nil
+}
+
 func convertToEntityPermission(perm desc.Permission) entity.Permission {
 	switch perm {
 	case desc.Permission_PERMISSION_NONE:
@@ -116,6 +188,12 @@ func convertToEntityPermission(perm desc.Permission) entity.Permission {
 		return entity.PermissionApplyOther
 	case desc.Permission_PERMISSION_ROLLBACK_OTHER:
 		return entity.PermissionRollbackOther
+	case desc.Permission_PERMISSION_MANAGE_TARGETS:
+		return entity.PermissionManageTargets
+	case desc.Permission_PERMISSION_BASELINE:
+		return entity.PermissionBaseline
+	case desc.Permission_PERMISSION_AUDIT:
+		return entity.PermissionAudit
 	}
 	return entity.PermissionNone
 }
@@ -128,7 +206,7 @@ func (s *Service) Logout(
 		return nil, status.Error(codes.InvalidArgument, "token is required")
 	}
 
-	err := s.auth.Logout(ctx, in.GetToken())
+	err := s.auth.Logout(withRequestMeta(ctx), in.GetToken())
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to logout")
 	}
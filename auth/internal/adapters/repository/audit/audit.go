@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/entity"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// Excecutor - интерфейс для выполнения запросов на базе данных.
+type Excecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+type Repository struct {
+	conn Excecutor
+}
+
+func New(conn Excecutor) *Repository {
+	return &Repository{
+		conn: conn,
+	}
+}
+
+// AddAuditEvent appends an event to the audit log.
+func (r *Repository) AddAuditEvent(ctx context.Context, event entity.AuditEvent) error {
+	query := `
+        INSERT INTO audit_log (actor_id, action, resource, ip, user_agent, outcome, error, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    `
+	_, err := r.conn.Exec(
+		ctx,
+		query,
+		event.ActorID,
+		event.Action,
+		event.Resource,
+		event.IP,
+		event.UserAgent,
+		event.Outcome.String(),
+		event.Error,
+		event.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add audit event: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns audit events from newest to oldest.
+func (r *Repository) ListAuditEvents(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEvent, error) {
+	query := `
+        SELECT id, actor_id, action, resource, ip, user_agent, outcome, error, created_at
+        FROM audit_log
+        WHERE ($1::bigint = 0 OR actor_id = $1)
+            AND ($2::timestamptz IS NULL OR created_at >= $2)
+            AND ($3::timestamptz IS NULL OR created_at < $3)
+            AND ($4::bigint = 0 OR id < $4)
+        ORDER BY id DESC
+        LIMIT $5
+    `
+	rows, err := r.conn.Query(
+		ctx,
+		query,
+		filter.ActorID,
+		nullTime(filter.From),
+		nullTime(filter.To),
+		filter.BeforeID,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []entity.AuditEvent
+	for rows.Next() {
+		var event entity.AuditEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.ActorID,
+			&event.Action,
+			&event.Resource,
+			&event.IP,
+			&event.UserAgent,
+			&event.Outcome,
+			&event.Error,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	return events, nil
+}
+
+// nullTime maps the zero time to NULL so that it does not limit the selection.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -45,7 +45,7 @@ func (r *Repository) GetUserByLogin(ctx context.Context, login string) (entity.U
 		&user.IsActive,
 	)
 	if err == pgx.ErrNoRows {
-		return entity.User{}, fmt.Errorf("user not found: %w", err) // Wrap ErrNoRows
+		return entity.User{}, fmt.Errorf("user not found: %w", entity.ErrNotFound)
 	}
 	if err != nil {
 		return entity.User{}, fmt.Errorf("failed to get user by login: %w", err)
@@ -53,6 +53,27 @@ func (r *Repository) GetUserByLogin(ctx context.Context, login string) (entity.U
 	return user, nil
 }
 
+// GetUserByID retrieves an active user by their ID.
+func (r *Repository) GetUserByID(ctx context.Context, userID int64) (entity.User, error) {
+	query := `SELECT id, login, password_hash, created_at, updated_at, is_active FROM users WHERE id = $1 AND is_active = TRUE`
+	var user entity.User
+	err := r.conn.QueryRow(ctx, query, userID).Scan(
+		&user.ID,
+		&user.Login,
+		&user.PassHash,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.IsActive,
+	)
+	if err == pgx.ErrNoRows {
+		return entity.User{}, fmt.Errorf("user not found: %w", entity.ErrNotFound)
+	}
+	if err != nil {
+		return entity.User{}, fmt.Errorf("failed to get user by id: %w", err)
+	}
+	return user, nil
+}
+
 // SaveUser saves a new user to the database.
 func (r *Repository) SaveUser(ctx context.Context, login string, passwordHash []byte) (int64, error) {
 	query := `INSERT INTO users (login, password_hash, created_at, updated_at) VALUES ($1, $2, NOW(), NOW()) RETURNING id`
@@ -85,6 +106,58 @@ func (r *Repository) CheckUserPermission(ctx context.Context, userID int64, perm
 	return hasPermission, nil
 }
 
+// CheckUserPermissions checks several permissions of a user with a single query.
+func (r *Repository) CheckUserPermissions(ctx context.Context, userID int64, permissions []entity.Permission) (map[entity.Permission]bool, error) {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = permission.String()
+	}
+
+	query := `
+        SELECT DISTINCT p.name
+        FROM users u
+        JOIN user_roles ur ON u.id = ur.user_id
+        JOIN roles r ON ur.role_id = r.id
+        JOIN role_permissions rp ON r.id = rp.role_id
+        JOIN permissions p ON rp.permission_id = p.id
+        WHERE u.id = $1 AND p.name = ANY($2) AND u.is_active = TRUE
+    `
+	rows, err := r.conn.Query(ctx, query, userID, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check user permissions: %w", err)
+	}
+	defer rows.Close()
+
+	granted := make(map[string]bool, len(permissions))
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		granted[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check user permissions: %w", err)
+	}
+
+	result := make(map[entity.Permission]bool, len(permissions))
+	for _, permission := range permissions {
+		result[permission] = granted[permission.String()]
+	}
+	return result, nil
+}
+
+// GetPermissionsRevision returns the current permissions revision.
+func (r *Repository) GetPermissionsRevision(ctx context.Context) (int64, error) {
+	query := `SELECT revision FROM permissions_revision WHERE id`
+	var revision int64
+	err := r.conn.QueryRow(ctx, query).Scan(&revision)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get permissions revision: %w", err)
+	}
+	return revision, nil
+}
+
 // SetUserInactive sets a user's is_active status to false.
 func (r *Repository) SetUserInactive(ctx context.Context, userID int64) error {
 	query := `UPDATE users SET is_active = FALSE, updated_at = NOW() WHERE id = $1`
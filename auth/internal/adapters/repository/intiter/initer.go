@@ -121,3 +121,82 @@ func (r *Repository) CreateIfNeededUserRolesTable(ctx context.Context) error {
 	}
 	return nil
 }
+
+const createPermissionsRevisionQuery = `
+CREATE TABLE IF NOT EXISTS permissions_revision (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    revision BIGINT NOT NULL
+);
+
+INSERT INTO permissions_revision (id, revision) VALUES (TRUE, 1) ON CONFLICT (id) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION bump_permissions_revision() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE permissions_revision SET revision = revision + 1 WHERE id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS user_roles_revision ON user_roles;
+CREATE TRIGGER user_roles_revision AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON user_roles
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_permissions_revision();
+
+DROP TRIGGER IF EXISTS role_permissions_revision ON role_permissions;
+CREATE TRIGGER role_permissions_revision AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON role_permissions
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_permissions_revision();
+
+DROP TRIGGER IF EXISTS users_revision ON users;
+CREATE TRIGGER users_revision AFTER UPDATE OF is_active OR DELETE ON users
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_permissions_revision();
+`
+
+// CreateIfNeededPermissionsRevision создает счетчик ревизии прав и триггеры,
+// увеличивающие его при изменении ролей пользователей, прав ролей и активности пользователей.
+func (r *Repository) CreateIfNeededPermissionsRevision(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createPermissionsRevisionQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create permissions revision: %w", err)
+	}
+	return nil
+}
+
+const createAuditLogTableQuery = `
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT NOT NULL DEFAULT 0,
+    action TEXT NOT NULL,
+    resource TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    outcome TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor_id, id);
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
+`
+
+// CreateIfNeededAuditLogTable создает журнал аудита, если его нет. Изменение
+// и удаление записей журнала запрещены триггерами.
+func (r *Repository) CreateIfNeededAuditLogTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createAuditLogTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create audit_log table: %w", err)
+	}
+	return nil
+}
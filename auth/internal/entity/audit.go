@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// AuditOutcome - итог действия, записанного в журнал аудита.
+type AuditOutcome string
+
+const (
+	// AuditOutcomeSucceeded - действие выполнено.
+	AuditOutcomeSucceeded AuditOutcome = "succeeded"
+	// AuditOutcomeDenied - действие отклонено: неверные учетные данные или нет права.
+	AuditOutcomeDenied AuditOutcome = "denied"
+	// AuditOutcomeFailed - действие завершилось ошибкой.
+	AuditOutcomeFailed AuditOutcome = "failed"
+)
+
+func (o AuditOutcome) String() string {
+	return string(o)
+}
+
+// AuditEvent - запись журнала аудита. Записи только добавляются.
+// ActorID - пользователь, выполнивший действие (0, если он не установлен,
+// например при неудачном входе), Resource - объект действия.
+type AuditEvent struct {
+	ID        int64
+	ActorID   int64
+	Action    string
+	Resource  string
+	IP        string
+	UserAgent string
+	Outcome   AuditOutcome
+	Error     string
+	CreatedAt time.Time
+}
+
+// AuditFilter - условия выборки журнала аудита. Нулевые значения не ограничивают выборку.
+// Записи возвращаются от новых к старым; BeforeID продолжает выборку после последней записи
+// предыдущей страницы.
+type AuditFilter struct {
+	ActorID  int64
+	From     time.Time
+	To       time.Time
+	BeforeID int64
+	Limit    int
+}
+
+// RequestMeta - сведения о клиенте, выполнившем запрос.
+type RequestMeta struct {
+	IP        string
+	UserAgent string
+}
+
+type requestMetaKey struct{}
+
+// ContextWithRequestMeta возвращает контекст со сведениями о клиенте.
+func ContextWithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFromContext возвращает сведения о клиенте, выполнившем запрос.
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
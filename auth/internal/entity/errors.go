@@ -7,4 +7,5 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrLoginAlreadyExists = errors.New("login already exists")
 	ErrInvalidToken       = errors.New("invalid token")
+	ErrPermissionDenied   = errors.New("permission denied")
 )
@@ -5,13 +5,16 @@ type Permission int32
 
 const (
 	PermissionNone          Permission = 0
-	PermissionCreate        Permission = 1 // Право на создание сущностей.
-	PermissionApply         Permission = 2 // Право на применение изменений.
-	PermissionRollback      Permission = 3 // Право на откат изменений.
-	PermissionList          Permission = 4 // Право на просмотр списков.
-	PermissionGet           Permission = 5 // Право на получение конкретной сущности.
-	PermissionApplyOther    Permission = 6 // Право на применение изменений, созданных другими.
-	PermissionRollbackOther Permission = 7 // Право на откат изменений, созданных другими.
+	PermissionCreate        Permission = 1  // Право на создание сущностей.
+	PermissionApply         Permission = 2  // Право на применение изменений.
+	PermissionRollback      Permission = 3  // Право на откат изменений.
+	PermissionList          Permission = 4  // Право на просмотр списков.
+	PermissionGet           Permission = 5  // Право на получение конкретной сущности.
+	PermissionApplyOther    Permission = 6  // Право на применение изменений, созданных другими.
+	PermissionRollbackOther Permission = 7  // Право на откат изменений, созданных другими.
+	PermissionManageTargets Permission = 8  // Право на управление реестром целевых баз данных.
+	PermissionBaseline      Permission = 9  // Право на отметку миграций примененными без выполнения скриптов.
+	PermissionAudit         Permission = 10 // Право на просмотр журнала аудита.
 )
 
 func (p Permission) String() string {
@@ -27,4 +30,7 @@ var Permission_name = map[Permission]string{
 	PermissionGet:           "PERMISSION_GET",
 	PermissionApplyOther:    "PERMISSION_APPLY_OTHER",
 	PermissionRollbackOther: "PERMISSION_ROLLBACK_OTHER",
+	PermissionManageTargets: "PERMISSION_MANAGE_TARGETS",
+	PermissionBaseline:      "PERMISSION_BASELINE",
+	PermissionAudit:         "PERMISSION_AUDIT",
 }
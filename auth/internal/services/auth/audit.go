@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"auth/internal/entity"
+	"auth/pkg/logger"
+)
+
+// auditTimeout - время на запись события в журнал аудита.
+const auditTimeout = 5 * time.Second
+
+// audit записывает действие в журнал аудита вместе со сведениями о клиенте
+// из контекста. Запись выполняется и после отмены запроса; ошибка записи
+// не меняет результат действия и только логируется.
+func (a *Auth) audit(ctx context.Context, action string, actorID int64, resource string, err error) {
+	meta := entity.RequestMetaFromContext(ctx)
+	event := entity.AuditEvent{
+		ActorID:   actorID,
+		Action:    action,
+		Resource:  resource,
+		IP:        meta.IP,
+		UserAgent: meta.UserAgent,
+		Outcome:   auditOutcome(err),
+		CreatedAt: time.Now(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditTimeout)
+	defer cancel()
+
+	if err := a.auditRepo.AddAuditEvent(ctx, event); err != nil {
+		logger.Error(fmt.Errorf("a.auditRepo.AddAuditEvent: %w", err))
+	}
+}
+
+// auditOutcome отделяет отказы в доступе от прочих ошибок.
+func auditOutcome(err error) entity.AuditOutcome {
+	switch {
+	case err == nil:
+		return entity.AuditOutcomeSucceeded
+	case errors.Is(err, entity.ErrInvalidCredentials),
+		errors.Is(err, entity.ErrInvalidToken),
+		errors.Is(err, entity.ErrPermissionDenied):
+		return entity.AuditOutcomeDenied
+	default:
+		return entity.AuditOutcomeFailed
+	}
+}
+
+func loginResource(login string) string {
+	return "login:" + login
+}
+
+func userResource(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
@@ -15,18 +15,30 @@ type authService interface {
 	Login(ctx context.Context, login, password string) (string, error)
 	Register(ctx context.Context, login, password string) (int64, error)
 	CheckPermission(ctx context.Context, userId int64, permission entity.Permission) (bool, error)
+	CheckPermissions(ctx context.Context, userID int64, permissions []entity.Permission) (map[entity.Permission]bool, int64, error)
+	PermissionsRevision(ctx context.Context) (int64, error)
+	ValidateToken(ctx context.Context, token string) (entity.User, error)
 	Logout(ctx context.Context, token string) error
+	ListAuditEvents(ctx context.Context, token string, filter entity.AuditFilter) ([]entity.AuditEvent, error)
 }
 
 var _ authService = (*Auth)(nil)
 
 type authRepo interface {
 	GetUserByLogin(ctx context.Context, login string) (entity.User, error)
+	GetUserByID(ctx context.Context, userID int64) (entity.User, error)
 	SaveUser(ctx context.Context, login string, passwordHash []byte) (int64, error)
 	CheckUserPermission(ctx context.Context, userID int64, permission entity.Permission) (bool, error)
+	CheckUserPermissions(ctx context.Context, userID int64, permissions []entity.Permission) (map[entity.Permission]bool, error)
+	GetPermissionsRevision(ctx context.Context) (int64, error)
 	SetUserInactive(ctx context.Context, userID int64) error
 }
 
+type auditRepo interface {
+	AddAuditEvent(ctx context.Context, event entity.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEvent, error)
+}
+
 type tokenProvider interface {
 	NewToken(user entity.User, duration time.Duration) (string, error)
 	ParseToken(token string) (entity.User, error)
@@ -35,6 +47,7 @@ type tokenProvider interface {
 // Auth - сервис аутентификации и авторизации.
 type Auth struct {
 	authRepo      authRepo
+	auditRepo     auditRepo
 	tokenProvider tokenProvider
 	tokenTTL      time.Duration
 }
@@ -42,12 +55,14 @@ type Auth struct {
 // New - конструктор сервиса аутентификации и авторизации.
 func New(
 	authRepo authRepo,
+	auditRepo auditRepo,
 	tokenProvider tokenProvider,
 	tokenTTL time.Duration,
 ) *Auth {
 	return &Auth{
 		tokenTTL:      tokenTTL,
 		authRepo:      authRepo,
+		auditRepo:     auditRepo,
 		tokenProvider: tokenProvider,
 	}
 }
@@ -56,6 +71,7 @@ func New(
 //
 // Если пользователь существует, но пароль неверный, возвращает ошибку.
 // Если пользователь не существует, возвращает ошибку.
+// Попытка входа записывается в журнал аудита.
 // Аргументы:
 //
 //	ctx: context.Context - Контекст запроса.
@@ -70,7 +86,10 @@ func (a *Auth) Login(
 	ctx context.Context,
 	login string,
 	password string,
-) (string, error) {
+) (_ string, err error) {
+	var actorID int64
+	defer func() { a.audit(ctx, "Login", actorID, loginResource(login), err) }()
+
 	user, err := a.authRepo.GetUserByLogin(ctx, login)
 	if err != nil {
 		if errors.Is(err, entity.ErrNotFound) {
@@ -87,12 +106,14 @@ func (a *Auth) Login(
 	if err != nil {
 		return "", fmt.Errorf("a.tokenProvider.NewToken: %w", err)
 	}
+	actorID = user.ID
 
 	return token, nil
 }
 
 // Register регистрирует нового пользователя в системе и возвращает его ID.
 // Если пользователь с данным логином уже существует, возвращает ошибку ErrLoginAlreadyExists.
+// Попытка регистрации записывается в журнал аудита.
 // Аргументы:
 //
 //	ctx: context.Context - Контекст запроса.
@@ -103,13 +124,15 @@ func (a *Auth) Login(
 //
 //	int64: Уникальный идентификатор созданного пользователя.
 //	error: Ошибка, если таковая имеется (например, логин уже существует).
-func (a *Auth) Register(ctx context.Context, login string, pass string) (int64, error) {
+func (a *Auth) Register(ctx context.Context, login string, pass string) (id int64, err error) {
+	defer func() { a.audit(ctx, "Register", id, loginResource(login), err) }()
+
 	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
 	if err != nil {
 		return 0, fmt.Errorf("bcrypt.GenerateFromPassword: %w", err)
 	}
 
-	id, err := a.authRepo.SaveUser(ctx, login, passHash)
+	id, err = a.authRepo.SaveUser(ctx, login, passHash)
 	if err != nil {
 		if errors.Is(err, entity.ErrLoginAlreadyExists) {
 			return 0, fmt.Errorf("a.authRepo.SaveUser: %w", entity.ErrLoginAlreadyExists)
@@ -139,7 +162,80 @@ func (a *Auth) CheckPermission(ctx context.Context, userID int64, permission ent
 	return allowed, nil
 }
 
+// CheckPermissions проверяет несколько разрешений пользователя одним запросом к базе данных.
+// Ревизия прав читается до проверки, поэтому кэш, помеченный этой ревизией,
+// не переживет изменение прав, произошедшее во время проверки.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	userID: int64 - Идентификатор пользователя.
+//	permissions: []entity.Permission - Проверяемые разрешения.
+//
+// Возвращает:
+//
+//	map[entity.Permission]bool: Решение по каждому разрешению.
+//	int64: Ревизия прав, на которой приняты решения.
+//	error: Ошибка, если таковая имеется.
+func (a *Auth) CheckPermissions(ctx context.Context, userID int64, permissions []entity.Permission) (map[entity.Permission]bool, int64, error) {
+	revision, err := a.authRepo.GetPermissionsRevision(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("a.authRepo.GetPermissionsRevision: %w", err)
+	}
+
+	decisions, err := a.authRepo.CheckUserPermissions(ctx, userID, permissions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("a.authRepo.CheckUserPermissions: %w", err)
+	}
+	return decisions, revision, nil
+}
+
+// PermissionsRevision возвращает текущую ревизию прав.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//
+// Возвращает:
+//
+//	int64: Ревизия прав; меняется при изменении ролей, прав ролей и активности пользователей.
+//	error: Ошибка, если таковая имеется.
+func (a *Auth) PermissionsRevision(ctx context.Context) (int64, error) {
+	revision, err := a.authRepo.GetPermissionsRevision(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("a.authRepo.GetPermissionsRevision: %w", err)
+	}
+	return revision, nil
+}
+
+// ValidateToken проверяет токен доступа и возвращает пользователя, которому он выдан.
+// Токен пользователя, вышедшего из системы, считается недействительным.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	token: string - Токен доступа пользователя.
+//
+// Возвращает:
+//
+//	entity.User: Пользователь, которому выдан токен.
+//	error: Ошибка, если таковая имеется (ErrInvalidToken для недействительного токена).
+func (a *Auth) ValidateToken(ctx context.Context, token string) (entity.User, error) {
+	claims, err := a.tokenProvider.ParseToken(token)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("a.tokenProvider.ParseToken: %w", err)
+	}
+
+	user, err := a.authRepo.GetUserByID(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return entity.User{}, fmt.Errorf("a.authRepo.GetUserByID: %w", entity.ErrInvalidToken)
+		}
+		return entity.User{}, fmt.Errorf("a.authRepo.GetUserByID: %w", err)
+	}
+
+	return user, nil
+}
+
 // Logout делает сессию пользователя недействительным.
+// Выход записывается в журнал аудита.
 // Аргументы:
 //
 //	ctx: context.Context - Контекст запроса.
@@ -148,11 +244,14 @@ func (a *Auth) CheckPermission(ctx context.Context, userID int64, permission ent
 // Возвращает:
 //
 //	error: Ошибка, если таковая имеется. Не возвращает ошибку, если токен уже недействителен.
-func (a *Auth) Logout(ctx context.Context, token string) error {
+func (a *Auth) Logout(ctx context.Context, token string) (err error) {
 	user, err := a.tokenProvider.ParseToken(token)
 	if err != nil {
+		// Недействительный токен не считается ошибкой выхода, но попытка записывается как отказ
+		a.audit(ctx, "Logout", 0, "", fmt.Errorf("a.tokenProvider.ParseToken: %w", err))
 		return nil
 	}
+	defer func() { a.audit(ctx, "Logout", user.ID, userResource(user.ID), err) }()
 
 	err = a.authRepo.SetUserInactive(ctx, user.ID)
 	if err != nil {
@@ -161,3 +260,41 @@ func (a *Auth) Logout(ctx context.Context, token string) error {
 
 	return nil
 }
+
+// ListAuditEvents возвращает журнал аудита пользователю с правом PERMISSION_AUDIT.
+// Просмотр журнала также записывается в журнал аудита.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	token: string - Токен доступа пользователя, запрашивающего журнал.
+//	filter: entity.AuditFilter - Условия выборки и размер страницы.
+//
+// Возвращает:
+//
+//	[]entity.AuditEvent: Записи журнала от новых к старым.
+//	error: Ошибка, если таковая имеется (ErrInvalidToken для недействительного токена,
+//	ErrPermissionDenied, если у пользователя нет права PERMISSION_AUDIT).
+func (a *Auth) ListAuditEvents(ctx context.Context, token string, filter entity.AuditFilter) (_ []entity.AuditEvent, err error) {
+	var actorID int64
+	defer func() { a.audit(ctx, "ListAuditEvents", actorID, "audit_log", err) }()
+
+	user, err := a.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("a.ValidateToken: %w", err)
+	}
+	actorID = user.ID
+
+	allowed, err := a.authRepo.CheckUserPermission(ctx, user.ID, entity.PermissionAudit)
+	if err != nil {
+		return nil, fmt.Errorf("a.authRepo.CheckUserPermission: %w", err)
+	}
+	if !allowed {
+		return nil, fmt.Errorf("user %d lacks %s: %w", user.ID, entity.PermissionAudit, entity.ErrPermissionDenied)
+	}
+
+	events, err := a.auditRepo.ListAuditEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("a.auditRepo.ListAuditEvents: %w", err)
+	}
+	return events, nil
+}
@@ -7,6 +7,12 @@ import (
 
 type initerRepository interface {
 	CreateIfNeededUsersTable(ctx context.Context) error
+	CreateIfNeededRolesTable(ctx context.Context) error
+	CreateIfNeededPermissionsTable(ctx context.Context) error
+	CreateIfNeededRolePermissionsTable(ctx context.Context) error
+	CreateIfNeededUserRolesTable(ctx context.Context) error
+	CreateIfNeededPermissionsRevision(ctx context.Context) error
+	CreateIfNeededAuditLogTable(ctx context.Context) error
 }
 
 type DbInitializerService struct {
@@ -21,9 +27,19 @@ func New(repo initerRepository) *DbInitializerService {
 
 // InitDB инициализирует базу данных.
 func (s *DbInitializerService) InitDB(ctx context.Context) error {
-	err := s.repo.CreateIfNeededUsersTable(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to initialize database tables: %w", err)
+	steps := []func(ctx context.Context) error{
+		s.repo.CreateIfNeededUsersTable,
+		s.repo.CreateIfNeededRolesTable,
+		s.repo.CreateIfNeededPermissionsTable,
+		s.repo.CreateIfNeededRolePermissionsTable,
+		s.repo.CreateIfNeededUserRolesTable,
+		s.repo.CreateIfNeededPermissionsRevision,
+		s.repo.CreateIfNeededAuditLogTable,
+	}
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return fmt.Errorf("failed to initialize database tables: %w", err)
+		}
 	}
 	return nil
 }
@@ -36,19 +36,27 @@ func (s *JWTService) NewToken(user entity.User, duration time.Duration) (string,
 	return tokenString, nil
 }
 
+// ParseToken проверяет подпись и срок действия токена и возвращает пользователя из него.
 func (s *JWTService) ParseToken(token string) (entity.User, error) {
 	claims := jwt.MapClaims{}
 
 	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
 		return []byte(s.secret), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
 	if err != nil {
-		return entity.User{}, fmt.Errorf("failed to parse token: %w", err)
+		return entity.User{}, fmt.Errorf("failed to parse token: %w: %w", entity.ErrInvalidToken, err)
 	}
 
+	// Числа в JSON декодируются как float64
+	uid, ok := claims["uid"].(float64)
+	if !ok || uid <= 0 {
+		return entity.User{}, fmt.Errorf("token has no uid claim: %w", entity.ErrInvalidToken)
+	}
+	login, _ := claims["login"].(string)
+
 	user := entity.User{
-		ID:    claims["uid"].(int64),
-		Login: claims["login"].(string),
+		ID:    int64(uid),
+		Login: login,
 	}
 
 	return user, nil
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// runImport реализует подкоманду import: упаковывает каталог с файлами миграций
+// в zip-архив и передает его запущенному сервису через ImportMigrations.
+//
+// Токен доступа, выданный сервисом авторизации, передается флагом -token
+// или переменной окружения MIGRATOR_TOKEN.
+//
+//	migrator import -addr localhost:50051 -target 1 -token <token> [-format auto] ./migrations
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:50051", "migrator gRPC address")
+	targetID := flags.Int64("target", 0, "target database ID")
+	token := flags.String("token", os.Getenv("MIGRATOR_TOKEN"), "access token issued by the auth service Login")
+	format := flags.String("format", "auto", "file layout: auto, golang-migrate, goose or flyway")
+	timeout := flags.Duration("timeout", time.Minute, "request timeout")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s import [flags] <dir>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("migrations directory is required")
+	}
+
+	if *token == "" {
+		return fmt.Errorf("access token is required: pass -token or set MIGRATOR_TOKEN")
+	}
+
+	archive, err := zipDir(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("pack migrations directory: %w", err)
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("connect to migrator: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+
+	resp, err := migrator.NewMigrationServiceClient(conn).ImportMigrations(ctx, &migrator.ImportMigrationsRequest{
+		TargetId: *targetID,
+		Archive:  archive,
+		Format:   *format,
+	})
+	if err != nil {
+		return fmt.Errorf("import migrations: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tID\tVERSION\tNAME\tSOURCE\tREASON")
+	for _, item := range resp.GetItems() {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n",
+			item.GetStatus(), item.GetMigrationId(), item.GetVersion(), item.GetName(), item.GetSource(), item.GetReason())
+	}
+	return w.Flush()
+}
+
+// zipDir упаковывает файлы каталога в zip-архив с путями относительно каталога.
+func zipDir(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		w, err := zw.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"flag"
 	"fmt"
 	"log"
@@ -17,11 +18,31 @@ import (
 	"migrator/config"
 	"migrator/internal/adapters/grpc/client"
 	grpc_server "migrator/internal/adapters/grpc/server"
+	"migrator/internal/adapters/pools"
+	auditRepo "migrator/internal/adapters/repository/audit"
+	backfillRepo "migrator/internal/adapters/repository/backfill"
+	environmentRepo "migrator/internal/adapters/repository/environment"
+	historyRepo "migrator/internal/adapters/repository/history"
+	idempotencyRepo "migrator/internal/adapters/repository/idempotency"
 	"migrator/internal/adapters/repository/intiter"
+	lockRepo "migrator/internal/adapters/repository/lock"
 	"migrator/internal/adapters/repository/migration"
+	schemaRepo "migrator/internal/adapters/repository/schema"
+	targetRepo "migrator/internal/adapters/repository/target"
+	"migrator/internal/adapters/targetdb"
+	"migrator/internal/entity"
+	auditService "migrator/internal/services/audit"
+	backfillService "migrator/internal/services/backfill"
 	"migrator/internal/services/checker"
+	environmentService "migrator/internal/services/environment"
+	"migrator/internal/services/exporter"
+	idempotencyService "migrator/internal/services/idempotency"
+	"migrator/internal/services/importer"
 	"migrator/internal/services/initializer"
+	"migrator/internal/services/linter"
+	"migrator/internal/services/locker"
 	migratorService "migrator/internal/services/migrator"
+	targetService "migrator/internal/services/target"
 	"migrator/pkg/api/auth"
 	"migrator/pkg/api/migrator"
 	"migrator/pkg/logger"
@@ -35,6 +56,7 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 )
@@ -44,6 +66,14 @@ const (
 )
 
 func main() {
+	// Подкоманда импорта миграций из файлов работает как клиент сервиса
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:]); err != nil {
+			log.Fatalf("import failed: %v", err)
+		}
+		return
+	}
+
 	// Получение пути к файлу конфигурации
 	path := flag.String("config", defaultConfigPath, "path to config file")
 
@@ -65,24 +95,82 @@ func main() {
 	defer cancel()
 
 	initerRepo := intiter.New(dbConn.Pool)
-	initerSrv := initializer.New(initerRepo)
+	initerSrv := initializer.New(initerRepo, cfg.Postgres.URL)
 	err = initerSrv.InitDB(ctx)
 	if err != nil {
 		log.Fatalf("failed to initialize database: %v", err)
 	}
 
-	migrationRepo := migration.New(dbConn.Pool)
-	migrationSrv := migratorService.New(migrationRepo)
+	targetsRepo := targetRepo.New(dbConn.Pool)
+	// Применение миграции арендаторов удерживает одно подключение под блокировкой
+	// целевой базы данных, поэтому пул вмещает его и транзакции всех схем
+	targetPoolSize := max(cfg.Targets.MaxPoolSize, cfg.Tenants.Concurrency+1)
+	targetPools := pools.New(
+		targetsRepo,
+		targetdb.MaxPoolSize(targetPoolSize),
+		targetdb.ConnAttempts(cfg.Targets.ConnAttempts),
+		targetdb.LockTimeout(cfg.Lock.WaitTimeout),
+	)
+	defer targetPools.Close()
+	targetSrv := targetService.New(targetsRepo, targetPools)
+	environmentSrv := environmentService.New(environmentRepo.New(dbConn.Pool), targetsRepo)
+
+	replica := cfg.Lock.Replica
+	if replica == "" {
+		replica, _ = os.Hostname()
+	}
+	lockerSrv := locker.New(lockRepo.New(dbConn.Pool), replica, cfg.Lock.WaitTimeout, cfg.Lock.Lease)
+
+	lintRules := make(map[string]entity.LintSeverity, len(cfg.Linter.Rules))
+	for rule, severity := range cfg.Linter.Rules {
+		lintRules[rule] = entity.LintSeverity(severity)
+	}
+	linterSrv, err := linter.New(entity.LintSeverity(cfg.Linter.BlockSeverity), lintRules)
+	if err != nil {
+		log.Fatalf("invalid linter config: %v", err)
+	}
 
-	grpcConn, err := grpc.NewClient(cfg.Auth.GRPC.Addr)
+	migrationRepo := migration.New(dbConn.Pool, targetPools, cfg.Tenants.DiscoveryQuery)
+	backfillsRepo := backfillRepo.New(dbConn.Pool, targetPools)
+	migrationSrv := migratorService.New(migrationRepo, lockerSrv, historyRepo.New(dbConn.Pool), linterSrv, environmentSrv, environmentSrv, schemaRepo.New(dbConn.Pool, targetPools), backfillsRepo, cfg.Tenants.Concurrency)
+	backfillSrv := backfillService.New(
+		backfillsRepo,
+		migrationRepo,
+		historyRepo.New(dbConn.Pool),
+		environmentSrv,
+		environmentSrv,
+		replica,
+		cfg.Backfill.Lease,
+		cfg.Backfill.PollInterval,
+	)
+	go backfillSrv.Run(ctx)
+
+	grpcConn, err := grpc.NewClient(cfg.Auth.GRPC.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		log.Fatalf("failed to connect to auth service: %v", err)
 	}
 	authSrv := auth.NewAuthClient(grpcConn)
-	authClient := client.New(authSrv)
+	authClient := client.New(
+		authSrv,
+		client.CacheTTL(cfg.Auth.Cache.TTL),
+		client.CacheSize(cfg.Auth.Cache.Size),
+	)
+	go authClient.WatchPermissionsRevision(ctx, cfg.Auth.Cache.RevisionInterval)
 
 	checkerSrv := checker.NewMigratorWithAuth(migrationSrv, authClient)
-	grpcService := grpc_server.NewMigration(checkerSrv)
+	targetCheckerSrv := checker.NewTargetsWithAuth(targetSrv, authClient)
+	environmentCheckerSrv := checker.NewEnvironmentsWithAuth(environmentSrv, authClient)
+	lockCheckerSrv := checker.NewLocksWithAuth(lockerSrv, authClient)
+	backfillCheckerSrv := checker.NewBackfillsWithAuth(backfillSrv, migrationSrv, authClient)
+	auditSrv := auditService.New(auditRepo.New(dbConn.Pool))
+	auditCheckerSrv := checker.NewAuditWithAuth(auditSrv, authClient)
+	idempotencySrv := idempotencyService.New(idempotencyRepo.New(dbConn.Pool), cfg.Idempotency.Retention, cfg.Idempotency.InProgressTimeout)
+	go idempotencySrv.Run(ctx)
+	importerSrv := importer.New(checkerSrv)
+	exporterSrv := exporter.New(checkerSrv, targetCheckerSrv)
+	grpcService := grpc_server.NewMigration(checkerSrv, targetCheckerSrv, environmentCheckerSrv, lockCheckerSrv, backfillCheckerSrv, auditCheckerSrv, idempotencySrv, importerSrv, exporterSrv)
+	authenticator := grpc_server.NewAuthenticator(authClient)
+	auditor := grpc_server.NewAuditor(auditSrv)
 
 	loggingOpts := []logging.Option{
 		logging.WithLogOnEvents(
@@ -103,41 +191,80 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
-		recovery.UnaryServerInterceptor(recoveryOpts...),
-		logging.UnaryServerInterceptor(InterceptorLogger(logger.New(logger.InfoLevel)), loggingOpts...),
-	))
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			recovery.UnaryServerInterceptor(recoveryOpts...),
+			logging.UnaryServerInterceptor(InterceptorLogger(logger.New(logger.InfoLevel)), loggingOpts...),
+			authenticator.UnaryServerInterceptor(),
+			auditor.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			recovery.StreamServerInterceptor(recoveryOpts...),
+			logging.StreamServerInterceptor(InterceptorLogger(logger.New(logger.InfoLevel)), loggingOpts...),
+			authenticator.StreamServerInterceptor(),
+			auditor.StreamServerInterceptor(),
+		),
+	)
 
 	reflection.Register(grpcServer)
 
 	migrator.RegisterMigrationServiceServer(grpcServer, grpcService)
 
-	mux := runtime.NewServeMux()
+	// Шлюз вызывает сервис напрямую, минуя перехватчики, поэтому аудит подключается к нему отдельно;
+	// заголовок Idempotency-Key передается сервису в метаданных
+	mux := runtime.NewServeMux(append(
+		auditor.ServeMuxOptions(),
+		runtime.WithIncomingHeaderMatcher(grpc_server.IncomingHeaderMatcher),
+	)...)
 	err = migrator.RegisterMigrationServiceHandlerServer(ctx, mux, grpcService)
 	if err != nil {
 		log.Fatalf("failed to register handler: %v", err)
 	}
+	// Потоковая выгрузка не поддерживается шлюзом, поэтому архив отдается отдельным обработчиком
+	err = mux.HandlePath(http.MethodGet, "/v1/export", grpcService.ExportHTTP)
+	if err != nil {
+		log.Fatalf("failed to register export handler: %v", err)
+	}
 
 	withCors := cors.New(cors.Options{
 		AllowedOrigins:   []string{"http://localhost", "http://localhost:8082"},
 		AllowedMethods:   []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"ACCEPT", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"ACCEPT", "Authorization", "Content-Type", "X-CSRF-Token", "Idempotency-Key"},
+		ExposedHeaders:   []string{"Link", "Grpc-Metadata-Idempotency-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           300,
-	}).Handler(mux)
+	}).Handler(authenticator.HTTPMiddleware(mux))
 
 	httpServer := &http.Server{
 		Addr:    ":" + cfg.HTTP.Port,
 		Handler: withCors,
 	}
 
+	// Метрики (в том числе кэша прав) доступны без токена, поэтому отдаются
+	// отдельным сервером, по умолчанию только на localhost
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/debug/vars", expvar.Handler())
+	adminServer := &http.Server{
+		Addr:    cfg.HTTP.AdminAddr,
+		Handler: adminMux,
+	}
+
 	// Start HTTP server (and proxy calls to gRPC server endpoint)
-	httpServer.ListenAndServe()
+	httpErrChan := make(chan error, 2)
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			httpErrChan <- err
+		}
+	}()
+	go func() {
+		if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			httpErrChan <- err
+		}
+	}()
 
-	grpcErrChan := make(chan error)
+	grpcErrChan := make(chan error, 1)
 	go func() {
-		if err = grpcServer.Serve(lis); err != nil {
+		if err := grpcServer.Serve(lis); err != nil {
 			grpcErrChan <- err
 		}
 	}()
@@ -151,15 +278,23 @@ func main() {
 		logger.Info("app - Run - signal: " + s.String())
 	case err = <-grpcErrChan:
 		logger.Error(fmt.Errorf("app - Run - grpcServer.Serve: %w", err))
+	case err = <-httpErrChan:
+		logger.Error(fmt.Errorf("app - Run - httpServer.ListenAndServe: %w", err))
 	}
 
 	// Shutdown
+	if err := httpServer.Shutdown(context.WithoutCancel(ctx)); err != nil {
+		logger.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
+	}
+	if err := adminServer.Shutdown(context.WithoutCancel(ctx)); err != nil {
+		logger.Error(fmt.Errorf("app - Run - adminServer.Shutdown: %w", err))
+	}
 	grpcServer.GracefulStop()
 }
 
 // InterceptorLogger adapts logger to interceptor logger.
 func InterceptorLogger(l *logger.Logger) logging.Logger {
 	return logging.LoggerFunc(func(ctx context.Context, lvl logging.Level, msg string, fields ...any) {
-		l.Debug(fmt.Sprintf("%s: %s", lvl, msg), fields...)
+		l.Debug(fmt.Sprintf("%v: %s", lvl, msg), fields...)
 	})
 }
@@ -3,6 +3,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -22,6 +23,18 @@ type (
 		HTTP HTTP `yaml:"http"`
 		// Auth contains auth service settings.
 		Auth Auth `yaml:"auth"`
+		// Targets contains target database connection settings.
+		Targets Targets `yaml:"targets"`
+		// Lock contains target database locking settings.
+		Lock Lock `yaml:"lock"`
+		// Linter contains SQL linter settings.
+		Linter Linter `yaml:"linter"`
+		// Tenants contains schema-per-tenant settings.
+		Tenants Tenants `yaml:"tenants"`
+		// Backfill contains batched data migration settings.
+		Backfill Backfill `yaml:"backfill"`
+		// Idempotency contains idempotency key settings.
+		Idempotency Idempotency `yaml:"idempotency"`
 	}
 
 	// App contains application settings.
@@ -44,6 +57,64 @@ type (
 		URL string `yaml:"url" env:"POSTGRES_URL"`
 	}
 
+	// Targets contains target database connection settings.
+	Targets struct {
+		// MaxPoolSize is the connection pool size per target database; it is raised to
+		// Tenants.Concurrency+1 so that a tenant migration keeps its lock connection
+		// and still migrates Tenants.Concurrency schemas at once.
+		MaxPoolSize int `yaml:"max_pool_size" env:"TARGETS_MAX_POOL_SIZE" env-default:"2"`
+		// ConnAttempts is the number of connection attempts to a target database.
+		ConnAttempts int `yaml:"conn_attempts" env:"TARGETS_CONN_ATTEMPTS" env-default:"1"`
+	}
+
+	// Lock contains target database locking settings.
+	Lock struct {
+		// WaitTimeout is how long to wait for a lock held by another replica,
+		// and for the lock on the target database itself.
+		WaitTimeout time.Duration `yaml:"wait_timeout" env:"LOCK_WAIT_TIMEOUT" env-default:"30s"`
+		// Lease is how long a lock stays valid without renewal; the holder renews it every third of
+		// the lease, and after a replica crash other replicas can take the lock once it expires.
+		Lease time.Duration `yaml:"lease" env:"LOCK_LEASE" env-default:"1m"`
+		// Replica is the replica name shown to lock holders; defaults to the hostname.
+		Replica string `yaml:"replica" env:"REPLICA_ID"`
+	}
+
+	// Linter contains SQL linter settings.
+	Linter struct {
+		// BlockSeverity is the lowest finding severity that blocks migration creation; "off" never blocks.
+		BlockSeverity string `yaml:"block_severity" env:"LINTER_BLOCK_SEVERITY" env-default:"error"`
+		// Rules overrides rule severities (info, warning, error or off),
+		// e.g. "drop_table:error,non_concurrent_index:off".
+		Rules map[string]string `yaml:"rules" env:"LINTER_RULES"`
+	}
+
+	// Tenants contains schema-per-tenant settings.
+	Tenants struct {
+		// DiscoveryQuery returns tenant schema names of a target database in its first column.
+		DiscoveryQuery string `yaml:"discovery_query" env:"TENANTS_DISCOVERY_QUERY" env-default:"SELECT nspname FROM pg_namespace WHERE nspname LIKE 'tenant\\_%' ORDER BY nspname"`
+		// Concurrency is the maximum number of tenant schemas migrated at once;
+		// each one uses its own target connection (see Targets.MaxPoolSize).
+		Concurrency int `yaml:"concurrency" env:"TENANTS_CONCURRENCY" env-default:"4"`
+	}
+
+	// Backfill contains batched data migration settings.
+	Backfill struct {
+		// PollInterval is how often a replica looks for running backfills without an owner.
+		PollInterval time.Duration `yaml:"poll_interval" env:"BACKFILL_POLL_INTERVAL" env-default:"10s"`
+		// Lease is how long after its last batch a backfill stays with its replica;
+		// it must exceed the longest batch plus the pause between batches.
+		Lease time.Duration `yaml:"lease" env:"BACKFILL_LEASE" env-default:"5m"`
+	}
+
+	// Idempotency contains idempotency key settings.
+	Idempotency struct {
+		// Retention is how long a completed request is replayed for a repeated idempotency key.
+		Retention time.Duration `yaml:"retention" env:"IDEMPOTENCY_RETENTION" env-default:"24h"`
+		// InProgressTimeout is how long a key stays reserved by a request that has not finished,
+		// for example because its replica stopped; after it a repeated request runs again.
+		InProgressTimeout time.Duration `yaml:"in_progress_timeout" env:"IDEMPOTENCY_IN_PROGRESS_TIMEOUT" env-default:"1h"`
+	}
+
 	// GRPC contains gRPC server settings.
 	GRPC struct {
 		// Port is the gRPC server port.
@@ -54,12 +125,27 @@ type (
 	HTTP struct {
 		// Port is the HTTP server port.
 		Port string `yaml:"port" env:"HTTP_PORT"`
+		// AdminAddr is the address of the admin HTTP server with metrics (/debug/vars);
+		// it listens on localhost by default because the metrics are served without a token.
+		AdminAddr string `yaml:"admin_addr" env:"HTTP_ADMIN_ADDR" env-default:"127.0.0.1:8090"`
 	}
 
 	// Auth contains auth service settings.
 	Auth struct {
 		// GRPC contains gRPC client settings.
 		GRPC AuthGRPC `yaml:"grpc"`
+		// Cache contains permission decision cache settings.
+		Cache AuthCache `yaml:"cache"`
+	}
+
+	// AuthCache contains permission decision cache settings.
+	AuthCache struct {
+		// TTL is how long a permission decision is cached; 0 disables the cache.
+		TTL time.Duration `yaml:"ttl" env:"AUTH_CACHE_TTL" env-default:"30s"`
+		// Size is the maximum number of cached decisions.
+		Size int `yaml:"size" env:"AUTH_CACHE_SIZE" env-default:"10000"`
+		// RevisionInterval is how often the permissions revision is polled to invalidate the cache.
+		RevisionInterval time.Duration `yaml:"revision_interval" env:"AUTH_CACHE_REVISION_INTERVAL" env-default:"5s"`
 	}
 
 	// GRPC contains gRPC client settings.
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"container/list"
+	"expvar"
+	"sync"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+// cacheMetrics публикуется в /debug/vars.
+var cacheMetrics = expvar.NewMap("auth_permission_cache")
+
+func init() {
+	cacheMetrics.Set("hit_ratio", expvar.Func(func() any {
+		hits, misses := metricValue("hits"), metricValue("misses")
+		if hits+misses == 0 {
+			return 0.0
+		}
+		return float64(hits) / float64(hits+misses)
+	}))
+}
+
+func metricValue(name string) int64 {
+	if v, ok := cacheMetrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+type decisionKey struct {
+	userID     int64
+	permission entity.Permission
+}
+
+type decision struct {
+	key       decisionKey
+	allowed   bool
+	revision  int64
+	expiresAt time.Time
+}
+
+// decisionCache - кэш решений о правах, ограниченный по времени жизни и размеру.
+// Решения помечаются ревизией прав сервиса авторизации: при появлении новой
+// ревизии кэш очищается целиком.
+type decisionCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	size     int
+	revision int64
+	order    *list.List // от недавно использованных к давно использованным
+	items    map[decisionKey]*list.Element
+}
+
+func newDecisionCache(ttl time.Duration, size int) *decisionCache {
+	return &decisionCache{
+		ttl:   ttl,
+		size:  size,
+		order: list.New(),
+		items: make(map[decisionKey]*list.Element),
+	}
+}
+
+func (c *decisionCache) enabled() bool {
+	return c.ttl > 0 && c.size > 0
+}
+
+// get возвращает решение из кэша, если оно не устарело.
+func (c *decisionCache) get(userID int64, permission entity.Permission) (bool, bool) {
+	if !c.enabled() {
+		return false, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[decisionKey{userID: userID, permission: permission}]
+	if !ok {
+		cacheMetrics.Add("misses", 1)
+		return false, false
+	}
+
+	d := element.Value.(*decision)
+	if d.revision != c.revision || time.Now().After(d.expiresAt) {
+		c.remove(element)
+		cacheMetrics.Add("misses", 1)
+		return false, false
+	}
+
+	c.order.MoveToFront(element)
+	cacheMetrics.Add("hits", 1)
+	return d.allowed, true
+}
+
+// put сохраняет решения, принятые на ревизии revision.
+func (c *decisionCache) put(userID int64, decisions map[entity.Permission]bool, revision int64) {
+	if !c.enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if revision > c.revision {
+		c.reset(revision)
+	}
+	if revision < c.revision {
+		// Решения приняты до последнего изменения прав
+		return
+	}
+
+	expiresAt := time.Now().Add(c.ttl)
+	for permission, allowed := range decisions {
+		key := decisionKey{userID: userID, permission: permission}
+		if element, ok := c.items[key]; ok {
+			c.remove(element)
+		}
+		c.items[key] = c.order.PushFront(&decision{
+			key:       key,
+			allowed:   allowed,
+			revision:  revision,
+			expiresAt: expiresAt,
+		})
+	}
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		cacheMetrics.Add("evictions", 1)
+	}
+	cacheMetrics.Set("size", intVar(c.order.Len()))
+}
+
+// invalidate очищает кэш, если ревизия прав изменилась.
+func (c *decisionCache) invalidate(revision int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if revision != c.revision {
+		c.reset(revision)
+	}
+}
+
+func (c *decisionCache) reset(revision int64) {
+	if c.order.Len() > 0 {
+		cacheMetrics.Add("invalidations", 1)
+	}
+	c.revision = revision
+	c.order.Init()
+	c.items = make(map[decisionKey]*list.Element)
+	cacheMetrics.Set("size", intVar(0))
+}
+
+func (c *decisionCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*decision).key)
+}
+
+func intVar(value int) *expvar.Int {
+	v := new(expvar.Int)
+	v.Set(int64(value))
+	return v
+}
@@ -3,91 +3,166 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"migrator/internal/entity"
 	desc "migrator/pkg/api/auth"
+	"migrator/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	_defaultCacheTTL  = 30 * time.Second
+	_defaultCacheSize = 10000
 )
 
 type authWrapper struct {
-	auth desc.AuthClient
+	auth      desc.AuthClient
+	cacheTTL  time.Duration
+	cacheSize int
+	cache     *decisionCache
 }
 
-func New(auth desc.AuthClient) *authWrapper {
-	return &authWrapper{auth: auth}
+func New(auth desc.AuthClient, opts ...Option) *authWrapper {
+	a := &authWrapper{
+		auth:      auth,
+		cacheTTL:  _defaultCacheTTL,
+		cacheSize: _defaultCacheSize,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	a.cache = newDecisionCache(a.cacheTTL, a.cacheSize)
+	return a
 }
 
-func (a *authWrapper) CheckPermissionCreate(ctx context.Context, userID int64) (bool, error) {
-	resp, err := a.auth.CheckPermission(ctx, &desc.PermissionRequest{
-		UserId:     userID,
-		Permission: desc.Permission_PERMISSION_CREATE,
-	})
+func (a *authWrapper) ValidateToken(ctx context.Context, token string) (entity.Identity, error) {
+	resp, err := a.auth.ValidateToken(ctx, &desc.ValidateTokenRequest{Token: token})
 	if err != nil {
-		return false, fmt.Errorf("a.auth.CheckPermission: %w", err)
+		switch status.Code(err) {
+		case codes.Unauthenticated, codes.InvalidArgument:
+			return entity.Identity{}, fmt.Errorf("a.auth.ValidateToken: %w", entity.ErrUnauthenticated)
+		}
+		return entity.Identity{}, fmt.Errorf("a.auth.ValidateToken: %w", err)
 	}
-	return resp.GetHavePermission(), nil
+	return entity.Identity{UserID: resp.GetUserId(), Login: resp.GetLogin()}, nil
 }
 
-func (a *authWrapper) CheckPermissionApply(ctx context.Context, userID int64) (bool, error) {
-	resp, err := a.auth.CheckPermission(ctx, &desc.PermissionRequest{
-		UserId:     userID,
-		Permission: desc.Permission_PERMISSION_APPLY,
+// CheckPermissions проверяет несколько прав пользователя. Решения берутся из кэша,
+// а недостающие запрашиваются у сервиса авторизации одним вызовом.
+func (a *authWrapper) CheckPermissions(ctx context.Context, userID int64, permissions ...entity.Permission) (map[entity.Permission]bool, error) {
+	result := make(map[entity.Permission]bool, len(permissions))
+	var missing []desc.Permission
+	for _, permission := range permissions {
+		if _, ok := result[permission]; ok {
+			continue
+		}
+		allowed, ok := a.cache.get(userID, permission)
+		if !ok {
+			missing = append(missing, desc.Permission(permission))
+		}
+		result[permission] = allowed
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	resp, err := a.auth.CheckPermissions(ctx, &desc.CheckPermissionsRequest{
+		UserId:      userID,
+		Permissions: missing,
 	})
 	if err != nil {
-		return false, fmt.Errorf("a.auth.CheckPermission: %w", err)
+		return nil, fmt.Errorf("a.auth.CheckPermissions: %w", err)
 	}
-	return resp.GetHavePermission(), nil
+
+	fetched := make(map[entity.Permission]bool, len(resp.GetDecisions()))
+	for _, d := range resp.GetDecisions() {
+		fetched[entity.Permission(d.GetPermission())] = d.GetHavePermission()
+		result[entity.Permission(d.GetPermission())] = d.GetHavePermission()
+	}
+	a.cache.put(userID, fetched, resp.GetRevision())
+
+	return result, nil
 }
 
-func (a *authWrapper) CheckPermissionRollback(ctx context.Context, userID int64) (bool, error) {
-	resp, err := a.auth.CheckPermission(ctx, &desc.PermissionRequest{
-		UserId:     userID,
-		Permission: desc.Permission_PERMISSION_ROLLBACK,
-	})
+func (a *authWrapper) check(ctx context.Context, userID int64, permission entity.Permission) (bool, error) {
+	decisions, err := a.CheckPermissions(ctx, userID, permission)
 	if err != nil {
-		return false, fmt.Errorf("a.auth.CheckPermission: %w", err)
+		return false, err
 	}
-	return resp.GetHavePermission(), nil
+	return decisions[permission], nil
 }
 
-func (a *authWrapper) CheckPermissionList(ctx context.Context, userID int64) (bool, error) {
-	resp, err := a.auth.CheckPermission(ctx, &desc.PermissionRequest{
-		UserId:     userID,
-		Permission: desc.Permission_PERMISSION_LIST,
-	})
-	if err != nil {
-		return false, fmt.Errorf("a.auth.CheckPermission: %w", err)
+// WatchPermissionsRevision опрашивает ревизию прав сервиса авторизации с интервалом interval
+// и сбрасывает кэш решений, когда роли или права пользователей меняются.
+// Работает до отмены ctx.
+func (a *authWrapper) WatchPermissionsRevision(ctx context.Context, interval time.Duration) {
+	if !a.cache.enabled() || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		resp, err := a.auth.GetPermissionsRevision(ctx, &desc.GetPermissionsRevisionRequest{})
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn(fmt.Sprintf("failed to get permissions revision: %v", err))
+			}
+			continue
+		}
+		a.cache.invalidate(resp.GetRevision())
 	}
-	return resp.GetHavePermission(), nil
+}
+
+func (a *authWrapper) CheckPermissionCreate(ctx context.Context, userID int64) (bool, error) {
+	return a.check(ctx, userID, entity.PermissionCreate)
+}
+
+func (a *authWrapper) CheckPermissionApply(ctx context.Context, userID int64) (bool, error) {
+	return a.check(ctx, userID, entity.PermissionApply)
+}
+
+func (a *authWrapper) CheckPermissionRollback(ctx context.Context, userID int64) (bool, error) {
+	return a.check(ctx, userID, entity.PermissionRollback)
+}
+
+func (a *authWrapper) CheckPermissionList(ctx context.Context, userID int64) (bool, error) {
+	return a.check(ctx, userID, entity.PermissionList)
 }
 
 func (a *authWrapper) CheckPermissionGet(ctx context.Context, userID int64) (bool, error) {
-	resp, err := a.auth.CheckPermission(ctx, &desc.PermissionRequest{
-		UserId:     userID,
-		Permission: desc.Permission_PERMISSION_GET,
-	})
-	if err != nil {
-		return false, fmt.Errorf("a.auth.CheckPermission: %w", err)
-	}
-	return resp.GetHavePermission(), nil
+	return a.check(ctx, userID, entity.PermissionGet)
 }
 
 func (a *authWrapper) CheckPermissionApplyOther(ctx context.Context, userID int64) (bool, error) {
-	resp, err := a.auth.CheckPermission(ctx, &desc.PermissionRequest{
-		UserId:     userID,
-		Permission: desc.Permission_PERMISSION_APPLY_OTHER,
-	})
-	if err != nil {
-		return false, fmt.Errorf("a.auth.CheckPermission: %w", err)
-	}
-	return resp.GetHavePermission(), nil
+	return a.check(ctx, userID, entity.PermissionApplyOther)
 }
 
 func (a *authWrapper) CheckPermissionRollbackOther(ctx context.Context, userID int64) (bool, error) {
-	resp, err := a.auth.CheckPermission(ctx, &desc.PermissionRequest{
-		UserId:     userID,
-		Permission: desc.Permission_PERMISSION_ROLLBACK_OTHER,
-	})
-	if err != nil {
-		return false, fmt.Errorf("a.auth.CheckPermission: %w", err)
-	}
-	return resp.GetHavePermission(), nil
+	return a.check(ctx, userID, entity.PermissionRollbackOther)
+}
+
+func (a *authWrapper) CheckPermissionManageTargets(ctx context.Context, userID int64) (bool, error) {
+	return a.check(ctx, userID, entity.PermissionManageTargets)
+}
+
+func (a *authWrapper) CheckPermissionBaseline(ctx context.Context, userID int64) (bool, error) {
+	return a.check(ctx, userID, entity.PermissionBaseline)
+}
+
+func (a *authWrapper) CheckPermissionAudit(ctx context.Context, userID int64) (bool, error) {
+	return a.check(ctx, userID, entity.PermissionAudit)
 }
@@ -0,0 +1,20 @@
+package client
+
+import "time"
+
+// Option - опция клиента сервиса авторизации.
+type Option func(*authWrapper)
+
+// CacheTTL задает время жизни решений о правах в кэше; 0 отключает кэш.
+func CacheTTL(ttl time.Duration) Option {
+	return func(a *authWrapper) {
+		a.cacheTTL = ttl
+	}
+}
+
+// CacheSize задает наибольшее число решений в кэше.
+func CacheSize(size int) Option {
+	return func(a *authWrapper) {
+		a.cacheSize = size
+	}
+}
@@ -0,0 +1,354 @@
+package grpc_server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+type AuditService interface {
+	ListAuditEvents(ctx context.Context, filter entity.AuditFilter, userID int64) ([]entity.AuditEvent, error)
+}
+
+func (s *Service) ListAuditEvents(ctx context.Context, req *migrator.ListAuditEventsRequest) (*migrator.ListAuditEventsResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entity.AuditFilter{
+		ActorID: req.GetActorId(),
+		Limit:   int(req.GetPageSize()),
+	}
+
+	switch {
+	case filter.Limit < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "page_size cannot be negative")
+	case filter.Limit == 0:
+		filter.Limit = defaultAuditPageSize
+	case filter.Limit > maxAuditPageSize:
+		filter.Limit = maxAuditPageSize
+	}
+
+	if filter.From, err = parseAuditTime(req.GetFrom()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "from must be in RFC 3339 format")
+	}
+	if filter.To, err = parseAuditTime(req.GetTo()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "to must be in RFC 3339 format")
+	}
+
+	if token := req.GetPageToken(); token != "" {
+		beforeID, err := strconv.ParseInt(token, 10, 64)
+		if err != nil || beforeID <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token")
+		}
+		filter.BeforeID = beforeID
+	}
+
+	events, err := s.audit.ListAuditEvents(ctx, filter, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	result := make([]*migrator.AuditEvent, len(events))
+	for i, event := range events {
+		result[i] = &migrator.AuditEvent{
+			Id:        event.ID,
+			ActorId:   event.ActorID,
+			Action:    event.Action,
+			Resource:  event.Resource,
+			Ip:        event.IP,
+			UserAgent: event.UserAgent,
+			Outcome:   event.Outcome.String(),
+			Error:     event.Error,
+			CreatedAt: event.CreatedAt.Format(time.DateTime),
+		}
+	}
+
+	var nextPageToken string
+	if len(events) == filter.Limit {
+		nextPageToken = strconv.FormatInt(events[len(events)-1].ID, 10)
+	}
+
+	return &migrator.ListAuditEventsResponse{Events: result, NextPageToken: nextPageToken}, nil
+}
+
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+type AuditRecorder interface {
+	Record(ctx context.Context, event entity.AuditEvent)
+}
+
+// Auditor записывает в журнал аудита каждый вызов методов сервиса миграций
+// пользователем, подтвержденным токеном. Вызовы через gRPC записываются
+// перехватчиками, вызовы через HTTP-шлюз, который обращается к сервису
+// напрямую, - обработчиками шлюза из ServeMuxOptions.
+type Auditor struct {
+	recorder AuditRecorder
+}
+
+func NewAuditor(recorder AuditRecorder) *Auditor {
+	return &Auditor{
+		recorder: recorder,
+	}
+}
+
+// UnaryServerInterceptor записывает унарные вызовы. Должен стоять после
+// перехватчика аутентификации, чтобы в контексте был пользователь.
+func (a *Auditor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !protectedMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+		message, _ := req.(proto.Message)
+		a.record(ctx, info.FullMethod, messageResource(message), grpcRequestMeta(ctx), err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor записывает потоковые вызовы после их завершения.
+// Объекты действия берутся из первого сообщения клиента.
+func (a *Auditor) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !protectedMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		stream := &auditedStream{ServerStream: ss}
+		err := handler(srv, stream)
+		a.record(ss.Context(), info.FullMethod, messageResource(stream.first), grpcRequestMeta(ss.Context()), err)
+		return err
+	}
+}
+
+// ServeMuxOptions подключает аудит к HTTP-шлюзу. Промежуточный обработчик
+// записывает вызов после ответа, а метод и ошибку узнает из обработчиков
+// ответа и ошибок шлюза. Запросы, не дошедшие до метода сервиса, например
+// к несуществующему пути, не записываются.
+func (a *Auditor) ServeMuxOptions() []runtime.ServeMuxOption {
+	return []runtime.ServeMuxOption{
+		runtime.WithMiddlewares(a.gatewayMiddleware),
+		runtime.WithForwardResponseOption(func(ctx context.Context, _ http.ResponseWriter, _ proto.Message) error {
+			if method, ok := runtime.RPCMethod(ctx); ok {
+				setGatewayCall(ctx, method, nil)
+			}
+			return nil
+		}),
+		runtime.WithErrorHandler(func(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+			if method, ok := runtime.RPCMethod(ctx); ok {
+				setGatewayCall(ctx, method, err)
+			}
+			runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+		}),
+	}
+}
+
+func (a *Auditor) gatewayMiddleware(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		call := &gatewayCall{}
+		next(w, r.WithContext(context.WithValue(r.Context(), gatewayCallKey{}, call)), pathParams)
+		if call.method == "" {
+			return
+		}
+
+		a.record(r.Context(), call.method, pathResource(pathParams), httpRequestMeta(r), call.err)
+	}
+}
+
+func (a *Auditor) record(ctx context.Context, fullMethod, resource string, meta requestMeta, err error) {
+	identity, _ := entity.IdentityFromContext(ctx)
+	event := entity.AuditEvent{
+		ActorID:   identity.UserID,
+		Action:    path.Base(fullMethod),
+		Resource:  resource,
+		IP:        meta.ip,
+		UserAgent: meta.userAgent,
+		Outcome:   auditOutcome(err),
+		CreatedAt: time.Now(),
+	}
+	if err != nil {
+		event.Error = status.Convert(err).Message()
+	}
+
+	a.recorder.Record(ctx, event)
+}
+
+// auditOutcome отделяет отказы в доступе от прочих ошибок.
+func auditOutcome(err error) entity.AuditOutcome {
+	switch {
+	case err == nil:
+		return entity.AuditOutcomeSucceeded
+	case errors.Is(err, entity.ErrPermissionDenied), status.Code(err) == codes.PermissionDenied, status.Code(err) == codes.Unauthenticated:
+		return entity.AuditOutcomeDenied
+	default:
+		return entity.AuditOutcomeFailed
+	}
+}
+
+type gatewayCallKey struct{}
+
+// gatewayCall - вызов метода через HTTP-шлюз, который заполняют обработчики шлюза.
+type gatewayCall struct {
+	method string
+	err    error
+}
+
+// setGatewayCall запоминает вызванный через HTTP-шлюз метод и его ошибку
+// для записи в журнал аудита.
+func setGatewayCall(ctx context.Context, method string, err error) {
+	if call, ok := ctx.Value(gatewayCallKey{}).(*gatewayCall); ok {
+		call.method = method
+		call.err = err
+	}
+}
+
+// auditedStream запоминает первое сообщение клиента потокового вызова.
+type auditedStream struct {
+	grpc.ServerStream
+	first proto.Message
+}
+
+func (s *auditedStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.first == nil {
+		s.first, _ = m.(proto.Message)
+	}
+	return err
+}
+
+// messageResource собирает объекты действия из полей запроса вида
+// <объект>_id и <объект>_ids.
+func messageResource(message proto.Message) string {
+	if message == nil {
+		return ""
+	}
+
+	var parts []string
+	message.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if field.Kind() != protoreflect.Int64Kind {
+			return true
+		}
+		name := string(field.Name())
+		if !field.IsList() {
+			parts = appendResource(parts, name, strconv.FormatInt(value.Int(), 10))
+			return true
+		}
+		list := value.List()
+		for i := 0; i < list.Len(); i++ {
+			parts = appendResource(parts, strings.TrimSuffix(name, "s"), strconv.FormatInt(list.Get(i).Int(), 10))
+		}
+		return true
+	})
+	return formatResource(parts)
+}
+
+// pathResource собирает объекты действия из параметров пути HTTP-запроса.
+func pathResource(pathParams map[string]string) string {
+	var parts []string
+	for name, value := range pathParams {
+		parts = appendResource(parts, name, value)
+	}
+	return formatResource(parts)
+}
+
+// appendResource добавляет идентификатор вида migration_id=12 как "migration:12".
+// user_id не относится к объектам: пользователь, выполнивший действие,
+// записывается отдельно.
+func appendResource(parts []string, name, value string) []string {
+	kind, ok := strings.CutSuffix(name, "_id")
+	if !ok || kind == "user" || value == "" || value == "0" {
+		return parts
+	}
+	return append(parts, kind+":"+value)
+}
+
+// formatResource перечисляет объекты действия через запятую по алфавиту.
+func formatResource(parts []string) string {
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// requestMeta - сведения о клиенте для журнала аудита.
+type requestMeta struct {
+	ip        string
+	userAgent string
+}
+
+func grpcRequestMeta(ctx context.Context) requestMeta {
+	meta := requestMeta{
+		ip:        firstForwardedFor(metadataValue(ctx, "x-forwarded-for")),
+		userAgent: metadataValue(ctx, "user-agent"),
+	}
+	if meta.ip == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			meta.ip = hostOnly(p.Addr.String())
+		}
+	}
+	return meta
+}
+
+func httpRequestMeta(r *http.Request) requestMeta {
+	meta := requestMeta{
+		ip:        firstForwardedFor(r.Header.Get("X-Forwarded-For")),
+		userAgent: r.UserAgent(),
+	}
+	if meta.ip == "" {
+		meta.ip = hostOnly(r.RemoteAddr)
+	}
+	return meta
+}
+
+// firstForwardedFor возвращает адрес клиента из X-Forwarded-For - первый в списке.
+func firstForwardedFor(value string) string {
+	ip, _, _ := strings.Cut(value, ",")
+	return strings.TrimSpace(ip)
+}
+
+func hostOnly(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
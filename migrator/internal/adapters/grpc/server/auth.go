@@ -0,0 +1,143 @@
+package grpc_server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+	"migrator/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (entity.Identity, error)
+}
+
+// Authenticator проверяет токен доступа, выданный сервисом авторизации,
+// и кладет подтвержденного пользователя в контекст запроса.
+type Authenticator struct {
+	validator TokenValidator
+}
+
+func NewAuthenticator(validator TokenValidator) *Authenticator {
+	return &Authenticator{
+		validator: validator,
+	}
+}
+
+// UnaryServerInterceptor проверяет токен для унарных методов сервиса миграций.
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !protectedMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := a.authenticate(ctx, authorizationFromMetadata(ctx))
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor проверяет токен для потоковых методов сервиса миграций.
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !protectedMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := a.authenticate(ss.Context(), authorizationFromMetadata(ss.Context()))
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// HTTPMiddleware проверяет токен для запросов HTTP-шлюза, которые вызывают
+// сервис напрямую, минуя перехватчики gRPC.
+func (a *Authenticator) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, err := a.authenticate(r.Context(), r.Header.Get("Authorization"))
+		if err != nil {
+			if status.Code(err) == codes.Unauthenticated {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, status.Convert(err).Message(), http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, "auth service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, authorization string) (context.Context, error) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	token = strings.TrimSpace(token)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "missing bearer token")
+	}
+
+	identity, err := a.validator.ValidateToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnauthenticated) {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+		}
+		logger.Error(fmt.Errorf("validate token: %w", err))
+		return nil, status.Errorf(codes.Unavailable, "failed to validate token")
+	}
+
+	return entity.ContextWithIdentity(ctx, identity), nil
+}
+
+// protectedMethod отделяет методы сервиса миграций от служебных сервисов, например reflection.
+func protectedMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+migrator.MigrationService_ServiceDesc.ServiceName+"/")
+}
+
+func authorizationFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// authenticatedStream подменяет контекст потока контекстом с пользователем.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// userIDFromContext возвращает идентификатор пользователя, подтвержденного токеном.
+// Поле user_id из тела запроса не используется.
+func userIDFromContext(ctx context.Context) (int64, error) {
+	identity, ok := entity.IdentityFromContext(ctx)
+	if !ok {
+		return 0, status.Errorf(codes.Unauthenticated, "request is not authenticated")
+	}
+	return identity.UserID, nil
+}
@@ -0,0 +1,131 @@
+package grpc_server
+
+import (
+	"context"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type BackfillService interface {
+	StartBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error)
+	PauseBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error)
+	ResumeBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error)
+	ThrottleBackfill(ctx context.Context, migrationID int64, batchSize int, pause time.Duration, userID int64) (entity.Backfill, error)
+	GetBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error)
+}
+
+func (s *Service) StartBackfill(ctx context.Context, req *migrator.StartBackfillRequest) (*migrator.BackfillResponse, error) {
+	return s.backfillCall(ctx, req.GetMigrationId(), s.backfills.StartBackfill)
+}
+
+func (s *Service) PauseBackfill(ctx context.Context, req *migrator.PauseBackfillRequest) (*migrator.BackfillResponse, error) {
+	return s.backfillCall(ctx, req.GetMigrationId(), s.backfills.PauseBackfill)
+}
+
+func (s *Service) ResumeBackfill(ctx context.Context, req *migrator.ResumeBackfillRequest) (*migrator.BackfillResponse, error) {
+	return s.backfillCall(ctx, req.GetMigrationId(), s.backfills.ResumeBackfill)
+}
+
+func (s *Service) GetBackfill(ctx context.Context, req *migrator.GetBackfillRequest) (*migrator.BackfillResponse, error) {
+	return s.backfillCall(ctx, req.GetMigrationId(), s.backfills.GetBackfill)
+}
+
+func (s *Service) ThrottleBackfill(ctx context.Context, req *migrator.ThrottleBackfillRequest) (*migrator.BackfillResponse, error) {
+	migrationID := req.GetMigrationId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrationID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
+	}
+	if req.GetBatchSize() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "batch_size cannot be negative")
+	}
+	if req.GetPauseMs() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "pause_ms cannot be negative")
+	}
+
+	pause := time.Duration(req.GetPauseMs()) * time.Millisecond
+	backfill, err := s.backfills.ThrottleBackfill(ctx, migrationID, int(req.GetBatchSize()), pause, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return &migrator.BackfillResponse{Backfill: convertToGrpcBackfill(backfill)}, nil
+}
+
+// backfillCall выполняет запрос к миграции данных, которому нужен только ее идентификатор.
+func (s *Service) backfillCall(
+	ctx context.Context,
+	migrationID int64,
+	call func(ctx context.Context, migrationID, userID int64) (entity.Backfill, error),
+) (*migrator.BackfillResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrationID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
+	}
+
+	backfill, err := call(ctx, migrationID, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return &migrator.BackfillResponse{Backfill: convertToGrpcBackfill(backfill)}, nil
+}
+
+func convertToGrpcBackfill(backfill entity.Backfill) *migrator.BackfillInfo {
+	result := &migrator.BackfillInfo{
+		MigrationId:    backfill.MigrationID,
+		TargetId:       backfill.TargetID,
+		State:          backfill.State.String(),
+		Cursor:         backfill.Cursor,
+		RowsDone:       backfill.RowsDone,
+		EstimatedTotal: backfill.EstimatedTotal,
+		BatchSize:      int32(backfill.BatchSize),
+		PauseMs:        backfill.Pause.Milliseconds(),
+		Error:          backfill.Error,
+		Owner:          backfill.Owner,
+		StartedBy:      backfill.StartedBy,
+		StartedAt:      backfill.StartedAt.Format(time.DateTime),
+		UpdatedAt:      backfill.UpdatedAt.Format(time.DateTime),
+	}
+	if !backfill.FinishedAt.IsZero() {
+		result.FinishedAt = backfill.FinishedAt.Format(time.DateTime)
+	}
+	return result
+}
+
+func convertFromGrpcBackfillSpec(spec *migrator.BackfillSpec) *entity.BackfillSpec {
+	if spec == nil {
+		return nil
+	}
+	return &entity.BackfillSpec{
+		CursorQuery:   spec.GetCursorQuery(),
+		EstimateQuery: spec.GetEstimateQuery(),
+		BatchSize:     int(spec.GetBatchSize()),
+		StartCursor:   spec.GetStartCursor(),
+	}
+}
+
+func convertToGrpcBackfillSpec(spec *entity.BackfillSpec) *migrator.BackfillSpec {
+	if spec == nil {
+		return nil
+	}
+	return &migrator.BackfillSpec{
+		CursorQuery:   spec.CursorQuery,
+		EstimateQuery: spec.EstimateQuery,
+		BatchSize:     int32(spec.BatchSize),
+		StartCursor:   spec.StartCursor,
+	}
+}
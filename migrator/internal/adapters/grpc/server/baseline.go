@@ -0,0 +1,46 @@
+package grpc_server
+
+import (
+	"context"
+	"time"
+
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) BaselineMigrations(ctx context.Context, req *migrator.BaselineMigrationsRequest) (*migrator.BaselineMigrationsResponse, error) {
+	migrationIDs := req.GetMigrationIds()
+	fromID := req.GetFromMigrationId()
+	toID := req.GetToMigrationId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetID := req.GetTargetId()
+
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+	hasRange := fromID != 0 || toID != 0
+	if len(migrationIDs) == 0 && !hasRange {
+		return nil, status.Errorf(codes.InvalidArgument, "either migration_ids or from_migration_id and to_migration_id must be set")
+	}
+	if len(migrationIDs) != 0 && hasRange {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_ids cannot be set together with a range")
+	}
+	if hasRange && (fromID <= 0 || toID < fromID) {
+		return nil, status.Errorf(codes.InvalidArgument, "from_migration_id must be greater than 0 and not greater than to_migration_id")
+	}
+
+	baselined, baselinedAt, err := s.srv.BaselineMigrations(ctx, targetID, migrationIDs, fromID, toID, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return &migrator.BaselineMigrationsResponse{
+		BaselinedIds: baselined,
+		BaselinedAt:  baselinedAt.Format(time.DateTime),
+	}, nil
+}
@@ -0,0 +1,202 @@
+package grpc_server
+
+import (
+	"context"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type EnvironmentService interface {
+	CreateEnvironment(ctx context.Context, environment entity.Environment, userID int64) (int64, error)
+	GetEnvironment(ctx context.Context, environmentID, userID int64) (entity.Environment, error)
+	ListEnvironments(ctx context.Context, userID int64) ([]entity.Environment, error)
+	UpdateEnvironment(ctx context.Context, environment entity.Environment, userID int64) error
+	DeleteEnvironment(ctx context.Context, environmentID, userID int64) error
+	ListMigrationEnvironments(ctx context.Context, filter entity.EnvironmentMigrationFilter, userID int64) ([]entity.MigrationEnvironments, error)
+}
+
+func (s *Service) CreateEnvironment(ctx context.Context, req *migrator.CreateEnvironmentRequest) (*migrator.CreateEnvironmentResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	environment, err := environmentFromRequest(req.GetName(), req.GetDescription(), req.GetPromoteFromId(), req.GetMinSoakHours())
+	if err != nil {
+		return nil, err
+	}
+	environment.Variables = req.GetVariables()
+
+	environmentID, err := s.environments.CreateEnvironment(ctx, environment, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	return &migrator.CreateEnvironmentResponse{EnvironmentId: environmentID}, nil
+}
+
+func (s *Service) GetEnvironment(ctx context.Context, req *migrator.GetEnvironmentRequest) (*migrator.GetEnvironmentResponse, error) {
+	environmentID := req.GetEnvironmentId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if environmentID <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "environment_id must be greater than 0")
+	}
+
+	environment, err := s.environments.GetEnvironment(ctx, environmentID, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	return &migrator.GetEnvironmentResponse{Environment: convertToGrpcEnvironment(environment)}, nil
+}
+
+func (s *Service) ListEnvironments(ctx context.Context, req *migrator.ListEnvironmentsRequest) (*migrator.ListEnvironmentsResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	environments, err := s.environments.ListEnvironments(ctx, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	result := make([]*migrator.EnvironmentInfo, len(environments))
+	for i, environment := range environments {
+		result[i] = convertToGrpcEnvironment(environment)
+	}
+
+	return &migrator.ListEnvironmentsResponse{Environments: result}, nil
+}
+
+func (s *Service) UpdateEnvironment(ctx context.Context, req *migrator.UpdateEnvironmentRequest) (*migrator.UpdateEnvironmentResponse, error) {
+	environmentID := req.GetEnvironmentId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if environmentID <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "environment_id must be greater than 0")
+	}
+	environment, err := environmentFromRequest(req.GetName(), req.GetDescription(), req.GetPromoteFromId(), req.GetMinSoakHours())
+	if err != nil {
+		return nil, err
+	}
+	environment.Variables = req.GetVariables()
+	environment.ID = environmentID
+
+	err = s.environments.UpdateEnvironment(ctx, environment, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	return &migrator.UpdateEnvironmentResponse{}, nil
+}
+
+func (s *Service) DeleteEnvironment(ctx context.Context, req *migrator.DeleteEnvironmentRequest) (*migrator.DeleteEnvironmentResponse, error) {
+	environmentID := req.GetEnvironmentId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if environmentID <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "environment_id must be greater than 0")
+	}
+
+	err = s.environments.DeleteEnvironment(ctx, environmentID, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	return &migrator.DeleteEnvironmentResponse{}, nil
+}
+
+func (s *Service) ListMigrationEnvironments(ctx context.Context, req *migrator.ListMigrationEnvironmentsRequest) (*migrator.ListMigrationEnvironmentsResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entity.EnvironmentMigrationFilter{
+		TargetID:      req.GetTargetId(),
+		Name:          req.GetName(),
+		EnvironmentID: req.GetEnvironmentId(),
+		Status:        entity.MigrationStatus(req.GetStatus()),
+	}
+	if filter.TargetID < 0 || filter.EnvironmentID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id and environment_id cannot be negative")
+	}
+
+	migrations, err := s.environments.ListMigrationEnvironments(ctx, filter, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	result := make([]*migrator.MigrationEnvironments, len(migrations))
+	for i, migration := range migrations {
+		statuses := make([]*migrator.EnvironmentMigrationStatus, len(migration.Statuses))
+		for j, state := range migration.Statuses {
+			statuses[j] = &migrator.EnvironmentMigrationStatus{
+				EnvironmentId:   state.EnvironmentID,
+				EnvironmentName: state.EnvironmentName,
+				TargetId:        state.TargetID,
+				TargetName:      state.TargetName,
+				MigrationId:     state.MigrationID,
+				Status:          state.Status.String(),
+				StatusUpdatedAt: state.StatusUpdatedAt.Format(time.DateTime),
+				Checksum:        state.Checksum,
+			}
+		}
+		result[i] = &migrator.MigrationEnvironments{
+			Name:     migration.Name,
+			Statuses: statuses,
+		}
+	}
+
+	return &migrator.ListMigrationEnvironmentsResponse{Migrations: result}, nil
+}
+
+// environmentFromRequest проверяет поля окружения из запроса создания или изменения.
+func environmentFromRequest(name, description string, promoteFromID int64, minSoakHours int32) (entity.Environment, error) {
+	if name == "" {
+		return entity.Environment{}, status.Errorf(codes.InvalidArgument, "name cannot be empty")
+	}
+	if promoteFromID < 0 {
+		return entity.Environment{}, status.Errorf(codes.InvalidArgument, "promote_from_id cannot be negative")
+	}
+	if minSoakHours < 0 {
+		return entity.Environment{}, status.Errorf(codes.InvalidArgument, "min_soak_hours cannot be negative")
+	}
+
+	return entity.Environment{
+		Name:          name,
+		Description:   description,
+		PromoteFromID: promoteFromID,
+		MinSoak:       time.Duration(minSoakHours) * time.Hour,
+	}, nil
+}
+
+func convertToGrpcEnvironment(environment entity.Environment) *migrator.EnvironmentInfo {
+	return &migrator.EnvironmentInfo{
+		Id:            environment.ID,
+		Name:          environment.Name,
+		Description:   environment.Description,
+		PromoteFromId: environment.PromoteFromID,
+		MinSoakHours:  int32(environment.MinSoak / time.Hour),
+		Variables:     environment.Variables,
+		CreatedBy:     environment.CreatedBy,
+		CreatedAt:     environment.CreatedAt.Format(time.DateTime),
+		UpdatedAt:     environment.UpdatedAt.Format(time.DateTime),
+	}
+}
@@ -0,0 +1,146 @@
+package grpc_server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+	"migrator/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// exportChunkSize - размер части архива в потоке выгрузки.
+const exportChunkSize = 32 * 1024
+
+type ExportService interface {
+	ExportMigrations(ctx context.Context, targetID, userID int64, w io.Writer) error
+}
+
+// chunkWriter отправляет записанные байты сообщениями ExportChunk.
+type chunkWriter struct {
+	stream migrator.MigrationService_ExportMigrationsServer
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&migrator.ExportChunk{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *Service) ExportMigrations(req *migrator.ExportMigrationsRequest, stream migrator.MigrationService_ExportMigrationsServer) error {
+	targetID := req.GetTargetId()
+	userID, err := userIDFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	if targetID < 0 {
+		return status.Errorf(codes.InvalidArgument, "target_id cannot be negative")
+	}
+
+	buf := bufio.NewWriterSize(chunkWriter{stream: stream}, exportChunkSize)
+	if err := s.exporter.ExportMigrations(stream.Context(), targetID, userID, buf); err != nil {
+		return exportError(err)
+	}
+	if err := buf.Flush(); err != nil {
+		return status.Errorf(codes.Unavailable, "send export: %v", err)
+	}
+
+	return nil
+}
+
+// ExportHTTP отдает архив выгрузки через HTTP, так как шлюз не поддерживает потоковые методы.
+func (s *Service) ExportHTTP(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	userID, err := userIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "request is not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	// Выгрузка через HTTP записывается в журнал аудита как вызов ExportMigrations
+	var exportErr error
+	defer func() {
+		setGatewayCall(r.Context(), migrator.MigrationService_ExportMigrations_FullMethodName, exportErr)
+	}()
+
+	var targetID int64
+	if value := r.URL.Query().Get("target_id"); value != "" {
+		var err error
+		targetID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || targetID < 0 {
+			exportErr = status.Errorf(codes.InvalidArgument, "invalid target_id")
+			http.Error(w, "invalid target_id", http.StatusBadRequest)
+			return
+		}
+	}
+
+	name := "migrations.tar.gz"
+	if targetID != 0 {
+		name = fmt.Sprintf("migrations_%d.tar.gz", targetID)
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+
+	// Ответ буферизуется, поэтому ошибка до отправки первой части возвращается обычным статусом
+	out := &countingWriter{w: w}
+	buf := bufio.NewWriterSize(out, exportChunkSize)
+	if err := s.exporter.ExportMigrations(r.Context(), targetID, userID, buf); err != nil {
+		exportErr = exportError(err)
+		if out.n > 0 {
+			// Заголовки уже отправлены, клиент получит оборванный архив
+			logger.Error(fmt.Errorf("export migrations: %w", err))
+			return
+		}
+		writeExportHTTPError(w, err)
+		return
+	}
+	if err := buf.Flush(); err != nil {
+		exportErr = status.Errorf(codes.Unavailable, "send export: %v", err)
+		logger.Error(fmt.Errorf("send export: %w", err))
+	}
+}
+
+// countingWriter считает байты, отправленные клиенту.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func writeExportHTTPError(w http.ResponseWriter, err error) {
+	w.Header().Del("Content-Disposition")
+	w.Header().Del("Content-Type")
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		http.Error(w, "target not found", http.StatusNotFound)
+	case errors.Is(err, entity.ErrPermissionDenied):
+		http.Error(w, "permission denied", http.StatusForbidden)
+	default:
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func exportError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return status.Errorf(codes.NotFound, "target not found")
+	case errors.Is(err, entity.ErrPermissionDenied):
+		return status.Errorf(codes.PermissionDenied, "permission denied")
+	default:
+		return internalError(err)
+	}
+}
@@ -0,0 +1,82 @@
+package grpc_server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 500
+)
+
+func (s *Service) ListMigrationHistory(ctx context.Context, req *migrator.ListMigrationHistoryRequest) (*migrator.ListMigrationHistoryResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entity.HistoryFilter{
+		TargetID:    req.GetTargetId(),
+		MigrationID: req.GetMigrationId(),
+		UserID:      req.GetUserId(),
+		Action:      entity.HistoryAction(req.GetAction()),
+		Outcome:     entity.HistoryOutcome(req.GetOutcome()),
+		Limit:       int(req.GetPageSize()),
+	}
+
+	switch {
+	case filter.Limit < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "page_size cannot be negative")
+	case filter.Limit == 0:
+		filter.Limit = defaultHistoryPageSize
+	case filter.Limit > maxHistoryPageSize:
+		filter.Limit = maxHistoryPageSize
+	}
+
+	if token := req.GetPageToken(); token != "" {
+		beforeID, err := strconv.ParseInt(token, 10, 64)
+		if err != nil || beforeID <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token")
+		}
+		filter.BeforeID = beforeID
+	}
+
+	entries, err := s.srv.ListMigrationHistory(ctx, filter, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	result := make([]*migrator.HistoryEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = &migrator.HistoryEntry{
+			Id:          entry.ID,
+			MigrationId: entry.MigrationID,
+			TargetId:    entry.TargetID,
+			Action:      entry.Action.String(),
+			UserId:      entry.UserID,
+			StartedAt:   entry.StartedAt.Format(time.DateTime),
+			FinishedAt:  entry.FinishedAt.Format(time.DateTime),
+			DurationMs:  entry.Duration.Milliseconds(),
+			Outcome:     entry.Outcome.String(),
+			Error:       entry.Error,
+			Script:      entry.Script,
+			Variables:   entry.Variables,
+			Tenant:      entry.Tenant,
+		}
+	}
+
+	var nextPageToken string
+	if len(entries) == filter.Limit {
+		nextPageToken = strconv.FormatInt(entries[len(entries)-1].ID, 10)
+	}
+
+	return &migrator.ListMigrationHistoryResponse{Entries: result, NextPageToken: nextPageToken}, nil
+}
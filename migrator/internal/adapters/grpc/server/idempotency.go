@@ -0,0 +1,111 @@
+package grpc_server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/textproto"
+	"path"
+
+	"migrator/internal/entity"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// idempotencyKeyMetadata - ключ метаданных gRPC с ключом идемпотентности.
+	idempotencyKeyMetadata = "idempotency-key"
+	// idempotencyReplayedMetadata - заголовок ответа, возвращенного повтору запроса.
+	idempotencyReplayedMetadata = "idempotency-replayed"
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, key entity.IdempotencyKey, requestHash string) ([]byte, bool, error)
+	Complete(ctx context.Context, key entity.IdempotencyKey, requestHash string, response []byte)
+	Release(ctx context.Context, key entity.IdempotencyKey, requestHash string)
+}
+
+// IncomingHeaderMatcher передает HTTP-заголовок Idempotency-Key в метаданные
+// idempotency-key, остальные заголовки - как runtime.DefaultHeaderMatcher.
+func IncomingHeaderMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == "Idempotency-Key" {
+		return idempotencyKeyMetadata, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// idempotent выполняет handler с учетом ключа идемпотентности из метаданных.
+// Без ключа запрос выполняется как обычно. Повтор выполненного запроса с тем же
+// ключом получает сохраненный ответ и заголовок idempotency-replayed, повтор
+// еще выполняемого запроса - ошибку Aborted, другой запрос с тем же ключом -
+// ошибку InvalidArgument. Ответ сохраняется только для успешного запроса:
+// после ошибки запрос с тем же ключом выполняется заново.
+func idempotent[Req, Resp proto.Message](ctx context.Context, store IdempotencyService, method string, req Req, handler func(context.Context, Req) (Resp, error)) (Resp, error) {
+	var zero Resp
+
+	value := metadataValue(ctx, idempotencyKeyMetadata)
+	if value == "" {
+		return handler(ctx, req)
+	}
+	if len(value) > entity.MaxIdempotencyKeyLength {
+		return zero, status.Errorf(codes.InvalidArgument, "idempotency key must not exceed %d characters", entity.MaxIdempotencyKeyLength)
+	}
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return zero, err
+	}
+
+	requestHash, err := hashRequest(req)
+	if err != nil {
+		return zero, internalError(err)
+	}
+	key := entity.IdempotencyKey{
+		UserID: userID,
+		Method: path.Base(method),
+		Key:    value,
+	}
+
+	saved, replayed, err := store.Begin(ctx, key, requestHash)
+	if err != nil {
+		return zero, migrationError(err)
+	}
+	if replayed {
+		resp := zero.ProtoReflect().New().Interface().(Resp)
+		if err := proto.Unmarshal(saved, resp); err != nil {
+			return zero, internalError(err)
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(idempotencyReplayedMetadata, "true"))
+		return resp, nil
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		store.Release(ctx, key, requestHash)
+		return resp, err
+	}
+	response, err := proto.Marshal(resp)
+	if err != nil {
+		// Запрос выполнен, поэтому ответ возвращается, а резерв ключа
+		// освобождается: повтор запроса выполнится заново.
+		store.Release(ctx, key, requestHash)
+		return resp, nil
+	}
+	store.Complete(ctx, key, requestHash, response)
+	return resp, nil
+}
+
+// hashRequest возвращает хэш запроса, по которому повтор запроса отличается
+// от другого запроса с тем же ключом идемпотентности.
+func hashRequest(req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
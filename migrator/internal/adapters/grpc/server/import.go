@@ -0,0 +1,61 @@
+package grpc_server
+
+import (
+	"context"
+	"errors"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ImportService interface {
+	ImportMigrations(ctx context.Context, targetID int64, archive []byte, format entity.ImportFormat, userID int64) ([]entity.ImportItem, error)
+}
+
+func (s *Service) ImportMigrations(ctx context.Context, req *migrator.ImportMigrationsRequest) (*migrator.ImportMigrationsResponse, error) {
+	targetID := req.GetTargetId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	archive := req.GetArchive()
+	format := entity.ImportFormat(req.GetFormat())
+
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+	if len(archive) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "archive cannot be empty")
+	}
+	if format == "" {
+		format = entity.ImportFormatAuto
+	}
+	if !format.Valid() {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown format %q", format)
+	}
+
+	items, err := s.importer.ImportMigrations(ctx, targetID, archive, format, userID)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidImport) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return nil, migrationError(err)
+	}
+
+	result := make([]*migrator.ImportItem, len(items))
+	for i, item := range items {
+		result[i] = &migrator.ImportItem{
+			Version:     item.Version,
+			Name:        item.Name,
+			Source:      item.Source,
+			Status:      item.Status.String(),
+			MigrationId: item.MigrationID,
+			Reason:      item.Reason,
+		}
+	}
+
+	return &migrator.ImportMigrationsResponse{Items: result}, nil
+}
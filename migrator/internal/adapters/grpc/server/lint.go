@@ -0,0 +1,61 @@
+package grpc_server
+
+import (
+	"context"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) LintMigration(ctx context.Context, req *migrator.LintMigrationRequest) (*migrator.LintMigrationResponse, error) {
+	migrationID := req.GetMigrationId()
+	script := req.GetScript()
+	rollbackScript := req.GetRollbackScript()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrationID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_id cannot be negative")
+	}
+	if migrationID == 0 && script == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "either migration_id or script must be set")
+	}
+	if migrationID != 0 && (script != "" || rollbackScript != "") {
+		return nil, status.Errorf(codes.InvalidArgument, "scripts cannot be set together with migration_id")
+	}
+
+	report, err := s.srv.LintMigration(ctx, migrationID, script, rollbackScript, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return convertToGrpcLintReport(report), nil
+}
+
+func convertToGrpcLintReport(report entity.LintReport) *migrator.LintMigrationResponse {
+	return &migrator.LintMigrationResponse{
+		Findings: convertToGrpcLintFindings(report.Findings),
+		Blocked:  report.Blocked,
+	}
+}
+
+func convertToGrpcLintFindings(findings []entity.LintFinding) []*migrator.LintFinding {
+	result := make([]*migrator.LintFinding, 0, len(findings))
+	for _, finding := range findings {
+		result = append(result, &migrator.LintFinding{
+			Rule:      finding.Rule,
+			Severity:  finding.Severity.String(),
+			Message:   finding.Message,
+			Rollback:  finding.Rollback,
+			Line:      int32(finding.Line),
+			Statement: finding.Statement,
+			Blocking:  finding.Blocking,
+		})
+	}
+	return result
+}
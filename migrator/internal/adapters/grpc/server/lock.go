@@ -0,0 +1,61 @@
+package grpc_server
+
+import (
+	"context"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type LockService interface {
+	ListLocks(ctx context.Context, userID int64) ([]entity.Lock, error)
+	ReleaseLock(ctx context.Context, targetID, userID int64) error
+}
+
+func (s *Service) ListLocks(ctx context.Context, req *migrator.ListLocksRequest) (*migrator.ListLocksResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	locks, err := s.locks.ListLocks(ctx, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	result := make([]*migrator.LockInfo, len(locks))
+	for i, lock := range locks {
+		result[i] = &migrator.LockInfo{
+			TargetId:   lock.TargetID,
+			Holder:     lock.Holder,
+			Operation:  lock.Operation,
+			AcquiredAt: lock.AcquiredAt.Format(time.DateTime),
+			ExpiresAt:  lock.ExpiresAt.Format(time.DateTime),
+		}
+	}
+
+	return &migrator.ListLocksResponse{Locks: result}, nil
+}
+
+func (s *Service) ReleaseLock(ctx context.Context, req *migrator.ReleaseLockRequest) (*migrator.ReleaseLockResponse, error) {
+	targetID := req.GetTargetId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+
+	err = s.locks.ReleaseLock(ctx, targetID, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	return &migrator.ReleaseLockResponse{}, nil
+}
@@ -0,0 +1,79 @@
+package grpc_server
+
+import (
+	"context"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) PlanApplyMigration(ctx context.Context, req *migrator.ApplyMigrationRequest) (*migrator.MigrationPlanResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetID := req.GetTargetId()
+
+	migrationIDs, err := s.migrationsToApply(ctx, req, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.srv.PlanApplyMigration(ctx, targetID, migrationIDs, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return convertToGrpcPlan(plan), nil
+}
+
+func (s *Service) PlanRollbackMigration(ctx context.Context, req *migrator.RollbackMigrationRequest) (*migrator.MigrationPlanResponse, error) {
+	migrationID := req.GetMigrationId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetID := req.GetTargetId()
+
+	if migrationID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
+	}
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+
+	plan, err := s.srv.PlanRollbackMigration(ctx, targetID, migrationID, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return convertToGrpcPlan(plan), nil
+}
+
+func convertToGrpcPlan(plan entity.Plan) *migrator.MigrationPlanResponse {
+	items := make([]*migrator.MigrationPlanItem, len(plan.Items))
+	for i, item := range plan.Items {
+		items[i] = &migrator.MigrationPlanItem{
+			MigrationId:  item.MigrationID,
+			Name:         item.Name,
+			Order:        int32(item.Order),
+			Status:       item.Status.String(),
+			Rejected:     item.Rejected,
+			RejectReason: item.RejectReason,
+			Success:      item.Success,
+			Error:        item.Error,
+			DurationMs:   item.Duration.Milliseconds(),
+			RowsAffected: item.RowsAffected,
+		}
+	}
+
+	return &migrator.MigrationPlanResponse{
+		Action:   plan.Action.String(),
+		TargetId: plan.TargetID,
+		Success:  plan.Success(),
+		Items:    items,
+	}
+}
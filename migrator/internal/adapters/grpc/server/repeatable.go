@@ -0,0 +1,72 @@
+package grpc_server
+
+import (
+	"context"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) UpdateRepeatableMigration(ctx context.Context, req *migrator.UpdateRepeatableMigrationRequest) (*migrator.UpdateRepeatableMigrationResponse, error) {
+	migrationID := req.GetMigrationId()
+	script := req.GetScript()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrationID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
+	}
+	if script == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "script cannot be empty")
+	}
+
+	pending, findings, err := s.srv.UpdateRepeatableMigration(ctx, migrationID, script, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return &migrator.UpdateRepeatableMigrationResponse{
+		Pending:      pending,
+		LintFindings: convertToGrpcLintFindings(findings),
+	}, nil
+}
+
+func (s *Service) ListMigrationRevisions(ctx context.Context, req *migrator.ListMigrationRevisionsRequest) (*migrator.ListMigrationRevisionsResponse, error) {
+	migrationID := req.GetMigrationId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrationID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
+	}
+
+	revisions, err := s.srv.ListMigrationRevisions(ctx, migrationID, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	result := make([]*migrator.MigrationRevision, len(revisions))
+	for i, revision := range revisions {
+		result[i] = convertToGrpcRevision(revision)
+	}
+
+	return &migrator.ListMigrationRevisionsResponse{Revisions: result}, nil
+}
+
+func convertToGrpcRevision(revision entity.MigrationRevision) *migrator.MigrationRevision {
+	return &migrator.MigrationRevision{
+		Revision:  revision.Revision,
+		Checksum:  revision.Checksum,
+		Script:    revision.Script,
+		AppliedBy: revision.AppliedBy,
+		AppliedAt: revision.AppliedAt.Format(time.DateTime),
+	}
+}
@@ -0,0 +1,79 @@
+package grpc_server
+
+import (
+	"context"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) GetDatabaseStatus(ctx context.Context, req *migrator.GetDatabaseStatusRequest) (*migrator.GetDatabaseStatusResponse, error) {
+	targetID := req.GetTargetId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+
+	databaseStatus, err := s.srv.GetDatabaseStatus(ctx, targetID, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	objects := make([]*migrator.SchemaObject, len(databaseStatus.Objects))
+	for i, object := range databaseStatus.Objects {
+		objects[i] = convertToGrpcSchemaObject(object)
+	}
+
+	drift := make([]*migrator.SchemaDrift, len(databaseStatus.Drift))
+	for i, d := range databaseStatus.Drift {
+		drift[i] = convertToGrpcSchemaDrift(d)
+	}
+
+	response := &migrator.GetDatabaseStatusResponse{
+		TargetId: databaseStatus.TargetID,
+		Objects:  objects,
+		Drift:    drift,
+		Drifted:  len(drift) > 0,
+	}
+	if databaseStatus.Snapshot != nil {
+		response.Snapshot = convertToGrpcSchemaSnapshot(*databaseStatus.Snapshot)
+	}
+
+	return response, nil
+}
+
+func convertToGrpcSchemaObject(object entity.SchemaObject) *migrator.SchemaObject {
+	return &migrator.SchemaObject{
+		Kind:       object.Kind.String(),
+		Name:       object.Name,
+		Definition: object.Definition,
+	}
+}
+
+func convertToGrpcSchemaSnapshot(snapshot entity.SchemaSnapshot) *migrator.SchemaSnapshotInfo {
+	return &migrator.SchemaSnapshotInfo{
+		Id:           snapshot.ID,
+		Action:       snapshot.Action.String(),
+		UserId:       snapshot.UserID,
+		TakenAt:      snapshot.TakenAt.Format(time.DateTime),
+		ObjectsCount: int64(len(snapshot.Objects)),
+	}
+}
+
+func convertToGrpcSchemaDrift(drift entity.SchemaDrift) *migrator.SchemaDrift {
+	return &migrator.SchemaDrift{
+		Kind:     drift.Kind.String(),
+		Name:     drift.Name,
+		Change:   drift.Change.String(),
+		Recorded: drift.Recorded,
+		Live:     drift.Live,
+	}
+}
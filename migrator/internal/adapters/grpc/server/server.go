@@ -3,9 +3,12 @@ package grpc_server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"migrator/pkg/api/migrator"
+	"migrator/pkg/logger"
 
 	"migrator/internal/entity"
 
@@ -14,50 +17,136 @@ import (
 )
 
 type MigrationService interface {
-	CreateMigration(ctx context.Context, name, description, script, rollbackScript string, userID int64) (int64, error)
-	ApplyMigration(ctx context.Context, migrationIDs []int64, userID int64) (time.Time, error)
-	RollbackMigration(ctx context.Context, migrationID, userID int64) (time.Time, error)
-	ListMigrations(ctx context.Context, statusFilter string) ([]entity.MigrationInfo, error)
-	GetMigration(ctx context.Context, migrationID int64) (entity.MigrationInfo, error)
+	CreateMigration(ctx context.Context, targetID int64, name, description, script, rollbackScript string, executionMode entity.ExecutionMode, kind entity.MigrationKind, labels map[string]string, tenantScoped, templated bool, backfill *entity.BackfillSpec, userID int64) (int64, []entity.LintFinding, error)
+	LintMigration(ctx context.Context, migrationID int64, script, rollbackScript string, userID int64) (entity.LintReport, error)
+	ApplyMigration(ctx context.Context, targetID int64, migrationIDs []int64, userID int64) ([]int64, time.Time, error)
+	RollbackMigration(ctx context.Context, targetID, migrationID, userID int64) (time.Time, error)
+	RollbackToMigration(ctx context.Context, targetID, migrationID, userID int64) ([]int64, time.Time, error)
+	ListMigrations(ctx context.Context, targetID int64, statusFilter string, selector entity.LabelSelector, userID int64) ([]entity.MigrationInfo, error)
+	SelectPendingMigrations(ctx context.Context, targetID int64, selector entity.LabelSelector, userID int64) ([]int64, error)
+	GetMigration(ctx context.Context, migrationID, userID int64) (entity.MigrationInfo, error)
+	PlanApplyMigration(ctx context.Context, targetID int64, migrationIDs []int64, userID int64) (entity.Plan, error)
+	PlanRollbackMigration(ctx context.Context, targetID, migrationID, userID int64) (entity.Plan, error)
+	VerifyMigrations(ctx context.Context, targetID, userID int64) ([]entity.ChecksumViolation, error)
+	ListMigrationHistory(ctx context.Context, filter entity.HistoryFilter, userID int64) ([]entity.HistoryEntry, error)
+	BaselineMigrations(ctx context.Context, targetID int64, migrationIDs []int64, fromID, toID, userID int64) ([]int64, time.Time, error)
+	ApplyTenantMigration(ctx context.Context, targetID, migrationID int64, tenants []string, concurrency int, userID int64) (entity.TenantApplyResult, error)
+	ListTenantStatuses(ctx context.Context, migrationID, userID int64) ([]entity.TenantStatus, error)
+	UpdateRepeatableMigration(ctx context.Context, migrationID int64, script string, userID int64) (bool, []entity.LintFinding, error)
+	ListMigrationRevisions(ctx context.Context, migrationID, userID int64) ([]entity.MigrationRevision, error)
+	GetDatabaseStatus(ctx context.Context, targetID, userID int64) (entity.DatabaseStatus, error)
 }
 
 type Service struct {
 	migrator.UnimplementedMigrationServiceServer
-	srv MigrationService
+	srv          MigrationService
+	targets      TargetService
+	environments EnvironmentService
+	locks        LockService
+	backfills    BackfillService
+	audit        AuditService
+	idempotency  IdempotencyService
+	importer     ImportService
+	exporter     ExportService
 }
 
-func NewMigration(srv MigrationService) *Service {
+func NewMigration(srv MigrationService, targets TargetService, environments EnvironmentService, locks LockService, backfills BackfillService, audit AuditService, idempotency IdempotencyService, importer ImportService, exporter ExportService) *Service {
 	return &Service{
-		srv: srv,
+		srv:          srv,
+		targets:      targets,
+		environments: environments,
+		locks:        locks,
+		backfills:    backfills,
+		audit:        audit,
+		idempotency:  idempotency,
+		importer:     importer,
+		exporter:     exporter,
 	}
 }
 
 func (s *Service) ApplyMigration(ctx context.Context, req *migrator.ApplyMigrationRequest) (*migrator.ApplyMigrationResponse, error) {
+	return idempotent(ctx, s.idempotency, migrator.MigrationService_ApplyMigration_FullMethodName, req, s.applyMigration)
+}
+
+func (s *Service) applyMigration(ctx context.Context, req *migrator.ApplyMigrationRequest) (*migrator.ApplyMigrationResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetID := req.GetTargetId()
+
+	migrationIDs, err := s.migrationsToApply(ctx, req, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, appliedAt, err := s.srv.ApplyMigration(ctx, targetID, migrationIDs, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return &migrator.ApplyMigrationResponse{
+		AppliedAt:    appliedAt.Format(time.DateTime),
+		MigrationIds: applied,
+	}, nil
+}
+
+// migrationsToApply возвращает идентификаторы миграций из запроса на применение:
+// либо перечисленные в migration_ids, либо ожидающие применения миграции, подходящие под selector.
+func (s *Service) migrationsToApply(ctx context.Context, req *migrator.ApplyMigrationRequest, userID int64) ([]int64, error) {
 	migrationIDs := req.GetMigrationIds()
-	userID := req.GetUserId()
+	targetID := req.GetTargetId()
 
 	// Проверяем правильность идентификаторов
-	if len(migrationIDs) == 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "migration_ids must be greater than 0")
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
 	}
-	if userID == 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "user_id must be greater than 0")
+	if req.GetSelector() == "" {
+		if len(migrationIDs) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "migration_ids must be greater than 0")
+		}
+		return migrationIDs, nil
+	}
+	if len(migrationIDs) != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_ids and selector are mutually exclusive")
 	}
 
-	appliedAt, err := s.srv.ApplyMigration(ctx, migrationIDs, userID)
+	selector, err := entity.ParseLabelSelector(req.GetSelector())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if len(selector) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "selector cannot be empty")
 	}
 
-	return &migrator.ApplyMigrationResponse{AppliedAt: appliedAt.Format(time.DateTime)}, nil
+	migrationIDs, err = s.srv.SelectPendingMigrations(ctx, targetID, selector, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return migrationIDs, nil
 }
 
 func (s *Service) CreateMigration(ctx context.Context, req *migrator.CreateMigrationRequest) (*migrator.CreateMigrationResponse, error) {
+	return idempotent(ctx, s.idempotency, migrator.MigrationService_CreateMigration_FullMethodName, req, s.createMigration)
+}
+
+func (s *Service) createMigration(ctx context.Context, req *migrator.CreateMigrationRequest) (*migrator.CreateMigrationResponse, error) {
 	name := req.GetName()
 	description := req.GetDescription()
 	script := req.GetScript()
 	rollbackScript := req.GetRollbackScript()
-	userID := req.GetUserId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetID := req.GetTargetId()
+	executionMode := entity.ExecutionMode(req.GetExecutionMode())
+	kind := entity.MigrationKind(req.GetKind())
+	labels := req.GetLabels()
+	tenantScoped := req.GetTenantScoped()
+	templated := req.GetTemplated()
+	backfill := convertFromGrpcBackfillSpec(req.GetBackfill())
 
 	if name == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "name cannot be empty")
@@ -65,28 +154,49 @@ func (s *Service) CreateMigration(ctx context.Context, req *migrator.CreateMigra
 	if script == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "script cannot be empty")
 	}
-	if userID == 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "user_id must be greater than 0")
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+	if executionMode != "" && !executionMode.Valid() {
+		return nil, status.Errorf(codes.InvalidArgument, "execution_mode must be %q or %q",
+			entity.ExecutionModeTransactional, entity.ExecutionModeNoTransaction)
+	}
+	if kind != "" && !kind.Valid() {
+		return nil, status.Errorf(codes.InvalidArgument, "kind must be %q, %q or %q",
+			entity.MigrationKindVersioned, entity.MigrationKindRepeatable, entity.MigrationKindBackfill)
+	}
+	if err := entity.ValidateLabels(labels); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if tenantScoped && executionMode == entity.ExecutionModeNoTransaction {
+		return nil, status.Errorf(codes.InvalidArgument, "tenant-scoped migration must run in a transaction")
 	}
 
-	migrationID, err := s.srv.CreateMigration(ctx, name, description, script, rollbackScript, userID)
+	migrationID, findings, err := s.srv.CreateMigration(ctx, targetID, name, description, script, rollbackScript, executionMode, kind, labels, tenantScoped, templated, backfill, userID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		return nil, migrationError(err)
 	}
 
-	return &migrator.CreateMigrationResponse{MigrationId: migrationID}, nil
+	return &migrator.CreateMigrationResponse{
+		MigrationId:  migrationID,
+		LintFindings: convertToGrpcLintFindings(findings),
+	}, nil
 }
 
 func (s *Service) GetMigration(ctx context.Context, req *migrator.GetMigrationRequest) (*migrator.GetMigrationResponse, error) {
 	migrationID := req.GetMigrationId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	if migrationID == 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
 	}
 
-	migration, err := s.srv.GetMigration(ctx, migrationID)
+	migration, err := s.srv.GetMigration(ctx, migrationID, userID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		return nil, migrationError(err)
 	}
 
 	return &migrator.GetMigrationResponse{Migration: convertToGrpcMigration(migration)}, nil
@@ -94,10 +204,23 @@ func (s *Service) GetMigration(ctx context.Context, req *migrator.GetMigrationRe
 
 func (s *Service) ListMigrations(ctx context.Context, req *migrator.ListMigrationsRequest) (*migrator.ListMigrationsResponse, error) {
 	statusFilter := req.GetStatus()
+	targetID := req.GetTargetId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+	selector, err := entity.ParseLabelSelector(req.GetSelector())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
-	migrations, err := s.srv.ListMigrations(ctx, statusFilter)
+	migrations, err := s.srv.ListMigrations(ctx, targetID, statusFilter, selector, userID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		return nil, migrationError(err)
 	}
 
 	result := convertToGrpcMigrations(migrations)
@@ -116,6 +239,7 @@ func convertToGrpcMigrations(migrations []entity.MigrationInfo) []*migrator.Migr
 func convertToGrpcMigration(migration entity.MigrationInfo) *migrator.MigrationInfo {
 	return &migrator.MigrationInfo{
 		Id:              migration.ID,
+		TargetId:        migration.TargetID,
 		Name:            migration.Name,
 		Description:     migration.Description,
 		Script:          migration.Script,
@@ -123,24 +247,159 @@ func convertToGrpcMigration(migration entity.MigrationInfo) *migrator.MigrationI
 		Status:          migration.Status.String(),
 		CreatedBy:       migration.CreatedBy,
 		StatusUpdatedAt: migration.StatusUpdatedAt.Format(time.DateTime),
+		Checksum:        migration.Checksum,
+		AppliedChecksum: migration.AppliedChecksum,
+		ExecutionMode:   migration.ExecutionMode.String(),
+		LastError:       convertToGrpcScriptError(migration.LastError),
+		Baselined:       migration.Baselined,
+		BaselinedBy:     migration.BaselinedBy,
+		Labels:          migration.Labels,
+		TenantScoped:    migration.TenantScoped,
+		Templated:       migration.Templated,
+		Kind:            migration.Kind.String(),
+		Backfill:        convertToGrpcBackfillSpec(migration.Backfill),
+	}
+}
+
+func convertToGrpcScriptError(scriptErr *entity.ScriptError) *migrator.ScriptError {
+	if scriptErr == nil {
+		return nil
+	}
+	return &migrator.ScriptError{
+		Sqlstate:  scriptErr.SQLState,
+		Message:   scriptErr.Message,
+		Detail:    scriptErr.Detail,
+		Hint:      scriptErr.Hint,
+		Position:  scriptErr.Position,
+		Line:      int32(scriptErr.Line),
+		Statement: scriptErr.Statement,
 	}
 }
 
+func (s *Service) VerifyMigrations(ctx context.Context, req *migrator.VerifyMigrationsRequest) (*migrator.VerifyMigrationsResponse, error) {
+	targetID := req.GetTargetId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+
+	violations, err := s.srv.VerifyMigrations(ctx, targetID, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	result := make([]*migrator.ChecksumViolation, len(violations))
+	for i, violation := range violations {
+		result[i] = &migrator.ChecksumViolation{
+			MigrationId:     violation.MigrationID,
+			Name:            violation.Name,
+			Status:          violation.Status.String(),
+			Checksum:        violation.Checksum,
+			AppliedChecksum: violation.AppliedChecksum,
+			ActualChecksum:  violation.ActualChecksum,
+			Reason:          violation.Reason,
+		}
+	}
+
+	return &migrator.VerifyMigrationsResponse{Violations: result}, nil
+}
+
 func (s *Service) RollbackMigration(ctx context.Context, req *migrator.RollbackMigrationRequest) (*migrator.RollbackMigrationResponse, error) {
 	migrationID := req.GetMigrationId()
-	userID := req.GetUserId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetID := req.GetTargetId()
 
 	if migrationID == 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
 	}
-	if userID == 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "user_id must be greater than 0")
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
 	}
 
-	rolledBackAt, err := s.srv.RollbackMigration(ctx, migrationID, userID)
+	rolledBackAt, err := s.srv.RollbackMigration(ctx, targetID, migrationID, userID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		return nil, migrationError(err)
 	}
 
 	return &migrator.RollbackMigrationResponse{RolledBackAt: rolledBackAt.Format(time.DateTime)}, nil
 }
+
+func (s *Service) RollbackToMigration(ctx context.Context, req *migrator.RollbackToMigrationRequest) (*migrator.RollbackToMigrationResponse, error) {
+	migrationID := req.GetMigrationId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetID := req.GetTargetId()
+
+	if migrationID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
+	}
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+
+	rolledBack, rolledBackAt, err := s.srv.RollbackToMigration(ctx, targetID, migrationID, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	return &migrator.RollbackToMigrationResponse{
+		RolledBackIds: rolledBack,
+		RolledBackAt:  rolledBackAt.Format(time.DateTime),
+	}, nil
+}
+
+func migrationError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrLocked), errors.Is(err, entity.ErrIdempotencyInProgress):
+		return status.Errorf(codes.Aborted, "%v", err)
+	case errors.Is(err, entity.ErrPermissionDenied):
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	case errors.Is(err, entity.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, entity.ErrAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	case errors.Is(err, entity.ErrPromotionBlocked), errors.Is(err, entity.ErrTemplate), errors.Is(err, entity.ErrBackfillActive),
+		errors.Is(err, entity.ErrChecksumMismatch), errors.Is(err, entity.ErrNotLastMigration), errors.Is(err, entity.ErrFailedPrecondition):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	case errors.Is(err, entity.ErrInvalidArgument):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	// Замечания линтера передаются в деталях, чтобы клиент мог показать их все.
+	var lintErr *entity.LintError
+	if errors.As(err, &lintErr) {
+		st, detailsErr := status.New(codes.FailedPrecondition, err.Error()).
+			WithDetails(convertToGrpcLintReport(lintErr.Report))
+		if detailsErr == nil {
+			return st.Err()
+		}
+	}
+
+	// Ошибка скрипта передается в деталях, чтобы клиент мог указать на строку скрипта.
+	var scriptErr *entity.ScriptError
+	if errors.As(err, &scriptErr) {
+		st, detailsErr := status.New(codes.FailedPrecondition, err.Error()).
+			WithDetails(convertToGrpcScriptError(scriptErr))
+		if detailsErr == nil {
+			return st.Err()
+		}
+	}
+
+	return internalError(err)
+}
+
+// internalError логирует ошибку, причина которой не относится к запросу, и возвращает
+// клиенту код Internal без ее текста: он может раскрыть устройство сервиса и баз данных.
+func internalError(err error) error {
+	logger.Error(fmt.Errorf("internal error: %w", err))
+	return status.Error(codes.Internal, "internal error")
+}
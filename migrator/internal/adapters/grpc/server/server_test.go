@@ -0,0 +1,77 @@
+package grpc_server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"migrator/internal/entity"
+)
+
+func TestMigrationError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{
+			name: "invalid argument",
+			err:  fmt.Errorf("%w: migration 3 does not belong to target 1", entity.ErrInvalidArgument),
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "failed precondition",
+			err:  fmt.Errorf("m.repo.DoInTransaction: %w", fmt.Errorf("%w: migration 3 is not applied", entity.ErrFailedPrecondition)),
+			want: codes.FailedPrecondition,
+		},
+		{
+			name: "not last migration",
+			err:  fmt.Errorf("%w: migration 2", entity.ErrNotLastMigration),
+			want: codes.FailedPrecondition,
+		},
+		{
+			name: "locked",
+			err:  fmt.Errorf("m.locker.Lock: %w", entity.ErrLocked),
+			want: codes.Aborted,
+		},
+		{
+			name: "not found",
+			err:  fmt.Errorf("m.repo.Get: %w", entity.ErrNotFound),
+			want: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(migrationError(tt.err))
+			if st.Code() != tt.want {
+				t.Fatalf("code = %v, want %v", st.Code(), tt.want)
+			}
+			if st.Message() != tt.err.Error() {
+				t.Errorf("message = %q, want %q", st.Message(), tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestInternalErrorHidesDetails(t *testing.T) {
+	err := errors.New(`m.repo.Get: ERROR: relation "migrations" does not exist (SQLSTATE 42P01)`)
+
+	for name, mapped := range map[string]error{
+		"migrationError": migrationError(err),
+		"targetError":    targetError(err),
+		"exportError":    exportError(err),
+	} {
+		st := status.Convert(mapped)
+		if st.Code() != codes.Internal {
+			t.Errorf("%s: code = %v, want Internal", name, st.Code())
+		}
+		if strings.Contains(st.Message(), "relation") {
+			t.Errorf("%s: message %q leaks the error text", name, st.Message())
+		}
+	}
+}
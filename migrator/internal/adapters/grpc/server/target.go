@@ -0,0 +1,184 @@
+package grpc_server
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type TargetService interface {
+	CreateTarget(ctx context.Context, name, description, url string, environmentID int64, variables map[string]string, userID int64) (int64, error)
+	GetTarget(ctx context.Context, targetID, userID int64) (entity.Target, error)
+	ListTargets(ctx context.Context, userID int64) ([]entity.Target, error)
+	UpdateTarget(ctx context.Context, targetID int64, name, description, url string, environmentID int64, variables map[string]string, userID int64) error
+	DeleteTarget(ctx context.Context, targetID, userID int64) error
+}
+
+func (s *Service) CreateTarget(ctx context.Context, req *migrator.CreateTargetRequest) (*migrator.CreateTargetResponse, error) {
+	name := req.GetName()
+	description := req.GetDescription()
+	targetURL := req.GetUrl()
+	environmentID := req.GetEnvironmentId()
+	variables := req.GetVariables()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name cannot be empty")
+	}
+	if targetURL == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "url cannot be empty")
+	}
+	if environmentID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "environment_id cannot be negative")
+	}
+
+	targetID, err := s.targets.CreateTarget(ctx, name, description, targetURL, environmentID, variables, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	return &migrator.CreateTargetResponse{TargetId: targetID}, nil
+}
+
+func (s *Service) GetTarget(ctx context.Context, req *migrator.GetTargetRequest) (*migrator.GetTargetResponse, error) {
+	targetID := req.GetTargetId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+
+	target, err := s.targets.GetTarget(ctx, targetID, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	return &migrator.GetTargetResponse{Target: convertToGrpcTarget(target)}, nil
+}
+
+func (s *Service) ListTargets(ctx context.Context, req *migrator.ListTargetsRequest) (*migrator.ListTargetsResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := s.targets.ListTargets(ctx, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	result := make([]*migrator.TargetInfo, len(targets))
+	for i, target := range targets {
+		result[i] = convertToGrpcTarget(target)
+	}
+
+	return &migrator.ListTargetsResponse{Targets: result}, nil
+}
+
+func (s *Service) UpdateTarget(ctx context.Context, req *migrator.UpdateTargetRequest) (*migrator.UpdateTargetResponse, error) {
+	targetID := req.GetTargetId()
+	name := req.GetName()
+	description := req.GetDescription()
+	targetURL := req.GetUrl()
+	environmentID := req.GetEnvironmentId()
+	variables := req.GetVariables()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name cannot be empty")
+	}
+	if targetURL == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "url cannot be empty")
+	}
+	if environmentID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "environment_id cannot be negative")
+	}
+
+	err = s.targets.UpdateTarget(ctx, targetID, name, description, targetURL, environmentID, variables, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	return &migrator.UpdateTargetResponse{}, nil
+}
+
+func (s *Service) DeleteTarget(ctx context.Context, req *migrator.DeleteTargetRequest) (*migrator.DeleteTargetResponse, error) {
+	targetID := req.GetTargetId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+
+	err = s.targets.DeleteTarget(ctx, targetID, userID)
+	if err != nil {
+		return nil, targetError(err)
+	}
+
+	return &migrator.DeleteTargetResponse{}, nil
+}
+
+func targetError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, entity.ErrAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	case errors.Is(err, entity.ErrTargetInUse), errors.Is(err, entity.ErrEnvironmentInUse):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	case errors.Is(err, entity.ErrPermissionDenied):
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	case errors.Is(err, entity.ErrInvalidArgument):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return internalError(err)
+}
+
+func convertToGrpcTarget(target entity.Target) *migrator.TargetInfo {
+	return &migrator.TargetInfo{
+		Id:            target.ID,
+		Name:          target.Name,
+		Description:   target.Description,
+		Url:           redactURL(target.URL),
+		EnvironmentId: target.EnvironmentID,
+		Variables:     target.Variables,
+		CreatedBy:     target.CreatedBy,
+		CreatedAt:     target.CreatedAt.Format(time.DateTime),
+		UpdatedAt:     target.UpdatedAt.Format(time.DateTime),
+	}
+}
+
+var passwordParam = regexp.MustCompile(`(password=)([^&\s]+)`)
+
+// redactURL скрывает пароль в строке подключения как в форме URL,
+// так и в форме "ключ=значение".
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err == nil && u.User != nil {
+		rawURL = u.Redacted()
+	}
+	return passwordParam.ReplaceAllString(rawURL, "${1}xxxxx")
+}
@@ -0,0 +1,92 @@
+package grpc_server
+
+import (
+	"context"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/api/migrator"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) ApplyTenantMigration(ctx context.Context, req *migrator.ApplyTenantMigrationRequest) (*migrator.ApplyTenantMigrationResponse, error) {
+	migrationID := req.GetMigrationId()
+	targetID := req.GetTargetId()
+	tenants := req.GetTenants()
+	concurrency := req.GetConcurrency()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrationID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
+	}
+	if targetID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_id must be greater than 0")
+	}
+	if concurrency < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "concurrency cannot be negative")
+	}
+	for _, tenant := range tenants {
+		if tenant == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "tenants cannot contain empty names")
+		}
+	}
+
+	result, err := s.srv.ApplyTenantMigration(ctx, targetID, migrationID, tenants, int(concurrency), userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	failed := make([]*migrator.TenantStatus, len(result.Failed))
+	for i, tenantStatus := range result.Failed {
+		failed[i] = convertToGrpcTenantStatus(tenantStatus)
+	}
+
+	return &migrator.ApplyTenantMigrationResponse{
+		Applied:    result.Applied,
+		Skipped:    result.Skipped,
+		Failed:     failed,
+		FinishedAt: result.FinishedAt.Format(time.DateTime),
+	}, nil
+}
+
+func (s *Service) ListTenantStatuses(ctx context.Context, req *migrator.ListTenantStatusesRequest) (*migrator.ListTenantStatusesResponse, error) {
+	migrationID := req.GetMigrationId()
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrationID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "migration_id must be greater than 0")
+	}
+
+	statuses, err := s.srv.ListTenantStatuses(ctx, migrationID, userID)
+	if err != nil {
+		return nil, migrationError(err)
+	}
+
+	result := make([]*migrator.TenantStatus, len(statuses))
+	for i, tenantStatus := range statuses {
+		result[i] = convertToGrpcTenantStatus(tenantStatus)
+	}
+
+	return &migrator.ListTenantStatusesResponse{Tenants: result}, nil
+}
+
+func convertToGrpcTenantStatus(tenantStatus entity.TenantStatus) *migrator.TenantStatus {
+	var updatedAt string
+	if !tenantStatus.UpdatedAt.IsZero() {
+		updatedAt = tenantStatus.UpdatedAt.Format(time.DateTime)
+	}
+	return &migrator.TenantStatus{
+		Tenant:    tenantStatus.Tenant,
+		Status:    tenantStatus.Status.String(),
+		Error:     tenantStatus.Error,
+		UpdatedAt: updatedAt,
+	}
+}
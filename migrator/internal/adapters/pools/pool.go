@@ -0,0 +1,144 @@
+package pools
+
+import (
+	"context"
+	"sync"
+
+	"migrator/internal/adapters/targetdb"
+	"migrator/internal/entity"
+)
+
+// retirer - пул, который можно вывести из обращения.
+type retirer interface {
+	retire()
+}
+
+// pool считает транзакции и подключения, начатые на пуле целевой базы данных,
+// чтобы пул, выведенный из обращения, закрывался только после них.
+type pool struct {
+	targetdb.DB
+
+	mu      sync.Mutex
+	users   int
+	retired bool
+}
+
+// tenantPool - пул целевой базы данных со схемами арендаторов.
+type tenantPool struct {
+	*pool
+	tenants targetdb.TenantDB
+}
+
+func wrap(db targetdb.DB) targetdb.DB {
+	p := &pool{DB: db}
+	if tenants, ok := db.(targetdb.TenantDB); ok {
+		return &tenantPool{pool: p, tenants: tenants}
+	}
+	return p
+}
+
+func (p *pool) acquire() {
+	p.mu.Lock()
+	p.users++
+	p.mu.Unlock()
+}
+
+func (p *pool) release() {
+	p.mu.Lock()
+	p.users--
+	closing := p.retired && p.users == 0
+	p.mu.Unlock()
+
+	if closing {
+		p.DB.Close()
+	}
+}
+
+func (p *pool) retire() {
+	p.mu.Lock()
+	if p.retired {
+		p.mu.Unlock()
+		return
+	}
+	p.retired = true
+	closing := p.users == 0
+	p.mu.Unlock()
+
+	if closing {
+		p.DB.Close()
+	}
+}
+
+// Close выводит пул из обращения; закрывает его менеджер пулов.
+func (p *pool) Close() {
+	p.retire()
+}
+
+func (p *pool) Begin(ctx context.Context) (targetdb.Tx, error) {
+	p.acquire()
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		p.release()
+		return nil, err
+	}
+	return &poolTx{Tx: tx, release: sync.OnceFunc(p.release)}, nil
+}
+
+func (p *pool) Conn(ctx context.Context) (targetdb.Conn, error) {
+	p.acquire()
+	conn, err := p.DB.Conn(ctx)
+	if err != nil {
+		p.release()
+		return nil, err
+	}
+	return &poolConn{Conn: conn, release: sync.OnceFunc(p.release)}, nil
+}
+
+func (p *pool) Schema(ctx context.Context) ([]entity.SchemaObject, error) {
+	p.acquire()
+	defer p.release()
+	return p.DB.Schema(ctx)
+}
+
+func (p *tenantPool) Tenants(ctx context.Context, query string) ([]string, error) {
+	p.acquire()
+	defer p.release()
+	return p.tenants.Tenants(ctx, query)
+}
+
+func (p *tenantPool) BeginTenant(ctx context.Context, tenant string) (targetdb.Tx, error) {
+	p.acquire()
+	tx, err := p.tenants.BeginTenant(ctx, tenant)
+	if err != nil {
+		p.release()
+		return nil, err
+	}
+	return &poolTx{Tx: tx, release: sync.OnceFunc(p.release)}, nil
+}
+
+// poolTx освобождает пул после завершения транзакции.
+type poolTx struct {
+	targetdb.Tx
+	release func()
+}
+
+func (t *poolTx) Commit(ctx context.Context) error {
+	defer t.release()
+	return t.Tx.Commit(ctx)
+}
+
+func (t *poolTx) Rollback(ctx context.Context) error {
+	defer t.release()
+	return t.Tx.Rollback(ctx)
+}
+
+// poolConn освобождает пул после возврата подключения.
+type poolConn struct {
+	targetdb.Conn
+	release func()
+}
+
+func (c *poolConn) Release() {
+	c.Conn.Release()
+	c.release()
+}
@@ -0,0 +1,149 @@
+// Package pools управляет пулами подключений к целевым базам данных.
+package pools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"migrator/internal/adapters/targetdb"
+	"migrator/internal/entity"
+)
+
+type targetRepository interface {
+	Get(ctx context.Context, targetID int64) (entity.Target, error)
+}
+
+// Manager - лениво открываемые пулы подключений к целевым базам данных.
+// СУБД каждой базы данных определяется по схеме строки подключения.
+type Manager struct {
+	repo targetRepository
+	opts []targetdb.Option
+
+	mu      sync.Mutex
+	entries map[int64]*entry
+}
+
+// entry - пул целевой базы данных, открытый или открываемый. Подключение
+// выполняется без блокировки менеджера, а одновременные обращения к той же
+// базе данных ждут, пока его откроет первое из них.
+type entry struct {
+	done chan struct{}
+	pool targetdb.DB
+	err  error
+}
+
+// New - конструктор менеджера пулов.
+func New(repo targetRepository, opts ...targetdb.Option) *Manager {
+	return &Manager{
+		repo:    repo,
+		opts:    opts,
+		entries: make(map[int64]*entry),
+	}
+}
+
+// DB возвращает пул подключений к целевой базе данных,
+// открывая его при первом обращении.
+func (m *Manager) DB(ctx context.Context, targetID int64) (targetdb.DB, error) {
+	m.mu.Lock()
+	e, ok := m.entries[targetID]
+	if !ok {
+		e = &entry{done: make(chan struct{})}
+		m.entries[targetID] = e
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		m.open(ctx, targetID, e)
+	}
+
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return e.pool, nil
+}
+
+// open подключается к целевой базе данных и сообщает результат ожидающим.
+// Подключение не отменяется вместе с запросом, открывшим пул, так как его
+// ждут и другие запросы.
+func (m *Manager) open(ctx context.Context, targetID int64, e *entry) {
+	pool, err := m.connect(context.WithoutCancel(ctx), targetID)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Результат публикуется под блокировкой, чтобы Invalidate либо увидел
+	// открытый пул, либо удалил запись до этой проверки.
+	e.pool, e.err = pool, err
+	defer close(e.done)
+
+	current := m.entries[targetID] == e
+	if e.err != nil && current {
+		// Следующее обращение попробует подключиться снова.
+		delete(m.entries, targetID)
+	}
+	if e.err == nil && !current {
+		// Пул инвалидирован, пока открывался: его получат только уже
+		// ожидающие запросы, после них он закрывается.
+		e.pool.(retirer).retire()
+	}
+}
+
+func (m *Manager) connect(ctx context.Context, targetID int64) (targetdb.DB, error) {
+	target, err := m.repo.Get(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("m.repo.Get: %w", err)
+	}
+
+	db, err := targetdb.Open(target.URL, m.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("connect to target %d: %w", targetID, err)
+	}
+
+	return wrap(db), nil
+}
+
+// Invalidate выводит из обращения пул целевой базы данных, например после
+// изменения ее настроек подключения. Следующий вызов DB откроет его заново,
+// а старый пул закрывается, когда завершатся начатые на нем транзакции
+// и освободятся выделенные подключения.
+func (m *Manager) Invalidate(targetID int64) {
+	m.mu.Lock()
+	e, ok := m.entries[targetID]
+	delete(m.entries, targetID)
+	m.mu.Unlock()
+
+	if ok {
+		retireWhenOpened(e)
+	}
+}
+
+// Close выводит из обращения все пулы.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	entries := m.entries
+	m.entries = make(map[int64]*entry)
+	m.mu.Unlock()
+
+	for _, e := range entries {
+		retireWhenOpened(e)
+	}
+}
+
+// retireWhenOpened выводит пул из обращения; открываемый пул выводится
+// из обращения тем, кто его открывает.
+func retireWhenOpened(e *entry) {
+	select {
+	case <-e.done:
+		if e.err == nil {
+			e.pool.(retirer).retire()
+		}
+	default:
+	}
+}
@@ -0,0 +1,182 @@
+package pools
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"migrator/internal/adapters/targetdb"
+	"migrator/internal/entity"
+)
+
+// fakeDB - пул целевой базы данных, считающий закрытия; методы, которые
+// тесты не вызывают, остаются у встроенного интерфейса.
+type fakeDB struct {
+	targetdb.DB
+	closed int
+}
+
+func (d *fakeDB) Begin(_ context.Context) (targetdb.Tx, error) {
+	return &fakeTx{}, nil
+}
+
+func (d *fakeDB) Conn(_ context.Context) (targetdb.Conn, error) {
+	return &fakeConn{}, nil
+}
+
+func (d *fakeDB) Close() {
+	d.closed++
+}
+
+type fakeTx struct {
+	targetdb.Tx
+}
+
+func (t *fakeTx) Commit(_ context.Context) error {
+	return nil
+}
+
+func (t *fakeTx) Rollback(_ context.Context) error {
+	return nil
+}
+
+type fakeConn struct {
+	targetdb.Conn
+}
+
+func (c *fakeConn) Release() {}
+
+func TestRetiredPoolClosesAfterUsers(t *testing.T) {
+	ctx := context.Background()
+	db := &fakeDB{}
+	p := wrap(db)
+
+	tx, err := p.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	conn, err := p.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+
+	p.(retirer).retire()
+	if db.closed != 0 {
+		t.Fatal("pool is closed while a transaction and a connection are in use")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	// Откат после фиксации, как в отложенном вызове, не освобождает пул второй раз
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if db.closed != 0 {
+		t.Fatal("pool is closed while a connection is in use")
+	}
+
+	conn.Release()
+	if db.closed != 1 {
+		t.Fatalf("pool closed %d times, want 1", db.closed)
+	}
+
+	p.Close()
+	if db.closed != 1 {
+		t.Errorf("retired pool closed %d times, want 1", db.closed)
+	}
+}
+
+func TestRetireUnusedPool(t *testing.T) {
+	db := &fakeDB{}
+	p := wrap(db)
+
+	p.Close()
+	if db.closed != 1 {
+		t.Errorf("pool closed %d times, want 1", db.closed)
+	}
+}
+
+// fakeTargets - реестр целевых баз данных SQLite во временном каталоге.
+type fakeTargets struct {
+	dir string
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (r *fakeTargets) Get(_ context.Context, targetID int64) (entity.Target, error) {
+	r.mu.Lock()
+	r.calls++
+	r.mu.Unlock()
+	return entity.Target{ID: targetID, URL: "sqlite://" + filepath.Join(r.dir, "target.db")}, nil
+}
+
+func TestManagerOpensPoolOnce(t *testing.T) {
+	targets := &fakeTargets{dir: t.TempDir()}
+	m := New(targets)
+	defer m.Close()
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		pools = make(map[targetdb.DB]bool)
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db, err := m.DB(context.Background(), 1)
+			if err != nil {
+				t.Errorf("DB() error = %v", err)
+				return
+			}
+			mu.Lock()
+			pools[db] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(pools) != 1 || targets.calls != 1 {
+		t.Errorf("opened %d pools with %d target lookups, want 1 and 1", len(pools), targets.calls)
+	}
+}
+
+func TestManagerInvalidate(t *testing.T) {
+	ctx := context.Background()
+	m := New(&fakeTargets{dir: t.TempDir()})
+	defer m.Close()
+
+	old, err := m.DB(ctx, 1)
+	if err != nil {
+		t.Fatalf("DB() error = %v", err)
+	}
+	tx, err := old.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+
+	m.Invalidate(1)
+
+	db, err := m.DB(ctx, 1)
+	if err != nil {
+		t.Fatalf("DB() error = %v", err)
+	}
+	if db == old {
+		t.Fatal("DB() returned the invalidated pool")
+	}
+
+	// Транзакция, начатая до инвалидации, завершается на старом пуле
+	if err := tx.Exec(ctx, "CREATE TABLE users (id INTEGER)"); err != nil {
+		t.Fatalf("Exec() on the invalidated pool error = %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit() on the invalidated pool error = %v", err)
+	}
+
+	if _, err := old.Begin(ctx); err == nil {
+		t.Error("invalidated pool is not closed after its last transaction")
+	}
+}
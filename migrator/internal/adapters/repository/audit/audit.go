@@ -0,0 +1,127 @@
+// Package audit реализует адаптер для журнала аудита вызовов API.
+//
+// Журнал только дополняется: изменение и удаление записей запрещены
+// триггерами таблицы.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// Excecutor - интерфейс для выполнения запросов на базе данных.
+type Excecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+type Repository struct {
+	conn Excecutor
+}
+
+func New(conn Excecutor) *Repository {
+	return &Repository{
+		conn: conn,
+	}
+}
+
+const addQuery = `-- Add
+	INSERT INTO audit_log (actor_id, action, resource, ip, user_agent, outcome, error, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+// Add добавляет запись в журнал аудита.
+func (r *Repository) Add(ctx context.Context, event entity.AuditEvent) error {
+	_, err := r.conn.Exec(
+		ctx,
+		addQuery,
+		event.ActorID,
+		event.Action,
+		event.Resource,
+		event.IP,
+		event.UserAgent,
+		event.Outcome.String(),
+		event.Error,
+		event.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("add audit event: %w", err)
+	}
+
+	return nil
+}
+
+const listQuery = `-- List
+	SELECT id, actor_id, action, resource, ip, user_agent, outcome, error, created_at
+	FROM audit_log
+	WHERE ($1::bigint = 0 OR actor_id = $1)
+		AND ($2::timestamptz IS NULL OR created_at >= $2)
+		AND ($3::timestamptz IS NULL OR created_at < $3)
+		AND ($4::bigint = 0 OR id < $4)
+	ORDER BY id DESC
+	LIMIT $5
+`
+
+// List возвращает записи журнала аудита от новых к старым.
+func (r *Repository) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEvent, error) {
+	rows, err := r.conn.Query(
+		ctx,
+		listQuery,
+		filter.ActorID,
+		nullTime(filter.From),
+		nullTime(filter.To),
+		filter.BeforeID,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []entity.AuditEvent
+	for rows.Next() {
+		var event entity.AuditEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.ActorID,
+			&event.Action,
+			&event.Resource,
+			&event.IP,
+			&event.UserAgent,
+			&event.Outcome,
+			&event.Error,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return events, nil
+}
+
+// nullTime передает нулевое время как NULL, чтобы оно не ограничивало выборку.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,391 @@
+// Package backfill реализует адаптер для хранения хода выполнения миграций данных
+// и выполнения их пачек на целевых базах данных.
+//
+// Ход выполнения хранится в общей для всех реплик базе данных сервиса: реплика,
+// выполняющая пачки, продлевает аренду (heartbeat_at), а после остановки реплики
+// выполнение подхватывает любая другая реплика или та же реплика после перезапуска.
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"migrator/internal/adapters/targetdb"
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// Excecutor - интерфейс для выполнения запросов на базе данных.
+type Excecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// targetPools - источник пулов подключений к целевым базам данных.
+type targetPools interface {
+	DB(ctx context.Context, targetID int64) (targetdb.DB, error)
+}
+
+type Repository struct {
+	conn    Excecutor
+	targets targetPools
+}
+
+func New(conn Excecutor, targets targetPools) *Repository {
+	return &Repository{
+		conn:    conn,
+		targets: targets,
+	}
+}
+
+const backfillColumns = `
+		migration_id,
+		target_id,
+		state,
+		cursor,
+		rows_done,
+		estimated_total,
+		batch_size,
+		pause_ms,
+		error,
+		owner,
+		started_by,
+		started_at,
+		updated_at,
+		COALESCE(finished_at, '0001-01-01 00:00:00+00')`
+
+func scanBackfill(row pgx.Row) (entity.Backfill, error) {
+	var (
+		backfill entity.Backfill
+		pauseMs  int64
+	)
+	err := row.Scan(
+		&backfill.MigrationID,
+		&backfill.TargetID,
+		&backfill.State,
+		&backfill.Cursor,
+		&backfill.RowsDone,
+		&backfill.EstimatedTotal,
+		&backfill.BatchSize,
+		&pauseMs,
+		&backfill.Error,
+		&backfill.Owner,
+		&backfill.StartedBy,
+		&backfill.StartedAt,
+		&backfill.UpdatedAt,
+		&backfill.FinishedAt,
+	)
+	backfill.Pause = time.Duration(pauseMs) * time.Millisecond
+	return backfill, err
+}
+
+const createQuery = `-- Create
+	INSERT INTO migration_backfills (migration_id, target_id, state, cursor, estimated_total, batch_size, started_by, started_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+	ON CONFLICT (migration_id) DO NOTHING
+`
+
+// Create сохраняет ход выполнения запущенной миграции данных.
+// Возвращает entity.ErrAlreadyExists, если миграция данных уже запускалась.
+func (r *Repository) Create(ctx context.Context, backfill entity.Backfill) error {
+	tag, err := r.conn.Exec(
+		ctx,
+		createQuery,
+		backfill.MigrationID,
+		backfill.TargetID,
+		backfill.State,
+		backfill.Cursor,
+		backfill.EstimatedTotal,
+		backfill.BatchSize,
+		backfill.StartedBy,
+		backfill.StartedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("create backfill: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("backfill of migration %d: %w", backfill.MigrationID, entity.ErrAlreadyExists)
+	}
+	return nil
+}
+
+const getQuery = `-- Get
+	SELECT` + backfillColumns + `
+	FROM migration_backfills
+	WHERE migration_id = $1
+`
+
+// Get возвращает ход выполнения миграции данных.
+func (r *Repository) Get(ctx context.Context, migrationID int64) (entity.Backfill, error) {
+	backfill, err := scanBackfill(r.conn.QueryRow(ctx, getQuery, migrationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Backfill{}, fmt.Errorf("backfill of migration %d: %w", migrationID, entity.ErrNotFound)
+		}
+		return entity.Backfill{}, fmt.Errorf("get backfill: %w", err)
+	}
+	return backfill, nil
+}
+
+const transitionQuery = `-- Transition
+	UPDATE migration_backfills
+	SET state = $1,
+		error = $2,
+		updated_at = $3
+	WHERE migration_id = $4 AND state = ANY($5)
+`
+
+// Transition переводит миграцию данных в состояние to, если она находится
+// в одном из состояний from. Возвращает false, если состояние другое.
+func (r *Repository) Transition(ctx context.Context, migrationID int64, from []entity.BackfillState, to entity.BackfillState, errText string, at time.Time) (bool, error) {
+	states := make([]string, len(from))
+	for i, state := range from {
+		states[i] = state.String()
+	}
+
+	tag, err := r.conn.Exec(ctx, transitionQuery, to, errText, at.UTC(), migrationID, states)
+	if err != nil {
+		return false, fmt.Errorf("transition backfill: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+const throttleQuery = `-- Throttle
+	UPDATE migration_backfills
+	SET batch_size = $1,
+		pause_ms = $2,
+		updated_at = $3
+	WHERE migration_id = $4
+`
+
+// Throttle изменяет размер пачки и паузу между пачками.
+func (r *Repository) Throttle(ctx context.Context, migrationID int64, batchSize int, pause time.Duration, at time.Time) error {
+	tag, err := r.conn.Exec(ctx, throttleQuery, batchSize, pause.Milliseconds(), at.UTC(), migrationID)
+	if err != nil {
+		return fmt.Errorf("throttle backfill: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("backfill of migration %d: %w", migrationID, entity.ErrNotFound)
+	}
+	return nil
+}
+
+const listActiveQuery = `-- ListActive
+	SELECT` + backfillColumns + `
+	FROM migration_backfills
+	WHERE target_id = $1 AND state IN ('running', 'paused')
+	ORDER BY migration_id
+`
+
+// ListActive возвращает выполняемые и приостановленные миграции данных целевой базы данных.
+func (r *Repository) ListActive(ctx context.Context, targetID int64) ([]entity.Backfill, error) {
+	rows, err := r.conn.Query(ctx, listActiveQuery, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("list active backfills: %w", err)
+	}
+	defer rows.Close()
+
+	var backfills []entity.Backfill
+	for rows.Next() {
+		backfill, err := scanBackfill(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan backfill: %w", err)
+		}
+		backfills = append(backfills, backfill)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return backfills, nil
+}
+
+// claimCondition - миграция данных выполняется и не удерживается другой репликой:
+// аренда принадлежит этой реплике, свободна или истекла.
+const claimCondition = `state = 'running' AND (owner = $1 OR owner = '' OR heartbeat_at IS NULL OR heartbeat_at < now() - $2::bigint * interval '1 millisecond')`
+
+const listClaimableQuery = `-- ListClaimable
+	SELECT` + backfillColumns + `
+	FROM migration_backfills
+	WHERE ` + claimCondition + `
+	ORDER BY migration_id
+`
+
+// ListClaimable возвращает выполняемые миграции данных, которые может взять реплика owner.
+func (r *Repository) ListClaimable(ctx context.Context, owner string, lease time.Duration) ([]entity.Backfill, error) {
+	rows, err := r.conn.Query(ctx, listClaimableQuery, owner, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("list claimable backfills: %w", err)
+	}
+	defer rows.Close()
+
+	var backfills []entity.Backfill
+	for rows.Next() {
+		backfill, err := scanBackfill(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan backfill: %w", err)
+		}
+		backfills = append(backfills, backfill)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return backfills, nil
+}
+
+const claimQuery = `-- Claim
+	UPDATE migration_backfills
+	SET owner = $1,
+		heartbeat_at = now()
+	WHERE migration_id = $3 AND ` + claimCondition + `
+	RETURNING` + backfillColumns + `
+`
+
+// Claim берет или продлевает аренду выполняемой миграции данных для реплики owner
+// и возвращает ее текущий ход выполнения. Возвращает false, если миграция данных
+// не выполняется (приостановлена, завершена) или удерживается другой репликой.
+func (r *Repository) Claim(ctx context.Context, migrationID int64, owner string, lease time.Duration) (entity.Backfill, bool, error) {
+	backfill, err := scanBackfill(r.conn.QueryRow(ctx, claimQuery, owner, lease.Milliseconds(), migrationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Backfill{}, false, nil
+		}
+		return entity.Backfill{}, false, fmt.Errorf("claim backfill: %w", err)
+	}
+	return backfill, true, nil
+}
+
+const advanceQuery = `-- Advance
+	UPDATE migration_backfills
+	SET cursor = $1,
+		rows_done = rows_done + $2,
+		updated_at = $3,
+		heartbeat_at = now()
+	WHERE migration_id = $4 AND owner = $5
+`
+
+// Advance сохраняет курсор и число строк после выполнения пачки репликой owner.
+// Возвращает false, если аренду за это время взяла другая реплика.
+func (r *Repository) Advance(ctx context.Context, migrationID int64, owner, cursor string, rows int64, at time.Time) (bool, error) {
+	tag, err := r.conn.Exec(ctx, advanceQuery, cursor, rows, at.UTC(), migrationID, owner)
+	if err != nil {
+		return false, fmt.Errorf("advance backfill: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+const finishQuery = `-- Finish
+	UPDATE migration_backfills
+	SET state = $1,
+		error = $2,
+		updated_at = $3,
+		finished_at = CASE WHEN $1 = 'completed' THEN $3 END,
+		owner = '',
+		heartbeat_at = NULL
+	WHERE migration_id = $4
+`
+
+// Finish завершает выполнение миграции данных репликой: completed - все строки
+// обработаны, failed - пачка завершилась ошибкой errText.
+func (r *Repository) Finish(ctx context.Context, migrationID int64, state entity.BackfillState, errText string, at time.Time) error {
+	_, err := r.conn.Exec(ctx, finishQuery, state, errText, at.UTC(), migrationID)
+	if err != nil {
+		return fmt.Errorf("finish backfill: %w", err)
+	}
+	return nil
+}
+
+// RunBatch выполняет одну пачку миграции данных в отдельной транзакции целевой
+// базы данных: cursorQuery выбирает ключи строк пачки, render строит по ним
+// скрипт пачки, который выполняется и фиксируется вместе с выборкой.
+// Блокировка целевой базы данных не захватывается, чтобы длительная миграция
+// данных не останавливала применение других миграций.
+// Возвращает ключи обработанных строк; пустой результат означает, что строк не осталось.
+func (r *Repository) RunBatch(ctx context.Context, targetID int64, cursorQuery string, render func(keys []string) (string, error)) ([]string, error) {
+	db, err := r.targets.DB(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("r.targets.DB: %w", err)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin target transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil {
+			logger.Error(fmt.Errorf("target transaction rollback error: %w", err))
+		}
+	}()
+
+	keys, err := tx.QueryColumn(ctx, cursorQuery)
+	if err != nil {
+		return nil, fmt.Errorf("cursor query: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	script, err := render(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Exec(ctx, script); err != nil {
+		return nil, fmt.Errorf("batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit target transaction: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Estimate выполняет запрос оценки общего числа строк на целевой базе данных.
+func (r *Repository) Estimate(ctx context.Context, targetID int64, query string) (int64, error) {
+	db, err := r.targets.DB(ctx, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("r.targets.DB: %w", err)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin target transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil {
+			logger.Error(fmt.Errorf("target transaction rollback error: %w", err))
+		}
+	}()
+
+	values, err := tx.QueryColumn(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("estimate query: %w", err)
+	}
+	if len(values) == 0 {
+		return 0, fmt.Errorf("estimate query returned no rows")
+	}
+
+	total, err := strconv.ParseFloat(strings.TrimSpace(values[0]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("estimate query returned %q, not a number", values[0])
+	}
+
+	return int64(total), nil
+}
@@ -0,0 +1,295 @@
+// Package environment реализует адаптер для работы с окружениями целевых баз данных.
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+)
+
+// Excecutor - интерфейс для выполнения запросов на базе данных.
+type Excecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+type Repository struct {
+	conn Excecutor
+}
+
+func New(conn Excecutor) *Repository {
+	return &Repository{
+		conn: conn,
+	}
+}
+
+const createQuery = `-- Create
+	INSERT INTO environments (name, description, promote_from_id, min_soak_seconds, variables, created_by, created_at, updated_at)
+	VALUES ($1, $2, NULLIF($3::BIGINT, 0), $4, $5, $6, $7, $7)
+	RETURNING id
+`
+
+func (r *Repository) Create(ctx context.Context, environment entity.Environment) (int64, error) {
+	variables, err := marshalVariables(environment.Variables)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err = r.conn.QueryRow(
+		ctx,
+		createQuery,
+		environment.Name,
+		environment.Description,
+		environment.PromoteFromID,
+		int64(environment.MinSoak/time.Second),
+		variables,
+		environment.CreatedBy,
+		time.Now().UTC(),
+	).Scan(&id)
+	if err != nil {
+		if isPgError(err, uniqueViolationCode) {
+			return 0, fmt.Errorf("create environment: %w", entity.ErrAlreadyExists)
+		}
+		if isPgError(err, foreignKeyViolationCode) {
+			return 0, fmt.Errorf("create environment: environment %d: %w", environment.PromoteFromID, entity.ErrNotFound)
+		}
+		return 0, fmt.Errorf("create environment: %w", err)
+	}
+	return id, nil
+}
+
+const environmentColumns = `
+		id,
+		name,
+		description,
+		COALESCE(promote_from_id, 0),
+		min_soak_seconds,
+		variables::text,
+		COALESCE(created_by, 0),
+		created_at,
+		updated_at`
+
+func scanEnvironment(row pgx.Row) (entity.Environment, error) {
+	var (
+		environment entity.Environment
+		minSoak     int64
+		variables   string
+	)
+	err := row.Scan(
+		&environment.ID,
+		&environment.Name,
+		&environment.Description,
+		&environment.PromoteFromID,
+		&minSoak,
+		&variables,
+		&environment.CreatedBy,
+		&environment.CreatedAt,
+		&environment.UpdatedAt,
+	)
+	if err != nil {
+		return environment, err
+	}
+
+	environment.MinSoak = time.Duration(minSoak) * time.Second
+	if err := json.Unmarshal([]byte(variables), &environment.Variables); err != nil {
+		return environment, fmt.Errorf("unmarshal variables: %w", err)
+	}
+
+	return environment, nil
+}
+
+const getQuery = `-- Get
+	SELECT` + environmentColumns + `
+	FROM environments
+	WHERE id = $1
+`
+
+func (r *Repository) Get(ctx context.Context, environmentID int64) (entity.Environment, error) {
+	environment, err := scanEnvironment(r.conn.QueryRow(ctx, getQuery, environmentID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Environment{}, fmt.Errorf("get environment %d: %w", environmentID, entity.ErrNotFound)
+		}
+		return entity.Environment{}, fmt.Errorf("get environment: %w", err)
+	}
+	return environment, nil
+}
+
+const listQuery = `-- List
+	SELECT` + environmentColumns + `
+	FROM environments
+	ORDER BY id
+`
+
+func (r *Repository) List(ctx context.Context) ([]entity.Environment, error) {
+	rows, err := r.conn.Query(ctx, listQuery)
+	if err != nil {
+		return nil, fmt.Errorf("list environments: %w", err)
+	}
+	defer rows.Close()
+
+	var environments []entity.Environment
+	for rows.Next() {
+		environment, err := scanEnvironment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan environment: %w", err)
+		}
+		environments = append(environments, environment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return environments, nil
+}
+
+const updateQuery = `-- Update
+	UPDATE environments
+	SET name = $1, description = $2, promote_from_id = NULLIF($3::BIGINT, 0), min_soak_seconds = $4, variables = $5, updated_at = $6
+	WHERE id = $7
+`
+
+func (r *Repository) Update(ctx context.Context, environment entity.Environment) error {
+	variables, err := marshalVariables(environment.Variables)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.conn.Exec(
+		ctx,
+		updateQuery,
+		environment.Name,
+		environment.Description,
+		environment.PromoteFromID,
+		int64(environment.MinSoak/time.Second),
+		variables,
+		time.Now().UTC(),
+		environment.ID,
+	)
+	if err != nil {
+		if isPgError(err, uniqueViolationCode) {
+			return fmt.Errorf("update environment: %w", entity.ErrAlreadyExists)
+		}
+		if isPgError(err, foreignKeyViolationCode) {
+			return fmt.Errorf("update environment: environment %d: %w", environment.PromoteFromID, entity.ErrNotFound)
+		}
+		return fmt.Errorf("update environment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("update environment %d: %w", environment.ID, entity.ErrNotFound)
+	}
+	return nil
+}
+
+const deleteQuery = `-- Delete
+	DELETE FROM environments
+	WHERE id = $1
+`
+
+func (r *Repository) Delete(ctx context.Context, environmentID int64) error {
+	tag, err := r.conn.Exec(ctx, deleteQuery, environmentID)
+	if err != nil {
+		if isPgError(err, foreignKeyViolationCode) {
+			return fmt.Errorf("delete environment %d: %w", environmentID, entity.ErrEnvironmentInUse)
+		}
+		return fmt.Errorf("delete environment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("delete environment %d: %w", environmentID, entity.ErrNotFound)
+	}
+	return nil
+}
+
+const listMigrationsQuery = `-- ListMigrations
+	SELECT
+		e.id,
+		e.name,
+		t.id,
+		t.name,
+		m.id,
+		m.name,
+		COALESCE(m.checksum, ''),
+		m.status,
+		m.status_updated_at
+	FROM migrations m
+	JOIN targets t ON t.id = m.target_id
+	JOIN environments e ON e.id = t.environment_id
+	WHERE (m.name = $1 OR $1 = '')
+		AND (e.id = $2 OR $2 = 0)
+		AND ($3 = 0 OR m.name IN (SELECT name FROM migrations WHERE target_id = $3))
+	ORDER BY m.name, e.id, t.id, m.id
+`
+
+// ListMigrations возвращает состояния миграций во всех целевых базах данных, относящихся
+// к окружениям. name, environmentID и targetID (миграции, зарегистрированные для
+// этой целевой базы данных) ограничивают выборку, если не пусты.
+func (r *Repository) ListMigrations(ctx context.Context, name string, environmentID, targetID int64) ([]entity.EnvironmentMigration, error) {
+	rows, err := r.conn.Query(ctx, listMigrationsQuery, name, environmentID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("list environment migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var migrations []entity.EnvironmentMigration
+	for rows.Next() {
+		var migration entity.EnvironmentMigration
+		err := rows.Scan(
+			&migration.EnvironmentID,
+			&migration.EnvironmentName,
+			&migration.TargetID,
+			&migration.TargetName,
+			&migration.MigrationID,
+			&migration.Name,
+			&migration.Checksum,
+			&migration.Status,
+			&migration.StatusUpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan environment migration: %w", err)
+		}
+		migrations = append(migrations, migration)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return migrations, nil
+}
+
+// marshalVariables сериализует переменные шаблонов для колонки JSONB.
+func marshalVariables(variables map[string]string) (string, error) {
+	if variables == nil {
+		variables = map[string]string{}
+	}
+	variablesJSON, err := json.Marshal(variables)
+	if err != nil {
+		return "", fmt.Errorf("marshal variables: %w", err)
+	}
+	return string(variablesJSON), nil
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,156 @@
+// Package history реализует адаптер для журнала выполнения миграций.
+//
+// Журнал пишется вне транзакций миграций, поэтому в нем остаются
+// и неудачные попытки применения и отката.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// Excecutor - интерфейс для выполнения запросов на базе данных.
+type Excecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+type Repository struct {
+	conn Excecutor
+}
+
+func New(conn Excecutor) *Repository {
+	return &Repository{
+		conn: conn,
+	}
+}
+
+const addQuery = `-- Add
+	INSERT INTO migration_history (migration_id, target_id, action, user_id, started_at, finished_at, duration_ms, outcome, error, script, variables, tenant)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+`
+
+// Add добавляет записи в журнал одним пакетом.
+func (r *Repository) Add(ctx context.Context, entries []entity.HistoryEntry) error {
+	batch := &pgx.Batch{}
+	for _, entry := range entries {
+		variables := entry.Variables
+		if variables == nil {
+			variables = map[string]string{}
+		}
+		variablesJSON, err := json.Marshal(variables)
+		if err != nil {
+			return fmt.Errorf("marshal history variables: %w", err)
+		}
+
+		batch.Queue(
+			addQuery,
+			entry.MigrationID,
+			entry.TargetID,
+			entry.Action,
+			entry.UserID,
+			entry.StartedAt.UTC(),
+			entry.FinishedAt.UTC(),
+			entry.Duration.Milliseconds(),
+			entry.Outcome,
+			entry.Error,
+			entry.Script,
+			string(variablesJSON),
+			entry.Tenant,
+		)
+	}
+
+	results := r.conn.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for range entries {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("add history entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+const listQuery = `-- List
+	SELECT id, migration_id, target_id, action, user_id, started_at, finished_at, duration_ms, outcome, error, script, variables::text, tenant
+	FROM migration_history
+	WHERE ($1::bigint = 0 OR target_id = $1)
+		AND ($2::bigint = 0 OR migration_id = $2)
+		AND ($3::bigint = 0 OR user_id = $3)
+		AND ($4::text = '' OR action = $4)
+		AND ($5::text = '' OR outcome = $5)
+		AND ($6::bigint = 0 OR id < $6)
+	ORDER BY id DESC
+	LIMIT $7
+`
+
+// List возвращает записи журнала от новых к старым.
+func (r *Repository) List(ctx context.Context, filter entity.HistoryFilter) ([]entity.HistoryEntry, error) {
+	rows, err := r.conn.Query(
+		ctx,
+		listQuery,
+		filter.TargetID,
+		filter.MigrationID,
+		filter.UserID,
+		filter.Action.String(),
+		filter.Outcome.String(),
+		filter.BeforeID,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []entity.HistoryEntry
+	for rows.Next() {
+		var (
+			entry      entity.HistoryEntry
+			durationMs int64
+			variables  string
+		)
+		err := rows.Scan(
+			&entry.ID,
+			&entry.MigrationID,
+			&entry.TargetID,
+			&entry.Action,
+			&entry.UserID,
+			&entry.StartedAt,
+			&entry.FinishedAt,
+			&durationMs,
+			&entry.Outcome,
+			&entry.Error,
+			&entry.Script,
+			&variables,
+			&entry.Tenant,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan history entry: %w", err)
+		}
+		entry.Duration = time.Duration(durationMs) * time.Millisecond
+		if err := json.Unmarshal([]byte(variables), &entry.Variables); err != nil {
+			return nil, fmt.Errorf("unmarshal history variables: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return entries, nil
+}
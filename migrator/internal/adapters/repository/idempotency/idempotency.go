@@ -0,0 +1,167 @@
+// Package idempotency реализует адаптер для ключей идемпотентности запросов.
+//
+// Ключ сначала резервируется запросом, который его первым передал, а после
+// успешного выполнения запроса вместе с ключом сохраняется ответ.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// Excecutor - интерфейс для выполнения запросов на базе данных.
+type Excecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+type Repository struct {
+	conn Excecutor
+}
+
+func New(conn Excecutor) *Repository {
+	return &Repository{
+		conn: conn,
+	}
+}
+
+// expiredCondition - ключ больше не действует: ответ хранится дольше срока
+// хранения или запрос, зарезервировавший ключ, так и не завершился.
+const expiredCondition = `
+	(completed AND completed_at < $1) OR (NOT completed AND created_at < $2)
+`
+
+const reserveQuery = `-- Reserve
+	INSERT INTO idempotency_keys AS k (user_id, method, key, request_hash, completed, created_at)
+	VALUES ($3, $4, $5, $6, FALSE, $7)
+	ON CONFLICT (user_id, method, key) DO UPDATE
+	SET request_hash = EXCLUDED.request_hash,
+		response = NULL,
+		completed = FALSE,
+		created_at = EXCLUDED.created_at,
+		completed_at = NULL
+	WHERE (k.completed AND k.completed_at < $1) OR (NOT k.completed AND k.created_at < $2)
+	RETURNING TRUE
+`
+
+// Reserve резервирует ключ за запросом. Ключ, который еще действует,
+// не резервируется повторно: тогда возвращается false.
+func (r *Repository) Reserve(ctx context.Context, record entity.IdempotencyRecord, completedBefore, staleBefore time.Time) (bool, error) {
+	var reserved bool
+	err := r.conn.QueryRow(
+		ctx,
+		reserveQuery,
+		completedBefore.UTC(),
+		staleBefore.UTC(),
+		record.Key.UserID,
+		record.Key.Method,
+		record.Key.Key,
+		record.RequestHash,
+		record.CreatedAt.UTC(),
+	).Scan(&reserved)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	return reserved, nil
+}
+
+const getQuery = `-- Get
+	SELECT request_hash, response, completed, created_at, completed_at
+	FROM idempotency_keys
+	WHERE user_id = $1 AND method = $2 AND key = $3
+`
+
+// Get возвращает запрос, выполненный с ключом.
+func (r *Repository) Get(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyRecord, error) {
+	record := entity.IdempotencyRecord{Key: key}
+	var completedAt *time.Time
+	err := r.conn.QueryRow(ctx, getQuery, key.UserID, key.Method, key.Key).Scan(
+		&record.RequestHash,
+		&record.Response,
+		&record.Completed,
+		&record.CreatedAt,
+		&completedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.IdempotencyRecord{}, fmt.Errorf("idempotency key %q: %w", key.Key, entity.ErrNotFound)
+	}
+	if err != nil {
+		return entity.IdempotencyRecord{}, fmt.Errorf("get idempotency key: %w", err)
+	}
+	if completedAt != nil {
+		record.CompletedAt = *completedAt
+	}
+
+	return record, nil
+}
+
+const completeQuery = `-- Complete
+	UPDATE idempotency_keys
+	SET response = $5, completed = TRUE, completed_at = $6
+	WHERE user_id = $1 AND method = $2 AND key = $3 AND request_hash = $4 AND NOT completed
+`
+
+// Complete сохраняет ответ выполненного запроса.
+func (r *Repository) Complete(ctx context.Context, record entity.IdempotencyRecord) error {
+	_, err := r.conn.Exec(
+		ctx,
+		completeQuery,
+		record.Key.UserID,
+		record.Key.Method,
+		record.Key.Key,
+		record.RequestHash,
+		record.Response,
+		record.CompletedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+const releaseQuery = `-- Release
+	DELETE FROM idempotency_keys
+	WHERE user_id = $1 AND method = $2 AND key = $3 AND request_hash = $4 AND NOT completed
+`
+
+// Release снимает резерв ключа, чтобы повтор запроса выполнился заново.
+func (r *Repository) Release(ctx context.Context, record entity.IdempotencyRecord) error {
+	_, err := r.conn.Exec(ctx, releaseQuery, record.Key.UserID, record.Key.Method, record.Key.Key, record.RequestHash)
+	if err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+const purgeQuery = `-- Purge
+	DELETE FROM idempotency_keys
+	WHERE ` + expiredCondition
+
+// Purge удаляет ключи, которые больше не действуют.
+func (r *Repository) Purge(ctx context.Context, completedBefore, staleBefore time.Time) (int64, error) {
+	tag, err := r.conn.Exec(ctx, purgeQuery, completedBefore.UTC(), staleBefore.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("purge idempotency keys: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
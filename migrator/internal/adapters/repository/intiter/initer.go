@@ -3,6 +3,7 @@ package intiter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgconn"
@@ -31,6 +32,72 @@ func New(conn Excecutor) *Repository {
 	}
 }
 
+const createTargetsTableQuery = `
+CREATE TABLE IF NOT EXISTS targets (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    url TEXT NOT NULL,
+    created_by BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+`
+
+// alterTargetsTableQuery добавляет в таблицу целевых баз данных колонки,
+// появившиеся после ее первоначального создания.
+const alterTargetsTableQuery = `
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS environment_id BIGINT REFERENCES environments (id);
+CREATE INDEX IF NOT EXISTS targets_environment_id_idx ON targets (environment_id);
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS variables JSONB NOT NULL DEFAULT '{}';
+`
+
+// CreateIfNeededTargetsTable создает таблицу целевых баз данных, если ее нет.
+func (r *Repository) CreateIfNeededTargetsTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createTargetsTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create targets table: %w", err)
+	}
+	_, err = r.conn.Exec(ctx, alterTargetsTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to alter targets table: %w", err)
+	}
+	return nil
+}
+
+const createEnvironmentsTableQuery = `
+CREATE TABLE IF NOT EXISTS environments (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    promote_from_id BIGINT REFERENCES environments (id),
+    min_soak_seconds BIGINT NOT NULL DEFAULT 0,
+    created_by BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+`
+
+// alterEnvironmentsTableQuery добавляет в таблицу окружений колонки,
+// появившиеся после ее первоначального создания.
+const alterEnvironmentsTableQuery = `
+ALTER TABLE environments ADD COLUMN IF NOT EXISTS variables JSONB NOT NULL DEFAULT '{}';
+`
+
+// CreateIfNeededEnvironmentsTable создает таблицу окружений, если ее нет.
+// Вызывается до создания таблицы целевых баз данных, которая на нее ссылается.
+func (r *Repository) CreateIfNeededEnvironmentsTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createEnvironmentsTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create environments table: %w", err)
+	}
+	_, err = r.conn.Exec(ctx, alterEnvironmentsTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to alter environments table: %w", err)
+	}
+	return nil
+}
+
 const createMigrationsTableQuery = `
 CREATE TABLE IF NOT EXISTS migrations (
     id SERIAL PRIMARY KEY,
@@ -45,11 +112,305 @@ CREATE TABLE IF NOT EXISTS migrations (
 );
 `
 
+// alterMigrationsTableQuery добавляет в таблицу миграций колонки,
+// появившиеся после ее первоначального создания.
+const alterMigrationsTableQuery = `
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS target_id BIGINT REFERENCES targets (id);
+CREATE INDEX IF NOT EXISTS migrations_target_id_idx ON migrations (target_id);
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS checksum TEXT;
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS applied_checksum TEXT;
+UPDATE migrations
+SET checksum = encode(sha256(convert_to(script, 'UTF8') || '\x00'::bytea || convert_to(rollback_script, 'UTF8')), 'hex')
+WHERE checksum IS NULL;
+UPDATE migrations
+SET applied_checksum = checksum
+WHERE applied_checksum IS NULL AND status = 'applied';
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS execution_mode TEXT NOT NULL DEFAULT 'transactional';
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS last_error JSONB;
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS baselined BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS baselined_by BIGINT;
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS tenant_scoped BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS templated BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'versioned';
+ALTER TABLE migrations ADD COLUMN IF NOT EXISTS backfill JSONB;
+`
+
 // CreateIfNeededMigrationsTable создает таблицу миграций, если ее нет.
 func (r *Repository) CreateIfNeededMigrationsTable(ctx context.Context) error {
 	_, err := r.conn.Exec(ctx, createMigrationsTableQuery)
 	if err != nil {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
+	_, err = r.conn.Exec(ctx, alterMigrationsTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to alter migrations table: %w", err)
+	}
+	return nil
+}
+
+// unassignedMigrationsExistQuery проверяет, есть ли миграции, созданные до
+// появления реестра целевых баз данных и не привязанные к целевой базе.
+const unassignedMigrationsExistQuery = `
+SELECT EXISTS (SELECT 1 FROM migrations WHERE target_id IS NULL);
+`
+
+// defaultTargetQuery возвращает целевую базу данных с заданным адресом,
+// а если ее нет - регистрирует целевую базу default с этим адресом.
+const defaultTargetQuery = `
+WITH existing AS (
+    SELECT id FROM targets WHERE url = $1 ORDER BY id LIMIT 1
+), inserted AS (
+    INSERT INTO targets (name, description, url, created_at, updated_at)
+    SELECT 'default', 'Database of migrations created before the target registry', $1, now(), now()
+    WHERE NOT EXISTS (SELECT 1 FROM existing)
+    ON CONFLICT (name) DO NOTHING
+    RETURNING id
+)
+SELECT id FROM existing
+UNION ALL
+SELECT id FROM inserted;
+`
+
+const assignDefaultTargetQuery = `
+UPDATE migrations SET target_id = $1 WHERE target_id IS NULL;
+`
+
+const requireMigrationsTargetQuery = `
+ALTER TABLE migrations ALTER COLUMN target_id SET NOT NULL;
+`
+
+// AssignDefaultTarget привязывает миграции, созданные до появления реестра
+// целевых баз данных, к целевой базе по умолчанию - базе, к которой они
+// применялись раньше, - и делает целевую базу миграции обязательной.
+// Целевая база по умолчанию регистрируется, только если такие миграции есть.
+func (r *Repository) AssignDefaultTarget(ctx context.Context, defaultTargetURL string) error {
+	return r.conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		var unassigned bool
+		if err := tx.QueryRow(ctx, unassignedMigrationsExistQuery).Scan(&unassigned); err != nil {
+			return fmt.Errorf("failed to check unassigned migrations: %w", err)
+		}
+		if unassigned {
+			var targetID int64
+			err := tx.QueryRow(ctx, defaultTargetQuery, defaultTargetURL).Scan(&targetID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("failed to register default target: target named default already exists with another url")
+			}
+			if err != nil {
+				return fmt.Errorf("failed to register default target: %w", err)
+			}
+			if _, err = tx.Exec(ctx, assignDefaultTargetQuery, targetID); err != nil {
+				return fmt.Errorf("failed to assign default target to migrations: %w", err)
+			}
+		}
+		if _, err := tx.Exec(ctx, requireMigrationsTargetQuery); err != nil {
+			return fmt.Errorf("failed to alter migrations table: %w", err)
+		}
+		return nil
+	})
+}
+
+const createLocksTableQuery = `
+CREATE TABLE IF NOT EXISTS migration_locks (
+    target_id BIGINT PRIMARY KEY REFERENCES targets (id) ON DELETE CASCADE,
+    holder TEXT NOT NULL,
+    token TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+ALTER TABLE migration_locks ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+`
+
+// CreateIfNeededLocksTable создает таблицу блокировок целевых баз данных, если ее нет.
+func (r *Repository) CreateIfNeededLocksTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createLocksTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create migration_locks table: %w", err)
+	}
+	return nil
+}
+
+const createHistoryTableQuery = `
+CREATE TABLE IF NOT EXISTS migration_history (
+    id BIGSERIAL PRIMARY KEY,
+    migration_id BIGINT NOT NULL,
+    target_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    user_id BIGINT NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    outcome TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS migration_history_target_id_idx ON migration_history (target_id, id);
+CREATE INDEX IF NOT EXISTS migration_history_migration_id_idx ON migration_history (migration_id, id);
+ALTER TABLE migration_history ADD COLUMN IF NOT EXISTS script TEXT NOT NULL DEFAULT '';
+ALTER TABLE migration_history ADD COLUMN IF NOT EXISTS variables JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE migration_history ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT '';
+`
+
+// CreateIfNeededHistoryTable создает таблицу журнала выполнения миграций, если ее нет.
+func (r *Repository) CreateIfNeededHistoryTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createHistoryTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create migration_history table: %w", err)
+	}
+	return nil
+}
+
+const createTenantsTableQuery = `
+CREATE TABLE IF NOT EXISTS migration_tenants (
+    migration_id BIGINT NOT NULL REFERENCES migrations (id),
+    tenant TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (migration_id, tenant)
+);
+`
+
+// CreateIfNeededTenantsTable создает таблицу состояний миграций в схемах арендаторов, если ее нет.
+func (r *Repository) CreateIfNeededTenantsTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createTenantsTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create migration_tenants table: %w", err)
+	}
+	return nil
+}
+
+const createRevisionsTableQuery = `
+CREATE TABLE IF NOT EXISTS migration_revisions (
+    migration_id BIGINT NOT NULL REFERENCES migrations (id),
+    revision BIGINT NOT NULL,
+    checksum TEXT NOT NULL,
+    script TEXT NOT NULL,
+    applied_by BIGINT NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (migration_id, revision)
+);
+`
+
+// CreateIfNeededRevisionsTable создает таблицу выполненных ревизий повторяемых миграций, если ее нет.
+func (r *Repository) CreateIfNeededRevisionsTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createRevisionsTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create migration_revisions table: %w", err)
+	}
+	return nil
+}
+
+const createBackfillsTableQuery = `
+CREATE TABLE IF NOT EXISTS migration_backfills (
+    migration_id BIGINT PRIMARY KEY REFERENCES migrations (id),
+    target_id BIGINT NOT NULL REFERENCES targets (id),
+    state TEXT NOT NULL,
+    cursor TEXT NOT NULL DEFAULT '',
+    rows_done BIGINT NOT NULL DEFAULT 0,
+    estimated_total BIGINT NOT NULL DEFAULT -1,
+    batch_size INT NOT NULL,
+    pause_ms BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    owner TEXT NOT NULL DEFAULT '',
+    heartbeat_at TIMESTAMP WITH TIME ZONE,
+    started_by BIGINT NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS migration_backfills_state_idx ON migration_backfills (state);
+`
+
+// CreateIfNeededBackfillsTable создает таблицу хода выполнения миграций данных, если ее нет.
+func (r *Repository) CreateIfNeededBackfillsTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createBackfillsTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create migration_backfills table: %w", err)
+	}
+	return nil
+}
+
+const createSchemaSnapshotsTableQuery = `
+CREATE TABLE IF NOT EXISTS schema_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    target_id BIGINT NOT NULL REFERENCES targets (id) ON DELETE CASCADE,
+    action TEXT NOT NULL,
+    user_id BIGINT NOT NULL,
+    objects JSONB NOT NULL,
+    taken_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS schema_snapshots_target_id_idx ON schema_snapshots (target_id, id);
+`
+
+// CreateIfNeededSchemaSnapshotsTable создает таблицу снимков схем целевых баз данных, если ее нет.
+func (r *Repository) CreateIfNeededSchemaSnapshotsTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createSchemaSnapshotsTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_snapshots table: %w", err)
+	}
+	return nil
+}
+
+const createAuditLogTableQuery = `
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT NOT NULL DEFAULT 0,
+    action TEXT NOT NULL,
+    resource TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    outcome TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor_id, id);
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
+`
+
+// CreateIfNeededAuditLogTable создает журнал аудита вызовов API, если его нет.
+// Изменение и удаление записей журнала запрещены триггерами.
+func (r *Repository) CreateIfNeededAuditLogTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createAuditLogTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create audit_log table: %w", err)
+	}
+	return nil
+}
+
+const createIdempotencyKeysTableQuery = `
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id BIGINT NOT NULL,
+    method TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    response BYTEA,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (user_id, method, key)
+);
+CREATE INDEX IF NOT EXISTS idempotency_keys_created_at_idx ON idempotency_keys (created_at);
+`
+
+// CreateIfNeededIdempotencyKeysTable создает таблицу ключей идемпотентности, если ее нет.
+func (r *Repository) CreateIfNeededIdempotencyKeysTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, createIdempotencyKeysTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create idempotency_keys table: %w", err)
+	}
 	return nil
 }
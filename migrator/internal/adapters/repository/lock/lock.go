@@ -0,0 +1,174 @@
+// Package lock реализует адаптер для хранения блокировок целевых баз данных.
+//
+// Блокировки хранятся в общей для всех реплик базе данных сервиса,
+// поэтому исключают одновременное применение миграций несколькими репликами.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// Excecutor - интерфейс для выполнения запросов на базе данных.
+type Excecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+type Repository struct {
+	conn Excecutor
+}
+
+func New(conn Excecutor) *Repository {
+	return &Repository{
+		conn: conn,
+	}
+}
+
+const tryAcquireQuery = `-- TryAcquire
+	INSERT INTO migration_locks (target_id, holder, token, operation, acquired_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5, now() + $6 * INTERVAL '1 millisecond')
+	ON CONFLICT (target_id) DO UPDATE
+	SET holder = EXCLUDED.holder,
+		token = EXCLUDED.token,
+		operation = EXCLUDED.operation,
+		acquired_at = EXCLUDED.acquired_at,
+		expires_at = EXCLUDED.expires_at
+	WHERE migration_locks.expires_at < now()
+`
+
+// TryAcquire захватывает блокировку целевой базы данных на время lease, если
+// она свободна или срок блокировки другой реплики истек. Срок отсчитывается
+// по часам базы данных сервиса, общим для всех реплик.
+// Возвращает false, если блокировка уже удерживается.
+func (r *Repository) TryAcquire(ctx context.Context, lock entity.Lock, lease time.Duration) (bool, error) {
+	tag, err := r.conn.Exec(ctx, tryAcquireQuery, lock.TargetID, lock.Holder, lock.Token, lock.Operation, lock.AcquiredAt, lease.Milliseconds())
+	if err != nil {
+		return false, fmt.Errorf("try acquire lock: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+const renewQuery = `-- Renew
+	UPDATE migration_locks
+	SET expires_at = now() + $3 * INTERVAL '1 millisecond'
+	WHERE target_id = $1 AND token = $2 AND expires_at >= now()
+`
+
+// Renew продлевает блокировку, захваченную с указанным токеном, на время lease.
+// Возвращает false, если блокировка больше не удерживается: ее срок истек
+// или она освобождена принудительно.
+func (r *Repository) Renew(ctx context.Context, targetID int64, token string, lease time.Duration) (bool, error) {
+	tag, err := r.conn.Exec(ctx, renewQuery, targetID, token, lease.Milliseconds())
+	if err != nil {
+		return false, fmt.Errorf("renew lock: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+const releaseQuery = `-- Release
+	DELETE FROM migration_locks
+	WHERE target_id = $1 AND token = $2
+`
+
+// Release освобождает блокировку, захваченную с указанным токеном.
+func (r *Repository) Release(ctx context.Context, targetID int64, token string) error {
+	_, err := r.conn.Exec(ctx, releaseQuery, targetID, token)
+	if err != nil {
+		return fmt.Errorf("release lock: %w", err)
+	}
+	return nil
+}
+
+const forceReleaseQuery = `-- ForceRelease
+	DELETE FROM migration_locks
+	WHERE target_id = $1
+`
+
+// ForceRelease освобождает блокировку целевой базы данных независимо от того, кто ее удерживает.
+func (r *Repository) ForceRelease(ctx context.Context, targetID int64) error {
+	tag, err := r.conn.Exec(ctx, forceReleaseQuery, targetID)
+	if err != nil {
+		return fmt.Errorf("force release lock: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("force release lock on target %d: %w", targetID, entity.ErrNotFound)
+	}
+	return nil
+}
+
+const getQuery = `-- Get
+	SELECT target_id, holder, token, operation, acquired_at, expires_at
+	FROM migration_locks
+	WHERE target_id = $1
+`
+
+func (r *Repository) Get(ctx context.Context, targetID int64) (entity.Lock, error) {
+	var lock entity.Lock
+	err := r.conn.QueryRow(ctx, getQuery, targetID).
+		Scan(
+			&lock.TargetID,
+			&lock.Holder,
+			&lock.Token,
+			&lock.Operation,
+			&lock.AcquiredAt,
+			&lock.ExpiresAt,
+		)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Lock{}, fmt.Errorf("get lock on target %d: %w", targetID, entity.ErrNotFound)
+		}
+		return entity.Lock{}, fmt.Errorf("get lock: %w", err)
+	}
+	return lock, nil
+}
+
+const listQuery = `-- List
+	SELECT target_id, holder, token, operation, acquired_at, expires_at
+	FROM migration_locks
+	ORDER BY acquired_at
+`
+
+func (r *Repository) List(ctx context.Context) ([]entity.Lock, error) {
+	rows, err := r.conn.Query(ctx, listQuery)
+	if err != nil {
+		return nil, fmt.Errorf("list locks: %w", err)
+	}
+	defer rows.Close()
+
+	var locks []entity.Lock
+	for rows.Next() {
+		var lock entity.Lock
+		err := rows.Scan(
+			&lock.TargetID,
+			&lock.Holder,
+			&lock.Token,
+			&lock.Operation,
+			&lock.AcquiredAt,
+			&lock.ExpiresAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan lock: %w", err)
+		}
+		locks = append(locks, lock)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return locks, nil
+}
@@ -1,11 +1,17 @@
 // Package migration реализует адаптер для работы с репозиторием миграций в базе данных.
+//
+// Метаданные миграций хранятся в собственной базе данных сервиса (PostgreSQL),
+// а скрипты выполняются на выбранной целевой базе данных через пакет targetdb.
 package migration
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"migrator/internal/adapters/targetdb"
 	"migrator/internal/entity"
 	"migrator/pkg/logger"
 
@@ -25,69 +31,168 @@ type Excecutor interface {
 	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
 }
 
+// targetPools - источник пулов подключений к целевым базам данных.
+type targetPools interface {
+	DB(ctx context.Context, targetID int64) (targetdb.DB, error)
+}
+
 type (
-	txKey struct{}
+	txKey       struct{}
+	targetTxKey struct{}
 )
 
+var errNoTargetTx = errors.New("no target transaction in context")
+
 type Repository struct {
-	conn Excecutor
+	conn        Excecutor
+	targets     targetPools
+	tenantQuery string
 }
 
-func New(conn Excecutor) *Repository {
+// New - конструктор репозитория миграций. tenantQuery - запрос, возвращающий
+// схемы арендаторов целевой базы данных.
+func New(conn Excecutor, targets targetPools, tenantQuery string) *Repository {
 	return &Repository{
-		conn: conn,
+		conn:        conn,
+		targets:     targets,
+		tenantQuery: tenantQuery,
 	}
 }
 
+const migrationColumns = `
+		id,
+		target_id,
+		name,
+		description,
+		script,
+		rollback_script,
+		COALESCE(checksum, ''),
+		COALESCE(applied_checksum, ''),
+		execution_mode,
+		kind,
+		COALESCE(backfill::text, ''),
+		COALESCE(last_error::text, ''),
+		status,
+		created_by,
+		labels::text,
+		tenant_scoped,
+		templated,
+		baselined,
+		COALESCE(baselined_by, 0),
+		status_updated_at`
+
+func scanMigration(row pgx.Row) (entity.MigrationInfo, error) {
+	var (
+		migration entity.MigrationInfo
+		lastError string
+		labels    string
+		backfill  string
+	)
+	err := row.Scan(
+		&migration.ID,
+		&migration.TargetID,
+		&migration.Name,
+		&migration.Description,
+		&migration.Script,
+		&migration.RollbackScript,
+		&migration.Checksum,
+		&migration.AppliedChecksum,
+		&migration.ExecutionMode,
+		&migration.Kind,
+		&backfill,
+		&lastError,
+		&migration.Status,
+		&migration.CreatedBy,
+		&labels,
+		&migration.TenantScoped,
+		&migration.Templated,
+		&migration.Baselined,
+		&migration.BaselinedBy,
+		&migration.StatusUpdatedAt,
+	)
+	if err != nil {
+		return migration, err
+	}
+
+	if err := json.Unmarshal([]byte(labels), &migration.Labels); err != nil {
+		return migration, fmt.Errorf("unmarshal labels: %w", err)
+	}
+
+	if backfill != "" {
+		migration.Backfill = &entity.BackfillSpec{}
+		if err := json.Unmarshal([]byte(backfill), migration.Backfill); err != nil {
+			return migration, fmt.Errorf("unmarshal backfill: %w", err)
+		}
+	}
+
+	if lastError != "" {
+		migration.LastError = &entity.ScriptError{}
+		if err := json.Unmarshal([]byte(lastError), migration.LastError); err != nil {
+			return migration, fmt.Errorf("unmarshal last error: %w", err)
+		}
+	}
+
+	return migration, nil
+}
+
 const getQuery = `-- Get
-	SELECT 
-		id, 
-		name, 
-		description, 
-		script, 
-		rollback_script, 
-		status, 
-		created_by, 
-		status_updated_at
+	SELECT` + migrationColumns + `
 	FROM migrations
 	WHERE id = $1
 `
 
 func (r *Repository) Get(ctx context.Context, migrationID int64) (entity.MigrationInfo, error) {
-	var migration entity.MigrationInfo
-	err := r.Do(ctx).QueryRow(ctx, getQuery, migrationID).
-		Scan(
-			&migration.ID,
-			&migration.Name,
-			&migration.Description,
-			&migration.Script,
-			&migration.RollbackScript,
-			&migration.Status,
-			&migration.CreatedBy,
-			&migration.StatusUpdatedAt,
-		)
+	migration, err := scanMigration(r.Do(ctx).QueryRow(ctx, getQuery, migrationID))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.MigrationInfo{}, fmt.Errorf("get migration %d: %w", migrationID, entity.ErrNotFound)
+		}
 		return entity.MigrationInfo{}, fmt.Errorf("get migration: %w", err)
 	}
 	return migration, nil
 }
 
 const createQuery = `-- Create
-	INSERT INTO migrations (name, description, script, rollback_script, created_by, status, created_at, status_updated_at)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+	INSERT INTO migrations (target_id, name, description, script, rollback_script, checksum, execution_mode, kind, backfill, labels, tenant_scoped, templated, created_by, status, created_at, status_updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $15)
 	RETURNING id
 `
 
-func (r *Repository) Create(ctx context.Context, name, description, script, rollbackScript string, userID int64) (int64, error) {
+func (r *Repository) Create(ctx context.Context, migration entity.MigrationInfo) (int64, error) {
+	labels := migration.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labelsJSON, err := json.Marshal(labels)
+	if err != nil {
+		return 0, fmt.Errorf("marshal labels: %w", err)
+	}
+
+	var backfill []byte
+	if migration.Backfill != nil {
+		backfill, err = json.Marshal(migration.Backfill)
+		if err != nil {
+			return 0, fmt.Errorf("marshal backfill: %w", err)
+		}
+	}
+
 	var id int64
-	err := r.Do(ctx).QueryRow(
+	err = r.Do(ctx).QueryRow(
 		ctx,
 		createQuery,
-		name,
-		description,
-		script,
-		rollbackScript,
-		userID,
+		migration.TargetID,
+		migration.Name,
+		migration.Description,
+		migration.Script,
+		migration.RollbackScript,
+		migration.Checksum,
+		migration.ExecutionMode,
+		migration.Kind,
+		backfill,
+		string(labelsJSON),
+		migration.TenantScoped,
+		migration.Templated,
+		migration.CreatedBy,
 		entity.StatusPending,
 		time.Now().UTC(),
 	).Scan(&id)
@@ -98,17 +203,155 @@ func (r *Repository) Create(ctx context.Context, name, description, script, roll
 	return id, nil
 }
 
+// Apply выполняет скрипт в транзакции целевой базы данных,
+// открытой DoInTransaction.
 func (r *Repository) Apply(ctx context.Context, script string) error {
-	_, err := r.Do(ctx).Exec(ctx, script)
+	tx, ok := ctx.Value(targetTxKey{}).(targetdb.Tx)
+	if !ok {
+		return fmt.Errorf("apply migration: %w", errNoTargetTx)
+	}
+
+	err := tx.Exec(ctx, script)
 	if err != nil {
 		return fmt.Errorf("apply migration: %w", err)
 	}
 	return nil
 }
 
+// TryApply выполняет скрипт в точке сохранения транзакции целевой базы данных,
+// так что ошибка в скрипте не прерывает всю транзакцию.
+// Возвращает суммарное число строк, затронутых всеми операторами скрипта.
+func (r *Repository) TryApply(ctx context.Context, script string) (int64, error) {
+	tx, ok := ctx.Value(targetTxKey{}).(targetdb.Tx)
+	if !ok {
+		return 0, fmt.Errorf("try apply migration: %w", errNoTargetTx)
+	}
+
+	rowsAffected, err := tx.TryExec(ctx, script)
+	if err != nil {
+		return 0, fmt.Errorf("try apply migration: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// ApplyOutsideTransaction выполняет операторы скрипта по одному на отдельном подключении
+// к целевой базе данных вне транзакции, так что каждый оператор фиксируется сразу.
+// Возвращает число успешно выполненных операторов, в том числе при ошибке.
+func (r *Repository) ApplyOutsideTransaction(ctx context.Context, targetID int64, script string) (int, error) {
+	db, err := r.targets.DB(ctx, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("r.targets.DB: %w", err)
+	}
+
+	statements := db.Parse(script)
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("acquire target connection: %w", err)
+	}
+	defer conn.Release()
+
+	err = conn.Lock(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("lock target: %w", err)
+	}
+
+	for i, statement := range statements {
+		err := conn.Exec(ctx, script, statement)
+		if err != nil {
+			return i, fmt.Errorf("statement %d of %d: %w", i+1, len(statements), err)
+		}
+	}
+
+	return len(statements), nil
+}
+
+// CountStatements возвращает число операторов скрипта по правилам СУБД целевой базы данных.
+func (r *Repository) CountStatements(ctx context.Context, targetID int64, script string) (int, error) {
+	db, err := r.targets.DB(ctx, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("r.targets.DB: %w", err)
+	}
+
+	return len(db.Parse(script)), nil
+}
+
+// TransactionalDDL сообщает, откатывает ли СУБД целевой базы данных операторы DDL
+// вместе с транзакцией.
+func (r *Repository) TransactionalDDL(ctx context.Context, targetID int64) (bool, error) {
+	db, err := r.targets.DB(ctx, targetID)
+	if err != nil {
+		return false, fmt.Errorf("r.targets.DB: %w", err)
+	}
+
+	return db.TransactionalDDL(), nil
+}
+
+// RecordOnTarget записывает состояние миграции в таблицу истории целевой базы данных:
+// в транзакции целевой базы данных, если ее открыл DoInTransaction,
+// иначе на отдельном подключении.
+func (r *Repository) RecordOnTarget(ctx context.Context, migration entity.MigrationInfo, status entity.MigrationStatus, updatedAt time.Time) error {
+	record := targetdb.Record{
+		MigrationID: migration.ID,
+		Name:        migration.Name,
+		Checksum:    migration.Checksum,
+		Status:      status,
+		UpdatedAt:   updatedAt,
+	}
+
+	if tx, ok := ctx.Value(targetTxKey{}).(targetdb.Tx); ok {
+		if err := tx.Record(ctx, record); err != nil {
+			return fmt.Errorf("record on target: %w", err)
+		}
+		return nil
+	}
+
+	db, err := r.targets.DB(ctx, migration.TargetID)
+	if err != nil {
+		return fmt.Errorf("r.targets.DB: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire target connection: %w", err)
+	}
+	defer conn.Release()
+
+	if err := conn.Record(ctx, record); err != nil {
+		return fmt.Errorf("record on target: %w", err)
+	}
+	return nil
+}
+
+// RecordedOnTarget сообщает, отмечена ли миграция статусом status в таблице истории
+// целевой базы данных, открытой DoInTransaction, хотя в метаданных ее статус другой:
+// так бывает, если транзакция целевой базы данных зафиксирована, а транзакция
+// метаданных - нет. Запись считается записью этой миграции, только если совпадают
+// имя и контрольная сумма.
+func (r *Repository) RecordedOnTarget(ctx context.Context, migration entity.MigrationInfo, status entity.MigrationStatus) (bool, error) {
+	tx, ok := ctx.Value(targetTxKey{}).(targetdb.Tx)
+	if !ok {
+		return false, fmt.Errorf("read target history: %w", errNoTargetTx)
+	}
+
+	record, found, err := tx.ReadRecord(ctx, migration.ID)
+	if err != nil {
+		return false, fmt.Errorf("read target history: %w", err)
+	}
+
+	return found && record.Status == status &&
+		record.Name == migration.Name && record.Checksum == migration.Checksum, nil
+}
+
+// setStatusQuery снимает отметку baselined при любом изменении статуса, кроме применения:
+// после отката миграция применяется заново уже выполнением скрипта.
 const setStatusQuery = `-- SetStatus
 	UPDATE migrations
-	SET status = $1, status_updated_at = $2
+	SET status = $1,
+		status_updated_at = $2,
+		baselined = baselined AND $1 = 'applied',
+		baselined_by = CASE WHEN $1 = 'applied' THEN baselined_by END
 	WHERE id = $3
 `
 
@@ -120,23 +363,74 @@ func (r *Repository) SetStatus(ctx context.Context, migrationID int64, updatedAt
 	return nil
 }
 
+const baselineQuery = `-- Baseline
+	UPDATE migrations
+	SET status = $1,
+		status_updated_at = $2,
+		applied_checksum = checksum,
+		last_error = NULL,
+		baselined = true,
+		baselined_by = $3
+	WHERE id = $4
+`
+
+// Baseline отмечает миграцию примененной без выполнения ее скрипта
+// и записывает пользователя, который это сделал.
+func (r *Repository) Baseline(ctx context.Context, migrationID, userID int64, baselinedAt time.Time) error {
+	_, err := r.Do(ctx).Exec(ctx, baselineQuery, entity.StatusApplied, baselinedAt, userID, migrationID)
+	if err != nil {
+		return fmt.Errorf("baseline migration: %w", err)
+	}
+	return nil
+}
+
+const setAppliedChecksumQuery = `-- SetAppliedChecksum
+	UPDATE migrations
+	SET applied_checksum = $1
+	WHERE id = $2
+`
+
+func (r *Repository) SetAppliedChecksum(ctx context.Context, migrationID int64, checksum string) error {
+	_, err := r.Do(ctx).Exec(ctx, setAppliedChecksumQuery, checksum, migrationID)
+	if err != nil {
+		return fmt.Errorf("set applied checksum: %w", err)
+	}
+	return nil
+}
+
+const setLastErrorQuery = `-- SetLastError
+	UPDATE migrations
+	SET last_error = $1
+	WHERE id = $2
+`
+
+// SetLastError сохраняет ошибку последнего выполнения скрипта миграции; nil очищает ее.
+func (r *Repository) SetLastError(ctx context.Context, migrationID int64, scriptErr *entity.ScriptError) error {
+	var lastError []byte
+	if scriptErr != nil {
+		var err error
+		lastError, err = json.Marshal(scriptErr)
+		if err != nil {
+			return fmt.Errorf("marshal last error: %w", err)
+		}
+	}
+
+	_, err := r.Do(ctx).Exec(ctx, setLastErrorQuery, lastError, migrationID)
+	if err != nil {
+		return fmt.Errorf("set last error: %w", err)
+	}
+	return nil
+}
+
 const listQuery = `-- List
-	SELECT
-		id,
-		name,
-		description,
-		script,
-		rollback_script,
-		status,
-		created_by,
-		status_updated_at
+	SELECT` + migrationColumns + `
 	FROM migrations
-	WHERE status = $1 OR $1 = ''
+	WHERE target_id = $1 AND (status = $2 OR $2 = '')
 	ORDER BY id
 `
 
-func (r *Repository) List(ctx context.Context, statusFilter string) ([]entity.MigrationInfo, error) {
-	rows, err := r.Do(ctx).Query(ctx, listQuery, statusFilter)
+func (r *Repository) List(ctx context.Context, targetID int64, statusFilter string) ([]entity.MigrationInfo, error) {
+	rows, err := r.Do(ctx).Query(ctx, listQuery, targetID, statusFilter)
 	if err != nil {
 		return nil, fmt.Errorf("list migrations: %w", err)
 	}
@@ -144,17 +438,39 @@ func (r *Repository) List(ctx context.Context, statusFilter string) ([]entity.Mi
 
 	var migrations []entity.MigrationInfo
 	for rows.Next() {
-		var migration entity.MigrationInfo
-		err := rows.Scan(
-			&migration.ID,
-			&migration.Name,
-			&migration.Description,
-			&migration.Script,
-			&migration.RollbackScript,
-			&migration.Status,
-			&migration.CreatedBy,
-			&migration.StatusUpdatedAt,
-		)
+		migration, err := scanMigration(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan migration: %w", err)
+		}
+		migrations = append(migrations, migration)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return migrations, nil
+}
+
+const listAppliedAfterQuery = `-- ListAppliedAfter
+	SELECT` + migrationColumns + `
+	FROM migrations
+	WHERE target_id = $1 AND status = ANY($2) AND id > $3 AND kind = 'versioned'
+	ORDER BY id DESC
+`
+
+// ListAppliedAfter возвращает примененные (в том числе частично) версионные миграции
+// целевой базы данных новее указанной, начиная с последней примененной.
+func (r *Repository) ListAppliedAfter(ctx context.Context, targetID, migrationID int64) ([]entity.MigrationInfo, error) {
+	rows, err := r.Do(ctx).Query(ctx, listAppliedAfterQuery, targetID, appliedStatuses, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var migrations []entity.MigrationInfo
+	for rows.Next() {
+		migration, err := scanMigration(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan migration: %w", err)
 		}
@@ -169,34 +485,21 @@ func (r *Repository) List(ctx context.Context, statusFilter string) ([]entity.Mi
 }
 
 const getLatestAppliedMigrationQuery = `-- GetLatestAppliedMigration
-	SELECT
-		id,
-		name,
-		description,
-		script,
-		rollback_script,
-		status,
-		created_by,
-		status_updated_at
+	SELECT` + migrationColumns + `
 	FROM migrations
-	WHERE status = $1
+	WHERE target_id = $1 AND status = ANY($2) AND kind = 'versioned'
 	ORDER BY id DESC
 	LIMIT 1
 `
 
-func (r *Repository) GetLatestAppliedMigration(ctx context.Context) (entity.MigrationInfo, error) {
-	var migration entity.MigrationInfo
-	err := r.Do(ctx).QueryRow(ctx, getLatestAppliedMigrationQuery, entity.StatusApplied).
-		Scan(
-			&migration.ID,
-			&migration.Name,
-			&migration.Description,
-			&migration.Script,
-			&migration.RollbackScript,
-			&migration.Status,
-			&migration.CreatedBy,
-			&migration.StatusUpdatedAt,
-		)
+// appliedStatuses - статусы миграций, скрипты которых (полностью или частично)
+// выполнены на целевой базе данных.
+var appliedStatuses = []string{entity.StatusApplied.String(), entity.StatusPartiallyApplied.String()}
+
+// GetLatestAppliedMigration возвращает последнюю примененную (в том числе частично) версионную миграцию.
+// Повторяемые миграции не откатываются, поэтому не учитываются.
+func (r *Repository) GetLatestAppliedMigration(ctx context.Context, targetID int64) (entity.MigrationInfo, error) {
+	migration, err := scanMigration(r.Do(ctx).QueryRow(ctx, getLatestAppliedMigrationQuery, targetID, appliedStatuses))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return entity.MigrationInfo{}, nil
@@ -207,25 +510,74 @@ func (r *Repository) GetLatestAppliedMigration(ctx context.Context) (entity.Migr
 	return migration, nil
 }
 
-func (r *Repository) DoInTransaction(ctx context.Context, f func(ctx context.Context) error) error {
+// DoInTransaction выполняет f в паре транзакций: на базе данных сервиса
+// (метаданные миграций) и на целевой базе данных (скрипты миграций).
+// Транзакция целевой базы данных фиксируется первой, так как изменения
+// метаданных без примененного скрипта хуже, чем наоборот. Если после этого
+// не удается зафиксировать метаданные, миграция остается в прежнем статусе,
+// хотя ее скрипт выполнен, а таблица истории целевой базы данных отмечает ее
+// примененной: по этой записи (RecordedOnTarget) сервис при следующем применении
+// восстанавливает метаданные, не выполняя скрипт повторно. Транзакция целевой
+// базы данных удерживает блокировку на ее стороне, чтобы с ней одновременно
+// не работали экземпляры сервиса с другой базой метаданных.
+func (r *Repository) DoInTransaction(ctx context.Context, targetID int64, f func(ctx context.Context) error) error {
+	db, err := r.targets.DB(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("r.targets.DB: %w", err)
+	}
+
 	tx, err := r.Do(ctx).Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer rollback(ctx, tx)
+
+	targetTx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin target transaction: %w", err)
+	}
 	defer func() {
-		if err := tx.Rollback(ctx); err != nil {
-			logger.Error(fmt.Errorf("transaction rollback error: %w", err))
+		if err := targetTx.Rollback(ctx); err != nil {
+			logger.Error(fmt.Errorf("target transaction rollback error: %w", err))
 		}
 	}()
 
+	err = targetTx.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("lock target: %w", err)
+	}
+
 	ctx = context.WithValue(ctx, txKey{}, tx)
+	ctx = context.WithValue(ctx, targetTxKey{}, targetTx)
 
 	err = f(ctx)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit(ctx)
+	// Изменения фиксируются, только если блокировка целевой базы данных
+	// не потеряна за время выполнения, например после принудительного освобождения.
+	err = entity.CheckLock(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = targetTx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit target transaction: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit metadata transaction after the target transaction was committed: %w", err)
+	}
+	return nil
+}
+
+func rollback(ctx context.Context, tx pgx.Tx) {
+	if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		logger.Error(fmt.Errorf("transaction rollback error: %w", err))
+	}
 }
 
 func (r *Repository) Do(ctx context.Context) Excecutor {
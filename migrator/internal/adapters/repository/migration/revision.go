@@ -0,0 +1,101 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"migrator/internal/entity"
+)
+
+const updateScriptQuery = `-- UpdateScript
+	UPDATE migrations
+	SET script = $1,
+		checksum = $2,
+		status = CASE WHEN applied_checksum IS DISTINCT FROM $2 THEN $3 ELSE status END,
+		status_updated_at = CASE WHEN applied_checksum IS DISTINCT FROM $2 THEN $4 ELSE status_updated_at END
+	WHERE id = $5
+`
+
+// UpdateScript заменяет скрипт повторяемой миграции. Если скрипт отличается
+// от последнего выполненного, миграция снова ожидает применения.
+func (r *Repository) UpdateScript(ctx context.Context, migration entity.MigrationInfo) error {
+	_, err := r.Do(ctx).Exec(
+		ctx,
+		updateScriptQuery,
+		migration.Script,
+		migration.Checksum,
+		entity.StatusPending,
+		migration.StatusUpdatedAt.UTC(),
+		migration.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update script: %w", err)
+	}
+	return nil
+}
+
+const addRevisionQuery = `-- AddRevision
+	INSERT INTO migration_revisions (migration_id, revision, checksum, script, applied_by, applied_at)
+	SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5
+	FROM migration_revisions
+	WHERE migration_id = $1
+	RETURNING revision
+`
+
+// AddRevision записывает выполненную ревизию повторяемой миграции
+// и возвращает ее порядковый номер.
+func (r *Repository) AddRevision(ctx context.Context, revision entity.MigrationRevision) (int64, error) {
+	var number int64
+	err := r.Do(ctx).QueryRow(
+		ctx,
+		addRevisionQuery,
+		revision.MigrationID,
+		revision.Checksum,
+		revision.Script,
+		revision.AppliedBy,
+		revision.AppliedAt.UTC(),
+	).Scan(&number)
+	if err != nil {
+		return 0, fmt.Errorf("add revision: %w", err)
+	}
+	return number, nil
+}
+
+const listRevisionsQuery = `-- ListRevisions
+	SELECT migration_id, revision, checksum, script, applied_by, applied_at
+	FROM migration_revisions
+	WHERE migration_id = $1
+	ORDER BY revision DESC
+`
+
+// ListRevisions возвращает выполненные ревизии повторяемой миграции, начиная с последней.
+func (r *Repository) ListRevisions(ctx context.Context, migrationID int64) ([]entity.MigrationRevision, error) {
+	rows, err := r.Do(ctx).Query(ctx, listRevisionsQuery, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("list revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []entity.MigrationRevision
+	for rows.Next() {
+		var revision entity.MigrationRevision
+		err := rows.Scan(
+			&revision.MigrationID,
+			&revision.Revision,
+			&revision.Checksum,
+			&revision.Script,
+			&revision.AppliedBy,
+			&revision.AppliedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,165 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"migrator/internal/adapters/targetdb"
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+)
+
+// tenantDB возвращает целевую базу данных со схемами арендаторов.
+func (r *Repository) tenantDB(ctx context.Context, targetID int64) (targetdb.TenantDB, error) {
+	db, err := r.targets.DB(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("r.targets.DB: %w", err)
+	}
+
+	tenantDB, ok := db.(targetdb.TenantDB)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s target databases have no tenant schemas", entity.ErrInvalidArgument, db.Driver())
+	}
+
+	return tenantDB, nil
+}
+
+// ListTenants возвращает схемы арендаторов целевой базы данных, найденные настроенным запросом.
+func (r *Repository) ListTenants(ctx context.Context, targetID int64) ([]string, error) {
+	db, err := r.tenantDB(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, err := db.Tenants(ctx, r.tenantQuery)
+	if err != nil {
+		return nil, fmt.Errorf("list tenants: %w", err)
+	}
+
+	return tenants, nil
+}
+
+// LockTarget захватывает блокировку целевой базы данных на отдельном подключении
+// на время применения миграции к схемам арендаторов, которые выполняются
+// в собственных транзакциях. Возвращает функцию освобождения блокировки.
+func (r *Repository) LockTarget(ctx context.Context, targetID int64) (func(), error) {
+	db, err := r.targets.DB(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("r.targets.DB: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire target connection: %w", err)
+	}
+
+	if err := conn.Lock(ctx); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("lock target: %w", err)
+	}
+
+	return conn.Release, nil
+}
+
+// ApplyToTenant выполняет скрипт миграции в схеме арендатора в отдельной транзакции
+// и отражает применение в таблице истории этой схемы.
+func (r *Repository) ApplyToTenant(ctx context.Context, migration entity.MigrationInfo, tenant, script string) error {
+	db, err := r.tenantDB(ctx, migration.TargetID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTenant(ctx, tenant)
+	if err != nil {
+		return fmt.Errorf("begin tenant transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil {
+			logger.Error(fmt.Errorf("tenant transaction rollback error: %w", err))
+		}
+	}()
+
+	if err := tx.Exec(ctx, script); err != nil {
+		return fmt.Errorf("apply migration: %w", err)
+	}
+
+	record := targetdb.Record{
+		MigrationID: migration.ID,
+		Name:        migration.Name,
+		Checksum:    migration.Checksum,
+		Status:      entity.StatusApplied,
+		UpdatedAt:   time.Now(),
+	}
+	if err := tx.Record(ctx, record); err != nil {
+		return fmt.Errorf("record on tenant: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tenant transaction: %w", err)
+	}
+
+	return nil
+}
+
+const setTenantStatusQuery = `-- SetTenantStatus
+	INSERT INTO migration_tenants (migration_id, tenant, status, error, updated_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (migration_id, tenant) DO UPDATE
+	SET status = EXCLUDED.status, error = EXCLUDED.error, updated_at = EXCLUDED.updated_at
+`
+
+// SetTenantStatus сохраняет состояние миграции в схеме арендатора.
+func (r *Repository) SetTenantStatus(ctx context.Context, status entity.TenantStatus) error {
+	_, err := r.Do(ctx).Exec(
+		ctx,
+		setTenantStatusQuery,
+		status.MigrationID,
+		status.Tenant,
+		status.Status,
+		status.Error,
+		status.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("set tenant status: %w", err)
+	}
+	return nil
+}
+
+const listTenantStatusesQuery = `-- ListTenantStatuses
+	SELECT migration_id, tenant, status, error, updated_at
+	FROM migration_tenants
+	WHERE migration_id = $1
+	ORDER BY tenant
+`
+
+// ListTenantStatuses возвращает состояния миграции в схемах арендаторов.
+func (r *Repository) ListTenantStatuses(ctx context.Context, migrationID int64) ([]entity.TenantStatus, error) {
+	rows, err := r.Do(ctx).Query(ctx, listTenantStatusesQuery, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("list tenant statuses: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []entity.TenantStatus
+	for rows.Next() {
+		var status entity.TenantStatus
+		err := rows.Scan(
+			&status.MigrationID,
+			&status.Tenant,
+			&status.Status,
+			&status.Error,
+			&status.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan tenant status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,126 @@
+// Package schema реализует адаптер для чтения схем целевых баз данных
+// и хранения их снимков в базе данных сервиса.
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"migrator/internal/adapters/targetdb"
+	"migrator/internal/entity"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// Excecutor - интерфейс для выполнения запросов на базе данных.
+type Excecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// targetPools - источник пулов подключений к целевым базам данных.
+type targetPools interface {
+	DB(ctx context.Context, targetID int64) (targetdb.DB, error)
+}
+
+type Repository struct {
+	conn    Excecutor
+	targets targetPools
+}
+
+func New(conn Excecutor, targets targetPools) *Repository {
+	return &Repository{
+		conn:    conn,
+		targets: targets,
+	}
+}
+
+// Introspect читает текущую схему целевой базы данных из ее системного каталога.
+func (r *Repository) Introspect(ctx context.Context, targetID int64) ([]entity.SchemaObject, error) {
+	db, err := r.targets.DB(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("r.targets.DB: %w", err)
+	}
+
+	objects, err := db.Schema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("introspect target %d: %w", targetID, err)
+	}
+
+	return objects, nil
+}
+
+const addSnapshotQuery = `-- AddSnapshot
+	INSERT INTO schema_snapshots (target_id, action, user_id, objects, taken_at)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id
+`
+
+// AddSnapshot сохраняет снимок схемы целевой базы данных.
+func (r *Repository) AddSnapshot(ctx context.Context, snapshot entity.SchemaSnapshot) (int64, error) {
+	objects, err := json.Marshal(snapshot.Objects)
+	if err != nil {
+		return 0, fmt.Errorf("marshal schema objects: %w", err)
+	}
+
+	var id int64
+	err = r.conn.QueryRow(
+		ctx,
+		addSnapshotQuery,
+		snapshot.TargetID,
+		snapshot.Action,
+		snapshot.UserID,
+		objects,
+		snapshot.TakenAt.UTC(),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("add schema snapshot: %w", err)
+	}
+
+	return id, nil
+}
+
+const getLatestSnapshotQuery = `-- GetLatestSnapshot
+	SELECT id, target_id, action, user_id, objects, taken_at
+	FROM schema_snapshots
+	WHERE target_id = $1
+	ORDER BY id DESC
+	LIMIT 1
+`
+
+// GetLatestSnapshot возвращает последний снимок схемы целевой базы данных.
+func (r *Repository) GetLatestSnapshot(ctx context.Context, targetID int64) (entity.SchemaSnapshot, error) {
+	var (
+		snapshot entity.SchemaSnapshot
+		objects  []byte
+	)
+	err := r.conn.QueryRow(ctx, getLatestSnapshotQuery, targetID).Scan(
+		&snapshot.ID,
+		&snapshot.TargetID,
+		&snapshot.Action,
+		&snapshot.UserID,
+		&objects,
+		&snapshot.TakenAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.SchemaSnapshot{}, fmt.Errorf("schema snapshot of target %d: %w", targetID, entity.ErrNotFound)
+		}
+		return entity.SchemaSnapshot{}, fmt.Errorf("get schema snapshot: %w", err)
+	}
+
+	if err := json.Unmarshal(objects, &snapshot.Objects); err != nil {
+		return entity.SchemaSnapshot{}, fmt.Errorf("unmarshal schema objects: %w", err)
+	}
+
+	return snapshot, nil
+}
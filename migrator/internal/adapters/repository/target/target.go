@@ -0,0 +1,216 @@
+// Package target реализует адаптер для работы с реестром целевых баз данных.
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+)
+
+// Excecutor - интерфейс для выполнения запросов на базе данных.
+type Excecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+type Repository struct {
+	conn Excecutor
+}
+
+func New(conn Excecutor) *Repository {
+	return &Repository{
+		conn: conn,
+	}
+}
+
+const createQuery = `-- Create
+	INSERT INTO targets (name, description, url, environment_id, variables, created_by, created_at, updated_at)
+	VALUES ($1, $2, $3, NULLIF($4::BIGINT, 0), $5, $6, $7, $7)
+	RETURNING id
+`
+
+func (r *Repository) Create(ctx context.Context, name, description, url string, environmentID int64, variables map[string]string, userID int64) (int64, error) {
+	variablesJSON, err := marshalVariables(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err = r.conn.QueryRow(ctx, createQuery, name, description, url, environmentID, variablesJSON, userID, time.Now().UTC()).Scan(&id)
+	if err != nil {
+		if isPgError(err, uniqueViolationCode) {
+			return 0, fmt.Errorf("create target: %w", entity.ErrAlreadyExists)
+		}
+		if isPgError(err, foreignKeyViolationCode) {
+			return 0, fmt.Errorf("create target: environment %d: %w", environmentID, entity.ErrNotFound)
+		}
+		return 0, fmt.Errorf("create target: %w", err)
+	}
+	return id, nil
+}
+
+const targetColumns = `
+		id,
+		name,
+		description,
+		url,
+		COALESCE(environment_id, 0),
+		variables::text,
+		created_by,
+		created_at,
+		updated_at`
+
+func scanTarget(row pgx.Row) (entity.Target, error) {
+	var (
+		target    entity.Target
+		variables string
+	)
+	err := row.Scan(
+		&target.ID,
+		&target.Name,
+		&target.Description,
+		&target.URL,
+		&target.EnvironmentID,
+		&variables,
+		&target.CreatedBy,
+		&target.CreatedAt,
+		&target.UpdatedAt,
+	)
+	if err != nil {
+		return target, err
+	}
+
+	if err := json.Unmarshal([]byte(variables), &target.Variables); err != nil {
+		return target, fmt.Errorf("unmarshal variables: %w", err)
+	}
+
+	return target, nil
+}
+
+const getQuery = `-- Get
+	SELECT` + targetColumns + `
+	FROM targets
+	WHERE id = $1
+`
+
+func (r *Repository) Get(ctx context.Context, targetID int64) (entity.Target, error) {
+	target, err := scanTarget(r.conn.QueryRow(ctx, getQuery, targetID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Target{}, fmt.Errorf("get target %d: %w", targetID, entity.ErrNotFound)
+		}
+		return entity.Target{}, fmt.Errorf("get target: %w", err)
+	}
+	return target, nil
+}
+
+const listQuery = `-- List
+	SELECT` + targetColumns + `
+	FROM targets
+	ORDER BY id
+`
+
+func (r *Repository) List(ctx context.Context) ([]entity.Target, error) {
+	rows, err := r.conn.Query(ctx, listQuery)
+	if err != nil {
+		return nil, fmt.Errorf("list targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []entity.Target
+	for rows.Next() {
+		target, err := scanTarget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan target: %w", err)
+		}
+		targets = append(targets, target)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return targets, nil
+}
+
+const updateQuery = `-- Update
+	UPDATE targets
+	SET name = $1, description = $2, url = $3, environment_id = NULLIF($4::BIGINT, 0), variables = $5, updated_at = $6
+	WHERE id = $7
+`
+
+func (r *Repository) Update(ctx context.Context, targetID int64, name, description, url string, environmentID int64, variables map[string]string) error {
+	variablesJSON, err := marshalVariables(variables)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.conn.Exec(ctx, updateQuery, name, description, url, environmentID, variablesJSON, time.Now().UTC(), targetID)
+	if err != nil {
+		if isPgError(err, uniqueViolationCode) {
+			return fmt.Errorf("update target: %w", entity.ErrAlreadyExists)
+		}
+		if isPgError(err, foreignKeyViolationCode) {
+			return fmt.Errorf("update target: environment %d: %w", environmentID, entity.ErrNotFound)
+		}
+		return fmt.Errorf("update target: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("update target %d: %w", targetID, entity.ErrNotFound)
+	}
+	return nil
+}
+
+const deleteQuery = `-- Delete
+	DELETE FROM targets
+	WHERE id = $1
+`
+
+func (r *Repository) Delete(ctx context.Context, targetID int64) error {
+	tag, err := r.conn.Exec(ctx, deleteQuery, targetID)
+	if err != nil {
+		if isPgError(err, foreignKeyViolationCode) {
+			return fmt.Errorf("delete target %d: %w", targetID, entity.ErrTargetInUse)
+		}
+		return fmt.Errorf("delete target: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("delete target %d: %w", targetID, entity.ErrNotFound)
+	}
+	return nil
+}
+
+// marshalVariables сериализует переменные шаблонов для колонки JSONB.
+func marshalVariables(variables map[string]string) (string, error) {
+	if variables == nil {
+		variables = map[string]string{}
+	}
+	variablesJSON, err := json.Marshal(variables)
+	if err != nil {
+		return "", fmt.Errorf("marshal variables: %w", err)
+	}
+	return string(variablesJSON), nil
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,49 @@
+package targetdb
+
+import "time"
+
+const (
+	_defaultMaxPoolSize  = 1
+	_defaultConnAttempts = 10
+	_defaultConnTimeout  = time.Second
+	_defaultLockTimeout  = 30 * time.Second
+)
+
+type options struct {
+	maxPoolSize  int
+	connAttempts int
+	connTimeout  time.Duration
+	lockTimeout  time.Duration
+}
+
+// Option -.
+type Option func(*options)
+
+// MaxPoolSize -.
+func MaxPoolSize(size int) Option {
+	return func(o *options) {
+		o.maxPoolSize = size
+	}
+}
+
+// ConnAttempts -.
+func ConnAttempts(attempts int) Option {
+	return func(o *options) {
+		o.connAttempts = attempts
+	}
+}
+
+// ConnTimeout -.
+func ConnTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.connTimeout = timeout
+	}
+}
+
+// LockTimeout - сколько ждать блокировку целевой базы данных, занятую
+// экземпляром сервиса с другой базой метаданных.
+func LockTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.lockTimeout = timeout
+	}
+}
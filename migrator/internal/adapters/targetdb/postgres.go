@@ -0,0 +1,403 @@
+package targetdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+	"migrator/pkg/postgres"
+	"migrator/pkg/sqlscript"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const pgHistoryDDL = `
+	CREATE TABLE IF NOT EXISTS ` + HistoryTable + ` (
+		migration_id BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		status TEXT NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	)
+`
+
+const pgHistoryUpsert = `
+	INSERT INTO ` + HistoryTable + ` (migration_id, name, checksum, status, updated_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (migration_id) DO UPDATE
+	SET name = EXCLUDED.name, checksum = EXCLUDED.checksum, status = EXCLUDED.status, updated_at = EXCLUDED.updated_at
+`
+
+const pgHistorySelect = `
+	SELECT name, checksum, status FROM ` + HistoryTable + ` WHERE migration_id = $1
+`
+
+// pgUserSchema - условие на схему пользовательских объектов: системные схемы
+// PostgreSQL и временные схемы сессий не включаются в снимок.
+const pgUserSchema = `n.nspname <> 'information_schema' AND n.nspname NOT LIKE 'pg\_%'`
+
+// pgSchemaQuery - объекты схемы из pg_catalog. Функции расширений не включаются:
+// они устанавливаются и обновляются вместе с расширением.
+const pgSchemaQuery = `
+	SELECT CASE WHEN c.relkind IN ('v', 'm') THEN 'view' ELSE 'table' END,
+		n.nspname || '.' || c.relname,
+		CASE WHEN c.relkind IN ('v', 'm') THEN pg_get_viewdef(c.oid) ELSE c.relkind::text END
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f') AND ` + pgUserSchema + ` AND c.relname <> '` + HistoryTable + `'
+	UNION ALL
+	SELECT 'column',
+		n.nspname || '.' || c.relname || '.' || a.attname,
+		format_type(a.atttypid, a.atttypmod)
+			|| CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END
+			|| COALESCE(' DEFAULT ' || pg_get_expr(d.adbin, d.adrelid), '')
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	WHERE a.attnum > 0 AND NOT a.attisdropped
+		AND c.relkind IN ('r', 'p', 'v', 'm', 'f') AND ` + pgUserSchema + ` AND c.relname <> '` + HistoryTable + `'
+	UNION ALL
+	SELECT 'index', n.nspname || '.' || i.relname, pg_get_indexdef(i.oid)
+	FROM pg_index x
+	JOIN pg_class i ON i.oid = x.indexrelid
+	JOIN pg_class t ON t.oid = x.indrelid
+	JOIN pg_namespace n ON n.oid = i.relnamespace
+	WHERE ` + pgUserSchema + ` AND t.relname <> '` + HistoryTable + `'
+	UNION ALL
+	SELECT 'constraint', n.nspname || '.' || t.relname || '.' || con.conname, pg_get_constraintdef(con.oid)
+	FROM pg_constraint con
+	JOIN pg_class t ON t.oid = con.conrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE ` + pgUserSchema + ` AND t.relname <> '` + HistoryTable + `'
+	UNION ALL
+	SELECT 'function',
+		n.nspname || '.' || p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')',
+		pg_get_functiondef(p.oid)
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	WHERE p.prokind IN ('f', 'p') AND ` + pgUserSchema + `
+		AND NOT EXISTS (SELECT 1 FROM pg_depend dep WHERE dep.objid = p.oid AND dep.deptype = 'e')
+	UNION ALL
+	SELECT 'trigger', n.nspname || '.' || t.relname || '.' || tg.tgname, pg_get_triggerdef(tg.oid)
+	FROM pg_trigger tg
+	JOIN pg_class t ON t.oid = tg.tgrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE NOT tg.tgisinternal AND ` + pgUserSchema + `
+`
+
+// pgDB - целевая база данных PostgreSQL.
+type pgDB struct {
+	pg          *postgres.Postgres
+	lockTimeout time.Duration
+}
+
+func openPostgres(url string, o options) (DB, error) {
+	pg, err := postgres.New(
+		url,
+		postgres.MaxPoolSize(o.maxPoolSize),
+		postgres.ConnAttempts(o.connAttempts),
+		postgres.ConnTimeout(o.connTimeout),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &pgDB{pg: pg, lockTimeout: o.lockTimeout}, nil
+}
+
+func (d *pgDB) Driver() entity.Driver {
+	return entity.DriverPostgres
+}
+
+func (d *pgDB) TransactionalDDL() bool {
+	return true
+}
+
+func (d *pgDB) Parse(script string) []sqlscript.Statement {
+	return sqlscript.Parse(script)
+}
+
+func (d *pgDB) Begin(ctx context.Context) (Tx, error) {
+	tx, err := d.pg.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pgTx{tx: tx, lockTimeout: d.lockTimeout}, nil
+}
+
+func (d *pgDB) Conn(ctx context.Context) (Conn, error) {
+	conn, err := d.pg.Pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pgConn{conn: conn, lockTimeout: d.lockTimeout}, nil
+}
+
+func (d *pgDB) Tenants(ctx context.Context, query string) ([]string, error) {
+	rows, err := d.pg.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenants []string
+	for rows.Next() {
+		var tenant string
+		if err := rows.Scan(&tenant); err != nil {
+			return nil, fmt.Errorf("scan tenant: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return tenants, nil
+}
+
+func (d *pgDB) BeginTenant(ctx context.Context, tenant string) (Tx, error) {
+	tx, err := d.pg.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Схема передается параметром и экранируется на стороне сервера
+	_, err = tx.Exec(ctx, "SELECT set_config('search_path', quote_ident($1), true)", tenant)
+	if err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			logger.Error(fmt.Errorf("tenant transaction rollback error: %w", rbErr))
+		}
+		return nil, fmt.Errorf("set search_path to %s: %w", tenant, err)
+	}
+
+	return &pgTx{tx: tx, lockTimeout: d.lockTimeout}, nil
+}
+
+func (d *pgDB) Schema(ctx context.Context) ([]entity.SchemaObject, error) {
+	rows, err := d.pg.Pool.Query(ctx, pgSchemaQuery)
+	if err != nil {
+		return nil, fmt.Errorf("query schema: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []entity.SchemaObject
+	for rows.Next() {
+		var object entity.SchemaObject
+		if err := rows.Scan(&object.Kind, &object.Name, &object.Definition); err != nil {
+			return nil, fmt.Errorf("scan schema object: %w", err)
+		}
+		objects = append(objects, object)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	entity.SortSchemaObjects(objects)
+	return objects, nil
+}
+
+func (d *pgDB) Close() {
+	d.pg.Close()
+}
+
+type pgTx struct {
+	tx          pgx.Tx
+	lockTimeout time.Duration
+}
+
+func (t *pgTx) Lock(ctx context.Context) error {
+	return waitLock(ctx, t.lockTimeout, func(ctx context.Context) error {
+		_, err := t.tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", lockName)
+		if err != nil {
+			return fmt.Errorf("pg_advisory_xact_lock: %w", err)
+		}
+		return nil
+	})
+}
+
+func (t *pgTx) Exec(ctx context.Context, script string) error {
+	_, err := t.tx.Exec(ctx, script)
+	if err != nil {
+		return pgScriptError(script, sqlscript.Statement{}, err)
+	}
+	return nil
+}
+
+func (t *pgTx) QueryColumn(ctx context.Context, query string) ([]string, error) {
+	rows, err := t.tx.Query(ctx, query)
+	if err != nil {
+		return nil, pgScriptError(query, sqlscript.Statement{}, err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		row, err := rows.Values()
+		if err != nil {
+			return nil, fmt.Errorf("read row: %w", err)
+		}
+		if len(row) == 0 {
+			return nil, fmt.Errorf("query returns no columns")
+		}
+		values = append(values, columnText(row[0]))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pgScriptError(query, sqlscript.Statement{}, err)
+	}
+
+	return values, nil
+}
+
+func (t *pgTx) TryExec(ctx context.Context, script string) (int64, error) {
+	savepoint, err := t.tx.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin savepoint: %w", err)
+	}
+	defer func() {
+		if err := savepoint.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.Error(fmt.Errorf("savepoint rollback error: %w", err))
+		}
+	}()
+
+	results, err := savepoint.Conn().PgConn().Exec(ctx, script).ReadAll()
+	if err != nil {
+		return 0, pgScriptError(script, sqlscript.Statement{}, err)
+	}
+
+	var rowsAffected int64
+	for _, result := range results {
+		rowsAffected += result.CommandTag.RowsAffected()
+	}
+
+	err = savepoint.Commit(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("release savepoint: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func (t *pgTx) Record(ctx context.Context, record Record) error {
+	if _, err := t.tx.Exec(ctx, pgHistoryDDL); err != nil {
+		return fmt.Errorf("create history table: %w", err)
+	}
+	_, err := t.tx.Exec(ctx, pgHistoryUpsert, record.MigrationID, record.Name, record.Checksum, string(record.Status), record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+	return nil
+}
+
+func (t *pgTx) ReadRecord(ctx context.Context, migrationID int64) (Record, bool, error) {
+	if _, err := t.tx.Exec(ctx, pgHistoryDDL); err != nil {
+		return Record{}, false, fmt.Errorf("create history table: %w", err)
+	}
+
+	record := Record{MigrationID: migrationID}
+	var status string
+	err := t.tx.QueryRow(ctx, pgHistorySelect, migrationID).Scan(&record.Name, &record.Checksum, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Record{}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, fmt.Errorf("read history: %w", err)
+	}
+	record.Status = entity.MigrationStatus(status)
+	return record, true, nil
+}
+
+func (t *pgTx) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *pgTx) Rollback(ctx context.Context) error {
+	err := t.tx.Rollback(ctx)
+	if errors.Is(err, pgx.ErrTxClosed) {
+		return nil
+	}
+	return err
+}
+
+type pgConn struct {
+	conn        *pgxpool.Conn
+	lockTimeout time.Duration
+	locked      bool
+}
+
+func (c *pgConn) Lock(ctx context.Context) error {
+	return waitLock(ctx, c.lockTimeout, func(ctx context.Context) error {
+		_, err := c.conn.Exec(ctx, "SELECT pg_advisory_lock(hashtext($1))", lockName)
+		if err != nil {
+			return fmt.Errorf("pg_advisory_lock: %w", err)
+		}
+		c.locked = true
+		return nil
+	})
+}
+
+func (c *pgConn) Exec(ctx context.Context, script string, statement sqlscript.Statement) error {
+	_, err := c.conn.Exec(ctx, statement.Text)
+	if err != nil {
+		return pgScriptError(script, statement, err)
+	}
+	return nil
+}
+
+func (c *pgConn) Record(ctx context.Context, record Record) error {
+	if _, err := c.conn.Exec(ctx, pgHistoryDDL); err != nil {
+		return fmt.Errorf("create history table: %w", err)
+	}
+	_, err := c.conn.Exec(ctx, pgHistoryUpsert, record.MigrationID, record.Name, record.Checksum, string(record.Status), record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+	return nil
+}
+
+func (c *pgConn) Release() {
+	if c.locked {
+		_, err := c.conn.Exec(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", lockName)
+		if err != nil {
+			// Подключение с удерживаемой блокировкой нельзя возвращать в пул
+			logger.Error(fmt.Errorf("pg_advisory_unlock: %w", err))
+			c.conn.Conn().Close(context.Background())
+		}
+	}
+	c.conn.Release()
+}
+
+// pgScriptError дополняет ошибку PostgreSQL положением в скрипте миграции.
+// statement - выполнявшийся оператор, если скрипт выполнялся по операторам,
+// или нулевое значение, если скрипт выполнялся целиком.
+func pgScriptError(script string, statement sqlscript.Statement, err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	se := entity.ScriptError{
+		SQLState: pgErr.Code,
+		Message:  pgErr.Message,
+		Detail:   pgErr.Detail,
+		Hint:     pgErr.Hint,
+		Position: pgErr.Position,
+	}
+
+	if pgErr.Position > 0 {
+		executed := script[statement.Offset:]
+		offset := statement.Offset + sqlscript.ByteOffset(executed, int(pgErr.Position))
+		se.Line, se.Statement = sqlscript.Locate(script, offset)
+	} else {
+		se = locate(se, script, statement)
+	}
+
+	return entity.NewScriptError(se, err)
+}
@@ -0,0 +1,335 @@
+package targetdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+	"migrator/pkg/sqlscript"
+)
+
+// savepointName - точка сохранения, в которой TryExec выполняет скрипт.
+const savepointName = "migrator_try"
+
+// dialect - особенности СУБД, подключаемой через database/sql.
+type dialect interface {
+	driver() entity.Driver
+	syntax() sqlscript.Syntax
+	transactionalDDL() bool
+	// scriptError переводит ошибку драйвера в *entity.ScriptError.
+	scriptError(script string, statement sqlscript.Statement, err error) error
+	// lock и unlock захватывают и освобождают блокировку на стороне целевой базы данных.
+	lock(ctx context.Context, q querier, timeout time.Duration) error
+	unlock(ctx context.Context, q querier) error
+	// historyDDL, historyUpsert и historySelect - запросы к таблице истории; historyUpsert
+	// принимает migration_id, name, checksum, status и updated_at, а historySelect
+	// по migration_id возвращает name, checksum и status.
+	historyDDL() string
+	historyUpsert() string
+	historySelect() string
+	// schemaQuery - запрос объектов схемы: вид, имя и определение объекта.
+	schemaQuery() string
+}
+
+// querier - общее для *sql.Tx и *sql.Conn.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqlDB - целевая база данных, подключаемая через database/sql.
+type sqlDB struct {
+	db          *sql.DB
+	dialect     dialect
+	lockTimeout time.Duration
+}
+
+func openSQL(driverName, dsn string, d dialect, o options, maxConns int) (DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sql.Open: %w", err)
+	}
+	db.SetMaxOpenConns(maxConns)
+
+	for attempts := o.connAttempts; ; attempts-- {
+		err = db.Ping()
+		if err == nil || attempts <= 1 {
+			break
+		}
+
+		logger.Info("%s is trying to connect, attempts left: %d", d.driver(), attempts)
+
+		time.Sleep(o.connTimeout)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to %s: %w", d.driver(), err)
+	}
+
+	return &sqlDB{db: db, dialect: d, lockTimeout: o.lockTimeout}, nil
+}
+
+func (d *sqlDB) Driver() entity.Driver {
+	return d.dialect.driver()
+}
+
+func (d *sqlDB) TransactionalDDL() bool {
+	return d.dialect.transactionalDDL()
+}
+
+func (d *sqlDB) Parse(script string) []sqlscript.Statement {
+	return sqlscript.ParseSyntax(script, d.dialect.syntax())
+}
+
+func (d *sqlDB) Begin(ctx context.Context) (Tx, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{tx: tx, db: d}, nil
+}
+
+func (d *sqlDB) Conn(ctx context.Context) (Conn, error) {
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConn{conn: conn, dialect: d.dialect, lockTimeout: d.lockTimeout}, nil
+}
+
+func (d *sqlDB) Schema(ctx context.Context) ([]entity.SchemaObject, error) {
+	rows, err := d.db.QueryContext(ctx, d.dialect.schemaQuery())
+	if err != nil {
+		return nil, fmt.Errorf("query schema: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []entity.SchemaObject
+	for rows.Next() {
+		var object entity.SchemaObject
+		if err := rows.Scan(&object.Kind, &object.Name, &object.Definition); err != nil {
+			return nil, fmt.Errorf("scan schema object: %w", err)
+		}
+		objects = append(objects, object)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	entity.SortSchemaObjects(objects)
+	return objects, nil
+}
+
+func (d *sqlDB) Close() {
+	if err := d.db.Close(); err != nil {
+		logger.Error(fmt.Errorf("close %s target: %w", d.dialect.driver(), err))
+	}
+}
+
+// exec выполняет операторы скрипта по одному, так как драйверы database/sql
+// не выполняют несколько операторов одним запросом, и возвращает
+// суммарное число затронутых строк.
+func (d *sqlDB) exec(ctx context.Context, q querier, script string) (int64, error) {
+	var rowsAffected int64
+	for _, statement := range d.Parse(script) {
+		result, err := q.ExecContext(ctx, statement.Text)
+		if err != nil {
+			return 0, d.dialect.scriptError(script, statement, err)
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			rowsAffected += n
+		}
+	}
+	return rowsAffected, nil
+}
+
+func record(ctx context.Context, q querier, d dialect, record Record) error {
+	if _, err := q.ExecContext(ctx, d.historyDDL()); err != nil {
+		return fmt.Errorf("create history table: %w", err)
+	}
+	_, err := q.ExecContext(ctx, d.historyUpsert(), record.MigrationID, record.Name, record.Checksum, string(record.Status), record.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+	return nil
+}
+
+func readRecord(ctx context.Context, q querier, d dialect, migrationID int64) (Record, bool, error) {
+	if _, err := q.ExecContext(ctx, d.historyDDL()); err != nil {
+		return Record{}, false, fmt.Errorf("create history table: %w", err)
+	}
+
+	r := Record{MigrationID: migrationID}
+	var status string
+	err := q.QueryRowContext(ctx, d.historySelect(), migrationID).Scan(&r.Name, &r.Checksum, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Record{}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, fmt.Errorf("read history: %w", err)
+	}
+	r.Status = entity.MigrationStatus(status)
+	return r, true, nil
+}
+
+type sqlTx struct {
+	tx     *sql.Tx
+	db     *sqlDB
+	locked bool
+	done   bool
+}
+
+func (t *sqlTx) Lock(ctx context.Context) error {
+	if err := t.db.dialect.lock(ctx, t.tx, t.db.lockTimeout); err != nil {
+		return err
+	}
+	t.locked = true
+	return nil
+}
+
+func (t *sqlTx) Exec(ctx context.Context, script string) error {
+	_, err := t.db.exec(ctx, t.tx, script)
+	return err
+}
+
+func (t *sqlTx) QueryColumn(ctx context.Context, query string) ([]string, error) {
+	rows, err := t.tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, t.db.dialect.scriptError(query, sqlscript.Statement{}, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("read columns: %w", err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("query returns no columns")
+	}
+
+	row := make([]any, len(columns))
+	for i := range row {
+		row[i] = new(any)
+	}
+
+	var values []string
+	for rows.Next() {
+		if err := rows.Scan(row...); err != nil {
+			return nil, fmt.Errorf("read row: %w", err)
+		}
+		values = append(values, columnText(*row[0].(*any)))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.db.dialect.scriptError(query, sqlscript.Statement{}, err)
+	}
+
+	return values, nil
+}
+
+func (t *sqlTx) TryExec(ctx context.Context, script string) (int64, error) {
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+savepointName); err != nil {
+		return 0, fmt.Errorf("begin savepoint: %w", err)
+	}
+
+	rowsAffected, err := t.db.exec(ctx, t.tx, script)
+	if err != nil {
+		if _, rbErr := t.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepointName); rbErr != nil {
+			logger.Error(fmt.Errorf("savepoint rollback error: %w", rbErr))
+		}
+		return 0, err
+	}
+
+	if _, err := t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepointName); err != nil {
+		return 0, fmt.Errorf("release savepoint: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func (t *sqlTx) Record(ctx context.Context, r Record) error {
+	return record(ctx, t.tx, t.db.dialect, r)
+}
+
+func (t *sqlTx) ReadRecord(ctx context.Context, migrationID int64) (Record, bool, error) {
+	return readRecord(ctx, t.tx, t.db.dialect, migrationID)
+}
+
+// unlock освобождает блокировку до завершения транзакции, пока ее подключение
+// еще не возвращено в пул.
+func (t *sqlTx) unlock(ctx context.Context) error {
+	if !t.locked {
+		return nil
+	}
+	t.locked = false
+	return t.db.dialect.unlock(ctx, t.tx)
+}
+
+func (t *sqlTx) Commit(ctx context.Context) error {
+	if err := t.unlock(ctx); err != nil {
+		return fmt.Errorf("release target lock: %w", err)
+	}
+	t.done = true
+	return t.tx.Commit()
+}
+
+func (t *sqlTx) Rollback(ctx context.Context) error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	if err := t.unlock(ctx); err != nil {
+		logger.Error(fmt.Errorf("release target lock: %w", err))
+	}
+	err := t.tx.Rollback()
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
+}
+
+type sqlConn struct {
+	conn        *sql.Conn
+	dialect     dialect
+	lockTimeout time.Duration
+	locked      bool
+}
+
+func (c *sqlConn) Lock(ctx context.Context) error {
+	if err := c.dialect.lock(ctx, c.conn, c.lockTimeout); err != nil {
+		return err
+	}
+	c.locked = true
+	return nil
+}
+
+func (c *sqlConn) Exec(ctx context.Context, script string, statement sqlscript.Statement) error {
+	_, err := c.conn.ExecContext(ctx, statement.Text)
+	if err != nil {
+		return c.dialect.scriptError(script, statement, err)
+	}
+	return nil
+}
+
+func (c *sqlConn) Record(ctx context.Context, r Record) error {
+	return record(ctx, c.conn, c.dialect, r)
+}
+
+func (c *sqlConn) Release() {
+	if c.locked {
+		if err := c.dialect.unlock(context.Background(), c.conn); err != nil {
+			logger.Error(fmt.Errorf("release target lock: %w", err))
+			// Подключение с удерживаемой блокировкой нельзя возвращать в пул
+			_ = c.conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}
+	if err := c.conn.Close(); err != nil {
+		logger.Error(fmt.Errorf("release target connection: %w", err))
+	}
+}
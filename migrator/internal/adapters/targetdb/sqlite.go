@@ -0,0 +1,149 @@
+package targetdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/sqlscript"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteBusyTimeout - время ожидания занятой базы данных SQLite в миллисекундах.
+const sqliteBusyTimeout = "5000"
+
+// sqliteDialect - SQLite. Транзакции открываются как BEGIN IMMEDIATE и сразу
+// захватывают блокировку записи файла базы данных, поэтому отдельная
+// блокировка не нужна.
+type sqliteDialect struct{}
+
+func openSQLite(rawURL string, o options) (DB, error) {
+	dsn, err := sqliteDSN(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite допускает одного писателя, поэтому пул состоит из одного подключения
+	return openSQL("sqlite", dsn, sqliteDialect{}, o, 1)
+}
+
+// sqliteDSN переводит строку подключения sqlite:путь, sqlite://относительный/путь
+// или sqlite:///абсолютный/путь в формат драйвера modernc.org/sqlite,
+// добавляя режим транзакций IMMEDIATE и время ожидания занятой базы данных.
+func sqliteDSN(rawURL string) (string, error) {
+	_, path, _ := strings.Cut(rawURL, ":")
+	path = strings.TrimPrefix(path, "//")
+	path, rawQuery, _ := strings.Cut(path, "?")
+	if path == "" {
+		return "", fmt.Errorf("%w: sqlite url has no database path", entity.ErrInvalidArgument)
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("parse sqlite url: %w", err)
+	}
+	if query.Get("_txlock") == "" {
+		query.Set("_txlock", "immediate")
+	}
+	hasBusyTimeout := false
+	for _, pragma := range query["_pragma"] {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(pragma)), "busy_timeout") {
+			hasBusyTimeout = true
+		}
+	}
+	if !hasBusyTimeout {
+		query.Add("_pragma", "busy_timeout("+sqliteBusyTimeout+")")
+	}
+
+	return path + "?" + query.Encode(), nil
+}
+
+func (sqliteDialect) driver() entity.Driver {
+	return entity.DriverSQLite
+}
+
+func (sqliteDialect) syntax() sqlscript.Syntax {
+	return sqlscript.SQLite
+}
+
+func (sqliteDialect) transactionalDDL() bool {
+	return true
+}
+
+func (sqliteDialect) scriptError(script string, statement sqlscript.Statement, err error) error {
+	var liteErr *sqlite.Error
+	if !errors.As(err, &liteErr) {
+		return err
+	}
+
+	se := entity.ScriptError{
+		SQLState: sqliteCodeName(liteErr.Code()),
+		Message:  liteErr.Error(),
+	}
+
+	return entity.NewScriptError(locate(se, script, statement), err)
+}
+
+// sqliteCodeName возвращает название основного кода результата SQLite, например SQLITE_CONSTRAINT.
+// Описания драйвера имеют вид "Abort due to constraint violation (SQLITE_CONSTRAINT)".
+func sqliteCodeName(code int) string {
+	description, ok := sqlite.ErrorCodeString[code&0xff]
+	if !ok {
+		return fmt.Sprintf("SQLITE_%d", code)
+	}
+	if start := strings.LastIndexByte(description, '('); start >= 0 {
+		return strings.TrimSuffix(description[start+1:], ")")
+	}
+	return description
+}
+
+func (sqliteDialect) lock(context.Context, querier, time.Duration) error {
+	return nil
+}
+
+func (sqliteDialect) unlock(context.Context, querier) error {
+	return nil
+}
+
+func (sqliteDialect) historyDDL() string {
+	return `
+	CREATE TABLE IF NOT EXISTS ` + HistoryTable + ` (
+		migration_id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		status TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	)`
+}
+
+func (sqliteDialect) historyUpsert() string {
+	return `
+	INSERT INTO ` + HistoryTable + ` (migration_id, name, checksum, status, updated_at)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (migration_id) DO UPDATE
+	SET name = excluded.name, checksum = excluded.checksum, status = excluded.status, updated_at = excluded.updated_at`
+}
+
+func (sqliteDialect) historySelect() string {
+	return `SELECT name, checksum, status FROM ` + HistoryTable + ` WHERE migration_id = ?`
+}
+
+// schemaQuery - объекты схемы из sqlite_master и pragma_table_info.
+func (sqliteDialect) schemaQuery() string {
+	return `
+	SELECT type, name, COALESCE(sql, '')
+	FROM sqlite_master
+	WHERE type IN ('table', 'view', 'index', 'trigger')
+		AND name NOT LIKE 'sqlite\_%' ESCAPE '\' AND tbl_name <> '` + HistoryTable + `'
+	UNION ALL
+	SELECT 'column', m.name || '.' || p.name,
+		p.type || CASE WHEN p."notnull" THEN ' NOT NULL' ELSE '' END || COALESCE(' DEFAULT ' || p.dflt_value, '')
+	FROM sqlite_master m
+	JOIN pragma_table_info(m.name) p
+	WHERE m.type IN ('table', 'view')
+		AND m.name NOT LIKE 'sqlite\_%' ESCAPE '\' AND m.name <> '` + HistoryTable + `'`
+}
@@ -0,0 +1,168 @@
+// Package targetdb реализует подключения к целевым базам данных PostgreSQL, MySQL и SQLite.
+//
+// Различия между СУБД - разбор и выполнение скриптов, транзакции и точки сохранения,
+// блокировка целевой базы данных и таблица истории на ее стороне - скрыты
+// за интерфейсами DB, Tx и Conn. Метаданные миграций по-прежнему хранятся
+// в собственной базе данных сервиса (PostgreSQL).
+package targetdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/sqlscript"
+)
+
+// HistoryTable - таблица истории на целевой базе данных, в которой отражаются
+// примененные и откаченные миграции, чтобы состояние схемы было видно без сервиса.
+const HistoryTable = "migrator_schema_history"
+
+// lockName - имя блокировки целевой базы данных на ее стороне.
+const lockName = "migrator"
+
+// Record - запись таблицы истории целевой базы данных.
+type Record struct {
+	MigrationID int64
+	Name        string
+	Checksum    string
+	Status      entity.MigrationStatus
+	UpdatedAt   time.Time
+}
+
+// DB - пул подключений к целевой базе данных.
+type DB interface {
+	// Driver возвращает СУБД целевой базы данных.
+	Driver() entity.Driver
+	// TransactionalDDL сообщает, откатываются ли операторы DDL вместе с транзакцией.
+	// В MySQL DDL фиксируется неявно, поэтому транзакция не защищает от частичного применения.
+	TransactionalDDL() bool
+	// Parse разбирает скрипт на операторы по правилам СУБД.
+	Parse(script string) []sqlscript.Statement
+	// Begin открывает транзакцию.
+	Begin(ctx context.Context) (Tx, error)
+	// Conn выделяет подключение для выполнения операторов вне транзакции.
+	Conn(ctx context.Context) (Conn, error)
+	// Schema возвращает объекты схемы целевой базы данных из системного каталога,
+	// упорядоченные по виду и имени. Системные схемы и таблица истории не включаются.
+	Schema(ctx context.Context) ([]entity.SchemaObject, error)
+	// Close закрывает пул.
+	Close()
+}
+
+// Tx - транзакция целевой базы данных.
+type Tx interface {
+	// Lock захватывает блокировку целевой базы данных до конца транзакции, чтобы
+	// экземпляры сервиса с разными базами метаданных не выполняли миграции
+	// одновременно. Если блокировка не освободилась за время ожидания,
+	// возвращает entity.ErrLocked.
+	Lock(ctx context.Context) error
+	// Exec выполняет скрипт. Ошибка базы данных возвращается как *entity.ScriptError.
+	Exec(ctx context.Context, script string) error
+	// TryExec выполняет скрипт в точке сохранения, так что ошибка не прерывает транзакцию.
+	// Возвращает суммарное число строк, затронутых операторами скрипта.
+	TryExec(ctx context.Context, script string) (int64, error)
+	// QueryColumn выполняет запрос и возвращает значения первого столбца
+	// строк результата в текстовом виде. Ошибка базы данных возвращается как *entity.ScriptError.
+	QueryColumn(ctx context.Context, query string) ([]string, error)
+	// Record записывает состояние миграции в таблицу истории целевой базы данных.
+	Record(ctx context.Context, record Record) error
+	// ReadRecord возвращает запись таблицы истории о миграции; false, если записи нет.
+	ReadRecord(ctx context.Context, migrationID int64) (Record, bool, error)
+	Commit(ctx context.Context) error
+	// Rollback откатывает транзакцию; после Commit ничего не делает.
+	Rollback(ctx context.Context) error
+}
+
+// Conn - подключение к целевой базе данных вне транзакции.
+type Conn interface {
+	// Lock захватывает блокировку целевой базы данных до Release, ожидая ее
+	// так же, как Tx.Lock.
+	Lock(ctx context.Context) error
+	// Exec выполняет оператор statement скрипта script.
+	Exec(ctx context.Context, script string, statement sqlscript.Statement) error
+	// Record записывает состояние миграции в таблицу истории целевой базы данных.
+	Record(ctx context.Context, record Record) error
+	// Release снимает блокировку и возвращает подключение в пул.
+	Release()
+}
+
+// TenantDB - целевая база данных со схемами арендаторов (PostgreSQL): миграции,
+// отмеченные как миграции арендаторов, применяются к каждой схеме отдельно.
+type TenantDB interface {
+	// Tenants возвращает схемы арендаторов: первый столбец результата запроса query.
+	Tenants(ctx context.Context, query string) ([]string, error)
+	// BeginTenant открывает транзакцию, в которой search_path установлен в схему арендатора.
+	// Транзакция не захватывает блокировку целевой базы данных: ее удерживает
+	// подключение, выделенное на все время применения.
+	BeginTenant(ctx context.Context, tenant string) (Tx, error)
+}
+
+// Open открывает пул подключений к целевой базе данных, выбирая СУБД
+// по схеме строки подключения.
+func Open(url string, opts ...Option) (DB, error) {
+	o := options{
+		maxPoolSize:  _defaultMaxPoolSize,
+		connAttempts: _defaultConnAttempts,
+		connTimeout:  _defaultConnTimeout,
+		lockTimeout:  _defaultLockTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	driver, err := entity.DriverFromURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	switch driver {
+	case entity.DriverMySQL:
+		return openMySQL(url, o)
+	case entity.DriverSQLite:
+		return openSQLite(url, o)
+	case entity.DriverPostgres:
+		return openPostgres(url, o)
+	}
+
+	return nil, fmt.Errorf("unsupported driver %q", driver)
+}
+
+// waitLock захватывает блокировку целевой базы данных функцией lock, ожидая
+// ее не дольше timeout. Если время ожидания истекло, возвращает entity.ErrLocked.
+func waitLock(ctx context.Context, timeout time.Duration, lock func(ctx context.Context) error) error {
+	lockCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := lock(lockCtx)
+	if err != nil && ctx.Err() == nil && lockCtx.Err() != nil {
+		return fmt.Errorf("%w by another migrator instance, waited %s", entity.ErrLocked, timeout)
+	}
+	return err
+}
+
+// locate дополняет ошибку оператора номером строки и текстом оператора в скрипте.
+func locate(se entity.ScriptError, script string, statement sqlscript.Statement) entity.ScriptError {
+	if statement.Text != "" {
+		se.Line, _ = sqlscript.Locate(script, statement.Offset)
+		se.Statement = statement.Text
+	}
+	return se
+}
+
+// columnText переводит значение столбца результата запроса в текст.
+func columnText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case [16]byte:
+		return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16])
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
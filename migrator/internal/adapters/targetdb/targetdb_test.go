@@ -0,0 +1,182 @@
+package targetdb
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgconn"
+
+	"migrator/internal/entity"
+	"migrator/pkg/sqlscript"
+)
+
+func TestPgScriptErrorLocation(t *testing.T) {
+	script := "CREATE TABLE users (name text DEFAULT 'Иван');\nINSERT INTO userz VALUES ('Пётр');\n"
+	statement := sqlscript.Parse(script)[1]
+
+	// PostgreSQL сообщает позицию в символах от начала выполненного текста
+	position := func(executed string) int32 {
+		return int32(utf8.RuneCountInString(executed[:strings.Index(executed, "userz")]) + 1)
+	}
+
+	tests := []struct {
+		name          string
+		statement     sqlscript.Statement
+		position      int32
+		wantLine      int
+		wantStatement string
+	}{
+		{
+			name:          "whole script",
+			position:      position(script),
+			wantLine:      2,
+			wantStatement: statement.Text,
+		},
+		{
+			name:          "single statement",
+			statement:     statement,
+			position:      position(statement.Text),
+			wantLine:      2,
+			wantStatement: statement.Text,
+		},
+		{
+			name:          "single statement without position",
+			statement:     statement,
+			wantLine:      2,
+			wantStatement: statement.Text,
+		},
+		{
+			name: "whole script without position",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgErr := &pgconn.PgError{Code: "42P01", Message: `relation "userz" does not exist`, Position: tt.position}
+
+			err := pgScriptError(script, tt.statement, pgErr)
+
+			var se *entity.ScriptError
+			if !errors.As(err, &se) {
+				t.Fatalf("pgScriptError() = %v, want *entity.ScriptError", err)
+			}
+			if se.SQLState != "42P01" || se.Position != tt.position {
+				t.Errorf("SQLState, Position = %s, %d, want 42P01, %d", se.SQLState, se.Position, tt.position)
+			}
+			if se.Line != tt.wantLine || se.Statement != tt.wantStatement {
+				t.Errorf("Line, Statement = %d, %q, want %d, %q", se.Line, se.Statement, tt.wantLine, tt.wantStatement)
+			}
+			if !errors.Is(err, pgErr) {
+				t.Error("pgScriptError() does not wrap the driver error")
+			}
+		})
+	}
+}
+
+func TestPgScriptErrorPassesOtherErrors(t *testing.T) {
+	err := errors.New("conn closed")
+
+	if got := pgScriptError("SELECT 1", sqlscript.Statement{}, err); got != err {
+		t.Errorf("pgScriptError() = %v, want the original error", got)
+	}
+}
+
+func TestWaitLock(t *testing.T) {
+	// held ждет блокировку, занятую другим экземпляром, пока не отменят запрос,
+	// как pg_advisory_xact_lock
+	held := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("acquired", func(t *testing.T) {
+		err := waitLock(context.Background(), time.Second, func(context.Context) error { return nil })
+		if err != nil {
+			t.Fatalf("waitLock() error = %v", err)
+		}
+	})
+
+	t.Run("held until the wait timeout", func(t *testing.T) {
+		err := waitLock(context.Background(), 20*time.Millisecond, held)
+		if !errors.Is(err, entity.ErrLocked) {
+			t.Fatalf("waitLock() error = %v, want ErrLocked", err)
+		}
+	})
+
+	t.Run("request canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := waitLock(ctx, time.Minute, held)
+		if errors.Is(err, entity.ErrLocked) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("waitLock() error = %v, want the request's context error", err)
+		}
+	})
+
+	t.Run("lock error", func(t *testing.T) {
+		lockErr := errors.New("conn closed")
+
+		err := waitLock(context.Background(), time.Second, func(context.Context) error { return lockErr })
+		if !errors.Is(err, lockErr) || errors.Is(err, entity.ErrLocked) {
+			t.Fatalf("waitLock() error = %v, want %v", err, lockErr)
+		}
+	})
+}
+
+func TestSQLiteHistoryRecords(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open("sqlite://" + filepath.Join(t.TempDir(), "target.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+
+	applied := Record{MigrationID: 7, Name: "7_users", Checksum: "abc", Status: entity.StatusApplied, UpdatedAt: time.Now()}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if _, found, err := tx.ReadRecord(ctx, applied.MigrationID); err != nil || found {
+		t.Fatalf("ReadRecord() before Record = %v, %v, want not found", found, err)
+	}
+	if err := tx.Record(ctx, applied); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	// Запись, сделанная в откаченной транзакции, не сохраняется
+	tx, err = db.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	rolledBack := applied
+	rolledBack.Status = entity.StatusRolledBack
+	if err := tx.Record(ctx, rolledBack); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	tx, err = db.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	record, found, err := tx.ReadRecord(ctx, applied.MigrationID)
+	if err != nil || !found {
+		t.Fatalf("ReadRecord() = %v, %v, want found", found, err)
+	}
+	if record.Name != applied.Name || record.Checksum != applied.Checksum || record.Status != entity.StatusApplied {
+		t.Errorf("ReadRecord() = %+v, want %s, %s, %s", record, applied.Name, applied.Checksum, entity.StatusApplied)
+	}
+}
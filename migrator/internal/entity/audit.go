@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// AuditOutcome - итог действия, записанного в журнал аудита.
+type AuditOutcome string
+
+const (
+	// AuditOutcomeSucceeded - действие выполнено.
+	AuditOutcomeSucceeded AuditOutcome = "succeeded"
+	// AuditOutcomeDenied - действие отклонено из-за отсутствия прав.
+	AuditOutcomeDenied AuditOutcome = "denied"
+	// AuditOutcomeFailed - действие завершилось ошибкой.
+	AuditOutcomeFailed AuditOutcome = "failed"
+)
+
+func (o AuditOutcome) String() string {
+	return string(o)
+}
+
+// AuditEvent - запись журнала аудита вызовов API. Записи только добавляются.
+// Action - вызванный метод, Resource - объекты действия в виде
+// "migration:12,target:3", IP и UserAgent - сведения о клиенте.
+type AuditEvent struct {
+	ID        int64        `json:"id" db:"id"`
+	ActorID   int64        `json:"actor_id" db:"actor_id"`
+	Action    string       `json:"action" db:"action"`
+	Resource  string       `json:"resource" db:"resource"`
+	IP        string       `json:"ip" db:"ip"`
+	UserAgent string       `json:"user_agent" db:"user_agent"`
+	Outcome   AuditOutcome `json:"outcome" db:"outcome"`
+	Error     string       `json:"error" db:"error"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter - условия выборки журнала аудита. Нулевые значения не ограничивают выборку.
+// From включается в период, To - нет. Записи возвращаются от новых к старым; BeforeID
+// продолжает выборку после последней записи предыдущей страницы.
+type AuditFilter struct {
+	ActorID  int64
+	From     time.Time
+	To       time.Time
+	BeforeID int64
+	Limit    int
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrBackfillActive - на целевой базе данных выполняется или приостановлена миграция данных.
+var ErrBackfillActive = fmt.Errorf("backfill is active")
+
+// Переменные шаблона, которые подставляются в запросы миграции данных (backfill)
+// при выполнении каждой пачки.
+const (
+	// BackfillCursorVariable - курсор: ключ последней обработанной строки.
+	BackfillCursorVariable = "cursor"
+	// BackfillNextCursorVariable - ключ последней строки текущей пачки.
+	BackfillNextCursorVariable = "next_cursor"
+	// BackfillBatchSizeVariable - размер пачки.
+	BackfillBatchSizeVariable = "batch_size"
+)
+
+// BackfillSpec - параметры миграции данных, которая выполняется многими небольшими
+// транзакциями. CursorQuery выбирает ключи следующей пачки строк после {{ cursor }}
+// в порядке возрастания (не больше {{ batch_size }}), а скрипт миграции обрабатывает
+// строки с ключами от {{ cursor }} (не включая) до {{ next_cursor }} (включая).
+// EstimateQuery - необязательный запрос, возвращающий оценку общего числа строк.
+// StartCursor - курсор перед первой пачкой.
+type BackfillSpec struct {
+	CursorQuery   string `json:"cursor_query"`
+	EstimateQuery string `json:"estimate_query,omitempty"`
+	BatchSize     int    `json:"batch_size"`
+	StartCursor   string `json:"start_cursor,omitempty"`
+}
+
+// Validate проверяет параметры миграции данных.
+func (s BackfillSpec) Validate() error {
+	if s.CursorQuery == "" {
+		return fmt.Errorf("%w: backfill cursor query cannot be empty", ErrInvalidArgument)
+	}
+	if s.BatchSize <= 0 {
+		return fmt.Errorf("%w: backfill batch size must be greater than 0", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// BackfillState - состояние выполнения миграции данных.
+type BackfillState string
+
+const (
+	// BackfillStateRunning - пачки выполняются одной из реплик сервиса.
+	BackfillStateRunning BackfillState = "running"
+	// BackfillStatePaused - выполнение приостановлено и продолжится с сохраненного курсора.
+	BackfillStatePaused BackfillState = "paused"
+	// BackfillStateCompleted - все строки обработаны, миграция применена.
+	BackfillStateCompleted BackfillState = "completed"
+	// BackfillStateFailed - пачка завершилась ошибкой; выполнение можно продолжить.
+	BackfillStateFailed BackfillState = "failed"
+)
+
+func (s BackfillState) String() string {
+	return string(s)
+}
+
+// Backfill - ход выполнения миграции данных. Cursor - ключ последней обработанной
+// строки, RowsDone - число обработанных строк, EstimatedTotal - оценка общего числа
+// строк (-1, если неизвестна). BatchSize и Pause - текущие размер пачки и пауза
+// между пачками, которые можно изменить во время выполнения. Owner - реплика
+// сервиса, выполняющая пачки, Error - ошибка последней пачки.
+type Backfill struct {
+	MigrationID    int64         `json:"migration_id" db:"migration_id"`
+	TargetID       int64         `json:"target_id" db:"target_id"`
+	State          BackfillState `json:"state" db:"state"`
+	Cursor         string        `json:"cursor" db:"cursor"`
+	RowsDone       int64         `json:"rows_done" db:"rows_done"`
+	EstimatedTotal int64         `json:"estimated_total" db:"estimated_total"`
+	BatchSize      int           `json:"batch_size" db:"batch_size"`
+	Pause          time.Duration `json:"pause" db:"pause_ms"`
+	Error          string        `json:"error" db:"error"`
+	Owner          string        `json:"owner" db:"owner"`
+	StartedBy      int64         `json:"started_by" db:"started_by"`
+	StartedAt      time.Time     `json:"started_at" db:"started_at"`
+	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
+	FinishedAt     time.Time     `json:"finished_at" db:"finished_at"`
+}
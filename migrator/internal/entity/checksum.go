@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+var ErrChecksumMismatch = fmt.Errorf("checksum mismatch")
+
+// ScriptChecksum вычисляет контрольную сумму скриптов миграции: SHA-256
+// от скрипта и скрипта отката, разделенных нулевым байтом, в hex-кодировке.
+//
+// Та же формула используется в SQL при заполнении контрольных сумм
+// миграций, созданных до их появления.
+func ScriptChecksum(script, rollbackScript string) string {
+	h := sha256.New()
+	h.Write([]byte(script))
+	h.Write([]byte{0})
+	h.Write([]byte(rollbackScript))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyChecksum проверяет, что скрипты миграции не изменялись после создания
+// и после применения. Скрипт повторяемой миграции изменяется через сервис,
+// поэтому для нее контрольная сумма применения не сравнивается.
+func (m MigrationInfo) VerifyChecksum() error {
+	actual := ScriptChecksum(m.Script, m.RollbackScript)
+
+	if m.Checksum != actual {
+		return fmt.Errorf("%w: migration %d recorded checksum %s, scripts hash to %s", ErrChecksumMismatch, m.ID, m.Checksum, actual)
+	}
+
+	if m.Kind != MigrationKindRepeatable && m.AppliedChecksum != "" && m.AppliedChecksum != actual {
+		return fmt.Errorf("%w: migration %d was applied with checksum %s, scripts hash to %s", ErrChecksumMismatch, m.ID, m.AppliedChecksum, actual)
+	}
+
+	return nil
+}
+
+// ChecksumViolation - миграция, скрипты которой не совпадают с записанной контрольной суммой.
+type ChecksumViolation struct {
+	MigrationID     int64           `json:"migration_id"`
+	Name            string          `json:"name"`
+	Status          MigrationStatus `json:"status"`
+	Checksum        string          `json:"checksum"`
+	AppliedChecksum string          `json:"applied_checksum"`
+	ActualChecksum  string          `json:"actual_checksum"`
+	Reason          string          `json:"reason"`
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrPromotionBlocked - миграция не прошла предыдущее окружение конвейера.
+var ErrPromotionBlocked = fmt.Errorf("promotion blocked")
+
+// Environment - окружение (dev, staging, prod), к которому относятся целевые базы данных.
+// Окружения образуют конвейер: миграцию можно применить в окружении только после того,
+// как она применена в окружении PromoteFromID и пробыла там не меньше MinSoak.
+// Variables - переменные шаблонов скриптов, общие для целевых баз данных окружения.
+type Environment struct {
+	ID            int64             `json:"id" db:"id"`
+	Name          string            `json:"name" db:"name"`
+	Description   string            `json:"description" db:"description"`
+	PromoteFromID int64             `json:"promote_from_id" db:"promote_from_id"`
+	MinSoak       time.Duration     `json:"min_soak" db:"min_soak_seconds"`
+	Variables     map[string]string `json:"variables" db:"variables"`
+	CreatedBy     int64             `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// EnvironmentMigration - состояние миграции в одной из целевых баз данных окружения.
+// Миграции разных целевых баз данных считаются одной миграцией, если совпадают их названия.
+type EnvironmentMigration struct {
+	EnvironmentID   int64           `json:"environment_id"`
+	EnvironmentName string          `json:"environment_name"`
+	TargetID        int64           `json:"target_id"`
+	TargetName      string          `json:"target_name"`
+	MigrationID     int64           `json:"migration_id"`
+	Name            string          `json:"name"`
+	Checksum        string          `json:"checksum"`
+	Status          MigrationStatus `json:"status"`
+	StatusUpdatedAt time.Time       `json:"status_updated_at"`
+}
+
+// MigrationEnvironments - состояния одной миграции во всех окружениях в порядке конвейера.
+type MigrationEnvironments struct {
+	Name     string                 `json:"name"`
+	Statuses []EnvironmentMigration `json:"statuses"`
+}
+
+// EnvironmentMigrationFilter - условия выборки состояний миграций по окружениям.
+// Нулевые значения не ограничивают выборку.
+type EnvironmentMigrationFilter struct {
+	// TargetID оставляет миграции, зарегистрированные для этой целевой базы данных.
+	TargetID int64
+	Name     string
+	// EnvironmentID и Status оставляют миграции, у которых есть состояние
+	// в этом окружении и с этим статусом.
+	EnvironmentID int64
+	Status        MigrationStatus
+}
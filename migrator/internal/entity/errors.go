@@ -2,4 +2,14 @@ package entity
 
 import "fmt"
 
-var ErrPermissionDenied = fmt.Errorf("permission denied")
+var (
+	ErrPermissionDenied   = fmt.Errorf("permission denied")
+	ErrUnauthenticated    = fmt.Errorf("unauthenticated")
+	ErrNotFound           = fmt.Errorf("not found")
+	ErrAlreadyExists      = fmt.Errorf("already exists")
+	ErrTargetInUse        = fmt.Errorf("target is in use")
+	ErrEnvironmentInUse   = fmt.Errorf("environment is in use")
+	ErrInvalidArgument    = fmt.Errorf("invalid argument")
+	ErrFailedPrecondition = fmt.Errorf("failed precondition")
+	ErrNotLastMigration   = fmt.Errorf("not last migration")
+)
@@ -0,0 +1,42 @@
+package entity
+
+// ExportManifestFormat - значение поля format манифеста выгрузки реестра миграций.
+const ExportManifestFormat = "migrator-export"
+
+// ExportManifestFile - имя файла манифеста в корне выгрузки.
+const ExportManifestFile = "manifest.json"
+
+// ExportManifest - манифест выгрузки реестра миграций.
+type ExportManifest struct {
+	Format  string         `json:"format"`
+	Version int            `json:"version"`
+	Targets []ExportTarget `json:"targets"`
+}
+
+// ExportTarget - целевая база данных в манифесте выгрузки. Строка подключения не выгружается.
+type ExportTarget struct {
+	ID          int64             `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Migrations  []ExportMigration `json:"migrations"`
+}
+
+// ExportMigration - миграция в манифесте выгрузки.
+type ExportMigration struct {
+	ID              int64             `json:"id"`
+	Name            string            `json:"name"`
+	Description     string            `json:"description,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Status          MigrationStatus   `json:"status"`
+	ExecutionMode   ExecutionMode     `json:"execution_mode"`
+	Kind            MigrationKind     `json:"kind,omitempty"`
+	Backfill        *BackfillSpec     `json:"backfill,omitempty"`
+	TenantScoped    bool              `json:"tenant_scoped,omitempty"`
+	Templated       bool              `json:"templated,omitempty"`
+	CreatedBy       int64             `json:"created_by"`
+	StatusUpdatedAt string            `json:"status_updated_at"`
+	Checksum        string            `json:"checksum"`
+	AppliedChecksum string            `json:"applied_checksum,omitempty"`
+	UpFile          string            `json:"up_file"`
+	DownFile        string            `json:"down_file"`
+}
@@ -0,0 +1,71 @@
+package entity
+
+import "time"
+
+// HistoryAction - действие над миграцией, записываемое в историю выполнения.
+type HistoryAction string
+
+const (
+	HistoryActionApply    HistoryAction = "apply"
+	HistoryActionRollback HistoryAction = "rollback"
+	// HistoryActionBaseline - миграция отмечена примененной без выполнения скрипта.
+	HistoryActionBaseline HistoryAction = "baseline"
+	// HistoryActionBackfill - миграция данных выполнена пачками до конца или остановлена ошибкой.
+	HistoryActionBackfill HistoryAction = "backfill"
+)
+
+func (a HistoryAction) String() string {
+	return string(a)
+}
+
+// HistoryOutcome - результат выполнения скрипта миграции.
+type HistoryOutcome string
+
+const (
+	// OutcomeSucceeded - скрипт выполнен и изменения зафиксированы.
+	OutcomeSucceeded HistoryOutcome = "succeeded"
+	// OutcomeFailed - скрипт завершился ошибкой или запрос был отклонен до его выполнения.
+	OutcomeFailed HistoryOutcome = "failed"
+	// OutcomeAborted - скрипт не выполнялся или его изменения откатились
+	// из-за ошибки другой миграции той же транзакции.
+	OutcomeAborted HistoryOutcome = "aborted"
+	// OutcomePartiallyApplied - миграция без транзакции прервалась после части операторов.
+	OutcomePartiallyApplied HistoryOutcome = "partially_applied"
+)
+
+func (o HistoryOutcome) String() string {
+	return string(o)
+}
+
+// HistoryEntry - запись журнала выполнения миграций. Записи только добавляются.
+// Script - выполненный скрипт после подстановки переменных шаблона, Variables -
+// использованные в нем переменные, Tenant - схема арендатора, если миграция
+// применялась к схемам арендаторов (пустая строка - итог по всем схемам).
+type HistoryEntry struct {
+	ID          int64             `json:"id" db:"id"`
+	MigrationID int64             `json:"migration_id" db:"migration_id"`
+	TargetID    int64             `json:"target_id" db:"target_id"`
+	Action      HistoryAction     `json:"action" db:"action"`
+	UserID      int64             `json:"user_id" db:"user_id"`
+	StartedAt   time.Time         `json:"started_at" db:"started_at"`
+	FinishedAt  time.Time         `json:"finished_at" db:"finished_at"`
+	Duration    time.Duration     `json:"duration" db:"duration_ms"`
+	Outcome     HistoryOutcome    `json:"outcome" db:"outcome"`
+	Error       string            `json:"error" db:"error"`
+	Script      string            `json:"script" db:"script"`
+	Variables   map[string]string `json:"variables" db:"variables"`
+	Tenant      string            `json:"tenant" db:"tenant"`
+}
+
+// HistoryFilter - условия выборки журнала выполнения. Нулевые значения не ограничивают выборку.
+// Записи возвращаются от новых к старым; BeforeID продолжает выборку после последней записи
+// предыдущей страницы.
+type HistoryFilter struct {
+	TargetID    int64
+	MigrationID int64
+	UserID      int64
+	Action      HistoryAction
+	Outcome     HistoryOutcome
+	BeforeID    int64
+	Limit       int
+}
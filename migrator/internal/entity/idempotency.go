@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrIdempotencyInProgress - запрос с тем же ключом идемпотентности еще выполняется.
+var ErrIdempotencyInProgress = fmt.Errorf("request with this idempotency key is in progress")
+
+// MaxIdempotencyKeyLength - наибольшая длина ключа идемпотентности.
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyKey - ключ идемпотентности, переданный клиентом. Ключи разных
+// пользователей и методов не пересекаются.
+type IdempotencyKey struct {
+	UserID int64  `json:"user_id" db:"user_id"`
+	Method string `json:"method" db:"method"`
+	Key    string `json:"key" db:"key"`
+}
+
+// IdempotencyRecord - запрос, выполненный с ключом идемпотентности. RequestHash
+// отличает повтор запроса от другого запроса с тем же ключом, Response -
+// сохраненный ответ выполненного запроса, который возвращается повторам.
+type IdempotencyRecord struct {
+	Key         IdempotencyKey `json:"key"`
+	RequestHash string         `json:"request_hash" db:"request_hash"`
+	Response    []byte         `json:"response" db:"response"`
+	Completed   bool           `json:"completed" db:"completed"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	CompletedAt time.Time      `json:"completed_at" db:"completed_at"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "context"
+
+// Identity - пользователь, подтвержденный токеном доступа.
+type Identity struct {
+	UserID int64
+	Login  string
+}
+
+type identityKey struct{}
+
+// ContextWithIdentity возвращает контекст с подтвержденным пользователем.
+func ContextWithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext возвращает пользователя, подтвержденного токеном доступа.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok && identity.UserID != 0
+}
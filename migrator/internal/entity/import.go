@@ -0,0 +1,58 @@
+package entity
+
+import "fmt"
+
+// ErrInvalidImport - архив или файлы миграций не удалось разобрать.
+var ErrInvalidImport = fmt.Errorf("invalid import")
+
+// ImportFormat - раскладка файлов миграций, из которой выполняется импорт.
+type ImportFormat string
+
+const (
+	// ImportFormatAuto - раскладка определяется по именам и содержимому файлов.
+	ImportFormatAuto ImportFormat = "auto"
+	// ImportFormatGolangMigrate - пары файлов 0001_name.up.sql / 0001_name.down.sql.
+	ImportFormatGolangMigrate ImportFormat = "golang-migrate"
+	// ImportFormatGoose - файлы 0001_name.sql с аннотациями -- +goose Up / -- +goose Down.
+	ImportFormatGoose ImportFormat = "goose"
+	// ImportFormatFlyway - файлы V1__name.sql и необязательные U1__name.sql.
+	ImportFormatFlyway ImportFormat = "flyway"
+	// ImportFormatExport - выгрузка реестра миграций этого сервиса с manifest.json.
+	ImportFormatExport ImportFormat = "migrator-export"
+)
+
+func (f ImportFormat) String() string {
+	return string(f)
+}
+
+// Valid сообщает, является ли значение известной раскладкой.
+func (f ImportFormat) Valid() bool {
+	switch f {
+	case ImportFormatAuto, ImportFormatGolangMigrate, ImportFormatGoose, ImportFormatFlyway, ImportFormatExport:
+		return true
+	}
+	return false
+}
+
+// ImportStatus - результат импорта одной миграции.
+type ImportStatus string
+
+const (
+	ImportStatusCreated   ImportStatus = "created"
+	ImportStatusDuplicate ImportStatus = "duplicate"
+	ImportStatusSkipped   ImportStatus = "skipped"
+)
+
+func (s ImportStatus) String() string {
+	return string(s)
+}
+
+// ImportItem - отчет об импорте одной миграции или файла.
+type ImportItem struct {
+	Version     string       `json:"version"`
+	Name        string       `json:"name"`
+	Source      string       `json:"source"`
+	Status      ImportStatus `json:"status"`
+	MigrationID int64        `json:"migration_id"`
+	Reason      string       `json:"reason"`
+}
@@ -0,0 +1,125 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// labelPattern - допустимые ключи и значения меток: буквы, цифры, '.', '_', '-' и '/'.
+var labelPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+// maxLabelLength - максимальная длина ключа и значения метки.
+const maxLabelLength = 63
+
+// ValidateLabels проверяет ключи и значения меток миграции.
+func ValidateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if len(key) > maxLabelLength || !labelPattern.MatchString(key) {
+			return fmt.Errorf("%w: invalid label key %q", ErrInvalidArgument, key)
+		}
+		if len(value) > maxLabelLength || value != "" && !labelPattern.MatchString(value) {
+			return fmt.Errorf("%w: invalid value %q of label %s", ErrInvalidArgument, value, key)
+		}
+	}
+	return nil
+}
+
+// SelectorOperator - оператор условия селектора меток.
+type SelectorOperator string
+
+const (
+	SelectorEquals    SelectorOperator = "="
+	SelectorNotEquals SelectorOperator = "!="
+	SelectorExists    SelectorOperator = "exists"
+	SelectorNotExists SelectorOperator = "!exists"
+)
+
+// LabelRequirement - одно условие селектора меток.
+type LabelRequirement struct {
+	Key      string
+	Operator SelectorOperator
+	Value    string
+}
+
+// LabelSelector - селектор меток: миграция подходит, если выполнены все условия.
+// Пустой селектор подходит любой миграции.
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector разбирает селектор меток: условия через запятую вида
+// "ключ=значение", "ключ!=значение", "ключ" (метка есть) и "!ключ" (метки нет),
+// например "release=2026.10,team=billing".
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var result LabelSelector
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var requirement LabelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			requirement = LabelRequirement{Key: key, Operator: SelectorNotEquals, Value: value}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			requirement = LabelRequirement{Key: key, Operator: SelectorEquals, Value: strings.TrimPrefix(value, "=")}
+		case strings.HasPrefix(part, "!"):
+			requirement = LabelRequirement{Key: part[1:], Operator: SelectorNotExists}
+		default:
+			requirement = LabelRequirement{Key: part, Operator: SelectorExists}
+		}
+
+		requirement.Key = strings.TrimSpace(requirement.Key)
+		requirement.Value = strings.TrimSpace(requirement.Value)
+		if !labelPattern.MatchString(requirement.Key) {
+			return nil, fmt.Errorf("%w: invalid label selector %q", ErrInvalidArgument, part)
+		}
+		result = append(result, requirement)
+	}
+	return result, nil
+}
+
+// Matches сообщает, подходят ли метки под селектор.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		value, ok := labels[requirement.Key]
+		switch requirement.Operator {
+		case SelectorEquals:
+			if !ok || value != requirement.Value {
+				return false
+			}
+		case SelectorNotEquals:
+			if ok && value == requirement.Value {
+				return false
+			}
+		case SelectorExists:
+			if !ok {
+				return false
+			}
+		case SelectorNotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s LabelSelector) String() string {
+	parts := make([]string, 0, len(s))
+	for _, requirement := range s {
+		switch requirement.Operator {
+		case SelectorExists:
+			parts = append(parts, requirement.Key)
+		case SelectorNotExists:
+			parts = append(parts, "!"+requirement.Key)
+		default:
+			parts = append(parts, requirement.Key+string(requirement.Operator)+requirement.Value)
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
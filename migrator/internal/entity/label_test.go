@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		wantErr bool
+	}{
+		{name: "no labels"},
+		{name: "valid labels", labels: map[string]string{"release": "2026.10", "team": "billing", "app.io/tier": "db-1"}},
+		{name: "empty value", labels: map[string]string{"hotfix": ""}},
+		{name: "empty key", labels: map[string]string{"": "x"}, wantErr: true},
+		{name: "key with space", labels: map[string]string{"my team": "x"}, wantErr: true},
+		{name: "key starting with dash", labels: map[string]string{"-team": "x"}, wantErr: true},
+		{name: "value with comma", labels: map[string]string{"team": "a,b"}, wantErr: true},
+		{name: "key too long", labels: map[string]string{strings.Repeat("k", 64): "x"}, wantErr: true},
+		{name: "value too long", labels: map[string]string{"team": strings.Repeat("v", 64)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLabels(tt.labels)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateLabels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("ValidateLabels() error = %v, want ErrInvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     LabelSelector
+		wantErr  bool
+	}{
+		{name: "empty", selector: ""},
+		{name: "only separators", selector: " , ,"},
+		{
+			name:     "equals",
+			selector: "release=2026.10",
+			want:     LabelSelector{{Key: "release", Operator: SelectorEquals, Value: "2026.10"}},
+		},
+		{
+			name:     "double equals",
+			selector: "team==billing",
+			want:     LabelSelector{{Key: "team", Operator: SelectorEquals, Value: "billing"}},
+		},
+		{
+			name:     "not equals",
+			selector: "team!=billing",
+			want:     LabelSelector{{Key: "team", Operator: SelectorNotEquals, Value: "billing"}},
+		},
+		{
+			name:     "exists and not exists",
+			selector: "hotfix, !experimental",
+			want: LabelSelector{
+				{Key: "hotfix", Operator: SelectorExists},
+				{Key: "experimental", Operator: SelectorNotExists},
+			},
+		},
+		{
+			name:     "spaces around parts",
+			selector: " release = 2026.10 , team != billing ",
+			want: LabelSelector{
+				{Key: "release", Operator: SelectorEquals, Value: "2026.10"},
+				{Key: "team", Operator: SelectorNotEquals, Value: "billing"},
+			},
+		},
+		{
+			name:     "empty value",
+			selector: "hotfix=",
+			want:     LabelSelector{{Key: "hotfix", Operator: SelectorEquals, Value: ""}},
+		},
+		{name: "missing key", selector: "=billing", wantErr: true},
+		{name: "missing key of not exists", selector: "!", wantErr: true},
+		{name: "invalid key", selector: "my team=billing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLabelSelector(tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLabelSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("ParseLabelSelector() error = %v, want ErrInvalidArgument", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLabelSelector() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := map[string]string{"release": "2026.10", "team": "billing", "hotfix": ""}
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{selector: "", want: true},
+		{selector: "release=2026.10", want: true},
+		{selector: "release=2026.11", want: false},
+		{selector: "release=2026.10,team=billing", want: true},
+		{selector: "release=2026.10,team=payments", want: false},
+		{selector: "team!=payments", want: true},
+		{selector: "team!=billing", want: false},
+		{selector: "owner!=alice", want: true},
+		{selector: "owner=", want: false},
+		{selector: "hotfix=", want: true},
+		{selector: "hotfix", want: true},
+		{selector: "owner", want: false},
+		{selector: "!owner", want: true},
+		{selector: "!hotfix", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseLabelSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("ParseLabelSelector: %v", err)
+			}
+			if got := selector.Matches(labels); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelSelectorString(t *testing.T) {
+	selector, err := ParseLabelSelector("team!=billing, !experimental, release=2026.10, hotfix")
+	if err != nil {
+		t.Fatalf("ParseLabelSelector: %v", err)
+	}
+
+	want := "!experimental,hotfix,release=2026.10,team!=billing"
+	if got := selector.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
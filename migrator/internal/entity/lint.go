@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LintSeverity - важность замечания линтера SQL.
+type LintSeverity string
+
+const (
+	// LintSeverityOff отключает правило.
+	LintSeverityOff     LintSeverity = "off"
+	LintSeverityInfo    LintSeverity = "info"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityError   LintSeverity = "error"
+)
+
+func (s LintSeverity) String() string {
+	return string(s)
+}
+
+// Valid сообщает, является ли значение известной важностью.
+func (s LintSeverity) Valid() bool {
+	return s.rank() >= 0
+}
+
+// AtLeast сообщает, что важность не ниже other. Отключенное правило
+// не достигает никакой важности.
+func (s LintSeverity) AtLeast(other LintSeverity) bool {
+	return s != LintSeverityOff && other != LintSeverityOff && s.rank() >= other.rank()
+}
+
+func (s LintSeverity) rank() int {
+	switch s {
+	case LintSeverityOff:
+		return 0
+	case LintSeverityInfo:
+		return 1
+	case LintSeverityWarning:
+		return 2
+	case LintSeverityError:
+		return 3
+	}
+	return -1
+}
+
+// LintFinding - замечание линтера к скрипту миграции.
+type LintFinding struct {
+	// Rule - название правила, например drop_table.
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+	// Rollback - замечание относится к скрипту отката.
+	Rollback bool `json:"rollback,omitempty"`
+	// Line - номер строки скрипта (с единицы), 0 если замечание относится ко всему скрипту.
+	Line      int    `json:"line,omitempty"`
+	Statement string `json:"statement,omitempty"`
+	// Blocking - замечание запрещает создание миграции.
+	Blocking bool `json:"blocking,omitempty"`
+}
+
+// LintReport - результат проверки скриптов миграции линтером.
+type LintReport struct {
+	Findings []LintFinding `json:"findings"`
+	// Blocked - среди замечаний есть запрещающие создание миграции.
+	Blocked bool `json:"blocked"`
+}
+
+// LintError - создание миграции запрещено замечаниями линтера.
+type LintError struct {
+	Report LintReport
+}
+
+func (e *LintError) Error() string {
+	var blocking []string
+	for _, finding := range e.Report.Findings {
+		if !finding.Blocking {
+			continue
+		}
+		if finding.Line > 0 {
+			blocking = append(blocking, fmt.Sprintf("%s at line %d: %s", finding.Rule, finding.Line, finding.Message))
+		} else {
+			blocking = append(blocking, fmt.Sprintf("%s: %s", finding.Rule, finding.Message))
+		}
+	}
+	return "migration is rejected by linter: " + strings.Join(blocking, "; ")
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+var ErrLocked = fmt.Errorf("target is locked")
+
+// Lock - блокировка целевой базы данных на время применения или отката миграций.
+// Блокировка действует до ExpiresAt: реплика, которая ее удерживает, продлевает
+// срок, а после его истечения блокировку может захватить другая реплика.
+type Lock struct {
+	TargetID   int64     `json:"target_id" db:"target_id"`
+	Holder     string    `json:"holder" db:"holder"`
+	Token      string    `json:"-" db:"token"`
+	Operation  string    `json:"operation" db:"operation"`
+	AcquiredAt time.Time `json:"acquired_at" db:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+}
+
+type lockCheckKey struct{}
+
+// ContextWithLockCheck возвращает контекст с проверкой того, что блокировка
+// целевой базы данных все еще удерживается.
+func ContextWithLockCheck(ctx context.Context, check func(ctx context.Context) error) context.Context {
+	return context.WithValue(ctx, lockCheckKey{}, check)
+}
+
+// CheckLock проверяет, что блокировка целевой базы данных из контекста все еще
+// удерживается. Если блокировки в контексте нет, ничего не проверяет.
+func CheckLock(ctx context.Context) error {
+	check, ok := ctx.Value(lockCheckKey{}).(func(ctx context.Context) error)
+	if !ok {
+		return nil
+	}
+	return check(ctx)
+}
@@ -4,14 +4,26 @@ package entity
 import "time"
 
 type MigrationInfo struct {
-	ID              int64           `json:"id" db:"id"`
-	Name            string          `json:"name" db:"name"`
-	Description     string          `json:"description" db:"description"`
-	Script          string          `json:"script" db:"script"`
-	RollbackScript  string          `json:"rollback_script" db:"rollback_script"`
-	Status          MigrationStatus `json:"status" db:"status"`
-	CreatedBy       int64           `json:"created_by" db:"created_by"`
-	StatusUpdatedAt time.Time       `json:"status_updated_at" db:"status_updated_at"`
+	ID              int64             `json:"id" db:"id"`
+	TargetID        int64             `json:"target_id" db:"target_id"`
+	Name            string            `json:"name" db:"name"`
+	Description     string            `json:"description" db:"description"`
+	Script          string            `json:"script" db:"script"`
+	RollbackScript  string            `json:"rollback_script" db:"rollback_script"`
+	Checksum        string            `json:"checksum" db:"checksum"`
+	AppliedChecksum string            `json:"applied_checksum" db:"applied_checksum"`
+	ExecutionMode   ExecutionMode     `json:"execution_mode" db:"execution_mode"`
+	Kind            MigrationKind     `json:"kind" db:"kind"`
+	Backfill        *BackfillSpec     `json:"backfill,omitempty" db:"backfill"`
+	LastError       *ScriptError      `json:"last_error,omitempty" db:"last_error"`
+	Status          MigrationStatus   `json:"status" db:"status"`
+	CreatedBy       int64             `json:"created_by" db:"created_by"`
+	Labels          map[string]string `json:"labels,omitempty" db:"labels"`
+	TenantScoped    bool              `json:"tenant_scoped" db:"tenant_scoped"`
+	Templated       bool              `json:"templated" db:"templated"`
+	Baselined       bool              `json:"baselined" db:"baselined"`
+	BaselinedBy     int64             `json:"baselined_by" db:"baselined_by"`
+	StatusUpdatedAt time.Time         `json:"status_updated_at" db:"status_updated_at"`
 }
 
 type MigrationStatus string
@@ -20,8 +32,69 @@ const (
 	StatusPending    MigrationStatus = "pending"
 	StatusApplied    MigrationStatus = "applied"
 	StatusRolledBack MigrationStatus = "rolled_back"
+	// StatusPartiallyApplied - миграция без транзакции, часть операторов которой
+	// была выполнена до ошибки.
+	StatusPartiallyApplied MigrationStatus = "partially_applied"
+	// StatusFailed - скрипт миграции завершился ошибкой, изменения откатились.
+	// Такую миграцию можно применить повторно.
+	StatusFailed MigrationStatus = "failed"
 )
 
 func (s MigrationStatus) String() string {
 	return string(s)
 }
+
+// Applicable сообщает, можно ли применить миграцию с этим статусом.
+func (s MigrationStatus) Applicable() bool {
+	return s == StatusPending || s == StatusFailed
+}
+
+// Applied сообщает, выполнялся ли скрипт миграции с этим статусом на целевой базе
+// данных: миграцию без транзакции, примененную частично, тоже можно откатить.
+func (s MigrationStatus) Applied() bool {
+	return s == StatusApplied || s == StatusPartiallyApplied
+}
+
+// ExecutionMode - режим выполнения скриптов миграции.
+type ExecutionMode string
+
+const (
+	// ExecutionModeTransactional - скрипты выполняются в транзакции вместе с изменением статуса.
+	ExecutionModeTransactional ExecutionMode = "transactional"
+	// ExecutionModeNoTransaction - операторы скриптов выполняются по одному на отдельном
+	// подключении вне транзакции (CREATE INDEX CONCURRENTLY, VACUUM и т.п.).
+	ExecutionModeNoTransaction ExecutionMode = "no_transaction"
+)
+
+func (m ExecutionMode) String() string {
+	return string(m)
+}
+
+// Valid сообщает, является ли значение известным режимом выполнения.
+func (m ExecutionMode) Valid() bool {
+	return m == ExecutionModeTransactional || m == ExecutionModeNoTransaction
+}
+
+// MigrationKind - вид миграции.
+type MigrationKind string
+
+const (
+	// MigrationKindVersioned - миграция выполняется один раз и откатывается скриптом отката.
+	MigrationKindVersioned MigrationKind = "versioned"
+	// MigrationKindRepeatable - миграция пересоздает объекты (представления, функции,
+	// триггеры) и выполняется заново при каждом изменении скрипта, после версионных
+	// миграций запроса. Скрипта отката у нее нет.
+	MigrationKindRepeatable MigrationKind = "repeatable"
+	// MigrationKindBackfill - миграция данных, которая выполняется многими небольшими
+	// транзакциями по курсору (см. BackfillSpec) и не откатывается.
+	MigrationKindBackfill MigrationKind = "backfill"
+)
+
+func (k MigrationKind) String() string {
+	return string(k)
+}
+
+// Valid сообщает, является ли значение известным видом миграции.
+func (k MigrationKind) Valid() bool {
+	return k == MigrationKindVersioned || k == MigrationKindRepeatable || k == MigrationKindBackfill
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// PlanAction - действие, для которого строится план.
+type PlanAction string
+
+const (
+	PlanActionApply    PlanAction = "apply"
+	PlanActionRollback PlanAction = "rollback"
+)
+
+func (a PlanAction) String() string {
+	return string(a)
+}
+
+// Plan - отчет о пробном выполнении миграций. Все изменения,
+// сделанные при его построении, откатываются.
+type Plan struct {
+	Action   PlanAction `json:"action"`
+	TargetID int64      `json:"target_id"`
+	Items    []PlanItem `json:"items"`
+}
+
+// Success сообщает, что ни одна миграция плана не отклонена и не завершилась ошибкой.
+func (p Plan) Success() bool {
+	for _, item := range p.Items {
+		if item.Rejected || !item.Success {
+			return false
+		}
+	}
+	return true
+}
+
+// PlanItem - результат пробного выполнения одной миграции.
+type PlanItem struct {
+	MigrationID  int64           `json:"migration_id"`
+	Name         string          `json:"name"`
+	Order        int             `json:"order"`
+	Status       MigrationStatus `json:"status"`
+	Rejected     bool            `json:"rejected"`
+	RejectReason string          `json:"reject_reason"`
+	Success      bool            `json:"success"`
+	Error        string          `json:"error"`
+	Duration     time.Duration   `json:"duration"`
+	RowsAffected int64           `json:"rows_affected"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// MigrationRevision - выполненная ревизия скрипта повторяемой миграции.
+// Revision - порядковый номер выполнения, начиная с 1, Script - выполненный
+// скрипт после подстановки переменных шаблона.
+type MigrationRevision struct {
+	MigrationID int64     `json:"migration_id" db:"migration_id"`
+	Revision    int64     `json:"revision" db:"revision"`
+	Checksum    string    `json:"checksum" db:"checksum"`
+	Script      string    `json:"script" db:"script"`
+	AppliedBy   int64     `json:"applied_by" db:"applied_by"`
+	AppliedAt   time.Time `json:"applied_at" db:"applied_at"`
+}
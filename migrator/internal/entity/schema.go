@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// SchemaObjectKind - вид объекта схемы целевой базы данных.
+type SchemaObjectKind string
+
+const (
+	SchemaObjectTable      SchemaObjectKind = "table"
+	SchemaObjectView       SchemaObjectKind = "view"
+	SchemaObjectColumn     SchemaObjectKind = "column"
+	SchemaObjectIndex      SchemaObjectKind = "index"
+	SchemaObjectConstraint SchemaObjectKind = "constraint"
+	SchemaObjectFunction   SchemaObjectKind = "function"
+	SchemaObjectTrigger    SchemaObjectKind = "trigger"
+)
+
+func (k SchemaObjectKind) String() string {
+	return string(k)
+}
+
+// SchemaObject - объект схемы целевой базы данных. Name - полное имя объекта
+// (для столбцов и ограничений вместе с таблицей), Definition - определение,
+// по которому обнаруживается изменение объекта: тип столбца, текст индекса,
+// тело функции.
+type SchemaObject struct {
+	Kind       SchemaObjectKind `json:"kind"`
+	Name       string           `json:"name"`
+	Definition string           `json:"definition"`
+}
+
+// SortSchemaObjects упорядочивает объекты схемы по виду и имени.
+func SortSchemaObjects(objects []SchemaObject) {
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Kind != objects[j].Kind {
+			return objects[i].Kind < objects[j].Kind
+		}
+		return objects[i].Name < objects[j].Name
+	})
+}
+
+// SchemaSnapshot - снимок схемы целевой базы данных, сохраненный после применения
+// или отката миграций. Action и UserID - действие и пользователь, после которых
+// снят снимок.
+type SchemaSnapshot struct {
+	ID       int64          `json:"id" db:"id"`
+	TargetID int64          `json:"target_id" db:"target_id"`
+	Action   HistoryAction  `json:"action" db:"action"`
+	UserID   int64          `json:"user_id" db:"user_id"`
+	Objects  []SchemaObject `json:"objects" db:"objects"`
+	TakenAt  time.Time      `json:"taken_at" db:"taken_at"`
+}
+
+// SchemaChange - вид расхождения схемы с последним снимком.
+type SchemaChange string
+
+const (
+	// SchemaChangeAdded - объект появился после снимка.
+	SchemaChangeAdded SchemaChange = "added"
+	// SchemaChangeRemoved - объект удален после снимка.
+	SchemaChangeRemoved SchemaChange = "removed"
+	// SchemaChangeChanged - определение объекта изменилось после снимка.
+	SchemaChangeChanged SchemaChange = "changed"
+)
+
+func (c SchemaChange) String() string {
+	return string(c)
+}
+
+// SchemaDrift - объект схемы, измененный в обход сервиса миграций. Recorded -
+// определение в последнем снимке, Live - на целевой базе данных.
+type SchemaDrift struct {
+	Kind     SchemaObjectKind
+	Name     string
+	Change   SchemaChange
+	Recorded string
+	Live     string
+}
+
+// DiffSchema сравнивает схему из снимка recorded с текущей схемой live
+// и возвращает расхождения, упорядоченные по виду и имени объекта.
+func DiffSchema(recorded, live []SchemaObject) []SchemaDrift {
+	type key struct {
+		kind SchemaObjectKind
+		name string
+	}
+
+	liveByKey := make(map[key]SchemaObject, len(live))
+	for _, object := range live {
+		liveByKey[key{object.Kind, object.Name}] = object
+	}
+
+	var drift []SchemaDrift
+	for _, object := range recorded {
+		k := key{object.Kind, object.Name}
+		current, ok := liveByKey[k]
+		delete(liveByKey, k)
+
+		switch {
+		case !ok:
+			drift = append(drift, SchemaDrift{Kind: object.Kind, Name: object.Name, Change: SchemaChangeRemoved, Recorded: object.Definition})
+		case current.Definition != object.Definition:
+			drift = append(drift, SchemaDrift{Kind: object.Kind, Name: object.Name, Change: SchemaChangeChanged, Recorded: object.Definition, Live: current.Definition})
+		}
+	}
+
+	for _, object := range liveByKey {
+		drift = append(drift, SchemaDrift{Kind: object.Kind, Name: object.Name, Change: SchemaChangeAdded, Live: object.Definition})
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Kind != drift[j].Kind {
+			return drift[i].Kind < drift[j].Kind
+		}
+		return drift[i].Name < drift[j].Name
+	})
+
+	return drift
+}
+
+// DatabaseStatus - состояние схемы целевой базы данных: текущие объекты,
+// последний сохраненный снимок (nil, если снимков еще нет) и расхождения с ним.
+type DatabaseStatus struct {
+	TargetID int64
+	Objects  []SchemaObject
+	Snapshot *SchemaSnapshot
+	Drift    []SchemaDrift
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScriptError - ошибка базы данных при выполнении скрипта миграции.
+type ScriptError struct {
+	// SQLState - код ошибки базы данных: SQLSTATE для PostgreSQL и MySQL,
+	// название кода результата для SQLite.
+	SQLState string `json:"sqlstate"`
+	Message  string `json:"message"`
+	Detail   string `json:"detail,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+	// Position - позиция ошибки в символах от начала выполненного текста (с единицы), 0 если неизвестна.
+	Position int32 `json:"position,omitempty"`
+	// Line - номер строки скрипта миграции (с единицы), 0 если неизвестен.
+	Line int `json:"line,omitempty"`
+	// Statement - оператор скрипта, на котором произошла ошибка.
+	Statement string `json:"statement,omitempty"`
+
+	err error
+}
+
+// NewScriptError создает ошибку выполнения скрипта, сохраняя исходную ошибку для errors.Is/As.
+func NewScriptError(se ScriptError, err error) *ScriptError {
+	se.err = err
+	return &se
+}
+
+func (e *ScriptError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (SQLSTATE %s)", e.Message, e.SQLState)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, " at line %d", e.Line)
+	}
+	if e.Detail != "" {
+		fmt.Fprintf(&b, ": %s", e.Detail)
+	}
+	if e.Hint != "" {
+		fmt.Fprintf(&b, " (hint: %s)", e.Hint)
+	}
+	return b.String()
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.err
+}
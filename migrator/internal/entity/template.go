@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrTemplate - скрипт миграции не удалось подставить в шаблон: переменная
+// не задана для целевой базы данных или ее значение нельзя вставить без экранирования.
+var ErrTemplate = errors.New("script template error")
+
+// templatePlaceholder - подстановка переменной в скрипт: {{ имя }} или {{ имя | фильтр }}.
+// Подстановка с обратной косой чертой перед ней (\{{ имя }}) экранирована.
+// Текст, не подходящий под это выражение, остается в скрипте без изменений.
+var templatePlaceholder = regexp.MustCompile(`(\\)?\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*(?:\|\s*([a-z]+)\s*)?\}\}`)
+
+// variableName - допустимое имя переменной шаблона.
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// rawValue - значение, которое можно вставить в скрипт без экранирования:
+// имя без кавычек, число или имя со схемой.
+var rawValue = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// Фильтры подстановки переменной.
+const (
+	// TemplateFilterRaw вставляет значение как есть; допускаются только буквы, цифры, '_' и '.'.
+	TemplateFilterRaw = "raw"
+	// TemplateFilterIdent вставляет значение идентификатором в кавычках СУБД.
+	TemplateFilterIdent = "ident"
+	// TemplateFilterLiteral вставляет значение строковым литералом.
+	TemplateFilterLiteral = "literal"
+)
+
+// ValidateVariables проверяет имена переменных шаблонов.
+func ValidateVariables(variables map[string]string) error {
+	for name := range variables {
+		if !variableName.MatchString(name) {
+			return fmt.Errorf("%w: invalid template variable name %q", ErrInvalidArgument, name)
+		}
+	}
+	return nil
+}
+
+// TemplateContext - переменные шаблонов целевой базы данных: переменные окружения,
+// дополненные и переопределенные переменными самой базы данных.
+type TemplateContext struct {
+	Driver    Driver
+	Variables map[string]string
+}
+
+// RenderedScript - скрипт миграции после подстановки переменных
+// и значения переменных, которые в нем использованы.
+type RenderedScript struct {
+	SQL       string
+	Variables map[string]string
+}
+
+// Render подставляет переменные в скрипт. Фильтр ident заключает значение
+// в кавычки идентификатора СУБД, literal - в кавычки строки, а без фильтра (raw)
+// значение вставляется как есть, только если не требует экранирования.
+// Экранированная подстановка \{{ имя }} остается в скрипте как {{ имя }}.
+func (c TemplateContext) Render(script string) (RenderedScript, error) {
+	rendered := RenderedScript{SQL: script}
+
+	var renderErr error
+	sql := templatePlaceholder.ReplaceAllStringFunc(script, func(placeholder string) string {
+		if renderErr != nil {
+			return placeholder
+		}
+
+		match := templatePlaceholder.FindStringSubmatch(placeholder)
+		if match[1] != "" {
+			return strings.TrimPrefix(placeholder, match[1])
+		}
+		name, filter := match[2], match[3]
+		value, ok := c.Variables[name]
+		if !ok {
+			renderErr = fmt.Errorf("%w: variable %s is not defined for the target database", ErrTemplate, name)
+			return placeholder
+		}
+
+		quoted, err := c.quote(value, filter)
+		if err != nil {
+			renderErr = fmt.Errorf("%w: variable %s: %v", ErrTemplate, name, err)
+			return placeholder
+		}
+
+		if rendered.Variables == nil {
+			rendered.Variables = make(map[string]string)
+		}
+		rendered.Variables[name] = value
+		return quoted
+	})
+	if renderErr != nil {
+		return RenderedScript{}, renderErr
+	}
+
+	rendered.SQL = sql
+	return rendered, nil
+}
+
+func (c TemplateContext) quote(value, filter string) (string, error) {
+	switch filter {
+	case "", TemplateFilterRaw:
+		if !rawValue.MatchString(value) {
+			return "", fmt.Errorf("value %q must be inserted with the %s or %s filter", value, TemplateFilterIdent, TemplateFilterLiteral)
+		}
+		return value, nil
+	case TemplateFilterIdent:
+		if c.Driver == DriverMySQL {
+			return "`" + strings.ReplaceAll(value, "`", "``") + "`", nil
+		}
+		return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`, nil
+	case TemplateFilterLiteral:
+		if c.Driver == DriverMySQL {
+			value = strings.ReplaceAll(value, `\`, `\\`)
+		}
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'", nil
+	default:
+		return "", fmt.Errorf("unknown filter %q", filter)
+	}
+}
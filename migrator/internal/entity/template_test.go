@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTemplateContextRender(t *testing.T) {
+	variables := map[string]string{
+		"schema": "app",
+		"table":  "public.users",
+		"owner":  "O'Brien",
+		"quoted": `my "table"`,
+		"path":   `C:\data`,
+	}
+
+	tests := []struct {
+		name          string
+		driver        Driver
+		script        string
+		want          string
+		wantVariables map[string]string
+		wantErr       bool
+	}{
+		{
+			name:   "no placeholders",
+			driver: DriverPostgres,
+			script: "SELECT '{' || '}'",
+			want:   "SELECT '{' || '}'",
+		},
+		{
+			name:          "raw value",
+			driver:        DriverPostgres,
+			script:        "CREATE TABLE {{ schema }}.t (); SELECT * FROM {{table}}",
+			want:          "CREATE TABLE app.t (); SELECT * FROM public.users",
+			wantVariables: map[string]string{"schema": "app", "table": "public.users"},
+		},
+		{
+			name:          "explicit raw filter",
+			driver:        DriverPostgres,
+			script:        "SET search_path TO {{ schema | raw }}",
+			want:          "SET search_path TO app",
+			wantVariables: map[string]string{"schema": "app"},
+		},
+		{
+			name:    "raw value that needs quoting",
+			driver:  DriverPostgres,
+			script:  "COMMENT ON TABLE t IS {{ owner }}",
+			wantErr: true,
+		},
+		{
+			name:          "postgres ident",
+			driver:        DriverPostgres,
+			script:        "DROP TABLE {{ quoted | ident }}",
+			want:          `DROP TABLE "my ""table"""`,
+			wantVariables: map[string]string{"quoted": `my "table"`},
+		},
+		{
+			name:          "mysql ident",
+			driver:        DriverMySQL,
+			script:        "DROP TABLE {{ quoted | ident }}",
+			want:          "DROP TABLE `my \"table\"`",
+			wantVariables: map[string]string{"quoted": `my "table"`},
+		},
+		{
+			name:          "postgres literal",
+			driver:        DriverPostgres,
+			script:        "SELECT {{ owner | literal }}, {{ path | literal }}",
+			want:          `SELECT 'O''Brien', 'C:\data'`,
+			wantVariables: map[string]string{"owner": "O'Brien", "path": `C:\data`},
+		},
+		{
+			name:          "mysql literal escapes backslashes",
+			driver:        DriverMySQL,
+			script:        "SELECT {{ owner | literal }}, {{ path | literal }}",
+			want:          `SELECT 'O''Brien', 'C:\\data'`,
+			wantVariables: map[string]string{"owner": "O'Brien", "path": `C:\data`},
+		},
+		{
+			name:          "escaped placeholder",
+			driver:        DriverPostgres,
+			script:        `SELECT '\{{ schema }}', {{ schema | literal }}, '\{{ undefined }}'`,
+			want:          `SELECT '{{ schema }}', 'app', '{{ undefined }}'`,
+			wantVariables: map[string]string{"schema": "app"},
+		},
+		{
+			name:   "not a placeholder",
+			driver: DriverPostgres,
+			script: "SELECT '{{ 1 }}', '{{ a b }}', '{{}}'",
+			want:   "SELECT '{{ 1 }}', '{{ a b }}', '{{}}'",
+		},
+		{
+			name:    "undefined variable",
+			driver:  DriverPostgres,
+			script:  "SELECT * FROM {{ undefined }}",
+			wantErr: true,
+		},
+		{
+			name:    "unknown filter",
+			driver:  DriverPostgres,
+			script:  "SELECT {{ schema | upper }}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := TemplateContext{Driver: tt.driver, Variables: variables}
+			got, err := c.Render(tt.script)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrTemplate) {
+					t.Errorf("Render() error = %v, want ErrTemplate", err)
+				}
+				return
+			}
+			if got.SQL != tt.want {
+				t.Errorf("Render() SQL = %q, want %q", got.SQL, tt.want)
+			}
+			if !reflect.DeepEqual(got.Variables, tt.wantVariables) {
+				t.Errorf("Render() Variables = %v, want %v", got.Variables, tt.wantVariables)
+			}
+		})
+	}
+}
+
+func TestValidateVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables map[string]string
+		wantErr   bool
+	}{
+		{name: "valid names", variables: map[string]string{"schema": "a", "_owner2": "b"}},
+		{name: "starts with digit", variables: map[string]string{"2schema": "a"}, wantErr: true},
+		{name: "contains dash", variables: map[string]string{"my-schema": "a"}, wantErr: true},
+		{name: "empty name", variables: map[string]string{"": "a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateVariables(tt.variables)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateVariables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
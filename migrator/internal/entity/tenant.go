@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// TenantVariable - переменная шаблона, в которую подставляется схема арендатора.
+const TenantVariable = "tenant"
+
+// TenantStatus - состояние миграции в схеме одного арендатора.
+// Status - applied или failed, Error - текст ошибки последней попытки.
+type TenantStatus struct {
+	MigrationID int64           `json:"migration_id" db:"migration_id"`
+	Tenant      string          `json:"tenant" db:"tenant"`
+	Status      MigrationStatus `json:"status" db:"status"`
+	Error       string          `json:"error" db:"error"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// TenantApplyResult - результат применения миграции к схемам арендаторов.
+// Applied - схемы, к которым миграция применена этим запросом, Skipped - схемы,
+// к которым она была применена раньше, Failed - схемы, в которых скрипт
+// завершился ошибкой; их можно повторить следующим запросом.
+type TenantApplyResult struct {
+	MigrationID int64
+	Applied     []string
+	Skipped     []string
+	Failed      []TenantStatus
+	FinishedAt  time.Time
+}
@@ -0,0 +1,70 @@
+// Package audit содержит журнал аудита вызовов API сервиса миграций:
+// кто, когда и с какого адреса вызвал метод и чем закончился вызов.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+)
+
+// _recordTimeout - время на запись события в журнал аудита.
+const _recordTimeout = 5 * time.Second
+
+type auditRepository interface {
+	Add(ctx context.Context, event entity.AuditEvent) error
+	List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEvent, error)
+}
+
+// Service - сервис журнала аудита.
+type Service struct {
+	repo auditRepository
+}
+
+// New - конструктор сервиса журнала аудита.
+func New(repo auditRepository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// Record записывает событие в журнал аудита. Запись выполняется и после
+// отмены запроса; ошибка записи не меняет результат вызова и только логируется.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	event: entity.AuditEvent - Событие; время события проставляется, если не задано.
+func (s *Service) Record(ctx context.Context, event entity.AuditEvent) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _recordTimeout)
+	defer cancel()
+
+	if err := s.repo.Add(ctx, event); err != nil {
+		logger.Error(fmt.Errorf("s.repo.Add: %w", err))
+	}
+}
+
+// ListAuditEvents возвращает записи журнала аудита.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	filter: entity.AuditFilter - Условия выборки и размер страницы.
+//
+// Возвращает:
+//
+//	[]entity.AuditEvent: Записи журнала от новых к старым.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) ListAuditEvents(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEvent, error) {
+	events, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.List: %w", err)
+	}
+
+	return events, nil
+}
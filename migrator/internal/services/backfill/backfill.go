@@ -0,0 +1,354 @@
+// Package backfill содержит бизнес-логику миграций данных, которые выполняются
+// многими небольшими транзакциями с сохранением курсора.
+//
+// Пачки выполняет фоновый обработчик Run. Ход выполнения хранится в базе данных
+// сервиса, поэтому после перезапуска реплики миграция данных продолжается
+// с сохраненного курсора. Пачка и сохранение курсора выполняются в разных базах
+// данных: после сбоя между ними пачка выполняется повторно, поэтому скрипт
+// миграции данных должен быть идемпотентным.
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+const (
+	_defaultPollInterval = 10 * time.Second
+	_defaultLease        = 5 * time.Minute
+	_recordTimeout       = 5 * time.Second
+)
+
+type backfillRepository interface {
+	Create(ctx context.Context, backfill entity.Backfill) error
+	Get(ctx context.Context, migrationID int64) (entity.Backfill, error)
+	Transition(ctx context.Context, migrationID int64, from []entity.BackfillState, to entity.BackfillState, errText string, at time.Time) (bool, error)
+	Throttle(ctx context.Context, migrationID int64, batchSize int, pause time.Duration, at time.Time) error
+	ListClaimable(ctx context.Context, owner string, lease time.Duration) ([]entity.Backfill, error)
+	Claim(ctx context.Context, migrationID int64, owner string, lease time.Duration) (entity.Backfill, bool, error)
+	Advance(ctx context.Context, migrationID int64, owner, cursor string, rows int64, at time.Time) (bool, error)
+	Finish(ctx context.Context, migrationID int64, state entity.BackfillState, errText string, at time.Time) error
+	RunBatch(ctx context.Context, targetID int64, cursorQuery string, render func(keys []string) (string, error)) ([]string, error)
+	Estimate(ctx context.Context, targetID int64, query string) (int64, error)
+}
+
+type migrationRepository interface {
+	Get(ctx context.Context, migrationID int64) (entity.MigrationInfo, error)
+	SetStatus(ctx context.Context, migrationID int64, updatedAt time.Time, status entity.MigrationStatus) error
+	SetLastError(ctx context.Context, migrationID int64, scriptErr *entity.ScriptError) error
+	SetAppliedChecksum(ctx context.Context, migrationID int64, checksum string) error
+	RecordOnTarget(ctx context.Context, migration entity.MigrationInfo, status entity.MigrationStatus, updatedAt time.Time) error
+}
+
+type historyRepository interface {
+	Add(ctx context.Context, entries []entity.HistoryEntry) error
+}
+
+// scriptTemplates - переменные шаблонов скриптов целевых баз данных.
+type scriptTemplates interface {
+	TemplateContext(ctx context.Context, targetID int64) (entity.TemplateContext, error)
+}
+
+// promotionChecker - правила продвижения миграций по окружениям.
+type promotionChecker interface {
+	CheckPromotion(ctx context.Context, targetID int64, migrations []entity.MigrationInfo) error
+}
+
+// Service - сервис миграций данных.
+type Service struct {
+	repo       backfillRepository
+	migrations migrationRepository
+	history    historyRepository
+	templates  scriptTemplates
+	promotion  promotionChecker
+
+	owner        string
+	lease        time.Duration
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	running map[int64]bool
+	wake    chan struct{}
+}
+
+// New - конструктор сервиса миграций данных.
+//
+// owner - имя реплики, которая берет миграции данных в аренду,
+// lease - через сколько после последней пачки аренду может взять другая реплика,
+// pollInterval - как часто искать миграции данных, оставшиеся без реплики.
+func New(
+	repo backfillRepository,
+	migrations migrationRepository,
+	history historyRepository,
+	templates scriptTemplates,
+	promotion promotionChecker,
+	owner string,
+	lease, pollInterval time.Duration,
+) *Service {
+	if lease <= 0 {
+		lease = _defaultLease
+	}
+	if pollInterval <= 0 {
+		pollInterval = _defaultPollInterval
+	}
+	return &Service{
+		repo:       repo,
+		migrations: migrations,
+		history:    history,
+		templates:  templates,
+		promotion:  promotion,
+
+		owner:        owner,
+		lease:        lease,
+		pollInterval: pollInterval,
+
+		running: make(map[int64]bool),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// StartBackfill запускает миграцию данных: сохраняет начальный курсор и оценку
+// общего числа строк и помечает миграцию частично примененной. Пачки выполняются
+// в фоне; ход выполнения возвращает GetBackfill.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	migrationID: int64 - Уникальный идентификатор миграции данных.
+//	userID: int64 - Идентификатор пользователя, запустившего миграцию данных.
+//
+// Возвращает:
+//
+//	entity.Backfill: Ход выполнения запущенной миграции данных.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) StartBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error) {
+	migration, err := s.migrations.Get(ctx, migrationID)
+	if err != nil {
+		return entity.Backfill{}, fmt.Errorf("s.migrations.Get: %w", err)
+	}
+
+	if migration.Kind != entity.MigrationKindBackfill || migration.Backfill == nil {
+		return entity.Backfill{}, fmt.Errorf("%w: migration %d is not a backfill", entity.ErrInvalidArgument, migrationID)
+	}
+
+	if !migration.Status.Applicable() {
+		return entity.Backfill{}, fmt.Errorf("%w: migration %d cannot be applied in status %s", entity.ErrFailedPrecondition, migrationID, migration.Status)
+	}
+
+	if err := migration.VerifyChecksum(); err != nil {
+		return entity.Backfill{}, err
+	}
+
+	_, err = s.repo.Get(ctx, migrationID)
+	if err == nil {
+		return entity.Backfill{}, fmt.Errorf("%w: backfill of migration %d is already started, use ResumeBackfill", entity.ErrAlreadyExists, migrationID)
+	}
+	if !errors.Is(err, entity.ErrNotFound) {
+		return entity.Backfill{}, fmt.Errorf("s.repo.Get: %w", err)
+	}
+
+	err = s.promotion.CheckPromotion(ctx, migration.TargetID, []entity.MigrationInfo{migration})
+	if err != nil {
+		return entity.Backfill{}, fmt.Errorf("s.promotion.CheckPromotion: %w", err)
+	}
+
+	spec := *migration.Backfill
+	if _, _, err := s.renderCursorQuery(ctx, migration, spec.StartCursor, spec.BatchSize); err != nil {
+		return entity.Backfill{}, err
+	}
+
+	now := time.Now()
+	backfill := entity.Backfill{
+		MigrationID:    migrationID,
+		TargetID:       migration.TargetID,
+		State:          entity.BackfillStateRunning,
+		Cursor:         spec.StartCursor,
+		EstimatedTotal: -1,
+		BatchSize:      spec.BatchSize,
+		StartedBy:      userID,
+		StartedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if spec.EstimateQuery != "" {
+		backfill.EstimatedTotal, err = s.estimate(ctx, migration)
+		if err != nil {
+			return entity.Backfill{}, err
+		}
+	}
+
+	if err := s.repo.Create(ctx, backfill); err != nil {
+		return entity.Backfill{}, fmt.Errorf("s.repo.Create: %w", err)
+	}
+
+	err = s.migrations.SetStatus(ctx, migrationID, now, entity.StatusPartiallyApplied)
+	if err != nil {
+		return entity.Backfill{}, fmt.Errorf("s.migrations.SetStatus: %w", err)
+	}
+
+	err = s.migrations.SetLastError(ctx, migrationID, nil)
+	if err != nil {
+		return entity.Backfill{}, fmt.Errorf("s.migrations.SetLastError: %w", err)
+	}
+
+	s.notify()
+
+	return backfill, nil
+}
+
+// PauseBackfill приостанавливает миграцию данных. Выполняемая пачка
+// завершается, следующая не начинается до ResumeBackfill.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	migrationID: int64 - Уникальный идентификатор миграции данных.
+//
+// Возвращает:
+//
+//	entity.Backfill: Ход выполнения миграции данных.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) PauseBackfill(ctx context.Context, migrationID int64) (entity.Backfill, error) {
+	from := []entity.BackfillState{entity.BackfillStateRunning}
+	if err := s.transition(ctx, migrationID, from, entity.BackfillStatePaused); err != nil {
+		return entity.Backfill{}, err
+	}
+
+	return s.GetBackfill(ctx, migrationID)
+}
+
+// ResumeBackfill продолжает приостановленную или завершившуюся ошибкой
+// миграцию данных с сохраненного курсора.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	migrationID: int64 - Уникальный идентификатор миграции данных.
+//
+// Возвращает:
+//
+//	entity.Backfill: Ход выполнения миграции данных.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) ResumeBackfill(ctx context.Context, migrationID int64) (entity.Backfill, error) {
+	from := []entity.BackfillState{entity.BackfillStatePaused, entity.BackfillStateFailed}
+	if err := s.transition(ctx, migrationID, from, entity.BackfillStateRunning); err != nil {
+		return entity.Backfill{}, err
+	}
+
+	err := s.migrations.SetLastError(ctx, migrationID, nil)
+	if err != nil {
+		return entity.Backfill{}, fmt.Errorf("s.migrations.SetLastError: %w", err)
+	}
+
+	s.notify()
+
+	return s.GetBackfill(ctx, migrationID)
+}
+
+// transition переводит миграцию данных в состояние to из одного из состояний from.
+func (s *Service) transition(ctx context.Context, migrationID int64, from []entity.BackfillState, to entity.BackfillState) error {
+	ok, err := s.repo.Transition(ctx, migrationID, from, to, "", time.Now())
+	if err != nil {
+		return fmt.Errorf("s.repo.Transition: %w", err)
+	}
+	if ok {
+		return nil
+	}
+
+	backfill, err := s.repo.Get(ctx, migrationID)
+	if err != nil {
+		return fmt.Errorf("s.repo.Get: %w", err)
+	}
+	return fmt.Errorf("%w: backfill of migration %d cannot become %s in state %s", entity.ErrInvalidArgument, migrationID, to, backfill.State)
+}
+
+// ThrottleBackfill изменяет размер пачки и паузу между пачками миграции данных.
+// Новые значения применяются со следующей пачки.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	migrationID: int64 - Уникальный идентификатор миграции данных.
+//	batchSize: int - Размер пачки; 0 оставляет текущий.
+//	pause: time.Duration - Пауза между пачками.
+//
+// Возвращает:
+//
+//	entity.Backfill: Ход выполнения миграции данных.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) ThrottleBackfill(ctx context.Context, migrationID int64, batchSize int, pause time.Duration) (entity.Backfill, error) {
+	if batchSize < 0 {
+		return entity.Backfill{}, fmt.Errorf("%w: batch size cannot be negative", entity.ErrInvalidArgument)
+	}
+	if pause < 0 {
+		return entity.Backfill{}, fmt.Errorf("%w: pause cannot be negative", entity.ErrInvalidArgument)
+	}
+
+	backfill, err := s.repo.Get(ctx, migrationID)
+	if err != nil {
+		return entity.Backfill{}, fmt.Errorf("s.repo.Get: %w", err)
+	}
+
+	if backfill.State == entity.BackfillStateCompleted {
+		return entity.Backfill{}, fmt.Errorf("%w: backfill of migration %d is completed", entity.ErrInvalidArgument, migrationID)
+	}
+
+	if batchSize == 0 {
+		batchSize = backfill.BatchSize
+	}
+
+	err = s.repo.Throttle(ctx, migrationID, batchSize, pause, time.Now())
+	if err != nil {
+		return entity.Backfill{}, fmt.Errorf("s.repo.Throttle: %w", err)
+	}
+
+	return s.GetBackfill(ctx, migrationID)
+}
+
+// GetBackfill возвращает ход выполнения миграции данных.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	migrationID: int64 - Уникальный идентификатор миграции данных.
+//
+// Возвращает:
+//
+//	entity.Backfill: Ход выполнения миграции данных.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) GetBackfill(ctx context.Context, migrationID int64) (entity.Backfill, error) {
+	backfill, err := s.repo.Get(ctx, migrationID)
+	if err != nil {
+		return entity.Backfill{}, fmt.Errorf("s.repo.Get: %w", err)
+	}
+
+	return backfill, nil
+}
+
+// estimate выполняет запрос оценки общего числа строк миграции данных.
+func (s *Service) estimate(ctx context.Context, migration entity.MigrationInfo) (int64, error) {
+	templateContext, err := s.templates.TemplateContext(ctx, migration.TargetID)
+	if err != nil {
+		return 0, fmt.Errorf("s.templates.TemplateContext: %w", err)
+	}
+
+	query, err := templateContext.Render(migration.Backfill.EstimateQuery)
+	if err != nil {
+		return 0, fmt.Errorf("migration %d estimate query: %w", migration.ID, err)
+	}
+
+	total, err := s.repo.Estimate(ctx, migration.TargetID, query.SQL)
+	if err != nil {
+		return 0, fmt.Errorf("s.repo.Estimate: %w", err)
+	}
+
+	return total, nil
+}
+
+// notify будит фоновый обработчик, не дожидаясь очередного опроса.
+func (s *Service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,407 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+const (
+	testMigration = int64(10)
+	testTarget    = int64(1)
+)
+
+// memoryBackfills - ход выполнения миграций данных и таблица целевой базы данных
+// в памяти. Пачка - следующие ключи строк после курсора в порядке возрастания.
+// Методы, которые тесты не вызывают, остаются у встроенного интерфейса.
+type memoryBackfills struct {
+	backfillRepository
+	backfills map[int64]entity.Backfill
+	rows      []string
+	failing   string
+	scripts   []string
+}
+
+func newMemoryBackfills(rows ...string) *memoryBackfills {
+	return &memoryBackfills{backfills: make(map[int64]entity.Backfill), rows: rows}
+}
+
+func (r *memoryBackfills) Create(_ context.Context, backfill entity.Backfill) error {
+	r.backfills[backfill.MigrationID] = backfill
+	return nil
+}
+
+func (r *memoryBackfills) Get(_ context.Context, migrationID int64) (entity.Backfill, error) {
+	backfill, ok := r.backfills[migrationID]
+	if !ok {
+		return entity.Backfill{}, entity.ErrNotFound
+	}
+	return backfill, nil
+}
+
+func (r *memoryBackfills) Transition(_ context.Context, migrationID int64, from []entity.BackfillState, to entity.BackfillState, errText string, at time.Time) (bool, error) {
+	backfill, ok := r.backfills[migrationID]
+	if !ok || !slices.Contains(from, backfill.State) {
+		return false, nil
+	}
+	backfill.State, backfill.Error, backfill.UpdatedAt = to, errText, at
+	r.backfills[migrationID] = backfill
+	return true, nil
+}
+
+func (r *memoryBackfills) Throttle(_ context.Context, migrationID int64, batchSize int, pause time.Duration, _ time.Time) error {
+	backfill := r.backfills[migrationID]
+	backfill.BatchSize, backfill.Pause = batchSize, pause
+	r.backfills[migrationID] = backfill
+	return nil
+}
+
+func (r *memoryBackfills) Claim(_ context.Context, migrationID int64, owner string, _ time.Duration) (entity.Backfill, bool, error) {
+	backfill, ok := r.backfills[migrationID]
+	if !ok || backfill.State != entity.BackfillStateRunning {
+		return entity.Backfill{}, false, nil
+	}
+	backfill.Owner = owner
+	r.backfills[migrationID] = backfill
+	return backfill, true, nil
+}
+
+func (r *memoryBackfills) Advance(_ context.Context, migrationID int64, owner, cursor string, rows int64, _ time.Time) (bool, error) {
+	backfill := r.backfills[migrationID]
+	if backfill.State != entity.BackfillStateRunning || backfill.Owner != owner {
+		return false, nil
+	}
+	backfill.Cursor = cursor
+	backfill.RowsDone += rows
+	r.backfills[migrationID] = backfill
+	return true, nil
+}
+
+func (r *memoryBackfills) Finish(_ context.Context, migrationID int64, state entity.BackfillState, errText string, at time.Time) error {
+	backfill := r.backfills[migrationID]
+	backfill.State, backfill.Error, backfill.FinishedAt = state, errText, at
+	r.backfills[migrationID] = backfill
+	return nil
+}
+
+func (r *memoryBackfills) RunBatch(_ context.Context, _ int64, _ string, render func(keys []string) (string, error)) ([]string, error) {
+	backfill := r.backfills[testMigration]
+
+	var keys []string
+	for _, key := range r.rows {
+		if key > backfill.Cursor && len(keys) < backfill.BatchSize {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	script, err := render(keys)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(keys, r.failing) {
+		return nil, entity.NewScriptError(entity.ScriptError{SQLState: "23505", Message: "duplicate key"}, errors.New("duplicate key"))
+	}
+	r.scripts = append(r.scripts, script)
+	return keys, nil
+}
+
+func (r *memoryBackfills) Estimate(_ context.Context, _ int64, _ string) (int64, error) {
+	return int64(len(r.rows)), nil
+}
+
+type fakeMigrations struct {
+	migrations map[int64]entity.MigrationInfo
+}
+
+func (r *fakeMigrations) Get(_ context.Context, migrationID int64) (entity.MigrationInfo, error) {
+	migration, ok := r.migrations[migrationID]
+	if !ok {
+		return entity.MigrationInfo{}, entity.ErrNotFound
+	}
+	return migration, nil
+}
+
+func (r *fakeMigrations) SetStatus(_ context.Context, migrationID int64, _ time.Time, status entity.MigrationStatus) error {
+	migration := r.migrations[migrationID]
+	migration.Status = status
+	r.migrations[migrationID] = migration
+	return nil
+}
+
+func (r *fakeMigrations) SetLastError(_ context.Context, migrationID int64, scriptErr *entity.ScriptError) error {
+	migration := r.migrations[migrationID]
+	migration.LastError = scriptErr
+	r.migrations[migrationID] = migration
+	return nil
+}
+
+func (r *fakeMigrations) SetAppliedChecksum(_ context.Context, migrationID int64, checksum string) error {
+	migration := r.migrations[migrationID]
+	migration.AppliedChecksum = checksum
+	r.migrations[migrationID] = migration
+	return nil
+}
+
+func (r *fakeMigrations) RecordOnTarget(_ context.Context, _ entity.MigrationInfo, _ entity.MigrationStatus, _ time.Time) error {
+	return nil
+}
+
+type fakeHistory struct {
+	entries []entity.HistoryEntry
+}
+
+func (h *fakeHistory) Add(_ context.Context, entries []entity.HistoryEntry) error {
+	h.entries = append(h.entries, entries...)
+	return nil
+}
+
+type fakeTemplates struct{}
+
+func (fakeTemplates) TemplateContext(_ context.Context, _ int64) (entity.TemplateContext, error) {
+	return entity.TemplateContext{Driver: entity.DriverPostgres, Variables: map[string]string{}}, nil
+}
+
+// fakePromotion запрещает продвижение, если blocked.
+type fakePromotion struct {
+	blocked bool
+}
+
+func (p *fakePromotion) CheckPromotion(_ context.Context, _ int64, _ []entity.MigrationInfo) error {
+	if p.blocked {
+		return entity.ErrPromotionBlocked
+	}
+	return nil
+}
+
+// testService - сервис миграций данных поверх заглушек.
+type testService struct {
+	*Service
+	repo       *memoryBackfills
+	migrations *fakeMigrations
+	history    *fakeHistory
+	promotion  *fakePromotion
+}
+
+func newTestService(migration entity.MigrationInfo, rows ...string) *testService {
+	migration.Checksum = entity.ScriptChecksum(migration.Script, migration.RollbackScript)
+	ts := &testService{
+		repo:       newMemoryBackfills(rows...),
+		migrations: &fakeMigrations{migrations: map[int64]entity.MigrationInfo{migration.ID: migration}},
+		history:    &fakeHistory{},
+		promotion:  &fakePromotion{},
+	}
+	ts.Service = New(ts.repo, ts.migrations, ts.history, fakeTemplates{}, ts.promotion, "replica-a", time.Minute, time.Minute)
+	return ts
+}
+
+func backfillMigration() entity.MigrationInfo {
+	return entity.MigrationInfo{
+		ID:       testMigration,
+		TargetID: testTarget,
+		Name:     "10_fill_emails",
+		Script:   "UPDATE users SET email = lower(email) WHERE id > {{ cursor }} AND id <= {{ next_cursor }}",
+		Kind:     entity.MigrationKindBackfill,
+		Status:   entity.StatusPending,
+		Backfill: &entity.BackfillSpec{
+			CursorQuery:   "SELECT id FROM users WHERE id > {{ cursor }} ORDER BY id LIMIT {{ batch_size }}",
+			EstimateQuery: "SELECT count(*) FROM users",
+			BatchSize:     2,
+			StartCursor:   "0",
+		},
+	}
+}
+
+func batchScript(cursor, nextCursor string) string {
+	return fmt.Sprintf("UPDATE users SET email = lower(email) WHERE id > %s AND id <= %s", cursor, nextCursor)
+}
+
+func TestStartBackfill(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(ts *testService)
+		wantErr error
+	}{
+		{name: "started"},
+		{
+			name: "not a backfill",
+			change: func(ts *testService) {
+				migration := ts.migrations.migrations[testMigration]
+				migration.Kind = entity.MigrationKindVersioned
+				ts.migrations.migrations[testMigration] = migration
+			},
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name: "already applied",
+			change: func(ts *testService) {
+				migration := ts.migrations.migrations[testMigration]
+				migration.Status = entity.StatusApplied
+				ts.migrations.migrations[testMigration] = migration
+			},
+			wantErr: entity.ErrFailedPrecondition,
+		},
+		{
+			name: "already started",
+			change: func(ts *testService) {
+				ts.repo.backfills[testMigration] = entity.Backfill{MigrationID: testMigration, State: entity.BackfillStatePaused}
+			},
+			wantErr: entity.ErrAlreadyExists,
+		},
+		{
+			name:    "blocked by promotion",
+			change:  func(ts *testService) { ts.promotion.blocked = true },
+			wantErr: entity.ErrPromotionBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestService(backfillMigration(), "1", "2", "3")
+			if tt.change != nil {
+				tt.change(ts)
+			}
+
+			backfill, err := ts.StartBackfill(context.Background(), testMigration, 7)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("StartBackfill() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StartBackfill() error = %v", err)
+			}
+
+			if backfill.State != entity.BackfillStateRunning || backfill.Cursor != "0" || backfill.EstimatedTotal != 3 || backfill.StartedBy != 7 {
+				t.Errorf("StartBackfill() = %+v, want running from cursor 0 with 3 estimated rows, started by 7", backfill)
+			}
+			if got := ts.migrations.migrations[testMigration].Status; got != entity.StatusPartiallyApplied {
+				t.Errorf("migration status = %s, want %s", got, entity.StatusPartiallyApplied)
+			}
+		})
+	}
+}
+
+func TestRunBackfill(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService(backfillMigration(), "1", "2", "3", "4", "5")
+
+	if _, err := ts.StartBackfill(ctx, testMigration, 7); err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+
+	ts.run(ctx, testMigration)
+
+	want := []string{batchScript("0", "2"), batchScript("2", "4"), batchScript("4", "5")}
+	if !reflect.DeepEqual(ts.repo.scripts, want) {
+		t.Errorf("batch scripts = %q, want %q", ts.repo.scripts, want)
+	}
+
+	backfill := ts.repo.backfills[testMigration]
+	if backfill.State != entity.BackfillStateCompleted || backfill.Cursor != "5" || backfill.RowsDone != 5 {
+		t.Errorf("backfill = %+v, want completed at cursor 5 with 5 rows done", backfill)
+	}
+	migration := ts.migrations.migrations[testMigration]
+	if migration.Status != entity.StatusApplied || migration.AppliedChecksum != migration.Checksum {
+		t.Errorf("migration status, applied checksum = %s, %q, want %s, %q", migration.Status, migration.AppliedChecksum, entity.StatusApplied, migration.Checksum)
+	}
+	if len(ts.history.entries) != 1 || ts.history.entries[0].Outcome != entity.OutcomeSucceeded {
+		t.Errorf("history = %+v, want one succeeded entry", ts.history.entries)
+	}
+}
+
+func TestRunBackfillResumesAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService(backfillMigration(), "1", "2", "3", "4")
+	ts.repo.failing = "3"
+
+	if _, err := ts.StartBackfill(ctx, testMigration, 7); err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+
+	ts.run(ctx, testMigration)
+
+	backfill := ts.repo.backfills[testMigration]
+	if backfill.State != entity.BackfillStateFailed || backfill.Cursor != "2" {
+		t.Fatalf("backfill = %+v, want failed at cursor 2", backfill)
+	}
+	migration := ts.migrations.migrations[testMigration]
+	if migration.Status != entity.StatusPartiallyApplied || migration.LastError == nil || migration.LastError.SQLState != "23505" {
+		t.Fatalf("migration status, last error = %s, %+v, want %s with the batch error", migration.Status, migration.LastError, entity.StatusPartiallyApplied)
+	}
+
+	ts.repo.failing = ""
+	if _, err := ts.ResumeBackfill(ctx, testMigration); err != nil {
+		t.Fatalf("ResumeBackfill() error = %v", err)
+	}
+	if ts.migrations.migrations[testMigration].LastError != nil {
+		t.Error("ResumeBackfill() did not clear the last error")
+	}
+
+	ts.run(ctx, testMigration)
+
+	want := []string{batchScript("0", "2"), batchScript("2", "4")}
+	if !reflect.DeepEqual(ts.repo.scripts, want) {
+		t.Errorf("batch scripts = %q, want %q", ts.repo.scripts, want)
+	}
+	if got := ts.repo.backfills[testMigration].State; got != entity.BackfillStateCompleted {
+		t.Errorf("backfill state = %s, want %s", got, entity.BackfillStateCompleted)
+	}
+}
+
+func TestPauseBackfill(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService(backfillMigration(), "1", "2", "3")
+
+	if _, err := ts.StartBackfill(ctx, testMigration, 7); err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+
+	backfill, err := ts.PauseBackfill(ctx, testMigration)
+	if err != nil {
+		t.Fatalf("PauseBackfill() error = %v", err)
+	}
+	if backfill.State != entity.BackfillStatePaused {
+		t.Errorf("PauseBackfill() state = %s, want %s", backfill.State, entity.BackfillStatePaused)
+	}
+
+	if _, err := ts.PauseBackfill(ctx, testMigration); !errors.Is(err, entity.ErrInvalidArgument) {
+		t.Errorf("second PauseBackfill() error = %v, want ErrInvalidArgument", err)
+	}
+
+	// Приостановленную миграцию данных обработчик не выполняет
+	ts.run(ctx, testMigration)
+	if len(ts.repo.scripts) != 0 {
+		t.Errorf("batch scripts = %q, want none while paused", ts.repo.scripts)
+	}
+}
+
+func TestThrottleBackfill(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService(backfillMigration(), "1", "2", "3")
+
+	if _, err := ts.StartBackfill(ctx, testMigration, 7); err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+
+	if _, err := ts.ThrottleBackfill(ctx, testMigration, -1, 0); !errors.Is(err, entity.ErrInvalidArgument) {
+		t.Errorf("ThrottleBackfill() with a negative batch size error = %v, want ErrInvalidArgument", err)
+	}
+
+	backfill, err := ts.ThrottleBackfill(ctx, testMigration, 0, time.Second)
+	if err != nil {
+		t.Fatalf("ThrottleBackfill() error = %v", err)
+	}
+	if backfill.BatchSize != 2 || backfill.Pause != time.Second {
+		t.Errorf("ThrottleBackfill() batch size, pause = %d, %s, want 2, 1s", backfill.BatchSize, backfill.Pause)
+	}
+}
@@ -0,0 +1,258 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+)
+
+// Run выполняет пачки миграций данных, пока не отменен контекст. Обработчик
+// берет в аренду выполняемые миграции данных, которые не удерживает другая
+// реплика, поэтому после перезапуска или остановки реплики выполнение
+// продолжается с сохраненного курсора.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.claimAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// claimAll запускает выполнение каждой миграции данных, которую может взять реплика.
+func (s *Service) claimAll(ctx context.Context) {
+	backfills, err := s.repo.ListClaimable(ctx, s.owner, s.lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error(fmt.Errorf("s.repo.ListClaimable: %w", err))
+		}
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, backfill := range backfills {
+		if s.running[backfill.MigrationID] {
+			continue
+		}
+		s.running[backfill.MigrationID] = true
+
+		go func(migrationID int64) {
+			defer func() {
+				s.mu.Lock()
+				delete(s.running, migrationID)
+				s.mu.Unlock()
+			}()
+			s.run(ctx, migrationID)
+		}(backfill.MigrationID)
+	}
+}
+
+// run выполняет пачки миграции данных, пока она не будет завершена,
+// приостановлена или взята другой репликой. Перед каждой пачкой аренда
+// продлевается, а размер пачки и пауза перечитываются.
+func (s *Service) run(ctx context.Context, migrationID int64) {
+	for {
+		backfill, claimed, err := s.repo.Claim(ctx, migrationID, s.owner, s.lease)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error(fmt.Errorf("s.repo.Claim: %w", err))
+			}
+			return
+		}
+		if !claimed {
+			return
+		}
+
+		migration, err := s.migrations.Get(ctx, migrationID)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error(fmt.Errorf("s.migrations.Get: %w", err))
+			}
+			return
+		}
+
+		keys, err := s.runBatch(ctx, migration, backfill)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.fail(ctx, migration, backfill, err)
+			}
+			return
+		}
+
+		if len(keys) == 0 {
+			s.complete(ctx, migration, backfill)
+			return
+		}
+
+		advanced, err := s.repo.Advance(ctx, migrationID, s.owner, keys[len(keys)-1], int64(len(keys)), time.Now())
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error(fmt.Errorf("s.repo.Advance: %w", err))
+			}
+			return
+		}
+		if !advanced {
+			return
+		}
+
+		if backfill.Pause > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backfill.Pause):
+			}
+		}
+	}
+}
+
+// runBatch выполняет одну пачку миграции данных с сохраненного курсора.
+func (s *Service) runBatch(ctx context.Context, migration entity.MigrationInfo, backfill entity.Backfill) ([]string, error) {
+	templateContext, cursorQuery, err := s.renderCursorQuery(ctx, migration, backfill.Cursor, backfill.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.RunBatch(ctx, migration.TargetID, cursorQuery, func(keys []string) (string, error) {
+		templateContext.Variables[entity.BackfillNextCursorVariable] = keys[len(keys)-1]
+
+		script, err := templateContext.Render(migration.Script)
+		if err != nil {
+			return "", fmt.Errorf("migration %d: %w", migration.ID, err)
+		}
+		return script.SQL, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.RunBatch: %w", err)
+	}
+
+	return keys, nil
+}
+
+// renderCursorQuery подставляет переменные в запрос курсора. Возвращает также
+// переменные шаблонов целевой базы данных, дополненные курсором и размером пачки,
+// для подстановки в скрипт пачки.
+func (s *Service) renderCursorQuery(ctx context.Context, migration entity.MigrationInfo, cursor string, batchSize int) (entity.TemplateContext, string, error) {
+	templateContext, err := s.templates.TemplateContext(ctx, migration.TargetID)
+	if err != nil {
+		return entity.TemplateContext{}, "", fmt.Errorf("s.templates.TemplateContext: %w", err)
+	}
+
+	variables := make(map[string]string, len(templateContext.Variables)+3)
+	maps.Copy(variables, templateContext.Variables)
+	variables[entity.BackfillCursorVariable] = cursor
+	variables[entity.BackfillBatchSizeVariable] = strconv.Itoa(batchSize)
+	templateContext.Variables = variables
+
+	cursorQuery, err := templateContext.Render(migration.Backfill.CursorQuery)
+	if err != nil {
+		return entity.TemplateContext{}, "", fmt.Errorf("migration %d cursor query: %w", migration.ID, err)
+	}
+
+	return templateContext, cursorQuery.SQL, nil
+}
+
+// complete отмечает миграцию данных примененной после обработки всех строк.
+func (s *Service) complete(ctx context.Context, migration entity.MigrationInfo, backfill entity.Backfill) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _recordTimeout)
+	defer cancel()
+
+	completedAt := time.Now()
+
+	err := s.repo.Finish(ctx, migration.ID, entity.BackfillStateCompleted, "", completedAt)
+	if err != nil {
+		logger.Error(fmt.Errorf("s.repo.Finish: %w", err))
+		return
+	}
+
+	err = s.migrations.SetStatus(ctx, migration.ID, completedAt, entity.StatusApplied)
+	if err != nil {
+		logger.Error(fmt.Errorf("complete backfill of migration %d: %w", migration.ID, err))
+	}
+
+	err = s.migrations.SetAppliedChecksum(ctx, migration.ID, migration.Checksum)
+	if err != nil {
+		logger.Error(fmt.Errorf("complete backfill of migration %d: %w", migration.ID, err))
+	}
+
+	err = s.migrations.SetLastError(ctx, migration.ID, nil)
+	if err != nil {
+		logger.Error(fmt.Errorf("complete backfill of migration %d: %w", migration.ID, err))
+	}
+
+	err = s.migrations.RecordOnTarget(ctx, migration, entity.StatusApplied, completedAt)
+	if err != nil {
+		logger.Error(fmt.Errorf("record migration %d on target: %w", migration.ID, err))
+	}
+
+	s.writeHistory(ctx, migration, backfill, completedAt, nil)
+}
+
+// fail останавливает миграцию данных после ошибки пачки. Обработанные пачки
+// остаются зафиксированными, поэтому миграция остается частично примененной,
+// а выполнение можно продолжить с сохраненного курсора через ResumeBackfill.
+func (s *Service) fail(ctx context.Context, migration entity.MigrationInfo, backfill entity.Backfill, batchErr error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _recordTimeout)
+	defer cancel()
+
+	failedAt := time.Now()
+
+	err := s.repo.Finish(ctx, migration.ID, entity.BackfillStateFailed, batchErr.Error(), failedAt)
+	if err != nil {
+		logger.Error(fmt.Errorf("s.repo.Finish: %w", err))
+	}
+
+	err = s.migrations.SetLastError(ctx, migration.ID, asScriptError(batchErr))
+	if err != nil {
+		logger.Error(fmt.Errorf("record failure of migration %d: %w", migration.ID, err))
+	}
+
+	s.writeHistory(ctx, migration, backfill, failedAt, batchErr)
+}
+
+// writeHistory записывает в журнал выполнения итог миграции данных.
+// Ошибка записи только логируется.
+func (s *Service) writeHistory(ctx context.Context, migration entity.MigrationInfo, backfill entity.Backfill, finishedAt time.Time, err error) {
+	entry := entity.HistoryEntry{
+		MigrationID: migration.ID,
+		TargetID:    migration.TargetID,
+		Action:      entity.HistoryActionBackfill,
+		UserID:      backfill.StartedBy,
+		StartedAt:   backfill.StartedAt,
+		FinishedAt:  finishedAt,
+		Duration:    finishedAt.Sub(backfill.StartedAt),
+		Outcome:     entity.OutcomeSucceeded,
+		Script:      migration.Script,
+	}
+	if err != nil {
+		entry.Outcome = entity.OutcomeFailed
+		entry.Error = err.Error()
+	}
+
+	if err := s.history.Add(ctx, []entity.HistoryEntry{entry}); err != nil {
+		logger.Error(fmt.Errorf("write migration history: %w", err))
+	}
+}
+
+// asScriptError извлекает ошибку базы данных; прочие ошибки сохраняются только текстом.
+func asScriptError(err error) *entity.ScriptError {
+	var scriptErr *entity.ScriptError
+	if errors.As(err, &scriptErr) {
+		return scriptErr
+	}
+	return &entity.ScriptError{Message: err.Error()}
+}
@@ -0,0 +1,39 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+
+	"migrator/internal/entity"
+)
+
+type auditSrv interface {
+	ListAuditEvents(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEvent, error)
+}
+
+// AuditWithAuth is a wrapper around the audit log that adds authorization checks.
+type AuditWithAuth struct {
+	audit      auditSrv
+	authClient authClient
+}
+
+// NewAuditWithAuth creates a new AuditWithAuth.
+func NewAuditWithAuth(audit auditSrv, authClient authClient) *AuditWithAuth {
+	return &AuditWithAuth{
+		audit:      audit,
+		authClient: authClient,
+	}
+}
+
+// ListAuditEvents возвращает журнал аудита после проверки права PERMISSION_AUDIT.
+func (awa *AuditWithAuth) ListAuditEvents(ctx context.Context, filter entity.AuditFilter, userID int64) ([]entity.AuditEvent, error) {
+	hasPermission, err := awa.authClient.CheckPermissionAudit(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("auth check failed for ListAuditEvents: %w", err)
+	}
+	if !hasPermission {
+		return nil, fmt.Errorf("%w: user %d lacks PERMISSION_AUDIT for ListAuditEvents", entity.ErrPermissionDenied, userID)
+	}
+
+	return awa.audit.ListAuditEvents(ctx, filter)
+}
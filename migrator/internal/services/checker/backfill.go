@@ -0,0 +1,103 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+type backfillSrv interface {
+	StartBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error)
+	PauseBackfill(ctx context.Context, migrationID int64) (entity.Backfill, error)
+	ResumeBackfill(ctx context.Context, migrationID int64) (entity.Backfill, error)
+	ThrottleBackfill(ctx context.Context, migrationID int64, batchSize int, pause time.Duration) (entity.Backfill, error)
+	GetBackfill(ctx context.Context, migrationID int64) (entity.Backfill, error)
+}
+
+// migrationGetter returns a migration to decide which apply permission its backfill requires.
+type migrationGetter interface {
+	GetMigration(ctx context.Context, migrationID int64) (entity.MigrationInfo, error)
+}
+
+// BackfillsWithAuth is a wrapper around the backfill service that adds authorization checks.
+type BackfillsWithAuth struct {
+	backfills  backfillSrv
+	migrations migrationGetter
+	authClient authClient
+}
+
+// NewBackfillsWithAuth creates a new BackfillsWithAuth.
+func NewBackfillsWithAuth(backfills backfillSrv, migrations migrationGetter, authClient authClient) *BackfillsWithAuth {
+	return &BackfillsWithAuth{
+		backfills:  backfills,
+		migrations: migrations,
+		authClient: authClient,
+	}
+}
+
+// StartBackfill starts a backfill after checking the same permissions as ApplyMigration.
+func (bwa *BackfillsWithAuth) StartBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error) {
+	if err := bwa.checkApply(ctx, migrationID, userID, "StartBackfill"); err != nil {
+		return entity.Backfill{}, err
+	}
+
+	return bwa.backfills.StartBackfill(ctx, migrationID, userID)
+}
+
+// PauseBackfill pauses a backfill after checking the same permissions as ApplyMigration.
+func (bwa *BackfillsWithAuth) PauseBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error) {
+	if err := bwa.checkApply(ctx, migrationID, userID, "PauseBackfill"); err != nil {
+		return entity.Backfill{}, err
+	}
+
+	return bwa.backfills.PauseBackfill(ctx, migrationID)
+}
+
+// ResumeBackfill resumes a backfill after checking the same permissions as ApplyMigration.
+func (bwa *BackfillsWithAuth) ResumeBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error) {
+	if err := bwa.checkApply(ctx, migrationID, userID, "ResumeBackfill"); err != nil {
+		return entity.Backfill{}, err
+	}
+
+	return bwa.backfills.ResumeBackfill(ctx, migrationID)
+}
+
+// ThrottleBackfill changes the batch size and pause of a backfill after checking the same permissions as ApplyMigration.
+func (bwa *BackfillsWithAuth) ThrottleBackfill(ctx context.Context, migrationID int64, batchSize int, pause time.Duration, userID int64) (entity.Backfill, error) {
+	if err := bwa.checkApply(ctx, migrationID, userID, "ThrottleBackfill"); err != nil {
+		return entity.Backfill{}, err
+	}
+
+	return bwa.backfills.ThrottleBackfill(ctx, migrationID, batchSize, pause)
+}
+
+// GetBackfill returns backfill progress after checking PERMISSION_GET.
+func (bwa *BackfillsWithAuth) GetBackfill(ctx context.Context, migrationID, userID int64) (entity.Backfill, error) {
+	if err := checkGet(ctx, bwa.authClient, userID, "GetBackfill"); err != nil {
+		return entity.Backfill{}, err
+	}
+
+	return bwa.backfills.GetBackfill(ctx, migrationID)
+}
+
+// checkApply проверяет право на применение миграции данных:
+// своей - PERMISSION_APPLY, созданной другим - PERMISSION_APPLY_OTHER.
+func (bwa *BackfillsWithAuth) checkApply(ctx context.Context, migrationID, actorUserID int64, action string) error {
+	migrationInfo, err := bwa.migrations.GetMigration(ctx, migrationID)
+	if err != nil {
+		return fmt.Errorf("failed to get migration info for %s auth check: %w", action, err)
+	}
+
+	permission := applyPermission(migrationInfo, actorUserID)
+	decisions, err := bwa.authClient.CheckPermissions(ctx, actorUserID, permission)
+	if err != nil {
+		return fmt.Errorf("auth check failed for %s: %w", action, err)
+	}
+	if !decisions[permission] {
+		return fmt.Errorf("%w: user %d lacks %s for migration %d", entity.ErrPermissionDenied, actorUserID, permission, migrationID)
+	}
+
+	return nil
+}
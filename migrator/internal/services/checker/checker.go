@@ -9,21 +9,39 @@ import (
 )
 
 type migratorSrv interface {
-	ApplyMigration(ctx context.Context, migrationIDs []int64, userID int64) (time.Time, error)
-	CreateMigration(ctx context.Context, name string, description string, script string, rollbackScript string, userID int64) (int64, error)
+	ApplyMigration(ctx context.Context, targetID int64, migrationIDs []int64, userID int64) ([]int64, time.Time, error)
+	PendingRepeatableMigrations(ctx context.Context, targetID int64, migrationIDs []int64) ([]entity.MigrationInfo, error)
+	CreateMigration(ctx context.Context, targetID int64, name string, description string, script string, rollbackScript string, executionMode entity.ExecutionMode, kind entity.MigrationKind, labels map[string]string, tenantScoped, templated bool, backfill *entity.BackfillSpec, userID int64) (int64, []entity.LintFinding, error)
+	LintMigration(ctx context.Context, migrationID int64, script string, rollbackScript string) (entity.LintReport, error)
 	GetMigration(ctx context.Context, migrationID int64) (entity.MigrationInfo, error)
-	ListMigrations(ctx context.Context, statusFilter string) ([]entity.MigrationInfo, error)
-	RollbackMigration(ctx context.Context, migrationID int64, userID int64) (time.Time, error)
+	ListMigrations(ctx context.Context, targetID int64, statusFilter string, selector entity.LabelSelector) ([]entity.MigrationInfo, error)
+	SelectPendingMigrations(ctx context.Context, targetID int64, selector entity.LabelSelector) ([]int64, error)
+	RollbackMigration(ctx context.Context, targetID int64, migrationID int64, userID int64) (time.Time, error)
+	RollbackToMigration(ctx context.Context, targetID int64, migrationID int64, userID int64, authorize func(ctx context.Context, migration entity.MigrationInfo) error) ([]int64, time.Time, error)
+	PlanApplyMigration(ctx context.Context, targetID int64, migrationIDs []int64, userID int64) (entity.Plan, error)
+	PlanRollbackMigration(ctx context.Context, targetID int64, migrationID int64, userID int64) (entity.Plan, error)
+	VerifyMigrations(ctx context.Context, targetID int64) ([]entity.ChecksumViolation, error)
+	ListMigrationHistory(ctx context.Context, filter entity.HistoryFilter) ([]entity.HistoryEntry, error)
+	BaselineMigrations(ctx context.Context, targetID int64, migrationIDs []int64, fromID int64, toID int64, userID int64) ([]int64, time.Time, error)
+	ApplyTenantMigration(ctx context.Context, targetID int64, migrationID int64, tenants []string, concurrency int, userID int64) (entity.TenantApplyResult, error)
+	ListTenantStatuses(ctx context.Context, migrationID int64) ([]entity.TenantStatus, error)
+	UpdateRepeatableMigration(ctx context.Context, migrationID int64, script string) (bool, []entity.LintFinding, error)
+	ListMigrationRevisions(ctx context.Context, migrationID int64) ([]entity.MigrationRevision, error)
+	GetDatabaseStatus(ctx context.Context, targetID int64) (entity.DatabaseStatus, error)
 }
 
 type authClient interface {
 	CheckPermissionApply(ctx context.Context, userID int64) (bool, error)
 	CheckPermissionApplyOther(ctx context.Context, userID int64) (bool, error)
+	CheckPermissionAudit(ctx context.Context, userID int64) (bool, error)
+	CheckPermissionBaseline(ctx context.Context, userID int64) (bool, error)
 	CheckPermissionCreate(ctx context.Context, userID int64) (bool, error)
 	CheckPermissionGet(ctx context.Context, userID int64) (bool, error)
 	CheckPermissionList(ctx context.Context, userID int64) (bool, error)
+	CheckPermissionManageTargets(ctx context.Context, userID int64) (bool, error)
 	CheckPermissionRollback(ctx context.Context, userID int64) (bool, error)
 	CheckPermissionRollbackOther(ctx context.Context, userID int64) (bool, error)
+	CheckPermissions(ctx context.Context, userID int64, permissions ...entity.Permission) (map[entity.Permission]bool, error)
 }
 
 // MigratorWithAuth is a wrapper around Migrator that adds authorization checks.
@@ -41,38 +59,212 @@ func NewMigratorWithAuth(migrator migratorSrv, authClient authClient) *MigratorW
 }
 
 // CreateMigration creates a new migration after checking permissions.
-func (mwa *MigratorWithAuth) CreateMigration(ctx context.Context, name, description, script, rollbackScript string, userID int64) (int64, error) {
-	hasPermission, err := mwa.authClient.CheckPermissionCreate(ctx, userID)
+func (mwa *MigratorWithAuth) CreateMigration(ctx context.Context, targetID int64, name, description, script, rollbackScript string, executionMode entity.ExecutionMode, kind entity.MigrationKind, labels map[string]string, tenantScoped, templated bool, backfill *entity.BackfillSpec, userID int64) (int64, []entity.LintFinding, error) {
+	if err := checkCreate(ctx, mwa.authClient, userID, "CreateMigration"); err != nil {
+		return 0, nil, err
+	}
+
+	return mwa.migrator.CreateMigration(ctx, targetID, name, description, script, rollbackScript, executionMode, kind, labels, tenantScoped, templated, backfill, userID)
+}
+
+// UpdateRepeatableMigration replaces the script of a repeatable migration after checking permissions:
+// PERMISSION_CREATE, and for a migration created by another user also PERMISSION_APPLY_OTHER,
+// since the new script runs on that user's migration at the next apply.
+func (mwa *MigratorWithAuth) UpdateRepeatableMigration(ctx context.Context, migrationID int64, script string, userID int64) (bool, []entity.LintFinding, error) {
+	migrationInfo, err := mwa.migrator.GetMigration(ctx, migrationID)
 	if err != nil {
-		return 0, fmt.Errorf("auth check failed for CreateMigration: %w", err)
+		return false, nil, fmt.Errorf("failed to get migration info for update auth check: %w", err)
 	}
-	if !hasPermission {
-		return 0, fmt.Errorf("%w: user %d lacks permission to create migration", entity.ErrPermissionDenied, userID)
+
+	permissions := []entity.Permission{entity.PermissionCreate}
+	if migrationInfo.CreatedBy != userID {
+		permissions = append(permissions, entity.PermissionApplyOther)
+	}
+	decisions, err := mwa.authClient.CheckPermissions(ctx, userID, permissions...)
+	if err != nil {
+		return false, nil, fmt.Errorf("auth check failed for UpdateRepeatableMigration: %w", err)
+	}
+	for _, permission := range permissions {
+		if !decisions[permission] {
+			return false, nil, fmt.Errorf("%w: user %d lacks %s for migration %d", entity.ErrPermissionDenied, userID, permission, migrationID)
+		}
+	}
+
+	return mwa.migrator.UpdateRepeatableMigration(ctx, migrationID, script)
+}
+
+// LintMigration lints migration scripts after checking permissions: a stored migration
+// requires PERMISSION_GET, ad hoc scripts are checked before creation and require PERMISSION_CREATE.
+func (mwa *MigratorWithAuth) LintMigration(ctx context.Context, migrationID int64, script, rollbackScript string, userID int64) (entity.LintReport, error) {
+	check := checkCreate
+	if migrationID != 0 {
+		check = checkGet
+	}
+	if err := check(ctx, mwa.authClient, userID, "LintMigration"); err != nil {
+		return entity.LintReport{}, err
+	}
+
+	return mwa.migrator.LintMigration(ctx, migrationID, script, rollbackScript)
+}
+
+// ApplyMigration applies migrations after checking permissions for every migration in the batch.
+// Pending repeatable migrations of the target are added to the batch when the user may apply them.
+func (mwa *MigratorWithAuth) ApplyMigration(ctx context.Context, targetID int64, migrationIDs []int64, userID int64) ([]int64, time.Time, error) {
+	migrationIDs, err := mwa.applySet(ctx, targetID, migrationIDs, userID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return mwa.migrator.ApplyMigration(ctx, targetID, migrationIDs, userID)
+}
+
+// PlanApplyMigration builds an apply plan for the same batch and after the same permission checks as ApplyMigration.
+func (mwa *MigratorWithAuth) PlanApplyMigration(ctx context.Context, targetID int64, migrationIDs []int64, userID int64) (entity.Plan, error) {
+	migrationIDs, err := mwa.applySet(ctx, targetID, migrationIDs, userID)
+	if err != nil {
+		return entity.Plan{}, err
+	}
+
+	return mwa.migrator.PlanApplyMigration(ctx, targetID, migrationIDs, userID)
+}
+
+// applySet возвращает миграции запроса на применение вместе с ожидающими
+// повторяемыми миграциями целевой базы данных. Права на миграции запроса
+// обязательны, а ожидающую повторяемую миграцию, на применение которой
+// у пользователя нет права, пакет пропускает: она остается ожидающей.
+func (mwa *MigratorWithAuth) applySet(ctx context.Context, targetID int64, migrationIDs []int64, actorUserID int64) ([]int64, error) {
+	if err := mwa.checkApply(ctx, migrationIDs, actorUserID); err != nil {
+		return nil, err
+	}
+
+	pending, err := mwa.migrator.PendingRepeatableMigrations(ctx, targetID, migrationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending repeatable migrations: %w", err)
+	}
+	if len(pending) == 0 {
+		return migrationIDs, nil
+	}
+
+	permissions := make([]entity.Permission, 0, len(pending))
+	for _, migrationInfo := range pending {
+		permissions = append(permissions, applyPermission(migrationInfo, actorUserID))
+	}
+
+	decisions, err := mwa.authClient.CheckPermissions(ctx, actorUserID, permissions...)
+	if err != nil {
+		return nil, fmt.Errorf("auth check failed for ApplyMigration: %w", err)
 	}
 
-	return mwa.migrator.CreateMigration(ctx, name, description, script, rollbackScript, userID)
+	applySet := append([]int64(nil), migrationIDs...)
+	for _, migrationInfo := range pending {
+		if decisions[applyPermission(migrationInfo, actorUserID)] {
+			applySet = append(applySet, migrationInfo.ID)
+		}
+	}
+
+	return applySet, nil
 }
 
-// ApplyMigration applies migrations after checking permissions.
-func (mwa *MigratorWithAuth) ApplyMigration(ctx context.Context, migrationIDs []int64, userID int64) (time.Time, error) {
-	hasPermission, err := mwa.authClient.CheckPermissionApply(ctx, userID)
+// ApplyTenantMigration applies a tenant-scoped migration to tenant schemas after checking the same permissions as ApplyMigration.
+func (mwa *MigratorWithAuth) ApplyTenantMigration(ctx context.Context, targetID, migrationID int64, tenants []string, concurrency int, userID int64) (entity.TenantApplyResult, error) {
+	if err := mwa.checkApply(ctx, []int64{migrationID}, userID); err != nil {
+		return entity.TenantApplyResult{}, err
+	}
+
+	return mwa.migrator.ApplyTenantMigration(ctx, targetID, migrationID, tenants, concurrency, userID)
+}
+
+// checkApply проверяет права на применение каждой миграции пакета:
+// своих - PERMISSION_APPLY, созданных другими - PERMISSION_APPLY_OTHER.
+// Нужные права запрашиваются у сервиса авторизации одним вызовом на весь пакет.
+func (mwa *MigratorWithAuth) checkApply(ctx context.Context, migrationIDs []int64, actorUserID int64) error {
+	migrations := make([]entity.MigrationInfo, 0, len(migrationIDs))
+	var permissions []entity.Permission
+	for _, migrationID := range migrationIDs {
+		migrationInfo, err := mwa.migrator.GetMigration(ctx, migrationID)
+		if err != nil {
+			return fmt.Errorf("failed to get migration info for apply auth check: %w", err)
+		}
+		migrations = append(migrations, migrationInfo)
+		permissions = append(permissions, applyPermission(migrationInfo, actorUserID))
+	}
+
+	decisions, err := mwa.authClient.CheckPermissions(ctx, actorUserID, permissions...)
+	if err != nil {
+		return fmt.Errorf("auth check failed for ApplyMigration: %w", err)
+	}
+
+	for _, migrationInfo := range migrations {
+		permission := applyPermission(migrationInfo, actorUserID)
+		if !decisions[permission] {
+			return fmt.Errorf("%w: user %d lacks %s for migration %d", entity.ErrPermissionDenied, actorUserID, permission, migrationInfo.ID)
+		}
+	}
+
+	return nil
+}
+
+// applyPermission возвращает право, нужное пользователю для применения миграции.
+func applyPermission(migrationInfo entity.MigrationInfo, actorUserID int64) entity.Permission {
+	if migrationInfo.CreatedBy == actorUserID {
+		return entity.PermissionApply
+	}
+	return entity.PermissionApplyOther
+}
+
+// BaselineMigrations отмечает миграции примененными без выполнения скриптов после проверки
+// права PERMISSION_BASELINE. Права на применение не требуются: скрипты не выполняются.
+func (mwa *MigratorWithAuth) BaselineMigrations(ctx context.Context, targetID int64, migrationIDs []int64, fromID, toID, userID int64) ([]int64, time.Time, error) {
+	hasPermission, err := mwa.authClient.CheckPermissionBaseline(ctx, userID)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("auth check failed for ApplyMigration: %w", err)
+		return nil, time.Time{}, fmt.Errorf("auth check failed for BaselineMigrations: %w", err)
 	}
 	if !hasPermission {
-		return time.Time{}, fmt.Errorf("%w: user %d lacks permission to apply migrations", entity.ErrPermissionDenied, userID)
+		return nil, time.Time{}, fmt.Errorf("%w: user %d lacks PERMISSION_BASELINE for BaselineMigrations", entity.ErrPermissionDenied, userID)
 	}
 
-	return mwa.migrator.ApplyMigration(ctx, migrationIDs, userID)
+	return mwa.migrator.BaselineMigrations(ctx, targetID, migrationIDs, fromID, toID, userID)
 }
 
 // RollbackMigration откатывает миграцию.
-func (mwa *MigratorWithAuth) RollbackMigration(ctx context.Context, migrationID, actorUserID int64) (time.Time, error) {
-	migrationInfo, err := mwa.GetMigration(ctx, migrationID)
+func (mwa *MigratorWithAuth) RollbackMigration(ctx context.Context, targetID, migrationID, actorUserID int64) (time.Time, error) {
+	if err := mwa.checkRollback(ctx, migrationID, actorUserID); err != nil {
+		return time.Time{}, err
+	}
+
+	return mwa.migrator.RollbackMigration(ctx, targetID, migrationID, actorUserID)
+}
+
+// PlanRollbackMigration строит план отката с теми же проверками прав, что и RollbackMigration.
+func (mwa *MigratorWithAuth) PlanRollbackMigration(ctx context.Context, targetID, migrationID, actorUserID int64) (entity.Plan, error) {
+	if err := mwa.checkRollback(ctx, migrationID, actorUserID); err != nil {
+		return entity.Plan{}, err
+	}
+
+	return mwa.migrator.PlanRollbackMigration(ctx, targetID, migrationID, actorUserID)
+}
+
+// RollbackToMigration откатывает все миграции новее указанной, проверяя права
+// на откат каждой из них так же, как RollbackMigration.
+func (mwa *MigratorWithAuth) RollbackToMigration(ctx context.Context, targetID, migrationID, actorUserID int64) ([]int64, time.Time, error) {
+	authorize := func(ctx context.Context, migration entity.MigrationInfo) error {
+		return mwa.checkRollbackOf(ctx, migration, actorUserID)
+	}
+
+	return mwa.migrator.RollbackToMigration(ctx, targetID, migrationID, actorUserID, authorize)
+}
+
+func (mwa *MigratorWithAuth) checkRollback(ctx context.Context, migrationID, actorUserID int64) error {
+	migrationInfo, err := mwa.migrator.GetMigration(ctx, migrationID)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to get migration info for rollback auth check: %w", err)
+		return fmt.Errorf("failed to get migration info for rollback auth check: %w", err)
 	}
 
+	return mwa.checkRollbackOf(ctx, migrationInfo, actorUserID)
+}
+
+func (mwa *MigratorWithAuth) checkRollbackOf(ctx context.Context, migrationInfo entity.MigrationInfo, actorUserID int64) error {
+	migrationID := migrationInfo.ID
 	creatorUserID := migrationInfo.CreatedBy
 
 	var hasPermission bool
@@ -88,21 +280,120 @@ func (mwa *MigratorWithAuth) RollbackMigration(ctx context.Context, migrationID,
 	}
 
 	if permCheckErr != nil {
-		return time.Time{}, fmt.Errorf("auth check failed for RollbackMigration (%s): %w", permType, permCheckErr)
+		return fmt.Errorf("auth check failed for RollbackMigration (%s): %w", permType, permCheckErr)
 	}
 	if !hasPermission {
-		return time.Time{}, fmt.Errorf("%w: user %d lacks %s for migration %d", entity.ErrPermissionDenied, actorUserID, permType, migrationID)
+		return fmt.Errorf("%w: user %d lacks %s for migration %d", entity.ErrPermissionDenied, actorUserID, permType, migrationID)
 	}
 
-	return mwa.migrator.RollbackMigration(ctx, migrationID, actorUserID)
+	return nil
 }
 
-// ListMigrations возвращает список миграций.
-func (mwa *MigratorWithAuth) ListMigrations(ctx context.Context, statusFilter string) ([]entity.MigrationInfo, error) {
-	return mwa.migrator.ListMigrations(ctx, statusFilter)
+// ListMigrations возвращает список миграций после проверки права PERMISSION_LIST.
+func (mwa *MigratorWithAuth) ListMigrations(ctx context.Context, targetID int64, statusFilter string, selector entity.LabelSelector, userID int64) ([]entity.MigrationInfo, error) {
+	if err := checkList(ctx, mwa.authClient, userID, "ListMigrations"); err != nil {
+		return nil, err
+	}
+
+	return mwa.migrator.ListMigrations(ctx, targetID, statusFilter, selector)
 }
 
-// GetMigration возвращает миграцию по ее ID.
-func (mwa *MigratorWithAuth) GetMigration(ctx context.Context, migrationID int64) (entity.MigrationInfo, error) {
+// SelectPendingMigrations возвращает ожидающие применения миграции, подходящие под селектор меток,
+// после проверки права PERMISSION_LIST. Права на применение проверяются при самом применении.
+func (mwa *MigratorWithAuth) SelectPendingMigrations(ctx context.Context, targetID int64, selector entity.LabelSelector, userID int64) ([]int64, error) {
+	if err := checkList(ctx, mwa.authClient, userID, "SelectPendingMigrations"); err != nil {
+		return nil, err
+	}
+
+	return mwa.migrator.SelectPendingMigrations(ctx, targetID, selector)
+}
+
+// VerifyMigrations проверяет контрольные суммы миграций после проверки права PERMISSION_LIST.
+func (mwa *MigratorWithAuth) VerifyMigrations(ctx context.Context, targetID, userID int64) ([]entity.ChecksumViolation, error) {
+	if err := checkList(ctx, mwa.authClient, userID, "VerifyMigrations"); err != nil {
+		return nil, err
+	}
+
+	return mwa.migrator.VerifyMigrations(ctx, targetID)
+}
+
+// ListMigrationHistory возвращает журнал выполнения миграций после проверки права PERMISSION_LIST.
+func (mwa *MigratorWithAuth) ListMigrationHistory(ctx context.Context, filter entity.HistoryFilter, userID int64) ([]entity.HistoryEntry, error) {
+	if err := checkList(ctx, mwa.authClient, userID, "ListMigrationHistory"); err != nil {
+		return nil, err
+	}
+
+	return mwa.migrator.ListMigrationHistory(ctx, filter)
+}
+
+// GetMigration возвращает миграцию по ее ID после проверки права PERMISSION_GET.
+func (mwa *MigratorWithAuth) GetMigration(ctx context.Context, migrationID, userID int64) (entity.MigrationInfo, error) {
+	if err := checkGet(ctx, mwa.authClient, userID, "GetMigration"); err != nil {
+		return entity.MigrationInfo{}, err
+	}
+
 	return mwa.migrator.GetMigration(ctx, migrationID)
 }
+
+// ListTenantStatuses возвращает состояние миграции арендаторов в схемах арендаторов после проверки права PERMISSION_GET.
+func (mwa *MigratorWithAuth) ListTenantStatuses(ctx context.Context, migrationID, userID int64) ([]entity.TenantStatus, error) {
+	if err := checkGet(ctx, mwa.authClient, userID, "ListTenantStatuses"); err != nil {
+		return nil, err
+	}
+
+	return mwa.migrator.ListTenantStatuses(ctx, migrationID)
+}
+
+// ListMigrationRevisions возвращает выполненные ревизии повторяемой миграции после проверки права PERMISSION_GET.
+func (mwa *MigratorWithAuth) ListMigrationRevisions(ctx context.Context, migrationID, userID int64) ([]entity.MigrationRevision, error) {
+	if err := checkGet(ctx, mwa.authClient, userID, "ListMigrationRevisions"); err != nil {
+		return nil, err
+	}
+
+	return mwa.migrator.ListMigrationRevisions(ctx, migrationID)
+}
+
+// GetDatabaseStatus возвращает схему целевой базы данных и ее расхождения с последним снимком после проверки права PERMISSION_GET.
+func (mwa *MigratorWithAuth) GetDatabaseStatus(ctx context.Context, targetID, userID int64) (entity.DatabaseStatus, error) {
+	if err := checkGet(ctx, mwa.authClient, userID, "GetDatabaseStatus"); err != nil {
+		return entity.DatabaseStatus{}, err
+	}
+
+	return mwa.migrator.GetDatabaseStatus(ctx, targetID)
+}
+
+// checkList проверяет право на просмотр списков.
+func checkList(ctx context.Context, authClient authClient, userID int64, action string) error {
+	hasPermission, err := authClient.CheckPermissionList(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("auth check failed for %s: %w", action, err)
+	}
+	if !hasPermission {
+		return fmt.Errorf("%w: user %d lacks PERMISSION_LIST for %s", entity.ErrPermissionDenied, userID, action)
+	}
+	return nil
+}
+
+// checkCreate проверяет право на создание миграций.
+func checkCreate(ctx context.Context, authClient authClient, userID int64, action string) error {
+	hasPermission, err := authClient.CheckPermissionCreate(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("auth check failed for %s: %w", action, err)
+	}
+	if !hasPermission {
+		return fmt.Errorf("%w: user %d lacks permission to create migration", entity.ErrPermissionDenied, userID)
+	}
+	return nil
+}
+
+// checkGet проверяет право на получение конкретной сущности.
+func checkGet(ctx context.Context, authClient authClient, userID int64, action string) error {
+	hasPermission, err := authClient.CheckPermissionGet(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("auth check failed for %s: %w", action, err)
+	}
+	if !hasPermission {
+		return fmt.Errorf("%w: user %d lacks PERMISSION_GET for %s", entity.ErrPermissionDenied, userID, action)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+const (
+	owner = int64(1)
+	other = int64(2)
+)
+
+// fakeMigrator - сервис миграций с заданными миграциями; методы, которые
+// тесты не вызывают, остаются у встроенного интерфейса.
+type fakeMigrator struct {
+	migratorSrv
+	migrations map[int64]entity.MigrationInfo
+	pending    []entity.MigrationInfo
+	newer      []entity.MigrationInfo
+
+	applied    []int64
+	planned    []int64
+	rolledBack []int64
+}
+
+func (f *fakeMigrator) GetMigration(_ context.Context, migrationID int64) (entity.MigrationInfo, error) {
+	migration, ok := f.migrations[migrationID]
+	if !ok {
+		return entity.MigrationInfo{}, entity.ErrNotFound
+	}
+	return migration, nil
+}
+
+func (f *fakeMigrator) PendingRepeatableMigrations(_ context.Context, _ int64, _ []int64) ([]entity.MigrationInfo, error) {
+	return f.pending, nil
+}
+
+func (f *fakeMigrator) ApplyMigration(_ context.Context, _ int64, migrationIDs []int64, _ int64) ([]int64, time.Time, error) {
+	f.applied = migrationIDs
+	return migrationIDs, time.Now(), nil
+}
+
+func (f *fakeMigrator) PlanApplyMigration(_ context.Context, _ int64, migrationIDs []int64, _ int64) (entity.Plan, error) {
+	f.planned = migrationIDs
+	return entity.Plan{}, nil
+}
+
+func (f *fakeMigrator) UpdateRepeatableMigration(_ context.Context, _ int64, _ string) (bool, []entity.LintFinding, error) {
+	return true, nil, nil
+}
+
+func (f *fakeMigrator) RollbackToMigration(ctx context.Context, _ int64, _ int64, _ int64, authorize func(ctx context.Context, migration entity.MigrationInfo) error) ([]int64, time.Time, error) {
+	for _, migration := range f.newer {
+		if err := authorize(ctx, migration); err != nil {
+			return nil, time.Time{}, err
+		}
+		f.rolledBack = append(f.rolledBack, migration.ID)
+	}
+	return f.rolledBack, time.Now(), nil
+}
+
+// fakeAuth - сервис авторизации, выдающий пользователю заданные права.
+type fakeAuth struct {
+	authClient
+	granted map[entity.Permission]bool
+}
+
+func (f *fakeAuth) CheckPermissions(_ context.Context, _ int64, permissions ...entity.Permission) (map[entity.Permission]bool, error) {
+	decisions := make(map[entity.Permission]bool, len(permissions))
+	for _, permission := range permissions {
+		decisions[permission] = f.granted[permission]
+	}
+	return decisions, nil
+}
+
+func (f *fakeAuth) CheckPermissionRollback(_ context.Context, _ int64) (bool, error) {
+	return f.granted[entity.PermissionRollback], nil
+}
+
+func (f *fakeAuth) CheckPermissionRollbackOther(_ context.Context, _ int64) (bool, error) {
+	return f.granted[entity.PermissionRollbackOther], nil
+}
+
+func grant(permissions ...entity.Permission) map[entity.Permission]bool {
+	granted := make(map[entity.Permission]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission] = true
+	}
+	return granted
+}
+
+func TestApplyMigrationPermissions(t *testing.T) {
+	migrations := map[int64]entity.MigrationInfo{
+		1: {ID: 1, CreatedBy: owner},
+		2: {ID: 2, CreatedBy: other},
+		3: {ID: 3, CreatedBy: owner, Kind: entity.MigrationKindRepeatable},
+		4: {ID: 4, CreatedBy: other, Kind: entity.MigrationKindRepeatable},
+	}
+
+	tests := []struct {
+		name         string
+		migrationIDs []int64
+		pending      []int64
+		granted      map[entity.Permission]bool
+		want         []int64
+		wantDenied   bool
+	}{
+		{
+			name:         "own migration",
+			migrationIDs: []int64{1},
+			granted:      grant(entity.PermissionApply),
+			want:         []int64{1},
+		},
+		{
+			name:         "own migration without apply",
+			migrationIDs: []int64{1},
+			granted:      grant(entity.PermissionApplyOther),
+			wantDenied:   true,
+		},
+		{
+			name:         "other's migration with apply only",
+			migrationIDs: []int64{1, 2},
+			granted:      grant(entity.PermissionApply),
+			wantDenied:   true,
+		},
+		{
+			name:         "other's migration with apply other",
+			migrationIDs: []int64{1, 2},
+			granted:      grant(entity.PermissionApply, entity.PermissionApplyOther),
+			want:         []int64{1, 2},
+		},
+		{
+			name:         "pending repeatables are added when permitted",
+			migrationIDs: []int64{1},
+			pending:      []int64{3, 4},
+			granted:      grant(entity.PermissionApply, entity.PermissionApplyOther),
+			want:         []int64{1, 3, 4},
+		},
+		{
+			name:         "other's pending repeatable is left without apply other",
+			migrationIDs: []int64{1},
+			pending:      []int64{3, 4},
+			granted:      grant(entity.PermissionApply),
+			want:         []int64{1, 3},
+		},
+		{
+			name:         "pending repeatables do not grant the request",
+			migrationIDs: []int64{2},
+			pending:      []int64{3},
+			granted:      grant(entity.PermissionApply),
+			wantDenied:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &fakeMigrator{migrations: migrations}
+			for _, migrationID := range tt.pending {
+				srv.pending = append(srv.pending, migrations[migrationID])
+			}
+			mwa := NewMigratorWithAuth(srv, &fakeAuth{granted: tt.granted})
+
+			applied, _, err := mwa.ApplyMigration(context.Background(), 1, tt.migrationIDs, owner)
+			if tt.wantDenied {
+				if !errors.Is(err, entity.ErrPermissionDenied) {
+					t.Fatalf("ApplyMigration() error = %v, want ErrPermissionDenied", err)
+				}
+				if srv.applied != nil {
+					t.Errorf("migrations %v were applied despite the denial", srv.applied)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyMigration() error = %v", err)
+			}
+			if !reflect.DeepEqual(applied, tt.want) {
+				t.Errorf("ApplyMigration() applied = %v, want %v", applied, tt.want)
+			}
+
+			if _, err := mwa.PlanApplyMigration(context.Background(), 1, tt.migrationIDs, owner); err != nil {
+				t.Fatalf("PlanApplyMigration() error = %v", err)
+			}
+			if !reflect.DeepEqual(srv.planned, tt.want) {
+				t.Errorf("PlanApplyMigration() planned = %v, want %v", srv.planned, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateRepeatableMigrationPermissions(t *testing.T) {
+	migrations := map[int64]entity.MigrationInfo{
+		1: {ID: 1, CreatedBy: owner, Kind: entity.MigrationKindRepeatable},
+		2: {ID: 2, CreatedBy: other, Kind: entity.MigrationKindRepeatable},
+	}
+
+	tests := []struct {
+		name        string
+		migrationID int64
+		granted     map[entity.Permission]bool
+		wantDenied  bool
+	}{
+		{name: "own migration", migrationID: 1, granted: grant(entity.PermissionCreate)},
+		{name: "own migration without create", migrationID: 1, granted: grant(entity.PermissionApply), wantDenied: true},
+		{name: "other's migration with create only", migrationID: 2, granted: grant(entity.PermissionCreate), wantDenied: true},
+		{name: "other's migration with apply other", migrationID: 2, granted: grant(entity.PermissionCreate, entity.PermissionApplyOther)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mwa := NewMigratorWithAuth(&fakeMigrator{migrations: migrations}, &fakeAuth{granted: tt.granted})
+
+			_, _, err := mwa.UpdateRepeatableMigration(context.Background(), tt.migrationID, "SELECT 1", owner)
+			if tt.wantDenied != errors.Is(err, entity.ErrPermissionDenied) {
+				t.Fatalf("UpdateRepeatableMigration() error = %v, wantDenied %v", err, tt.wantDenied)
+			}
+			if !tt.wantDenied && err != nil {
+				t.Fatalf("UpdateRepeatableMigration() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRollbackToMigrationPermissions(t *testing.T) {
+	newer := []entity.MigrationInfo{
+		{ID: 3, CreatedBy: owner},
+		{ID: 2, CreatedBy: other},
+	}
+
+	tests := []struct {
+		name       string
+		granted    map[entity.Permission]bool
+		wantDenied bool
+	}{
+		{name: "rollback own and other", granted: grant(entity.PermissionRollback, entity.PermissionRollbackOther)},
+		{name: "rollback own only", granted: grant(entity.PermissionRollback), wantDenied: true},
+		{name: "rollback other only", granted: grant(entity.PermissionRollbackOther), wantDenied: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &fakeMigrator{newer: newer}
+			mwa := NewMigratorWithAuth(srv, &fakeAuth{granted: tt.granted})
+
+			_, _, err := mwa.RollbackToMigration(context.Background(), 1, 1, owner)
+			if tt.wantDenied != errors.Is(err, entity.ErrPermissionDenied) {
+				t.Fatalf("RollbackToMigration() error = %v, wantDenied %v", err, tt.wantDenied)
+			}
+			if !tt.wantDenied && !reflect.DeepEqual(srv.rolledBack, []int64{3, 2}) {
+				t.Errorf("rolled back %v, want [3 2]", srv.rolledBack)
+			}
+		})
+	}
+}
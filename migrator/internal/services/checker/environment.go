@@ -0,0 +1,97 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+
+	"migrator/internal/entity"
+)
+
+type environmentSrv interface {
+	CreateEnvironment(ctx context.Context, environment entity.Environment, userID int64) (int64, error)
+	GetEnvironment(ctx context.Context, environmentID int64) (entity.Environment, error)
+	ListEnvironments(ctx context.Context) ([]entity.Environment, error)
+	UpdateEnvironment(ctx context.Context, environment entity.Environment, userID int64) error
+	DeleteEnvironment(ctx context.Context, environmentID, userID int64) error
+	ListMigrationEnvironments(ctx context.Context, filter entity.EnvironmentMigrationFilter) ([]entity.MigrationEnvironments, error)
+}
+
+// EnvironmentsWithAuth is a wrapper around the environment service that adds authorization checks.
+// Environments are part of the target registry and are managed with PERMISSION_MANAGE_TARGETS.
+type EnvironmentsWithAuth struct {
+	environments environmentSrv
+	authClient   authClient
+}
+
+// NewEnvironmentsWithAuth creates a new EnvironmentsWithAuth.
+func NewEnvironmentsWithAuth(environments environmentSrv, authClient authClient) *EnvironmentsWithAuth {
+	return &EnvironmentsWithAuth{
+		environments: environments,
+		authClient:   authClient,
+	}
+}
+
+func (ewa *EnvironmentsWithAuth) checkManage(ctx context.Context, userID int64, action string) error {
+	hasPermission, err := ewa.authClient.CheckPermissionManageTargets(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("auth check failed for %s: %w", action, err)
+	}
+	if !hasPermission {
+		return fmt.Errorf("%w: user %d lacks permission to manage environments", entity.ErrPermissionDenied, userID)
+	}
+	return nil
+}
+
+// CreateEnvironment creates an environment after checking permissions.
+func (ewa *EnvironmentsWithAuth) CreateEnvironment(ctx context.Context, environment entity.Environment, userID int64) (int64, error) {
+	if err := ewa.checkManage(ctx, userID, "CreateEnvironment"); err != nil {
+		return 0, err
+	}
+
+	return ewa.environments.CreateEnvironment(ctx, environment, userID)
+}
+
+// GetEnvironment возвращает окружение по его ID после проверки права PERMISSION_GET.
+func (ewa *EnvironmentsWithAuth) GetEnvironment(ctx context.Context, environmentID, userID int64) (entity.Environment, error) {
+	if err := checkGet(ctx, ewa.authClient, userID, "GetEnvironment"); err != nil {
+		return entity.Environment{}, err
+	}
+
+	return ewa.environments.GetEnvironment(ctx, environmentID)
+}
+
+// ListEnvironments возвращает список окружений после проверки права PERMISSION_LIST.
+func (ewa *EnvironmentsWithAuth) ListEnvironments(ctx context.Context, userID int64) ([]entity.Environment, error) {
+	if err := checkList(ctx, ewa.authClient, userID, "ListEnvironments"); err != nil {
+		return nil, err
+	}
+
+	return ewa.environments.ListEnvironments(ctx)
+}
+
+// UpdateEnvironment updates an environment and its promotion rule after checking permissions.
+func (ewa *EnvironmentsWithAuth) UpdateEnvironment(ctx context.Context, environment entity.Environment, userID int64) error {
+	if err := ewa.checkManage(ctx, userID, "UpdateEnvironment"); err != nil {
+		return err
+	}
+
+	return ewa.environments.UpdateEnvironment(ctx, environment, userID)
+}
+
+// DeleteEnvironment removes an environment after checking permissions.
+func (ewa *EnvironmentsWithAuth) DeleteEnvironment(ctx context.Context, environmentID, userID int64) error {
+	if err := ewa.checkManage(ctx, userID, "DeleteEnvironment"); err != nil {
+		return err
+	}
+
+	return ewa.environments.DeleteEnvironment(ctx, environmentID, userID)
+}
+
+// ListMigrationEnvironments возвращает состояния миграций по окружениям после проверки права PERMISSION_LIST.
+func (ewa *EnvironmentsWithAuth) ListMigrationEnvironments(ctx context.Context, filter entity.EnvironmentMigrationFilter, userID int64) ([]entity.MigrationEnvironments, error) {
+	if err := checkList(ctx, ewa.authClient, userID, "ListMigrationEnvironments"); err != nil {
+		return nil, err
+	}
+
+	return ewa.environments.ListMigrationEnvironments(ctx, filter)
+}
@@ -0,0 +1,49 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+
+	"migrator/internal/entity"
+)
+
+type lockSrv interface {
+	ListLocks(ctx context.Context) ([]entity.Lock, error)
+	ReleaseLock(ctx context.Context, targetID, userID int64) error
+}
+
+// LocksWithAuth is a wrapper around the target locker that adds authorization checks.
+type LocksWithAuth struct {
+	locks      lockSrv
+	authClient authClient
+}
+
+// NewLocksWithAuth creates a new LocksWithAuth.
+func NewLocksWithAuth(locks lockSrv, authClient authClient) *LocksWithAuth {
+	return &LocksWithAuth{
+		locks:      locks,
+		authClient: authClient,
+	}
+}
+
+// ListLocks возвращает удерживаемые сейчас блокировки после проверки права PERMISSION_LIST.
+func (lwa *LocksWithAuth) ListLocks(ctx context.Context, userID int64) ([]entity.Lock, error) {
+	if err := checkList(ctx, lwa.authClient, userID, "ListLocks"); err != nil {
+		return nil, err
+	}
+
+	return lwa.locks.ListLocks(ctx)
+}
+
+// ReleaseLock force-releases a target database lock after checking permissions.
+func (lwa *LocksWithAuth) ReleaseLock(ctx context.Context, targetID, userID int64) error {
+	hasPermission, err := lwa.authClient.CheckPermissionManageTargets(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("auth check failed for ReleaseLock: %w", err)
+	}
+	if !hasPermission {
+		return fmt.Errorf("%w: user %d lacks permission to release locks", entity.ErrPermissionDenied, userID)
+	}
+
+	return lwa.locks.ReleaseLock(ctx, targetID, userID)
+}
@@ -0,0 +1,86 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+
+	"migrator/internal/entity"
+)
+
+type targetSrv interface {
+	CreateTarget(ctx context.Context, name, description, url string, environmentID int64, variables map[string]string, userID int64) (int64, error)
+	GetTarget(ctx context.Context, targetID int64) (entity.Target, error)
+	ListTargets(ctx context.Context) ([]entity.Target, error)
+	UpdateTarget(ctx context.Context, targetID int64, name, description, url string, environmentID int64, variables map[string]string, userID int64) error
+	DeleteTarget(ctx context.Context, targetID, userID int64) error
+}
+
+// TargetsWithAuth is a wrapper around the target registry that adds authorization checks.
+type TargetsWithAuth struct {
+	targets    targetSrv
+	authClient authClient
+}
+
+// NewTargetsWithAuth creates a new TargetsWithAuth.
+func NewTargetsWithAuth(targets targetSrv, authClient authClient) *TargetsWithAuth {
+	return &TargetsWithAuth{
+		targets:    targets,
+		authClient: authClient,
+	}
+}
+
+func (twa *TargetsWithAuth) checkManage(ctx context.Context, userID int64, action string) error {
+	hasPermission, err := twa.authClient.CheckPermissionManageTargets(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("auth check failed for %s: %w", action, err)
+	}
+	if !hasPermission {
+		return fmt.Errorf("%w: user %d lacks permission to manage targets", entity.ErrPermissionDenied, userID)
+	}
+	return nil
+}
+
+// CreateTarget registers a new target database after checking permissions.
+func (twa *TargetsWithAuth) CreateTarget(ctx context.Context, name, description, url string, environmentID int64, variables map[string]string, userID int64) (int64, error) {
+	if err := twa.checkManage(ctx, userID, "CreateTarget"); err != nil {
+		return 0, err
+	}
+
+	return twa.targets.CreateTarget(ctx, name, description, url, environmentID, variables, userID)
+}
+
+// GetTarget возвращает целевую базу данных по ее ID после проверки права PERMISSION_GET.
+func (twa *TargetsWithAuth) GetTarget(ctx context.Context, targetID, userID int64) (entity.Target, error) {
+	if err := checkGet(ctx, twa.authClient, userID, "GetTarget"); err != nil {
+		return entity.Target{}, err
+	}
+
+	return twa.targets.GetTarget(ctx, targetID)
+}
+
+// ListTargets возвращает список целевых баз данных после проверки права PERMISSION_LIST.
+func (twa *TargetsWithAuth) ListTargets(ctx context.Context, userID int64) ([]entity.Target, error) {
+	if err := checkList(ctx, twa.authClient, userID, "ListTargets"); err != nil {
+		return nil, err
+	}
+
+	return twa.targets.ListTargets(ctx)
+}
+
+// UpdateTarget updates target database settings after checking permissions.
+func (twa *TargetsWithAuth) UpdateTarget(ctx context.Context, targetID int64, name, description, url string, environmentID int64, variables map[string]string, userID int64) error {
+	if err := twa.checkManage(ctx, userID, "UpdateTarget"); err != nil {
+		return err
+	}
+
+	return twa.targets.UpdateTarget(ctx, targetID, name, description, url, environmentID, variables, userID)
+}
+
+// DeleteTarget removes a target database after checking permissions.
+func (twa *TargetsWithAuth) DeleteTarget(ctx context.Context, targetID, userID int64) error {
+	if err := twa.checkManage(ctx, userID, "DeleteTarget"); err != nil {
+		return err
+	}
+
+	return twa.targets.DeleteTarget(ctx, targetID, userID)
+}
@@ -0,0 +1,363 @@
+// Package environment содержит бизнес-логику окружений и правил продвижения
+// миграций по конвейеру окружений (dev -> staging -> prod).
+package environment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+type environmentRepository interface {
+	Create(ctx context.Context, environment entity.Environment) (int64, error)
+	Get(ctx context.Context, environmentID int64) (entity.Environment, error)
+	List(ctx context.Context) ([]entity.Environment, error)
+	Update(ctx context.Context, environment entity.Environment) error
+	Delete(ctx context.Context, environmentID int64) error
+	ListMigrations(ctx context.Context, name string, environmentID, targetID int64) ([]entity.EnvironmentMigration, error)
+}
+
+type targetRepository interface {
+	Get(ctx context.Context, targetID int64) (entity.Target, error)
+}
+
+// Service - сервис окружений.
+type Service struct {
+	repo    environmentRepository
+	targets targetRepository
+}
+
+// New - конструктор сервиса окружений.
+func New(repo environmentRepository, targets targetRepository) *Service {
+	return &Service{
+		repo:    repo,
+		targets: targets,
+	}
+}
+
+// CreateEnvironment создает окружение.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	environment: entity.Environment - Название, описание, правило продвижения и переменные шаблонов окружения.
+//	userID: int64 - Идентификатор пользователя, создающего окружение.
+//
+// Возвращает:
+//
+//	int64: Уникальный идентификатор окружения.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) CreateEnvironment(ctx context.Context, environment entity.Environment, userID int64) (int64, error) {
+	if environment.MinSoak < 0 {
+		return 0, fmt.Errorf("%w: min soak cannot be negative", entity.ErrInvalidArgument)
+	}
+	if err := entity.ValidateVariables(environment.Variables); err != nil {
+		return 0, err
+	}
+
+	environment.CreatedBy = userID
+	environmentID, err := s.repo.Create(ctx, environment)
+	if err != nil {
+		return 0, fmt.Errorf("s.repo.Create: %w", err)
+	}
+
+	return environmentID, nil
+}
+
+// GetEnvironment возвращает окружение по его ID.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	environmentID: int64 - Идентификатор окружения.
+//
+// Возвращает:
+//
+//	entity.Environment: Информация об окружении.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) GetEnvironment(ctx context.Context, environmentID int64) (entity.Environment, error) {
+	environment, err := s.repo.Get(ctx, environmentID)
+	if err != nil {
+		return entity.Environment{}, fmt.Errorf("s.repo.Get: %w", err)
+	}
+
+	return environment, nil
+}
+
+// ListEnvironments возвращает окружения в порядке конвейера: сначала окружения
+// без предыдущего, затем продвигаемые из них.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//
+// Возвращает:
+//
+//	[]entity.Environment: Список окружений.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) ListEnvironments(ctx context.Context) ([]entity.Environment, error) {
+	environments, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.List: %w", err)
+	}
+
+	sortPipeline(environments)
+
+	return environments, nil
+}
+
+// UpdateEnvironment изменяет окружение. Правило продвижения не может замыкать конвейер в цикл.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	environment: entity.Environment - Окружение с новыми значениями.
+//	userID: int64 - Идентификатор пользователя, изменяющего окружение.
+//
+// Возвращает:
+//
+//	error: Ошибка, если таковая имеется.
+func (s *Service) UpdateEnvironment(ctx context.Context, environment entity.Environment, userID int64) error {
+	if environment.MinSoak < 0 {
+		return fmt.Errorf("%w: min soak cannot be negative", entity.ErrInvalidArgument)
+	}
+	if err := entity.ValidateVariables(environment.Variables); err != nil {
+		return err
+	}
+
+	for previousID := environment.PromoteFromID; previousID != 0; {
+		if previousID == environment.ID {
+			return fmt.Errorf("%w: environment %d cannot be promoted from itself", entity.ErrInvalidArgument, environment.ID)
+		}
+		previous, err := s.repo.Get(ctx, previousID)
+		if err != nil {
+			return fmt.Errorf("s.repo.Get: %w", err)
+		}
+		previousID = previous.PromoteFromID
+	}
+
+	err := s.repo.Update(ctx, environment)
+	if err != nil {
+		return fmt.Errorf("s.repo.Update: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEnvironment удаляет окружение. Окружение, к которому относятся целевые
+// базы данных или из которого продвигаются другие окружения, удалить нельзя.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	environmentID: int64 - Идентификатор окружения.
+//	userID: int64 - Идентификатор пользователя, удаляющего окружение.
+//
+// Возвращает:
+//
+//	error: Ошибка, если таковая имеется.
+func (s *Service) DeleteEnvironment(ctx context.Context, environmentID, userID int64) error {
+	err := s.repo.Delete(ctx, environmentID)
+	if err != nil {
+		return fmt.Errorf("s.repo.Delete: %w", err)
+	}
+
+	return nil
+}
+
+// ListMigrationEnvironments возвращает состояния миграций во всех окружениях.
+// Миграции целевых баз данных сопоставляются по названию.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	filter: entity.EnvironmentMigrationFilter - Условия выборки.
+//
+// Возвращает:
+//
+//	[]entity.MigrationEnvironments: Состояния миграций по окружениям в порядке названий.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) ListMigrationEnvironments(ctx context.Context, filter entity.EnvironmentMigrationFilter) ([]entity.MigrationEnvironments, error) {
+	environments, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.List: %w", err)
+	}
+	sortPipeline(environments)
+	order := make(map[int64]int, len(environments))
+	for i, environment := range environments {
+		order[environment.ID] = i
+	}
+
+	rows, err := s.repo.ListMigrations(ctx, filter.Name, 0, filter.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.ListMigrations: %w", err)
+	}
+
+	var result []entity.MigrationEnvironments
+	for _, row := range rows {
+		if len(result) == 0 || result[len(result)-1].Name != row.Name {
+			result = append(result, entity.MigrationEnvironments{Name: row.Name})
+		}
+		last := &result[len(result)-1]
+		last.Statuses = append(last.Statuses, row)
+	}
+
+	filtered := result[:0]
+	for _, migration := range result {
+		sort.SliceStable(migration.Statuses, func(i, j int) bool {
+			return order[migration.Statuses[i].EnvironmentID] < order[migration.Statuses[j].EnvironmentID]
+		})
+		if matches(migration, filter) {
+			filtered = append(filtered, migration)
+		}
+	}
+
+	return filtered, nil
+}
+
+// matches сообщает, есть ли у миграции состояние в окружении и со статусом из фильтра.
+func matches(migration entity.MigrationEnvironments, filter entity.EnvironmentMigrationFilter) bool {
+	if filter.EnvironmentID == 0 && filter.Status == "" {
+		return true
+	}
+	for _, status := range migration.Statuses {
+		if (filter.EnvironmentID == 0 || status.EnvironmentID == filter.EnvironmentID) &&
+			(filter.Status == "" || status.Status == filter.Status) {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateContext возвращает переменные шаблонов скриптов целевой базы данных:
+// переменные ее окружения, дополненные и переопределенные переменными самой базы данных,
+// и СУБД, по правилам которой экранируются значения.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//
+// Возвращает:
+//
+//	entity.TemplateContext: Переменные шаблонов и СУБД целевой базы данных.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) TemplateContext(ctx context.Context, targetID int64) (entity.TemplateContext, error) {
+	target, err := s.targets.Get(ctx, targetID)
+	if err != nil {
+		return entity.TemplateContext{}, fmt.Errorf("s.targets.Get: %w", err)
+	}
+
+	driver, err := entity.DriverFromURL(target.URL)
+	if err != nil {
+		return entity.TemplateContext{}, err
+	}
+
+	variables := make(map[string]string)
+	if target.EnvironmentID != 0 {
+		environment, err := s.repo.Get(ctx, target.EnvironmentID)
+		if err != nil {
+			return entity.TemplateContext{}, fmt.Errorf("s.repo.Get: %w", err)
+		}
+		for name, value := range environment.Variables {
+			variables[name] = value
+		}
+	}
+	for name, value := range target.Variables {
+		variables[name] = value
+	}
+
+	return entity.TemplateContext{Driver: driver, Variables: variables}, nil
+}
+
+// CheckPromotion проверяет правило продвижения окружения целевой базы данных:
+// каждая миграция должна быть применена с теми же скриптами во всех целевых базах данных
+// предыдущего окружения, в которых она зарегистрирована, не меньше MinSoak назад.
+// Для целевых баз данных вне конвейера и первого окружения конвейера проверок нет.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	migrations: []entity.MigrationInfo - Применяемые миграции.
+//
+// Возвращает:
+//
+//	error: entity.ErrPromotionBlocked с причиной, если миграцию применять рано.
+func (s *Service) CheckPromotion(ctx context.Context, targetID int64, migrations []entity.MigrationInfo) error {
+	target, err := s.targets.Get(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("s.targets.Get: %w", err)
+	}
+	if target.EnvironmentID == 0 {
+		return nil
+	}
+
+	environment, err := s.repo.Get(ctx, target.EnvironmentID)
+	if err != nil {
+		return fmt.Errorf("s.repo.Get: %w", err)
+	}
+	if environment.PromoteFromID == 0 {
+		return nil
+	}
+
+	previous, err := s.repo.Get(ctx, environment.PromoteFromID)
+	if err != nil {
+		return fmt.Errorf("s.repo.Get: %w", err)
+	}
+
+	now := time.Now()
+	for _, migration := range migrations {
+		states, err := s.repo.ListMigrations(ctx, migration.Name, previous.ID, 0)
+		if err != nil {
+			return fmt.Errorf("s.repo.ListMigrations: %w", err)
+		}
+
+		if reason := promotionReason(migration, states, previous, environment.MinSoak, now); reason != "" {
+			return fmt.Errorf("%w: migration %d (%s) cannot be applied in %s: %s",
+				entity.ErrPromotionBlocked, migration.ID, migration.Name, environment.Name, reason)
+		}
+	}
+
+	return nil
+}
+
+// promotionReason возвращает причину, по которой миграцию нельзя продвинуть
+// из предыдущего окружения, или пустую строку.
+func promotionReason(migration entity.MigrationInfo, states []entity.EnvironmentMigration, previous entity.Environment, minSoak time.Duration, now time.Time) string {
+	if len(states) == 0 {
+		return fmt.Sprintf("it is not registered in %s", previous.Name)
+	}
+
+	var reasons []string
+	for _, state := range states {
+		switch {
+		case state.Status != entity.StatusApplied:
+			reasons = append(reasons, fmt.Sprintf("it is %s in %s", state.Status, state.TargetName))
+		case state.Checksum != migration.Checksum:
+			reasons = append(reasons, fmt.Sprintf("%s has different scripts", state.TargetName))
+		case now.Sub(state.StatusUpdatedAt) < minSoak:
+			reasons = append(reasons, fmt.Sprintf("it was applied in %s at %s, promotion is allowed after %s",
+				state.TargetName, state.StatusUpdatedAt.Format(time.DateTime), state.StatusUpdatedAt.Add(minSoak).Format(time.DateTime)))
+		}
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+// sortPipeline упорядочивает окружения по числу предыдущих окружений в конвейере.
+func sortPipeline(environments []entity.Environment) {
+	byID := make(map[int64]entity.Environment, len(environments))
+	for _, environment := range environments {
+		byID[environment.ID] = environment
+	}
+
+	depth := func(environment entity.Environment) int {
+		d := 0
+		for environment.PromoteFromID != 0 && d < len(environments) {
+			environment = byID[environment.PromoteFromID]
+			d++
+		}
+		return d
+	}
+
+	sort.SliceStable(environments, func(i, j int) bool {
+		return depth(environments[i]) < depth(environments[j])
+	})
+}
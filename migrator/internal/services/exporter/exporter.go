@@ -0,0 +1,192 @@
+// Package exporter содержит логику выгрузки реестра миграций в дерево файлов.
+//
+// Выгрузка детерминирована: при неизменном реестре архив получается
+// побайтно одинаковым, поэтому его можно хранить в git и сравнивать.
+package exporter
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"migrator/internal/entity"
+)
+
+type migrationLister interface {
+	ListMigrations(ctx context.Context, targetID int64, statusFilter string, selector entity.LabelSelector, userID int64) ([]entity.MigrationInfo, error)
+}
+
+type targetLister interface {
+	GetTarget(ctx context.Context, targetID, userID int64) (entity.Target, error)
+	ListTargets(ctx context.Context, userID int64) ([]entity.Target, error)
+}
+
+// Exporter - сервис выгрузки реестра миграций.
+type Exporter struct {
+	migrations migrationLister
+	targets    targetLister
+}
+
+// New - конструктор сервиса выгрузки.
+func New(migrations migrationLister, targets targetLister) *Exporter {
+	return &Exporter{
+		migrations: migrations,
+		targets:    targets,
+	}
+}
+
+// ExportMigrations записывает в w архив tar.gz с реестром миграций:
+// по каталогу на целевую базу данных, файлы <id>_<название>.up.sql и .down.sql
+// на каждую миграцию и manifest.json со статусами, авторами и контрольными суммами.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных; 0 выгружает все базы данных.
+//	userID: int64 - Идентификатор пользователя, выгружающего реестр.
+//	w: io.Writer - Получатель архива.
+//
+// Возвращает:
+//
+//	error: Ошибка, если таковая имеется.
+func (e *Exporter) ExportMigrations(ctx context.Context, targetID, userID int64, w io.Writer) error {
+	var targets []entity.Target
+	if targetID != 0 {
+		target, err := e.targets.GetTarget(ctx, targetID, userID)
+		if err != nil {
+			return fmt.Errorf("e.targets.GetTarget: %w", err)
+		}
+		targets = []entity.Target{target}
+	} else {
+		var err error
+		targets, err = e.targets.ListTargets(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("e.targets.ListTargets: %w", err)
+		}
+	}
+
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return fmt.Errorf("create gzip writer: %w", err)
+	}
+	tw := tar.NewWriter(gz)
+
+	manifest := entity.ExportManifest{
+		Format:  entity.ExportManifestFormat,
+		Version: 1,
+		Targets: []entity.ExportTarget{},
+	}
+
+	for _, target := range targets {
+		migrations, err := e.migrations.ListMigrations(ctx, target.ID, "", nil, userID)
+		if err != nil {
+			return fmt.Errorf("e.migrations.ListMigrations: %w", err)
+		}
+
+		exportTarget := entity.ExportTarget{
+			ID:          target.ID,
+			Name:        target.Name,
+			Description: target.Description,
+			Migrations:  []entity.ExportMigration{},
+		}
+		dir := fmt.Sprintf("%d_%s", target.ID, slug(target.Name))
+
+		for _, migration := range migrations {
+			base := fmt.Sprintf("%s/%06d_%s", dir, migration.ID, slug(migration.Name))
+			upFile, downFile := base+".up.sql", base+".down.sql"
+
+			if err := writeFile(tw, upFile, []byte(migration.Script)); err != nil {
+				return err
+			}
+			if err := writeFile(tw, downFile, []byte(migration.RollbackScript)); err != nil {
+				return err
+			}
+
+			exportTarget.Migrations = append(exportTarget.Migrations, entity.ExportMigration{
+				ID:              migration.ID,
+				Name:            migration.Name,
+				Description:     migration.Description,
+				Labels:          migration.Labels,
+				TenantScoped:    migration.TenantScoped,
+				Templated:       migration.Templated,
+				Status:          migration.Status,
+				ExecutionMode:   migration.ExecutionMode,
+				Kind:            migration.Kind,
+				Backfill:        migration.Backfill,
+				CreatedBy:       migration.CreatedBy,
+				StatusUpdatedAt: migration.StatusUpdatedAt.UTC().Format(time.RFC3339),
+				Checksum:        migration.Checksum,
+				AppliedChecksum: migration.AppliedChecksum,
+				UpFile:          upFile,
+				DownFile:        downFile,
+			})
+		}
+
+		manifest.Targets = append(manifest.Targets, exportTarget)
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+	if err := writeFile(tw, entity.ExportManifestFile, append(content, '\n')); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close tar: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("close gzip: %w", err)
+	}
+	return nil
+}
+
+// writeFile добавляет файл в архив с постоянными атрибутами, чтобы архив был детерминированным.
+func writeFile(tw *tar.Writer, name string, content []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(content)),
+		ModTime:  time.Unix(0, 0).UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("write header %s: %w", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// slug превращает название в безопасное имя файла.
+func slug(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			underscore = false
+			continue
+		}
+		if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+
+	s := strings.TrimRight(b.String(), "_")
+	if len(s) > 64 {
+		s = strings.TrimRight(s[:64], "_")
+	}
+	if s == "" {
+		s = "migration"
+	}
+	return s
+}
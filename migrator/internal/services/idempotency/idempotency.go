@@ -0,0 +1,163 @@
+// Package idempotency содержит ключи идемпотентности запросов: повтор запроса
+// с тем же ключом возвращает ответ первого запроса, а не выполняется заново.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+)
+
+// _saveTimeout - время на сохранение ответа или снятие резерва ключа.
+const _saveTimeout = 5 * time.Second
+
+// _purgeInterval - период удаления ключей, которые больше не действуют.
+const _purgeInterval = time.Hour
+
+type idempotencyRepository interface {
+	Reserve(ctx context.Context, record entity.IdempotencyRecord, completedBefore, staleBefore time.Time) (bool, error)
+	Get(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyRecord, error)
+	Complete(ctx context.Context, record entity.IdempotencyRecord) error
+	Release(ctx context.Context, record entity.IdempotencyRecord) error
+	Purge(ctx context.Context, completedBefore, staleBefore time.Time) (int64, error)
+}
+
+// Service - сервис ключей идемпотентности.
+type Service struct {
+	repo              idempotencyRepository
+	retention         time.Duration
+	inProgressTimeout time.Duration
+}
+
+// New - конструктор сервиса ключей идемпотентности.
+// Аргументы:
+//
+//	repo: idempotencyRepository - Хранилище ключей.
+//	retention: time.Duration - Сколько ответ выполненного запроса возвращается повторам.
+//	inProgressTimeout: time.Duration - Сколько ключ занят незавершенным запросом.
+func New(repo idempotencyRepository, retention, inProgressTimeout time.Duration) *Service {
+	return &Service{
+		repo:              repo,
+		retention:         retention,
+		inProgressTimeout: inProgressTimeout,
+	}
+}
+
+// Begin резервирует ключ за запросом. Если запрос с этим ключом уже выполнен,
+// возвращается его сохраненный ответ, и запрос выполнять не нужно.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	key: entity.IdempotencyKey - Ключ идемпотентности.
+//	requestHash: string - Хэш запроса.
+//
+// Возвращает:
+//
+//	[]byte: Сохраненный ответ запроса, выполненного с ключом.
+//	bool: Признак того, что запрос уже выполнен и возвращается сохраненный ответ.
+//	error: entity.ErrIdempotencyInProgress, если запрос с ключом еще выполняется,
+//	или entity.ErrInvalidArgument, если ключ передан с другим запросом.
+func (s *Service) Begin(ctx context.Context, key entity.IdempotencyKey, requestHash string) ([]byte, bool, error) {
+	now := time.Now()
+	reserved, err := s.repo.Reserve(ctx, entity.IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+	}, now.Add(-s.retention), now.Add(-s.inProgressTimeout))
+	if err != nil {
+		return nil, false, fmt.Errorf("s.repo.Reserve: %w", err)
+	}
+	if reserved {
+		return nil, false, nil
+	}
+
+	record, err := s.repo.Get(ctx, key)
+	if errors.Is(err, entity.ErrNotFound) {
+		// Резерв ключа сняли между запросами: первый запрос завершился ошибкой.
+		return nil, false, entity.ErrIdempotencyInProgress
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("s.repo.Get: %w", err)
+	}
+	if record.RequestHash != requestHash {
+		return nil, false, fmt.Errorf("%w: idempotency key %q is already used with a different request", entity.ErrInvalidArgument, key.Key)
+	}
+	if !record.Completed {
+		return nil, false, entity.ErrIdempotencyInProgress
+	}
+
+	return record.Response, true, nil
+}
+
+// Complete сохраняет ответ запроса, зарезервировавшего ключ. Сохранение
+// выполняется и после отмены запроса; ошибка только логируется, так как
+// запрос уже выполнен.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	key: entity.IdempotencyKey - Ключ идемпотентности.
+//	requestHash: string - Хэш запроса.
+//	response: []byte - Ответ запроса.
+func (s *Service) Complete(ctx context.Context, key entity.IdempotencyKey, requestHash string, response []byte) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _saveTimeout)
+	defer cancel()
+
+	err := s.repo.Complete(ctx, entity.IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		Response:    response,
+		Completed:   true,
+		CompletedAt: time.Now(),
+	})
+	if err != nil {
+		logger.Error(fmt.Errorf("s.repo.Complete: %w", err))
+	}
+}
+
+// Release снимает резерв ключа с запроса, завершившегося ошибкой, чтобы
+// повтор запроса выполнился заново.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	key: entity.IdempotencyKey - Ключ идемпотентности.
+//	requestHash: string - Хэш запроса.
+func (s *Service) Release(ctx context.Context, key entity.IdempotencyKey, requestHash string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _saveTimeout)
+	defer cancel()
+
+	err := s.repo.Release(ctx, entity.IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+	})
+	if err != nil {
+		logger.Error(fmt.Errorf("s.repo.Release: %w", err))
+	}
+}
+
+// Run периодически удаляет ключи, которые больше не действуют, до отмены контекста.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(_purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		s.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge удаляет ключи с истекшим сроком хранения и брошенные резервы.
+func (s *Service) purge(ctx context.Context) {
+	now := time.Now()
+	if _, err := s.repo.Purge(ctx, now.Add(-s.retention), now.Add(-s.inProgressTimeout)); err != nil && ctx.Err() == nil {
+		logger.Error(fmt.Errorf("s.repo.Purge: %w", err))
+	}
+}
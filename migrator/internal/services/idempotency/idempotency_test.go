@@ -0,0 +1,289 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+// memoryRepository - хранилище ключей в памяти с теми же условиями резерва,
+// что и таблица idempotency_keys.
+type memoryRepository struct {
+	mu      sync.Mutex
+	records map[entity.IdempotencyKey]entity.IdempotencyRecord
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{records: make(map[entity.IdempotencyKey]entity.IdempotencyRecord)}
+}
+
+func expired(record entity.IdempotencyRecord, completedBefore, staleBefore time.Time) bool {
+	if record.Completed {
+		return record.CompletedAt.Before(completedBefore)
+	}
+	return record.CreatedAt.Before(staleBefore)
+}
+
+func (r *memoryRepository) Reserve(_ context.Context, record entity.IdempotencyRecord, completedBefore, staleBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.records[record.Key]; ok && !expired(existing, completedBefore, staleBefore) {
+		return false, nil
+	}
+	r.records[record.Key] = entity.IdempotencyRecord{
+		Key:         record.Key,
+		RequestHash: record.RequestHash,
+		CreatedAt:   record.CreatedAt,
+	}
+	return true, nil
+}
+
+func (r *memoryRepository) Get(_ context.Context, key entity.IdempotencyKey) (entity.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[key]
+	if !ok {
+		return entity.IdempotencyRecord{}, entity.ErrNotFound
+	}
+	return record, nil
+}
+
+func (r *memoryRepository) Complete(_ context.Context, record entity.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.records[record.Key]
+	if !ok || existing.Completed || existing.RequestHash != record.RequestHash {
+		return nil
+	}
+	existing.Response = record.Response
+	existing.Completed = true
+	existing.CompletedAt = record.CompletedAt
+	r.records[record.Key] = existing
+	return nil
+}
+
+func (r *memoryRepository) Release(_ context.Context, record entity.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.records[record.Key]
+	if ok && !existing.Completed && existing.RequestHash == record.RequestHash {
+		delete(r.records, record.Key)
+	}
+	return nil
+}
+
+func (r *memoryRepository) Purge(_ context.Context, completedBefore, staleBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for key, record := range r.records {
+		if expired(record, completedBefore, staleBefore) {
+			delete(r.records, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// age сдвигает время создания и выполнения всех ключей в прошлое.
+func (r *memoryRepository) age(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, record := range r.records {
+		record.CreatedAt = record.CreatedAt.Add(-d)
+		if record.Completed {
+			record.CompletedAt = record.CompletedAt.Add(-d)
+		}
+		r.records[key] = record
+	}
+}
+
+const (
+	retention         = 24 * time.Hour
+	inProgressTimeout = 10 * time.Minute
+)
+
+// step - действие с ключом идемпотентности в сценарии теста.
+type step struct {
+	action string // begin, complete, release, age
+	key    entity.IdempotencyKey
+	hash   string
+	// response - сохраняемый ответ для complete и ожидаемый ответ повтора для begin.
+	response string
+	age      time.Duration
+
+	wantReplayed bool
+	wantErr      error
+}
+
+func TestServiceBegin(t *testing.T) {
+	key := entity.IdempotencyKey{UserID: 1, Method: "ApplyMigration", Key: "k1"}
+	otherUser := entity.IdempotencyKey{UserID: 2, Method: "ApplyMigration", Key: "k1"}
+	otherMethod := entity.IdempotencyKey{UserID: 1, Method: "CreateMigration", Key: "k1"}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "first request reserves the key",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+			},
+		},
+		{
+			name: "repeat of a completed request is replayed",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "complete", key: key, hash: "a", response: "ok"},
+				{action: "begin", key: key, hash: "a", response: "ok", wantReplayed: true},
+				{action: "begin", key: key, hash: "a", response: "ok", wantReplayed: true},
+			},
+		},
+		{
+			name: "repeat of a request in progress is aborted",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "begin", key: key, hash: "a", wantErr: entity.ErrIdempotencyInProgress},
+			},
+		},
+		{
+			name: "different request with the same key conflicts",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "complete", key: key, hash: "a", response: "ok"},
+				{action: "begin", key: key, hash: "b", wantErr: entity.ErrInvalidArgument},
+			},
+		},
+		{
+			name: "different request conflicts while the first is in progress",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "begin", key: key, hash: "b", wantErr: entity.ErrInvalidArgument},
+			},
+		},
+		{
+			name: "failed request releases the key",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "release", key: key, hash: "a"},
+				{action: "begin", key: key, hash: "a"},
+			},
+		},
+		{
+			name: "release by a different request keeps the reservation",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "release", key: key, hash: "b"},
+				{action: "begin", key: key, hash: "a", wantErr: entity.ErrIdempotencyInProgress},
+			},
+		},
+		{
+			name: "stale reservation is taken over",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "age", age: inProgressTimeout + time.Minute},
+				{action: "begin", key: key, hash: "b"},
+			},
+		},
+		{
+			name: "completed request is replayed within retention",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "complete", key: key, hash: "a", response: "ok"},
+				{action: "age", age: retention - time.Hour},
+				{action: "begin", key: key, hash: "a", response: "ok", wantReplayed: true},
+			},
+		},
+		{
+			name: "key is reused after retention",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "complete", key: key, hash: "a", response: "ok"},
+				{action: "age", age: retention + time.Hour},
+				{action: "begin", key: key, hash: "b"},
+			},
+		},
+		{
+			name: "keys of other users and methods are independent",
+			steps: []step{
+				{action: "begin", key: key, hash: "a"},
+				{action: "begin", key: otherUser, hash: "b"},
+				{action: "begin", key: otherMethod, hash: "c"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRepository()
+			s := New(repo, retention, inProgressTimeout)
+
+			for i, st := range tt.steps {
+				switch st.action {
+				case "begin":
+					response, replayed, err := s.Begin(ctx, st.key, st.hash)
+					if !errors.Is(err, st.wantErr) || (err == nil) != (st.wantErr == nil) {
+						t.Fatalf("step %d: Begin() error = %v, want %v", i, err, st.wantErr)
+					}
+					if replayed != st.wantReplayed {
+						t.Fatalf("step %d: Begin() replayed = %v, want %v", i, replayed, st.wantReplayed)
+					}
+					if string(response) != st.response {
+						t.Fatalf("step %d: Begin() response = %q, want %q", i, response, st.response)
+					}
+				case "complete":
+					s.Complete(ctx, st.key, st.hash, []byte(st.response))
+				case "release":
+					s.Release(ctx, st.key, st.hash)
+				case "age":
+					repo.age(st.age)
+				default:
+					t.Fatalf("step %d: unknown action %q", i, st.action)
+				}
+			}
+		})
+	}
+}
+
+func TestServicePurge(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	s := New(repo, retention, inProgressTimeout)
+
+	completed := entity.IdempotencyKey{UserID: 1, Method: "CreateMigration", Key: "completed"}
+	stale := entity.IdempotencyKey{UserID: 1, Method: "CreateMigration", Key: "stale"}
+	if _, _, err := s.Begin(ctx, completed, "a"); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	s.Complete(ctx, completed, "a", []byte("ok"))
+	if _, _, err := s.Begin(ctx, stale, "b"); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	repo.age(time.Hour)
+	s.purge(ctx)
+	if len(repo.records) != 1 {
+		t.Fatalf("after purge records = %v, want only the completed key", repo.records)
+	}
+	if _, ok := repo.records[completed]; !ok {
+		t.Errorf("completed key was purged within retention")
+	}
+
+	repo.age(retention)
+	s.purge(ctx)
+	if len(repo.records) != 0 {
+		t.Errorf("after retention records = %v, want none", repo.records)
+	}
+}
@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxArchiveContentSize ограничивает суммарный размер распакованных файлов.
+const maxArchiveContentSize = 64 << 20
+
+var errArchiveTooLarge = fmt.Errorf("archive content exceeds %d bytes", maxArchiveContentSize)
+
+// readArchive читает файлы из zip, tar или tar.gz архива.
+func readArchive(data []byte) ([]File, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return readZip(data)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("read gzip: %w", err)
+		}
+		defer gz.Close()
+		return readTar(gz)
+	case len(data) > 262 && string(data[257:262]) == "ustar":
+		return readTar(bytes.NewReader(data))
+	}
+	return nil, fmt.Errorf("unsupported archive format, expected zip, tar or tar.gz")
+}
+
+func readZip(data []byte) ([]File, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("read zip: %w", err)
+	}
+
+	var (
+		files []File
+		total int64
+	)
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", entry.Name, err)
+		}
+		content, err := readLimited(rc, &total)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", entry.Name, err)
+		}
+
+		files = append(files, File{Path: entry.Name, Content: content})
+	}
+
+	return files, nil
+}
+
+func readTar(r io.Reader) ([]File, error) {
+	reader := tar.NewReader(r)
+
+	var (
+		files []File
+		total int64
+	)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read tar: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := readLimited(reader, &total)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", header.Name, err)
+		}
+
+		files = append(files, File{Path: header.Name, Content: content})
+	}
+
+	return files, nil
+}
+
+func readLimited(r io.Reader, total *int64) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxArchiveContentSize-*total+1))
+	if err != nil {
+		return nil, err
+	}
+	*total += int64(len(content))
+	if *total > maxArchiveContentSize {
+		return nil, errArchiveTooLarge
+	}
+	return content, nil
+}
@@ -0,0 +1,127 @@
+// Package importer содержит логику импорта миграций из файлов в раскладках
+// golang-migrate, goose и Flyway.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"migrator/internal/entity"
+)
+
+type migrationRegistry interface {
+	CreateMigration(ctx context.Context, targetID int64, name, description, script, rollbackScript string, executionMode entity.ExecutionMode, kind entity.MigrationKind, labels map[string]string, tenantScoped, templated bool, backfill *entity.BackfillSpec, userID int64) (int64, []entity.LintFinding, error)
+	ListMigrations(ctx context.Context, targetID int64, statusFilter string, selector entity.LabelSelector, userID int64) ([]entity.MigrationInfo, error)
+}
+
+// Importer - сервис импорта миграций.
+type Importer struct {
+	migrations migrationRegistry
+}
+
+// New - конструктор сервиса импорта миграций.
+func New(migrations migrationRegistry) *Importer {
+	return &Importer{
+		migrations: migrations,
+	}
+}
+
+// ImportMigrations создает миграции из файлов архива в порядке их версий.
+// Миграции, название которых уже есть в реестре целевой базы данных,
+// не создаются повторно и возвращаются в отчете как дубликаты.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	archive: []byte - Архив zip, tar или tar.gz с файлами миграций.
+//	format: entity.ImportFormat - Раскладка файлов; auto определяет ее автоматически.
+//	userID: int64 - Идентификатор пользователя, импортирующего миграции.
+//
+// Возвращает:
+//
+//	[]entity.ImportItem: Отчет по каждой миграции и пропущенному файлу.
+//	error: Ошибка, если таковая имеется.
+func (i *Importer) ImportMigrations(ctx context.Context, targetID int64, archive []byte, format entity.ImportFormat, userID int64) ([]entity.ImportItem, error) {
+	files, err := readArchive(archive)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidImport, err)
+	}
+
+	migrations, report, err := parse(files, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidImport, err)
+	}
+
+	existing, err := i.migrations.ListMigrations(ctx, targetID, "", nil, userID)
+	if err != nil {
+		return nil, fmt.Errorf("i.migrations.ListMigrations: %w", err)
+	}
+
+	byName := make(map[string]entity.MigrationInfo, len(existing))
+	for _, migration := range existing {
+		byName[migration.Name] = migration
+	}
+
+	for _, migration := range migrations {
+		item := entity.ImportItem{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Source:  migration.Source,
+		}
+
+		if duplicate, ok := byName[migration.Name]; ok {
+			item.Status = entity.ImportStatusDuplicate
+			item.MigrationID = duplicate.ID
+			item.Reason = "migration with this name already exists"
+			if duplicate.Checksum != entity.ScriptChecksum(migration.Script, migration.RollbackScript) {
+				item.Reason = "migration with this name already exists with different scripts"
+			}
+			report = append(report, item)
+			continue
+		}
+
+		description := migration.Description
+		if description == "" {
+			description = fmt.Sprintf("imported from %s", migration.Source)
+		}
+
+		migrationID, findings, err := i.migrations.CreateMigration(
+			ctx,
+			targetID,
+			migration.Name,
+			description,
+			migration.Script,
+			migration.RollbackScript,
+			migration.ExecutionMode,
+			migration.Kind,
+			migration.Labels,
+			migration.TenantScoped,
+			migration.Templated,
+			migration.Backfill,
+			userID,
+		)
+		if err != nil {
+			return report, fmt.Errorf("create migration %s: %w", migration.Name, err)
+		}
+
+		item.Status = entity.ImportStatusCreated
+		item.MigrationID = migrationID
+		item.Reason = lintSummary(findings)
+		report = append(report, item)
+	}
+
+	return report, nil
+}
+
+// lintSummary перечисляет замечания линтера к созданной миграции.
+func lintSummary(findings []entity.LintFinding) string {
+	if len(findings) == 0 {
+		return ""
+	}
+	rules := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		rules = append(rules, fmt.Sprintf("%s (%s)", finding.Rule, finding.Severity))
+	}
+	return "lint: " + strings.Join(rules, ", ")
+}
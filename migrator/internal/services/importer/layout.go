@@ -0,0 +1,420 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"migrator/internal/entity"
+)
+
+// File - файл из импортируемого каталога.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// parsedMigration - миграция, разобранная из файлов.
+type parsedMigration struct {
+	Version        string
+	Name           string
+	Description    string
+	Source         string
+	Script         string
+	RollbackScript string
+	ExecutionMode  entity.ExecutionMode
+	Kind           entity.MigrationKind
+	Backfill       *entity.BackfillSpec
+	Labels         map[string]string
+	TenantScoped   bool
+	Templated      bool
+}
+
+var (
+	golangMigrateFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+	gooseFile         = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+	flywayFile        = regexp.MustCompile(`^([VU])(\d+(?:[._]\d+)*)__(.+)\.sql$`)
+	flywayRepeatable  = regexp.MustCompile(`^R__(.+)\.sql$`)
+)
+
+// detectFormat определяет раскладку по именам и содержимому файлов.
+func detectFormat(files []File) (entity.ImportFormat, error) {
+	if _, ok := exportManifest(files); ok {
+		return entity.ImportFormatExport, nil
+	}
+
+	for _, file := range files {
+		name := path.Base(file.Path)
+		switch {
+		case golangMigrateFile.MatchString(name):
+			return entity.ImportFormatGolangMigrate, nil
+		case flywayFile.MatchString(name), flywayRepeatable.MatchString(name):
+			return entity.ImportFormatFlyway, nil
+		case gooseFile.MatchString(name) && strings.Contains(string(file.Content), "+goose Up"):
+			return entity.ImportFormatGoose, nil
+		}
+	}
+	return "", fmt.Errorf("cannot detect migration file layout")
+}
+
+// parse разбирает файлы в миграции, упорядоченные по версии; повторяемые
+// миграции следуют за версионными в порядке названий.
+// Файлы, которые не удалось сопоставить с раскладкой, возвращаются в отчете как пропущенные.
+func parse(files []File, format entity.ImportFormat) ([]parsedMigration, []entity.ImportItem, error) {
+	if format == "" || format == entity.ImportFormatAuto {
+		var err error
+		format, err = detectFormat(files)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if format == entity.ImportFormatExport {
+		migrations, err := parseExport(files)
+		return migrations, nil, err
+	}
+
+	files = migrationFiles(files)
+
+	var (
+		migrations []parsedMigration
+		skipped    []entity.ImportItem
+		err        error
+	)
+	switch format {
+	case entity.ImportFormatGolangMigrate:
+		migrations, skipped, err = parseGolangMigrate(files)
+	case entity.ImportFormatGoose:
+		migrations, skipped, err = parseGoose(files)
+	case entity.ImportFormatFlyway:
+		migrations, skipped, err = parseFlyway(files)
+	default:
+		return nil, nil, fmt.Errorf("unknown import format %q", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.SliceStable(migrations, func(i, j int) bool {
+		iRepeatable := migrations[i].Kind == entity.MigrationKindRepeatable
+		jRepeatable := migrations[j].Kind == entity.MigrationKindRepeatable
+		switch {
+		case iRepeatable != jRepeatable:
+			return jRepeatable
+		case iRepeatable:
+			return migrations[i].Name < migrations[j].Name
+		}
+		return compareVersions(migrations[i].Version, migrations[j].Version) < 0
+	})
+
+	return migrations, skipped, nil
+}
+
+// migrationFiles отбрасывает файлы, не относящиеся к миграциям (README, конфигурация и т.п.).
+func migrationFiles(files []File) []File {
+	var result []File
+	for _, file := range files {
+		if ext := path.Ext(file.Path); ext == ".sql" || ext == ".go" {
+			result = append(result, file)
+		}
+	}
+	return result
+}
+
+func parseGolangMigrate(files []File) ([]parsedMigration, []entity.ImportItem, error) {
+	byVersion := make(map[string]*parsedMigration)
+	var skipped []entity.ImportItem
+
+	for _, file := range files {
+		name := path.Base(file.Path)
+		match := golangMigrateFile.FindStringSubmatch(name)
+		if match == nil {
+			skipped = append(skipped, skip(file, "file name does not match <version>_<name>.(up|down).sql"))
+			continue
+		}
+		version, title, direction := match[1], match[2], match[3]
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &parsedMigration{
+				Version:       version,
+				Name:          version + "_" + title,
+				ExecutionMode: entity.ExecutionModeTransactional,
+			}
+			byVersion[version] = migration
+		}
+		if migration.Name != version+"_"+title {
+			return nil, nil, fmt.Errorf("version %s is used by %s and %s", version, migration.Name, version+"_"+title)
+		}
+
+		switch direction {
+		case "up":
+			if migration.Source != "" {
+				return nil, nil, fmt.Errorf("duplicate up file for version %s: %s", version, file.Path)
+			}
+			migration.Source = file.Path
+			migration.Script = string(file.Content)
+		case "down":
+			migration.RollbackScript = string(file.Content)
+		}
+	}
+
+	var migrations []parsedMigration
+	for _, migration := range byVersion {
+		if migration.Source == "" {
+			return nil, nil, fmt.Errorf("version %s has a down file but no up file", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	return migrations, skipped, nil
+}
+
+func parseGoose(files []File) ([]parsedMigration, []entity.ImportItem, error) {
+	var (
+		migrations []parsedMigration
+		skipped    []entity.ImportItem
+	)
+	versions := make(map[string]string)
+
+	for _, file := range files {
+		name := path.Base(file.Path)
+		if strings.HasSuffix(name, ".go") {
+			skipped = append(skipped, skip(file, "Go migrations are not supported"))
+			continue
+		}
+		match := gooseFile.FindStringSubmatch(name)
+		if match == nil {
+			skipped = append(skipped, skip(file, "file name does not match <version>_<name>.sql"))
+			continue
+		}
+		version := strings.TrimLeft(match[1], "0")
+		if other, ok := versions[version]; ok {
+			return nil, nil, fmt.Errorf("version %s is used by %s and %s", match[1], other, file.Path)
+		}
+		versions[version] = file.Path
+
+		up, down, noTransaction, err := splitGoose(string(file.Content))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", file.Path, err)
+		}
+
+		mode := entity.ExecutionModeTransactional
+		if noTransaction {
+			mode = entity.ExecutionModeNoTransaction
+		}
+
+		migrations = append(migrations, parsedMigration{
+			Version:        match[1],
+			Name:           match[1] + "_" + match[2],
+			Source:         file.Path,
+			Script:         up,
+			RollbackScript: down,
+			ExecutionMode:  mode,
+		})
+	}
+
+	return migrations, skipped, nil
+}
+
+// splitGoose делит файл goose на секции Up и Down.
+// Аннотации goose удаляются, остальные строки сохраняются как есть.
+func splitGoose(content string) (string, string, bool, error) {
+	var (
+		up, down      strings.Builder
+		current       *strings.Builder
+		noTransaction bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		annotation, ok := strings.CutPrefix(strings.TrimSpace(line), "-- +goose ")
+		if ok {
+			switch strings.ToUpper(strings.TrimSpace(annotation)) {
+			case "UP":
+				current = &up
+			case "DOWN":
+				current = &down
+			case "NO TRANSACTION":
+				noTransaction = true
+			}
+			continue
+		}
+		if current != nil {
+			current.WriteString(line)
+			current.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", false, err
+	}
+
+	if strings.TrimSpace(up.String()) == "" {
+		return "", "", false, fmt.Errorf("no -- +goose Up section")
+	}
+
+	return strings.TrimSpace(up.String()), strings.TrimSpace(down.String()), noTransaction, nil
+}
+
+func parseFlyway(files []File) ([]parsedMigration, []entity.ImportItem, error) {
+	byVersion := make(map[string]*parsedMigration)
+	undo := make(map[string]string)
+	var (
+		repeatable []parsedMigration
+		skipped    []entity.ImportItem
+	)
+
+	for _, file := range files {
+		name := path.Base(file.Path)
+		if match := flywayRepeatable.FindStringSubmatch(name); match != nil {
+			repeatable = append(repeatable, parsedMigration{
+				Name:          "R__" + match[1],
+				Source:        file.Path,
+				Script:        string(file.Content),
+				ExecutionMode: entity.ExecutionModeTransactional,
+				Kind:          entity.MigrationKindRepeatable,
+			})
+			continue
+		}
+		match := flywayFile.FindStringSubmatch(name)
+		if match == nil {
+			skipped = append(skipped, skip(file, "file name does not match V<version>__<name>.sql or U<version>__<name>.sql"))
+			continue
+		}
+		prefix, version, title := match[1], normalizeFlywayVersion(match[2]), match[3]
+
+		if prefix == "U" {
+			undo[version] = string(file.Content)
+			continue
+		}
+
+		if other, ok := byVersion[version]; ok {
+			return nil, nil, fmt.Errorf("version %s is used by %s and %s", version, other.Source, file.Path)
+		}
+		byVersion[version] = &parsedMigration{
+			Version:       version,
+			Name:          "V" + version + "__" + title,
+			Source:        file.Path,
+			Script:        string(file.Content),
+			ExecutionMode: entity.ExecutionModeTransactional,
+		}
+	}
+
+	var migrations []parsedMigration
+	for version, migration := range byVersion {
+		migration.RollbackScript = undo[version]
+		delete(undo, version)
+		migrations = append(migrations, *migration)
+	}
+	for version := range undo {
+		return nil, nil, fmt.Errorf("undo migration U%s has no versioned migration", version)
+	}
+
+	return append(migrations, repeatable...), skipped, nil
+}
+
+// exportManifest находит манифест выгрузки реестра миграций.
+func exportManifest(files []File) (File, bool) {
+	for _, file := range files {
+		if path.Clean(file.Path) == entity.ExportManifestFile &&
+			strings.Contains(string(file.Content), entity.ExportManifestFormat) {
+			return file, true
+		}
+	}
+	return File{}, false
+}
+
+// parseExport разбирает выгрузку реестра миграций одной целевой базы данных,
+// сохраняя названия, описания, метки и режимы выполнения миграций.
+func parseExport(files []File) ([]parsedMigration, error) {
+	manifestFile, ok := exportManifest(files)
+	if !ok {
+		return nil, fmt.Errorf("%s not found", entity.ExportManifestFile)
+	}
+
+	var manifest entity.ExportManifest
+	if err := json.Unmarshal(manifestFile.Content, &manifest); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", entity.ExportManifestFile, err)
+	}
+	if manifest.Format != entity.ExportManifestFormat {
+		return nil, fmt.Errorf("unexpected manifest format %q", manifest.Format)
+	}
+	if len(manifest.Targets) != 1 {
+		return nil, fmt.Errorf("export contains %d targets, import requires an export of a single target", len(manifest.Targets))
+	}
+
+	contents := make(map[string]string, len(files))
+	for _, file := range files {
+		contents[path.Clean(file.Path)] = string(file.Content)
+	}
+
+	var migrations []parsedMigration
+	for _, migration := range manifest.Targets[0].Migrations {
+		script, ok := contents[path.Clean(migration.UpFile)]
+		if !ok {
+			return nil, fmt.Errorf("file %s of migration %s not found", migration.UpFile, migration.Name)
+		}
+
+		mode := migration.ExecutionMode
+		if mode == "" {
+			mode = entity.ExecutionModeTransactional
+		}
+
+		migrations = append(migrations, parsedMigration{
+			Version:        strconv.FormatInt(migration.ID, 10),
+			Name:           migration.Name,
+			Description:    migration.Description,
+			Source:         migration.UpFile,
+			Script:         script,
+			RollbackScript: contents[path.Clean(migration.DownFile)],
+			ExecutionMode:  mode,
+			Kind:           migration.Kind,
+			Backfill:       migration.Backfill,
+			Labels:         migration.Labels,
+			TenantScoped:   migration.TenantScoped,
+			Templated:      migration.Templated,
+		})
+	}
+
+	return migrations, nil
+}
+
+func normalizeFlywayVersion(version string) string {
+	return strings.ReplaceAll(version, "_", ".")
+}
+
+// compareVersions сравнивает версии, состоящие из чисел, разделенных точками.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y uint64
+		if i < len(as) {
+			x, _ = strconv.ParseUint(as[i], 10, 64)
+		}
+		if i < len(bs) {
+			y, _ = strconv.ParseUint(bs[i], 10, 64)
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func skip(file File, reason string) entity.ImportItem {
+	return entity.ImportItem{
+		Source: file.Path,
+		Status: entity.ImportStatusSkipped,
+		Reason: reason,
+	}
+}
@@ -0,0 +1,257 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+
+	"migrator/internal/entity"
+)
+
+func files(pairs ...string) []File {
+	result := make([]File, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		result = append(result, File{Path: pairs[i], Content: []byte(pairs[i+1])})
+	}
+	return result
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []File
+		want    entity.ImportFormat
+		wantErr bool
+	}{
+		{
+			name: "golang-migrate",
+			files: files(
+				"migrations/README.md", "docs",
+				"migrations/0001_init.up.sql", "CREATE TABLE a (id INT);",
+				"migrations/0001_init.down.sql", "DROP TABLE a;",
+			),
+			want: entity.ImportFormatGolangMigrate,
+		},
+		{
+			name: "goose",
+			files: files(
+				"20260101120000_init.sql", "-- +goose Up\nCREATE TABLE a (id INT);\n-- +goose Down\nDROP TABLE a;",
+			),
+			want: entity.ImportFormatGoose,
+		},
+		{
+			name:  "flyway versioned",
+			files: files("sql/V1_1__init.sql", "CREATE TABLE a (id INT);"),
+			want:  entity.ImportFormatFlyway,
+		},
+		{
+			name:  "flyway repeatable",
+			files: files("R__views.sql", "CREATE OR REPLACE VIEW v AS SELECT 1;"),
+			want:  entity.ImportFormatFlyway,
+		},
+		{
+			name: "export manifest",
+			files: files(
+				entity.ExportManifestFile, `{"format": "`+entity.ExportManifestFormat+`"}`,
+				"1_init.up.sql", "CREATE TABLE a (id INT);",
+			),
+			want: entity.ImportFormatExport,
+		},
+		{
+			name:    "numbered file without goose annotations",
+			files:   files("0001_init.sql", "CREATE TABLE a (id INT);"),
+			wantErr: true,
+		},
+		{
+			name:    "no migration files",
+			files:   files("README.md", "docs"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := detectFormat(tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("detectFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("detectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// parsedScript - поля разобранной миграции, которые проверяют тесты раскладок.
+type parsedScript struct {
+	Name           string
+	Script         string
+	RollbackScript string
+	ExecutionMode  entity.ExecutionMode
+	Kind           entity.MigrationKind
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       []File
+		format      entity.ImportFormat
+		want        []parsedScript
+		wantSkipped []string
+		wantErr     bool
+	}{
+		{
+			name:   "golang-migrate ordered by version",
+			format: entity.ImportFormatAuto,
+			files: files(
+				"10_add_email.up.sql", "ALTER TABLE a ADD email TEXT;",
+				"2_init.up.sql", "CREATE TABLE a (id INT);",
+				"2_init.down.sql", "DROP TABLE a;",
+				"README.md", "docs",
+				"notes.sql", "-- scratch",
+			),
+			want: []parsedScript{
+				{Name: "2_init", Script: "CREATE TABLE a (id INT);", RollbackScript: "DROP TABLE a;", ExecutionMode: entity.ExecutionModeTransactional},
+				{Name: "10_add_email", Script: "ALTER TABLE a ADD email TEXT;", ExecutionMode: entity.ExecutionModeTransactional},
+			},
+			wantSkipped: []string{"notes.sql"},
+		},
+		{
+			name:    "golang-migrate down file without up file",
+			format:  entity.ImportFormatGolangMigrate,
+			files:   files("1_init.down.sql", "DROP TABLE a;"),
+			wantErr: true,
+		},
+		{
+			name:   "golang-migrate version used twice",
+			format: entity.ImportFormatGolangMigrate,
+			files: files(
+				"1_init.up.sql", "CREATE TABLE a (id INT);",
+				"1_other.up.sql", "CREATE TABLE b (id INT);",
+			),
+			wantErr: true,
+		},
+		{
+			name:   "goose sections and no transaction",
+			format: entity.ImportFormatAuto,
+			files: files(
+				"00002_index.sql", "-- +goose NO TRANSACTION\n-- +goose Up\nCREATE INDEX CONCURRENTLY i ON a (id);\n-- +goose Down\nDROP INDEX CONCURRENTLY i;\n",
+				"00001_init.sql", "-- comment before sections\n-- +goose Up\n-- +goose StatementBegin\nCREATE TABLE a (id INT);\n-- +goose StatementEnd\n\n-- +goose Down\nDROP TABLE a;\n",
+				"00003_seed.go", "package migrations",
+			),
+			want: []parsedScript{
+				{Name: "00001_init", Script: "CREATE TABLE a (id INT);", RollbackScript: "DROP TABLE a;", ExecutionMode: entity.ExecutionModeTransactional},
+				{Name: "00002_index", Script: "CREATE INDEX CONCURRENTLY i ON a (id);", RollbackScript: "DROP INDEX CONCURRENTLY i;", ExecutionMode: entity.ExecutionModeNoTransaction},
+			},
+			wantSkipped: []string{"00003_seed.go"},
+		},
+		{
+			name:    "goose file without up section",
+			format:  entity.ImportFormatGoose,
+			files:   files("00001_init.sql", "-- +goose Down\nDROP TABLE a;"),
+			wantErr: true,
+		},
+		{
+			name:   "goose version used twice",
+			format: entity.ImportFormatGoose,
+			files: files(
+				"001_init.sql", "-- +goose Up\nSELECT 1;",
+				"1_other.sql", "-- +goose Up\nSELECT 2;",
+			),
+			wantErr: true,
+		},
+		{
+			name:   "flyway versions, undo and repeatables",
+			format: entity.ImportFormatAuto,
+			files: files(
+				"R__b_view.sql", "CREATE OR REPLACE VIEW b AS SELECT 1;",
+				"V1_10__later.sql", "ALTER TABLE a ADD c INT;",
+				"V1.2__init.sql", "CREATE TABLE a (id INT);",
+				"U1.2__init.sql", "DROP TABLE a;",
+				"R__a_view.sql", "CREATE OR REPLACE VIEW a AS SELECT 1;",
+				"B1__baseline.sql", "SELECT 1;",
+			),
+			want: []parsedScript{
+				{Name: "V1.2__init", Script: "CREATE TABLE a (id INT);", RollbackScript: "DROP TABLE a;", ExecutionMode: entity.ExecutionModeTransactional},
+				{Name: "V1.10__later", Script: "ALTER TABLE a ADD c INT;", ExecutionMode: entity.ExecutionModeTransactional},
+				{Name: "R__a_view", Script: "CREATE OR REPLACE VIEW a AS SELECT 1;", ExecutionMode: entity.ExecutionModeTransactional, Kind: entity.MigrationKindRepeatable},
+				{Name: "R__b_view", Script: "CREATE OR REPLACE VIEW b AS SELECT 1;", ExecutionMode: entity.ExecutionModeTransactional, Kind: entity.MigrationKindRepeatable},
+			},
+			wantSkipped: []string{"B1__baseline.sql"},
+		},
+		{
+			name:    "flyway undo without versioned migration",
+			format:  entity.ImportFormatFlyway,
+			files:   files("U3__drop.sql", "CREATE TABLE a (id INT);"),
+			wantErr: true,
+		},
+		{
+			name:   "flyway version used twice",
+			format: entity.ImportFormatFlyway,
+			files: files(
+				"V1_1__init.sql", "SELECT 1;",
+				"V1.1__other.sql", "SELECT 2;",
+			),
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			format:  "liquibase",
+			files:   files("1_init.up.sql", "SELECT 1;"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, skipped, err := parse(tt.files, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			var got []parsedScript
+			for _, migration := range migrations {
+				got = append(got, parsedScript{
+					Name:           migration.Name,
+					Script:         migration.Script,
+					RollbackScript: migration.RollbackScript,
+					ExecutionMode:  migration.ExecutionMode,
+					Kind:           migration.Kind,
+				})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse() migrations = %+v, want %+v", got, tt.want)
+			}
+
+			var gotSkipped []string
+			for _, item := range skipped {
+				gotSkipped = append(gotSkipped, item.Source)
+			}
+			if !reflect.DeepEqual(gotSkipped, tt.wantSkipped) {
+				t.Errorf("parse() skipped = %v, want %v", gotSkipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1", b: "1", want: 0},
+		{a: "2", b: "10", want: -1},
+		{a: "1.10", b: "1.2", want: 1},
+		{a: "1.2", b: "1.2.1", want: -1},
+		{a: "001", b: "1", want: 0},
+		{a: "20260101120000", b: "20251231235959", want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
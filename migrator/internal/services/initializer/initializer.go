@@ -7,22 +7,82 @@ import (
 )
 
 type initerRepository interface {
+	CreateIfNeededEnvironmentsTable(ctx context.Context) error
+	CreateIfNeededTargetsTable(ctx context.Context) error
 	CreateIfNeededMigrationsTable(ctx context.Context) error
+	AssignDefaultTarget(ctx context.Context, defaultTargetURL string) error
+	CreateIfNeededLocksTable(ctx context.Context) error
+	CreateIfNeededHistoryTable(ctx context.Context) error
+	CreateIfNeededTenantsTable(ctx context.Context) error
+	CreateIfNeededRevisionsTable(ctx context.Context) error
+	CreateIfNeededBackfillsTable(ctx context.Context) error
+	CreateIfNeededSchemaSnapshotsTable(ctx context.Context) error
+	CreateIfNeededAuditLogTable(ctx context.Context) error
+	CreateIfNeededIdempotencyKeysTable(ctx context.Context) error
 }
 
 type DbInitializerService struct {
-	repo initerRepository
+	repo             initerRepository
+	defaultTargetURL string
 }
 
-func New(repo initerRepository) *DbInitializerService {
+// New - конструктор сервиса инициализации базы данных. defaultTargetURL -
+// адрес базы данных, к которой применялись миграции до появления реестра
+// целевых баз данных; такие миграции привязываются к ней.
+func New(repo initerRepository, defaultTargetURL string) *DbInitializerService {
 	return &DbInitializerService{
-		repo: repo,
+		repo:             repo,
+		defaultTargetURL: defaultTargetURL,
 	}
 }
 
 // InitDB инициализирует базу данных.
 func (s *DbInitializerService) InitDB(ctx context.Context) error {
-	err := s.repo.CreateIfNeededMigrationsTable(ctx)
+	err := s.repo.CreateIfNeededEnvironmentsTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededTargetsTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededMigrationsTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.AssignDefaultTarget(ctx, s.defaultTargetURL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededLocksTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededHistoryTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededTenantsTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededRevisionsTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededBackfillsTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededSchemaSnapshotsTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededAuditLogTable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database tables: %w", err)
+	}
+	err = s.repo.CreateIfNeededIdempotencyKeysTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to initialize database tables: %w", err)
 	}
@@ -0,0 +1,123 @@
+// Package linter содержит бизнес-логику проверки скриптов миграций на опасные операции.
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"migrator/internal/entity"
+)
+
+// Названия правил линтера.
+const (
+	RuleDropTable             = "drop_table"
+	RuleDropColumn            = "drop_column"
+	RuleAlterColumnType       = "alter_column_type"
+	RuleNotNullWithoutDefault = "not_null_without_default"
+	RuleNonConcurrentIndex    = "non_concurrent_index"
+	RuleMissingWhere          = "missing_where"
+	RuleEmptyRollback         = "empty_rollback"
+	RuleRollbackMismatch      = "rollback_mismatch"
+)
+
+// defaultSeverities - важность правил по умолчанию. Ошибками по умолчанию
+// считаются только операции, которые почти всегда означают ошибку в скрипте.
+var defaultSeverities = map[string]entity.LintSeverity{
+	RuleDropTable:             entity.LintSeverityWarning,
+	RuleDropColumn:            entity.LintSeverityWarning,
+	RuleAlterColumnType:       entity.LintSeverityWarning,
+	RuleNotNullWithoutDefault: entity.LintSeverityError,
+	RuleNonConcurrentIndex:    entity.LintSeverityWarning,
+	RuleMissingWhere:          entity.LintSeverityError,
+	RuleEmptyRollback:         entity.LintSeverityWarning,
+	RuleRollbackMismatch:      entity.LintSeverityWarning,
+}
+
+// Linter - линтер скриптов миграций.
+type Linter struct {
+	severities    map[string]entity.LintSeverity
+	blockSeverity entity.LintSeverity
+}
+
+// New - конструктор линтера.
+// Аргументы:
+//
+//	blockSeverity: entity.LintSeverity - Наименьшая важность замечания, запрещающего создание миграции; off не запрещает никогда.
+//	overrides: map[string]entity.LintSeverity - Важность правил, заменяющая значения по умолчанию.
+//
+// Возвращает:
+//
+//	*Linter: Линтер.
+//	error: Ошибка, если правило или важность неизвестны.
+func New(blockSeverity entity.LintSeverity, overrides map[string]entity.LintSeverity) (*Linter, error) {
+	if !blockSeverity.Valid() {
+		return nil, fmt.Errorf("unknown block severity %q", blockSeverity)
+	}
+
+	severities := make(map[string]entity.LintSeverity, len(defaultSeverities))
+	for rule, severity := range defaultSeverities {
+		severities[rule] = severity
+	}
+
+	for rule, severity := range overrides {
+		if _, ok := defaultSeverities[rule]; !ok {
+			return nil, fmt.Errorf("unknown lint rule %q, known rules: %s", rule, strings.Join(Rules(), ", "))
+		}
+		if !severity.Valid() {
+			return nil, fmt.Errorf("unknown severity %q for lint rule %q", severity, rule)
+		}
+		severities[rule] = severity
+	}
+
+	return &Linter{
+		severities:    severities,
+		blockSeverity: blockSeverity,
+	}, nil
+}
+
+// Rules возвращает названия всех правил линтера.
+func Rules() []string {
+	rules := make([]string, 0, len(defaultSeverities))
+	for rule := range defaultSeverities {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// Lint проверяет скрипты миграции.
+// Аргументы:
+//
+//	script: string - Текст скрипта миграции.
+//	rollbackScript: string - Текст скрипта отката миграции.
+//
+// Возвращает:
+//
+//	entity.LintReport: Замечания линтера; Blocked, если среди них есть запрещающие создание миграции.
+func (l *Linter) Lint(script, rollbackScript string) entity.LintReport {
+	var report entity.LintReport
+
+	for _, f := range lint(script, rollbackScript) {
+		severity := l.severities[f.rule]
+		if severity == entity.LintSeverityOff {
+			continue
+		}
+
+		finding := entity.LintFinding{
+			Rule:      f.rule,
+			Severity:  severity,
+			Message:   f.message,
+			Rollback:  f.rollback,
+			Line:      f.line,
+			Statement: f.statement,
+			Blocking:  severity.AtLeast(l.blockSeverity),
+		}
+		if finding.Blocking {
+			report.Blocked = true
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+
+	return report
+}
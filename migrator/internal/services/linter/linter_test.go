@@ -0,0 +1,272 @@
+package linter
+
+import (
+	"reflect"
+	"testing"
+
+	"migrator/internal/entity"
+)
+
+func rulesOf(report entity.LintReport) []string {
+	var rules []string
+	for _, finding := range report.Findings {
+		rules = append(rules, finding.Rule)
+	}
+	return rules
+}
+
+func TestLintRules(t *testing.T) {
+	l, err := New(entity.LintSeverityError, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		script         string
+		rollbackScript string
+		want           []string
+	}{
+		{
+			name:           "create table with rollback",
+			script:         "CREATE TABLE users (id BIGINT PRIMARY KEY, name TEXT NOT NULL);",
+			rollbackScript: "DROP TABLE users;",
+		},
+		{
+			name:           "drop table",
+			script:         "DROP TABLE users;",
+			rollbackScript: "CREATE TABLE users (id BIGINT);",
+			want:           []string{RuleDropTable},
+		},
+		{
+			name:           "drop column",
+			script:         "ALTER TABLE users DROP COLUMN name;",
+			rollbackScript: "ALTER TABLE users ADD COLUMN name TEXT;",
+			want:           []string{RuleDropColumn},
+		},
+		{
+			name:           "drop constraint is not a column",
+			script:         "ALTER TABLE users DROP CONSTRAINT users_name_key;",
+			rollbackScript: "ALTER TABLE users ADD CONSTRAINT users_name_key UNIQUE (name);",
+		},
+		{
+			name:           "alter column type",
+			script:         "ALTER TABLE users ALTER COLUMN age TYPE BIGINT;",
+			rollbackScript: "ALTER TABLE users ALTER COLUMN age TYPE INT;",
+			want:           []string{RuleAlterColumnType},
+		},
+		{
+			name:           "widen to text",
+			script:         "ALTER TABLE users ALTER COLUMN name TYPE TEXT;",
+			rollbackScript: "ALTER TABLE users ALTER COLUMN name TYPE VARCHAR(100);",
+		},
+		{
+			name:           "not null column without default",
+			script:         "ALTER TABLE users ADD COLUMN email TEXT NOT NULL;",
+			rollbackScript: "ALTER TABLE users DROP COLUMN email;",
+			want:           []string{RuleNotNullWithoutDefault},
+		},
+		{
+			name:           "not null column with default",
+			script:         "ALTER TABLE users ADD COLUMN email TEXT NOT NULL DEFAULT '';",
+			rollbackScript: "ALTER TABLE users DROP COLUMN email;",
+		},
+		{
+			name:           "set not null",
+			script:         "ALTER TABLE users ALTER COLUMN email SET NOT NULL;",
+			rollbackScript: "ALTER TABLE users ALTER COLUMN email DROP NOT NULL;",
+			want:           []string{RuleNotNullWithoutDefault},
+		},
+		{
+			name:           "index on existing table",
+			script:         "CREATE INDEX users_email_idx ON users (email);",
+			rollbackScript: "DROP INDEX users_email_idx;",
+			want:           []string{RuleNonConcurrentIndex},
+		},
+		{
+			name:           "concurrent index",
+			script:         "CREATE INDEX CONCURRENTLY users_email_idx ON users (email);",
+			rollbackScript: "DROP INDEX CONCURRENTLY users_email_idx;",
+		},
+		{
+			name: "index and not null column on new table",
+			script: `CREATE TABLE orders (id BIGINT);
+ALTER TABLE orders ADD COLUMN total NUMERIC NOT NULL;
+CREATE INDEX orders_total_idx ON orders (total);`,
+			rollbackScript: "DROP TABLE orders;",
+		},
+		{
+			name:           "update without where",
+			script:         "UPDATE users SET active = true;",
+			rollbackScript: "UPDATE users SET active = false WHERE id > 0;",
+			want:           []string{RuleMissingWhere},
+		},
+		{
+			name:           "where only in subquery",
+			script:         "DELETE FROM users USING (SELECT id FROM banned WHERE id > 0) b;",
+			rollbackScript: "SELECT 1;",
+			want:           []string{RuleMissingWhere},
+		},
+		{
+			name:           "delete with where",
+			script:         "DELETE FROM users WHERE active = false;",
+			rollbackScript: "SELECT 1;",
+		},
+		{
+			name:           "where in comment or string is ignored",
+			script:         "UPDATE users SET note = 'WHERE'; -- WHERE id = 1",
+			rollbackScript: "SELECT 1;",
+			want:           []string{RuleMissingWhere},
+		},
+		{
+			name:   "empty rollback",
+			script: "CREATE TABLE users (id BIGINT);",
+			want:   []string{RuleEmptyRollback},
+		},
+		{
+			name:           "rollback repeats script",
+			script:         "CREATE TABLE users (id BIGINT);",
+			rollbackScript: "create table users (id bigint);",
+			want:           []string{RuleRollbackMismatch},
+		},
+		{
+			name:           "rollback misses created objects",
+			script:         "CREATE TABLE users (id BIGINT); CREATE VIEW active_users AS SELECT * FROM users;",
+			rollbackScript: "DROP TABLE users_archive;",
+			want:           []string{RuleRollbackMismatch, RuleRollbackMismatch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rulesOf(l.Lint(tt.script, tt.rollbackScript))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintSeverityThreshold(t *testing.T) {
+	const (
+		script         = "DROP TABLE users; UPDATE accounts SET active = true;"
+		rollbackScript = "CREATE TABLE users (id BIGINT);"
+	)
+
+	tests := []struct {
+		name          string
+		blockSeverity entity.LintSeverity
+		overrides     map[string]entity.LintSeverity
+		wantBlocked   bool
+		wantBlocking  map[string]bool
+	}{
+		{
+			name:          "defaults block errors only",
+			blockSeverity: entity.LintSeverityError,
+			wantBlocked:   true,
+			wantBlocking:  map[string]bool{RuleDropTable: false, RuleMissingWhere: true},
+		},
+		{
+			name:          "warning threshold",
+			blockSeverity: entity.LintSeverityWarning,
+			wantBlocked:   true,
+			wantBlocking:  map[string]bool{RuleDropTable: true, RuleMissingWhere: true},
+		},
+		{
+			name:          "off threshold never blocks",
+			blockSeverity: entity.LintSeverityOff,
+			wantBlocking:  map[string]bool{RuleDropTable: false, RuleMissingWhere: false},
+		},
+		{
+			name:          "downgraded rule",
+			blockSeverity: entity.LintSeverityError,
+			overrides:     map[string]entity.LintSeverity{RuleMissingWhere: entity.LintSeverityInfo},
+			wantBlocking:  map[string]bool{RuleDropTable: false, RuleMissingWhere: false},
+		},
+		{
+			name:          "disabled rule",
+			blockSeverity: entity.LintSeverityError,
+			overrides:     map[string]entity.LintSeverity{RuleMissingWhere: entity.LintSeverityOff},
+			wantBlocking:  map[string]bool{RuleDropTable: false},
+		},
+		{
+			name:          "upgraded rule",
+			blockSeverity: entity.LintSeverityError,
+			overrides: map[string]entity.LintSeverity{
+				RuleDropTable:    entity.LintSeverityError,
+				RuleMissingWhere: entity.LintSeverityOff,
+			},
+			wantBlocked:  true,
+			wantBlocking: map[string]bool{RuleDropTable: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(tt.blockSeverity, tt.overrides)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			report := l.Lint(script, rollbackScript)
+			if report.Blocked != tt.wantBlocked {
+				t.Errorf("Blocked = %v, want %v", report.Blocked, tt.wantBlocked)
+			}
+
+			blocking := make(map[string]bool)
+			for _, finding := range report.Findings {
+				blocking[finding.Rule] = finding.Blocking
+			}
+			if !reflect.DeepEqual(blocking, tt.wantBlocking) {
+				t.Errorf("blocking findings = %v, want %v", blocking, tt.wantBlocking)
+			}
+		})
+	}
+}
+
+func TestNewRejectsUnknownSettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		blockSeverity entity.LintSeverity
+		overrides     map[string]entity.LintSeverity
+	}{
+		{
+			name:          "unknown block severity",
+			blockSeverity: "fatal",
+		},
+		{
+			name:          "unknown rule",
+			blockSeverity: entity.LintSeverityError,
+			overrides:     map[string]entity.LintSeverity{"drop_database": entity.LintSeverityError},
+		},
+		{
+			name:          "unknown rule severity",
+			blockSeverity: entity.LintSeverityError,
+			overrides:     map[string]entity.LintSeverity{RuleDropTable: "critical"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.blockSeverity, tt.overrides); err == nil {
+				t.Error("New() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLintFindingLine(t *testing.T) {
+	l, err := New(entity.LintSeverityError, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	script := "CREATE TABLE logs (id BIGINT);\n\n-- cleanup\nDROP TABLE users;"
+	report := l.Lint(script, "DROP TABLE logs; CREATE TABLE users (id BIGINT);")
+	if len(report.Findings) != 1 {
+		t.Fatalf("Findings = %+v, want one finding", report.Findings)
+	}
+	if got := report.Findings[0].Line; got != 4 {
+		t.Errorf("Line = %d, want 4", got)
+	}
+}
@@ -0,0 +1,327 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"migrator/pkg/sqlscript"
+)
+
+// maxStatementLength - длина оператора в замечании, после которой он сокращается.
+const maxStatementLength = 200
+
+var (
+	reDropTable   = regexp.MustCompile(`(?i)^DROP\s+TABLE\b`)
+	reAlterTable  = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(\S+)\s+(.+)$`)
+	reCreateTable = regexp.MustCompile(`(?i)^CREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)`)
+	reCreateIndex = regexp.MustCompile(`(?i)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(?:([^\s(]+)\s+)?ON\s+(?:ONLY\s+)?([^\s(]+)`)
+	reCreateOther = regexp.MustCompile(`(?i)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:MATERIALIZED\s+)?(VIEW|SEQUENCE|TYPE)\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)`)
+	reUpdate      = regexp.MustCompile(`(?i)^UPDATE\s`)
+	reDelete      = regexp.MustCompile(`(?i)^DELETE\s+FROM\s`)
+	reWhere       = regexp.MustCompile(`(?i)\bWHERE\b`)
+
+	// Действия ALTER TABLE.
+	reDropAction  = regexp.MustCompile(`(?i)^DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?(\S+)`)
+	reAlterType   = regexp.MustCompile(`(?i)^ALTER\s+(?:COLUMN\s+)?(\S+)\s+(?:SET\s+DATA\s+)?TYPE\s+(.+)$`)
+	reModify      = regexp.MustCompile(`(?i)^(?:MODIFY|CHANGE)\s+(?:COLUMN\s+)?(\S+)`)
+	reSetNotNull  = regexp.MustCompile(`(?i)^ALTER\s+(?:COLUMN\s+)?(\S+)\s+SET\s+NOT\s+NULL\b`)
+	reAddColumn   = regexp.MustCompile(`(?i)^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(\S+)\s+(.*)$`)
+	reNotNull     = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	reHasDefault  = regexp.MustCompile(`(?i)\b(?:DEFAULT|GENERATED)\b`)
+	reCheapRetype = regexp.MustCompile(`(?i)^(?:TEXT|VARCHAR|CHARACTER\s+VARYING)\b`)
+)
+
+// notColumns - слова после DROP и ADD в ALTER TABLE, означающие не столбец.
+var notColumns = map[string]bool{
+	"CONSTRAINT": true,
+	"PRIMARY":    true,
+	"UNIQUE":     true,
+	"FOREIGN":    true,
+	"CHECK":      true,
+	"INDEX":      true,
+	"KEY":        true,
+	"EXCLUDE":    true,
+	"PARTITION":  true,
+	"FULLTEXT":   true,
+	"SPATIAL":    true,
+}
+
+// finding - замечание правила до применения настроенной важности.
+type finding struct {
+	rule      string
+	message   string
+	rollback  bool
+	line      int
+	statement string
+}
+
+// object - объект, созданный скриптом миграции, который должен упоминаться в скрипте отката.
+type object struct {
+	kind string
+	name string
+	// parent - таблица, созданная этим же скриптом, с удалением которой удаляется и объект.
+	parent string
+}
+
+// lint проверяет скрипт миграции по всем правилам, а скрипт отката -
+// на пустоту и соответствие скрипту миграции.
+func lint(script, rollbackScript string) []finding {
+	var (
+		findings []finding
+		created  []object
+		// tables - таблицы, созданные скриптом: они пусты, поэтому операции над ними безопасны.
+		tables = make(map[string]bool)
+	)
+
+	for _, statement := range sqlscript.Parse(script) {
+		text := sqlscript.Normalize(statement.Text)
+		at := func(rule, format string, args ...any) {
+			findings = append(findings, finding{
+				rule:      rule,
+				message:   fmt.Sprintf(format, args...),
+				line:      strings.Count(script[:codeOffset(script, statement.Offset)], "\n") + 1,
+				statement: abbreviate(statement.Text),
+			})
+		}
+
+		switch {
+		case reDropTable.MatchString(text):
+			at(RuleDropTable, "DROP TABLE deletes the table with all its data")
+
+		case reCreateTable.MatchString(text):
+			name := unquote(reCreateTable.FindStringSubmatch(text)[1])
+			tables[name] = true
+			created = append(created, object{kind: "table", name: name})
+
+		case reCreateIndex.MatchString(text):
+			match := reCreateIndex.FindStringSubmatch(text)
+			table := unquote(match[3])
+			if match[2] != "" {
+				index := object{kind: "index", name: unquote(match[2])}
+				if tables[table] {
+					index.parent = table
+				}
+				created = append(created, index)
+			}
+			if match[1] == "" && !tables[table] {
+				at(RuleNonConcurrentIndex, "CREATE INDEX on existing table %s blocks writes while the index is built, "+
+					"use CREATE INDEX CONCURRENTLY in a no_transaction migration", table)
+			}
+
+		case reCreateOther.MatchString(text):
+			match := reCreateOther.FindStringSubmatch(text)
+			created = append(created, object{kind: strings.ToLower(match[1]), name: unquote(match[2])})
+
+		case reAlterTable.MatchString(text):
+			match := reAlterTable.FindStringSubmatch(text)
+			table := unquote(match[1])
+			for _, action := range splitTopLevel(match[2]) {
+				created = append(created, lintAlterAction(table, action, tables[table], at)...)
+			}
+
+		case reUpdate.MatchString(text) || reDelete.MatchString(text):
+			if !reWhere.MatchString(topLevel(text)) {
+				at(RuleMissingWhere, "%s without WHERE changes every row of the table", strings.ToUpper(strings.Fields(text)[0]))
+			}
+		}
+	}
+
+	return append(findings, lintRollback(script, rollbackScript, created)...)
+}
+
+// lintAlterAction проверяет одно действие ALTER TABLE и возвращает добавленные им столбцы.
+// newTable - таблица создана этим же скриптом.
+func lintAlterAction(table, action string, newTable bool, at func(rule, format string, args ...any)) []object {
+	switch {
+	case reDropAction.MatchString(action):
+		column := reDropAction.FindStringSubmatch(action)[1]
+		if !notColumns[strings.ToUpper(column)] {
+			at(RuleDropColumn, "DROP COLUMN %s.%s deletes the column with all its data", table, unquote(column))
+		}
+
+	case reSetNotNull.MatchString(action):
+		column := unquote(reSetNotNull.FindStringSubmatch(action)[1])
+		if !newTable {
+			at(RuleNotNullWithoutDefault, "SET NOT NULL on %s.%s scans the whole table under an exclusive lock "+
+				"and fails if it has NULL values", table, column)
+		}
+
+	case reAlterType.MatchString(action):
+		match := reAlterType.FindStringSubmatch(action)
+		if !newTable && !reCheapRetype.MatchString(match[2]) {
+			at(RuleAlterColumnType, "changing the type of %s.%s rewrites the table under an exclusive lock", table, unquote(match[1]))
+		}
+
+	case reModify.MatchString(action):
+		if !newTable {
+			at(RuleAlterColumnType, "changing the definition of %s.%s may rewrite the table", table, unquote(reModify.FindStringSubmatch(action)[1]))
+		}
+
+	case reAddColumn.MatchString(action):
+		match := reAddColumn.FindStringSubmatch(action)
+		if notColumns[strings.ToUpper(match[1])] {
+			return nil
+		}
+		column := unquote(match[1])
+		if !newTable && reNotNull.MatchString(match[2]) && !reHasDefault.MatchString(match[2]) {
+			at(RuleNotNullWithoutDefault, "adding NOT NULL column %s.%s without DEFAULT fails if the table has rows", table, column)
+		}
+		added := object{kind: "column", name: column}
+		if newTable {
+			added.parent = table
+		}
+		return []object{added}
+	}
+
+	return nil
+}
+
+// lintRollback проверяет, что скрипт отката не пуст, не повторяет скрипт
+// миграции и упоминает созданные ей объекты.
+func lintRollback(script, rollbackScript string, created []object) []finding {
+	if len(sqlscript.Parse(rollbackScript)) == 0 {
+		return []finding{{
+			rule:     RuleEmptyRollback,
+			message:  "rollback script is empty, the migration cannot be rolled back",
+			rollback: true,
+		}}
+	}
+
+	rollback := strings.ToLower(sqlscript.Normalize(rollbackScript))
+	if rollback == strings.ToLower(sqlscript.Normalize(script)) {
+		return []finding{{
+			rule:     RuleRollbackMismatch,
+			message:  "rollback script repeats the migration script",
+			rollback: true,
+		}}
+	}
+
+	var findings []finding
+	for _, obj := range created {
+		if containsWord(rollback, strings.ToLower(obj.name)) ||
+			obj.parent != "" && containsWord(rollback, strings.ToLower(obj.parent)) {
+			continue
+		}
+		findings = append(findings, finding{
+			rule:     RuleRollbackMismatch,
+			message:  fmt.Sprintf("rollback script does not reference %s %s created by the migration", obj.kind, obj.name),
+			rollback: true,
+		})
+	}
+
+	return findings
+}
+
+// codeOffset пропускает пробелы и комментарии перед оператором, начинающимся со смещения offset,
+// чтобы замечание указывало на строку с кодом.
+func codeOffset(script string, offset int) int {
+	for offset < len(script) {
+		rest := script[offset:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r':
+			offset++
+		case strings.HasPrefix(rest, "--"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				return len(script)
+			}
+			offset += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				return len(script)
+			}
+			offset += end + 2
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// splitTopLevel разбивает список действий по запятым вне скобок.
+func splitTopLevel(s string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// topLevel возвращает текст без содержимого скобок, чтобы условия подзапросов
+// не принимались за условие самого оператора.
+func topLevel(s string) string {
+	var (
+		b     strings.Builder
+		depth int
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '(':
+			depth++
+		case s[i] == ')':
+			depth--
+		case depth == 0:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// unquote возвращает имя объекта без схемы и кавычек.
+func unquote(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.Trim(name, "\"`[]")
+}
+
+// containsWord сообщает, встречается ли word в text отдельным словом.
+func containsWord(text, word string) bool {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(word)
+		if (start == 0 || !isWordChar(text[start-1])) && (end == len(text) || !isWordChar(text[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func abbreviate(statement string) string {
+	if len(statement) <= maxStatementLength {
+		return statement
+	}
+	cut := maxStatementLength
+	for cut > 0 && !isRuneStart(statement[cut]) {
+		cut--
+	}
+	return statement[:cut] + "..."
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,288 @@
+// Package locker содержит логику распределенной блокировки целевых баз данных,
+// которая не дает нескольким репликам сервиса одновременно применять миграции.
+package locker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+)
+
+const (
+	_defaultPollInterval   = 500 * time.Millisecond
+	_defaultReleaseTimeout = 5 * time.Second
+)
+
+type lockRepository interface {
+	TryAcquire(ctx context.Context, lock entity.Lock, lease time.Duration) (bool, error)
+	Renew(ctx context.Context, targetID int64, token string, lease time.Duration) (bool, error)
+	Release(ctx context.Context, targetID int64, token string) error
+	ForceRelease(ctx context.Context, targetID int64) error
+	Get(ctx context.Context, targetID int64) (entity.Lock, error)
+	List(ctx context.Context) ([]entity.Lock, error)
+}
+
+// Locker - сервис блокировок целевых баз данных.
+type Locker struct {
+	repo         lockRepository
+	replica      string
+	waitTimeout  time.Duration
+	lease        time.Duration
+	pollInterval time.Duration
+}
+
+// New - конструктор сервиса блокировок.
+//
+// replica - имя реплики, которое видно в ошибках и при просмотре блокировок,
+// waitTimeout - сколько ждать освобождения занятой блокировки,
+// lease - срок блокировки, который продлевается, пока она удерживается;
+// после аварийного завершения реплики ее блокировка освобождается через этот срок.
+func New(repo lockRepository, replica string, waitTimeout, lease time.Duration) *Locker {
+	return &Locker{
+		repo:         repo,
+		replica:      replica,
+		waitTimeout:  waitTimeout,
+		lease:        lease,
+		pollInterval: _defaultPollInterval,
+	}
+}
+
+// Lock захватывает блокировку целевой базы данных, ожидая ее освобождения
+// или истечения ее срока не дольше настроенного времени. Пока блокировка
+// удерживается, ее срок продлевается. Если блокировка потеряна - освобождена
+// принудительно или не продлена вовремя, - возвращенный контекст отменяется,
+// а entity.CheckLock с этим контекстом возвращает ошибку, чтобы изменения
+// не фиксировались без блокировки.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	operation: string - Операция, для которой захватывается блокировка.
+//
+// Возвращает:
+//
+//	context.Context: Контекст, действующий, пока блокировка удерживается; при ошибке - исходный контекст.
+//	func(): Функция освобождения блокировки.
+//	error: entity.ErrLocked, если блокировку не удалось захватить за время ожидания.
+func (l *Locker) Lock(ctx context.Context, targetID int64, operation string) (context.Context, func(), error) {
+	token, err := newToken()
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	if err := l.acquire(ctx, targetID, token, operation); err != nil {
+		return ctx, nil, err
+	}
+
+	leaseCtx, cancel := context.WithCancelCause(ctx)
+	ls := &lease{
+		locker:   l,
+		targetID: targetID,
+		token:    token,
+		cancel:   cancel,
+		renewed:  time.Now(),
+		done:     make(chan struct{}),
+	}
+	go ls.keep(leaseCtx)
+
+	unlock := func() {
+		ls.stop()
+		cancel(nil)
+		l.release(targetID, token)
+	}
+
+	return entity.ContextWithLockCheck(leaseCtx, ls.check), unlock, nil
+}
+
+func (l *Locker) acquire(ctx context.Context, targetID int64, token, operation string) error {
+	ctx, cancel := context.WithTimeout(ctx, l.waitTimeout)
+	defer cancel()
+
+	for {
+		acquired, err := l.repo.TryAcquire(ctx, entity.Lock{
+			TargetID:   targetID,
+			Holder:     l.replica,
+			Token:      token,
+			Operation:  operation,
+			AcquiredAt: time.Now().UTC(),
+		}, l.lease)
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("l.repo.TryAcquire: %w", err)
+		}
+		if acquired {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return l.lockedError(targetID)
+		case <-time.After(l.pollInterval):
+		}
+	}
+}
+
+func (l *Locker) release(targetID int64, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), _defaultReleaseTimeout)
+	defer cancel()
+
+	if err := l.repo.Release(ctx, targetID, token); err != nil {
+		logger.Error(fmt.Errorf("release lock on target %d: %w", targetID, err))
+	}
+}
+
+// lease продлевает удерживаемую блокировку и отменяет контекст ее владельца,
+// когда блокировка потеряна.
+type lease struct {
+	locker   *Locker
+	targetID int64
+	token    string
+	cancel   context.CancelCauseFunc
+
+	mu      sync.Mutex
+	renewed time.Time
+	lost    error
+
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// keep продлевает блокировку каждую треть ее срока, пока она не освобождена.
+// Ошибки продления повторяются, пока срок блокировки не истек.
+func (ls *lease) keep(ctx context.Context) {
+	ticker := time.NewTicker(ls.locker.lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ls.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := ls.renew(ctx, false); err != nil {
+			logger.Error(err)
+			return
+		}
+	}
+}
+
+// renew продлевает блокировку. Возвращает ошибку с entity.ErrLocked, если
+// блокировка потеряна. Временная ошибка базы данных при фоновом продлении
+// считается потерей блокировки, только если срок блокировки уже истек,
+// а перед фиксацией изменений (strict) не позволяет их зафиксировать.
+func (ls *lease) renew(ctx context.Context, strict bool) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.lost != nil {
+		return ls.lost
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _defaultReleaseTimeout)
+	defer cancel()
+
+	held, err := ls.locker.repo.Renew(ctx, ls.targetID, ls.token, ls.locker.lease)
+	switch {
+	case err != nil && strict:
+		return fmt.Errorf("l.repo.Renew: %w", err)
+	case err != nil && time.Since(ls.renewed) < ls.locker.lease:
+		logger.Error(fmt.Errorf("renew lock on target %d: %w", ls.targetID, err))
+		return nil
+	case err != nil:
+		ls.lost = fmt.Errorf("%w: lock on target %d expired, renewal failed: %w", entity.ErrLocked, ls.targetID, err)
+	case !held:
+		ls.lost = fmt.Errorf("%w: lock on target %d was lost: it was released or expired", entity.ErrLocked, ls.targetID)
+	default:
+		ls.renewed = time.Now()
+		return nil
+	}
+
+	ls.cancel(ls.lost)
+	return ls.lost
+}
+
+// check проверяет и продлевает блокировку перед фиксацией изменений.
+func (ls *lease) check(ctx context.Context) error {
+	return ls.renew(ctx, true)
+}
+
+func (ls *lease) stop() {
+	ls.stopOnce.Do(func() { close(ls.done) })
+}
+
+func (l *Locker) lockedError(targetID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _defaultReleaseTimeout)
+	defer cancel()
+
+	lock, err := l.repo.Get(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("%w: target %d, waited %s", entity.ErrLocked, targetID, l.waitTimeout)
+	}
+
+	return fmt.Errorf("%w: target %d locked by replica %s for %s since %s, waited %s",
+		entity.ErrLocked, targetID, lock.Holder, lock.Operation, lock.AcquiredAt.Format(time.DateTime), l.waitTimeout)
+}
+
+// ListLocks возвращает удерживаемые сейчас блокировки.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//
+// Возвращает:
+//
+//	[]entity.Lock: Список блокировок.
+//	error: Ошибка, если таковая имеется.
+func (l *Locker) ListLocks(ctx context.Context) ([]entity.Lock, error) {
+	locks, err := l.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("l.repo.List: %w", err)
+	}
+	return locks, nil
+}
+
+// ReleaseLock принудительно освобождает блокировку целевой базы данных, не
+// дожидаясь истечения ее срока. Реплика, удерживавшая блокировку, узнает об
+// этом при следующем продлении или перед фиксацией изменений и прерывает
+// выполнение, не фиксируя его.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	userID: int64 - Идентификатор пользователя, освобождающего блокировку.
+//
+// Возвращает:
+//
+//	error: Ошибка, если таковая имеется.
+func (l *Locker) ReleaseLock(ctx context.Context, targetID, userID int64) error {
+	lock, err := l.repo.Get(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("l.repo.Get: %w", err)
+	}
+
+	err = l.repo.ForceRelease(ctx, targetID)
+	if err != nil && !errors.Is(err, entity.ErrNotFound) {
+		return fmt.Errorf("l.repo.ForceRelease: %w", err)
+	}
+
+	logger.Warn("lock on target %d held by replica %s since %s was released by user %d",
+		targetID, lock.Holder, lock.AcquiredAt.Format(time.DateTime), userID)
+
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
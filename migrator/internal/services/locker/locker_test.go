@@ -0,0 +1,226 @@
+package locker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+const testTarget = int64(1)
+
+// memoryLocks - таблица блокировок в памяти: блокировку можно захватить,
+// если ее нет или ее срок истек, и продлить, только предъявив ее токен.
+type memoryLocks struct {
+	mu       sync.Mutex
+	locks    map[int64]entity.Lock
+	renewErr error
+}
+
+func newMemoryLocks() *memoryLocks {
+	return &memoryLocks{locks: make(map[int64]entity.Lock)}
+}
+
+func (r *memoryLocks) TryAcquire(_ context.Context, lock entity.Lock, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if held, ok := r.locks[lock.TargetID]; ok && time.Now().Before(held.ExpiresAt) {
+		return false, nil
+	}
+	lock.ExpiresAt = time.Now().Add(lease)
+	r.locks[lock.TargetID] = lock
+	return true, nil
+}
+
+func (r *memoryLocks) Renew(_ context.Context, targetID int64, token string, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.renewErr != nil {
+		return false, r.renewErr
+	}
+	held, ok := r.locks[targetID]
+	if !ok || held.Token != token {
+		return false, nil
+	}
+	held.ExpiresAt = time.Now().Add(lease)
+	r.locks[targetID] = held
+	return true, nil
+}
+
+func (r *memoryLocks) Release(_ context.Context, targetID int64, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if held, ok := r.locks[targetID]; ok && held.Token == token {
+		delete(r.locks, targetID)
+	}
+	return nil
+}
+
+func (r *memoryLocks) ForceRelease(_ context.Context, targetID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.locks[targetID]; !ok {
+		return entity.ErrNotFound
+	}
+	delete(r.locks, targetID)
+	return nil
+}
+
+func (r *memoryLocks) Get(_ context.Context, targetID int64) (entity.Lock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lock, ok := r.locks[targetID]
+	if !ok {
+		return entity.Lock{}, entity.ErrNotFound
+	}
+	return lock, nil
+}
+
+func (r *memoryLocks) List(_ context.Context) ([]entity.Lock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var locks []entity.Lock
+	for _, lock := range r.locks {
+		locks = append(locks, lock)
+	}
+	return locks, nil
+}
+
+func (r *memoryLocks) hold(holder string, expiresIn time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.locks[testTarget] = entity.Lock{
+		TargetID:   testTarget,
+		Holder:     holder,
+		Token:      holder,
+		Operation:  "apply",
+		AcquiredAt: time.Now(),
+		ExpiresAt:  time.Now().Add(expiresIn),
+	}
+}
+
+func newTestLocker(repo lockRepository, waitTimeout, lease time.Duration) *Locker {
+	l := New(repo, "replica-a", waitTimeout, lease)
+	l.pollInterval = 5 * time.Millisecond
+	return l
+}
+
+func TestLockTakesOverExpiredLease(t *testing.T) {
+	repo := newMemoryLocks()
+	repo.hold("replica-b", 30*time.Millisecond)
+	l := newTestLocker(repo, time.Second, time.Minute)
+
+	_, unlock, err := l.Lock(context.Background(), testTarget, "apply")
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	defer unlock()
+
+	lock, err := repo.Get(context.Background(), testTarget)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if lock.Holder != "replica-a" {
+		t.Errorf("lock holder = %s, want replica-a", lock.Holder)
+	}
+}
+
+func TestLockWaitTimeout(t *testing.T) {
+	repo := newMemoryLocks()
+	repo.hold("replica-b", time.Minute)
+	l := newTestLocker(repo, 30*time.Millisecond, time.Minute)
+
+	_, _, err := l.Lock(context.Background(), testTarget, "apply")
+	if !errors.Is(err, entity.ErrLocked) {
+		t.Fatalf("Lock() error = %v, want ErrLocked", err)
+	}
+	if !strings.Contains(err.Error(), "replica-b") {
+		t.Errorf("Lock() error = %q, want the holder replica-b", err)
+	}
+}
+
+func TestLockRenewsLease(t *testing.T) {
+	repo := newMemoryLocks()
+	l := newTestLocker(repo, time.Second, 30*time.Millisecond)
+
+	ctx, unlock, err := l.Lock(context.Background(), testTarget, "apply")
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	// Без продления блокировка истекла бы за это время несколько раз
+	time.Sleep(100 * time.Millisecond)
+
+	other := newTestLocker(repo, 10*time.Millisecond, time.Minute)
+	if _, _, err := other.Lock(context.Background(), testTarget, "apply"); !errors.Is(err, entity.ErrLocked) {
+		t.Fatalf("second Lock() error = %v, want ErrLocked", err)
+	}
+	if err := entity.CheckLock(ctx); err != nil {
+		t.Fatalf("CheckLock() error = %v", err)
+	}
+
+	unlock()
+
+	if _, err := repo.Get(context.Background(), testTarget); !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("lock is not released: Get() error = %v", err)
+	}
+}
+
+func TestLockLostAfterForceRelease(t *testing.T) {
+	repo := newMemoryLocks()
+	l := newTestLocker(repo, time.Second, 30*time.Millisecond)
+
+	ctx, unlock, err := l.Lock(context.Background(), testTarget, "apply")
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	defer unlock()
+
+	if err := l.ReleaseLock(context.Background(), testTarget, 1); err != nil {
+		t.Fatalf("ReleaseLock() error = %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("lock context is not canceled after the lock was released")
+	}
+	if err := entity.CheckLock(ctx); !errors.Is(err, entity.ErrLocked) {
+		t.Errorf("CheckLock() error = %v, want ErrLocked", err)
+	}
+}
+
+func TestCheckLockFailsOnRenewalError(t *testing.T) {
+	repo := newMemoryLocks()
+	l := newTestLocker(repo, time.Second, time.Minute)
+
+	ctx, unlock, err := l.Lock(context.Background(), testTarget, "apply")
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	defer unlock()
+
+	repo.mu.Lock()
+	repo.renewErr = errors.New("connection reset")
+	repo.mu.Unlock()
+
+	// Перед фиксацией изменений ошибка продления не позволяет их зафиксировать,
+	// хотя срок блокировки еще не истек
+	if err := entity.CheckLock(ctx); err == nil {
+		t.Fatal("CheckLock() error = nil, want the renewal error")
+	}
+	if ctx.Err() != nil {
+		t.Errorf("lock context is canceled by a transient renewal error: %v", context.Cause(ctx))
+	}
+}
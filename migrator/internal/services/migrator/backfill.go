@@ -0,0 +1,37 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+
+	"migrator/internal/entity"
+)
+
+// backfillApplyError - миграция данных выполняется пачками через StartBackfill,
+// а не одной транзакцией вместе с другими миграциями.
+func backfillApplyError(migrationID int64) error {
+	return fmt.Errorf("%w: migration %d is a backfill and must be started with StartBackfill", entity.ErrInvalidArgument, migrationID)
+}
+
+// checkBackfills запрещает откат миграций, пока на целевой базе данных выполняется
+// или приостановлена миграция данных: ее пачки выполняются без блокировки
+// целевой базы данных и могут зависеть от откатываемых изменений схемы.
+func (m *Migrator) checkBackfills(ctx context.Context, targetID int64) error {
+	backfills, err := m.backfills.ListActive(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("m.backfills.ListActive: %w", err)
+	}
+
+	if len(backfills) > 0 {
+		return fmt.Errorf("%w: backfill of migration %d is %s on target %d, complete it before rolling back",
+			entity.ErrBackfillActive, backfills[0].MigrationID, backfills[0].State, targetID)
+	}
+
+	return nil
+}
+
+// backfillRollbackError - миграцию данных нельзя откатить: обработанные пачки
+// уже зафиксированы на целевой базе данных.
+func backfillRollbackError(migrationID int64) error {
+	return fmt.Errorf("%w: migration %d is a backfill and cannot be rolled back", entity.ErrInvalidArgument, migrationID)
+}
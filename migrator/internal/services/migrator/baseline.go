@@ -0,0 +1,120 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+// BaselineMigrations отмечает миграции примененными без выполнения их скриптов:
+// так в реестр заносится целевая база данных, схема которой уже создана.
+// Миграции задаются списком migrationIDs или диапазоном идентификаторов
+// [fromID, toID]; из диапазона выбираются миграции целевой базы данных, которые
+// еще можно применить. У каждой миграции записывается отметка baselined,
+// пользователь и контрольная сумма скриптов, а на целевой базе данных -
+// запись в таблице истории. Операция удерживает блокировку целевой базы данных
+// и записывается в журнал выполнения действием baseline.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	migrationIDs: []int64 - Уникальные идентификаторы миграций; пустой список означает диапазон.
+//	fromID: int64 - Первый идентификатор диапазона.
+//	toID: int64 - Последний идентификатор диапазона.
+//	userID: int64 - Идентификатор пользователя, отмечающего миграции.
+//
+// Возвращает:
+//
+//	[]int64: Идентификаторы отмеченных миграций.
+//	time.Time: Дата и время отметки.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) BaselineMigrations(ctx context.Context, targetID int64, migrationIDs []int64, fromID, toID, userID int64) (baselined []int64, baselinedAt time.Time, err error) {
+	history := newExecLog(entity.HistoryActionBaseline, targetID, userID, migrationIDs...)
+	defer func() { m.writeHistory(ctx, history, err) }()
+
+	ctx, unlock, err := m.locker.Lock(ctx, targetID, "baseline")
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("m.locker.Lock: %w", err)
+	}
+	defer unlock()
+	defer m.recordSnapshot(ctx, history)
+
+	err = m.repo.DoInTransaction(ctx, targetID, func(ctx context.Context) error {
+		migrations, err := m.baselineCandidates(ctx, targetID, migrationIDs, fromID, toID)
+		if err != nil {
+			return err
+		}
+
+		baselinedAt = time.Now()
+
+		for _, migration := range migrations {
+			history.start(migration.ID)
+
+			err := m.repo.Baseline(ctx, migration.ID, userID, baselinedAt)
+			if err != nil {
+				return fmt.Errorf("m.repo.Baseline: %w", err)
+			}
+
+			err = m.repo.RecordOnTarget(ctx, migration, entity.StatusApplied, baselinedAt)
+			if err != nil {
+				return fmt.Errorf("m.repo.RecordOnTarget: %w", err)
+			}
+
+			history.finish(migration.ID, entity.OutcomeSucceeded, nil)
+			baselined = append(baselined, migration.ID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("m.repo.DoInTransaction: %w", err)
+	}
+
+	return baselined, baselinedAt, nil
+}
+
+// baselineCandidates возвращает миграции для отметки. Миграции из списка должны
+// принадлежать целевой базе данных и допускать применение, а из диапазона
+// молча пропускаются уже примененные и откаченные.
+func (m *Migrator) baselineCandidates(ctx context.Context, targetID int64, migrationIDs []int64, fromID, toID int64) ([]entity.MigrationInfo, error) {
+	var migrations []entity.MigrationInfo
+
+	if len(migrationIDs) == 0 {
+		all, err := m.repo.List(ctx, targetID, "")
+		if err != nil {
+			return nil, fmt.Errorf("m.repo.ListMigrations: %w", err)
+		}
+		for _, migration := range all {
+			if migration.ID >= fromID && migration.ID <= toID && migration.Status.Applicable() {
+				migrations = append(migrations, migration)
+			}
+		}
+	} else {
+		for _, migrationID := range migrationIDs {
+			migration, err := m.repo.Get(ctx, migrationID)
+			if err != nil {
+				return nil, fmt.Errorf("m.repo.GetMigration: %w", err)
+			}
+
+			if migration.TargetID != targetID {
+				return nil, fmt.Errorf("%w: migration %d does not belong to target %d", entity.ErrInvalidArgument, migrationID, targetID)
+			}
+
+			if !migration.Status.Applicable() {
+				return nil, fmt.Errorf("%w: migration %d cannot be baselined in status %s", entity.ErrFailedPrecondition, migrationID, migration.Status)
+			}
+
+			migrations = append(migrations, migration)
+		}
+	}
+
+	for _, migration := range migrations {
+		if err := migration.VerifyChecksum(); err != nil {
+			return nil, err
+		}
+	}
+
+	return migrations, nil
+}
@@ -0,0 +1,165 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+)
+
+// execLog собирает записи журнала выполнения для одного запроса применения или отката.
+type execLog struct {
+	action   entity.HistoryAction
+	targetID int64
+	userID   int64
+	started  time.Time
+	entries  []entity.HistoryEntry
+}
+
+func newExecLog(action entity.HistoryAction, targetID, userID int64, migrationIDs ...int64) *execLog {
+	l := &execLog{
+		action:   action,
+		targetID: targetID,
+		userID:   userID,
+		started:  time.Now(),
+	}
+	for _, migrationID := range migrationIDs {
+		l.entry(migrationID)
+	}
+	return l
+}
+
+func (l *execLog) entry(migrationID int64) *entity.HistoryEntry {
+	for i := range l.entries {
+		if l.entries[i].MigrationID == migrationID {
+			return &l.entries[i]
+		}
+	}
+
+	l.entries = append(l.entries, entity.HistoryEntry{
+		MigrationID: migrationID,
+		TargetID:    l.targetID,
+		Action:      l.action,
+		UserID:      l.userID,
+		StartedAt:   l.started,
+	})
+	return &l.entries[len(l.entries)-1]
+}
+
+// start отмечает начало выполнения скрипта миграции.
+func (l *execLog) start(migrationID int64) {
+	l.entry(migrationID).StartedAt = time.Now()
+}
+
+// script записывает выполняемый скрипт после подстановки переменных шаблона.
+func (l *execLog) script(migrationID int64, rendered entity.RenderedScript) {
+	entry := l.entry(migrationID)
+	entry.Script = rendered.SQL
+	entry.Variables = rendered.Variables
+}
+
+// tenant добавляет запись о выполнении скрипта миграции в схеме арендатора.
+func (l *execLog) tenant(migrationID int64, run *tenantRun) {
+	entry := entity.HistoryEntry{
+		MigrationID: migrationID,
+		TargetID:    l.targetID,
+		Action:      l.action,
+		UserID:      l.userID,
+		StartedAt:   run.startedAt,
+		FinishedAt:  run.finishedAt,
+		Duration:    run.finishedAt.Sub(run.startedAt),
+		Outcome:     entity.OutcomeSucceeded,
+		Script:      run.script.SQL,
+		Variables:   run.script.Variables,
+		Tenant:      run.tenant,
+	}
+	if run.err != nil {
+		entry.Outcome = entity.OutcomeFailed
+		entry.Error = run.err.Error()
+	}
+	l.entries = append(l.entries, entry)
+}
+
+// finish отмечает окончание выполнения скрипта миграции.
+func (l *execLog) finish(migrationID int64, outcome entity.HistoryOutcome, err error) {
+	entry := l.entry(migrationID)
+	entry.FinishedAt = time.Now()
+	entry.Duration = entry.FinishedAt.Sub(entry.StartedAt)
+	entry.Outcome = outcome
+	if err != nil {
+		entry.Error = err.Error()
+	}
+}
+
+// close проставляет итог записям после завершения запроса с ошибкой err.
+// Успешно выполненные скрипты откатываются вместе с транзакцией, а невыполненные
+// считаются прерванными, если ошибку вызвала другая миграция, и неудачными иначе.
+func (l *execLog) close(err error) []entity.HistoryEntry {
+	if err == nil {
+		return l.entries
+	}
+
+	failedScript := false
+	for _, entry := range l.entries {
+		if entry.Outcome == entity.OutcomeFailed || entry.Outcome == entity.OutcomePartiallyApplied {
+			failedScript = true
+		}
+	}
+
+	now := time.Now()
+	for i := range l.entries {
+		entry := &l.entries[i]
+		switch entry.Outcome {
+		case entity.OutcomeSucceeded:
+			entry.Outcome = entity.OutcomeAborted
+			entry.Error = fmt.Sprintf("transaction rolled back: %v", err)
+		case "":
+			entry.FinishedAt = now
+			entry.Duration = now.Sub(entry.StartedAt)
+			entry.Outcome = entity.OutcomeFailed
+			if failedScript {
+				entry.Outcome = entity.OutcomeAborted
+			}
+			entry.Error = err.Error()
+		}
+	}
+
+	return l.entries
+}
+
+// writeHistory записывает журнал выполнения. Ошибка записи не отменяет
+// результат самой операции и только логируется.
+func (m *Migrator) writeHistory(ctx context.Context, l *execLog, err error) {
+	entries := l.close(err)
+	if len(entries) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := m.history.Add(ctx, entries); err != nil {
+		logger.Error(fmt.Errorf("write migration history: %w", err))
+	}
+}
+
+// ListMigrationHistory возвращает журнал выполнения миграций.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	filter: entity.HistoryFilter - Условия выборки и размер страницы.
+//
+// Возвращает:
+//
+//	[]entity.HistoryEntry: Записи журнала от новых к старым.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) ListMigrationHistory(ctx context.Context, filter entity.HistoryFilter) ([]entity.HistoryEntry, error) {
+	entries, err := m.history.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("m.history.List: %w", err)
+	}
+
+	return entries, nil
+}
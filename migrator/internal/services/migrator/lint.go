@@ -0,0 +1,32 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+
+	"migrator/internal/entity"
+)
+
+// LintMigration проверяет скрипты линтером по тем же правилам, что и при создании миграции.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	migrationID: int64 - Идентификатор сохраненной миграции; если 0, проверяются переданные скрипты.
+//	script: string - Текст скрипта миграции.
+//	rollbackScript: string - Текст скрипта отката миграции.
+//
+// Возвращает:
+//
+//	entity.LintReport: Замечания линтера.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) LintMigration(ctx context.Context, migrationID int64, script, rollbackScript string) (entity.LintReport, error) {
+	if migrationID != 0 {
+		migration, err := m.repo.Get(ctx, migrationID)
+		if err != nil {
+			return entity.LintReport{}, fmt.Errorf("m.repo.GetMigration: %w", err)
+		}
+		script, rollbackScript = migration.Script, migration.RollbackScript
+	}
+
+	return m.linter.Lint(script, rollbackScript), nil
+}
@@ -3,112 +3,395 @@ package migrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"migrator/internal/entity"
+	"migrator/pkg/logger"
 )
 
 type migrationRepository interface {
 	Get(ctx context.Context, migrationID int64) (entity.MigrationInfo, error)
-	Create(ctx context.Context, name, description, script, rollbackScript string, userID int64) (int64, error)
+	Create(ctx context.Context, migration entity.MigrationInfo) (int64, error)
 	Apply(ctx context.Context, script string) error
+	TryApply(ctx context.Context, script string) (int64, error)
+	ApplyOutsideTransaction(ctx context.Context, targetID int64, script string) (int, error)
+	CountStatements(ctx context.Context, targetID int64, script string) (int, error)
+	TransactionalDDL(ctx context.Context, targetID int64) (bool, error)
+	RecordOnTarget(ctx context.Context, migration entity.MigrationInfo, status entity.MigrationStatus, updatedAt time.Time) error
+	RecordedOnTarget(ctx context.Context, migration entity.MigrationInfo, status entity.MigrationStatus) (bool, error)
 	SetStatus(ctx context.Context, migrationID int64, updatedAt time.Time, status entity.MigrationStatus) error
-	List(ctx context.Context, statusFilter string) ([]entity.MigrationInfo, error)
-	GetLatestAppliedMigration(ctx context.Context) (entity.MigrationInfo, error)
-	DoInTransaction(ctx context.Context, f func(ctx context.Context) error) error
+	SetLastError(ctx context.Context, migrationID int64, scriptErr *entity.ScriptError) error
+	SetAppliedChecksum(ctx context.Context, migrationID int64, checksum string) error
+	Baseline(ctx context.Context, migrationID, userID int64, baselinedAt time.Time) error
+	List(ctx context.Context, targetID int64, statusFilter string) ([]entity.MigrationInfo, error)
+	GetLatestAppliedMigration(ctx context.Context, targetID int64) (entity.MigrationInfo, error)
+	ListAppliedAfter(ctx context.Context, targetID, migrationID int64) ([]entity.MigrationInfo, error)
+	DoInTransaction(ctx context.Context, targetID int64, f func(ctx context.Context) error) error
+	ListTenants(ctx context.Context, targetID int64) ([]string, error)
+	LockTarget(ctx context.Context, targetID int64) (func(), error)
+	ApplyToTenant(ctx context.Context, migration entity.MigrationInfo, tenant, script string) error
+	SetTenantStatus(ctx context.Context, status entity.TenantStatus) error
+	ListTenantStatuses(ctx context.Context, migrationID int64) ([]entity.TenantStatus, error)
+	UpdateScript(ctx context.Context, migration entity.MigrationInfo) error
+	AddRevision(ctx context.Context, revision entity.MigrationRevision) (int64, error)
+	ListRevisions(ctx context.Context, migrationID int64) ([]entity.MigrationRevision, error)
+}
+
+type historyRepository interface {
+	Add(ctx context.Context, entries []entity.HistoryEntry) error
+	List(ctx context.Context, filter entity.HistoryFilter) ([]entity.HistoryEntry, error)
+}
+
+// scriptLinter - проверка скриптов миграций на опасные операции.
+type scriptLinter interface {
+	Lint(script, rollbackScript string) entity.LintReport
+}
+
+// targetLocker - блокировка целевой базы данных, общая для всех реплик сервиса.
+type targetLocker interface {
+	Lock(ctx context.Context, targetID int64, operation string) (context.Context, func(), error)
+}
+
+// promotionChecker - правила продвижения миграций по окружениям.
+type promotionChecker interface {
+	CheckPromotion(ctx context.Context, targetID int64, migrations []entity.MigrationInfo) error
+}
+
+// scriptTemplates - переменные шаблонов скриптов целевых баз данных.
+type scriptTemplates interface {
+	TemplateContext(ctx context.Context, targetID int64) (entity.TemplateContext, error)
+}
+
+// schemaRepository - схемы целевых баз данных и их снимки.
+type schemaRepository interface {
+	Introspect(ctx context.Context, targetID int64) ([]entity.SchemaObject, error)
+	AddSnapshot(ctx context.Context, snapshot entity.SchemaSnapshot) (int64, error)
+	GetLatestSnapshot(ctx context.Context, targetID int64) (entity.SchemaSnapshot, error)
+}
+
+// backfillRepository - ход выполнения миграций данных.
+type backfillRepository interface {
+	ListActive(ctx context.Context, targetID int64) ([]entity.Backfill, error)
 }
 
 // Migrator - сервис миграций.
 type Migrator struct {
-	repo migrationRepository
+	repo      migrationRepository
+	locker    targetLocker
+	history   historyRepository
+	linter    scriptLinter
+	promotion promotionChecker
+	templates scriptTemplates
+	schemas   schemaRepository
+	backfills backfillRepository
+
+	maxTenantConcurrency int
 }
 
 // New - конструктор сервиса миграций.
-func New(repo migrationRepository) *Migrator {
+func New(repo migrationRepository, locker targetLocker, history historyRepository, linter scriptLinter, promotion promotionChecker, templates scriptTemplates, schemas schemaRepository, backfills backfillRepository, maxTenantConcurrency int) *Migrator {
+	if maxTenantConcurrency < 1 {
+		maxTenantConcurrency = 1
+	}
 	return &Migrator{
-		repo: repo,
+		repo:      repo,
+		locker:    locker,
+		history:   history,
+		linter:    linter,
+		promotion: promotion,
+		templates: templates,
+		schemas:   schemas,
+		backfills: backfills,
+
+		maxTenantConcurrency: maxTenantConcurrency,
 	}
 }
 
-// CreateMigration создает новую миграцию.
+// CreateMigration создает новую миграцию и записывает контрольную сумму ее скриптов.
+// Перед созданием скрипты проверяются линтером: если среди замечаний есть
+// запрещающие, миграция не создается и возвращается *entity.LintError.
+// У повторяемой миграции и миграции данных нет скрипта отката, и они выполняются
+// в транзакции. Миграция данных выполняется пачками через StartBackfill.
 // Аргументы:
 //
 //	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
 //	name: string - Название миграции.
 //	description: string - Описание миграции.
 //	script: string - Текст скрипта миграции.
 //	rollbackScript: string - Текст скрипта отката миграции.
+//	executionMode: entity.ExecutionMode - Режим выполнения скриптов; по умолчанию в транзакции.
+//	kind: entity.MigrationKind - Вид миграции; по умолчанию версионная.
+//	labels: map[string]string - Метки миграции (например, release и team).
+//	tenantScoped: bool - Миграция применяется к каждой схеме арендатора через ApplyTenantMigration.
+//	templated: bool - В скрипты подставляются значения {{ ... }} (см. entity.TemplateContext).
+//	backfill: *entity.BackfillSpec - Параметры пачек миграции данных; только для вида backfill.
 //	userID: int64 - Идентификатор пользователя, создающего миграцию.
 //
 // Возвращает:
 //
 //	int64: Уникальный идентификатор созданной миграции.
+//	[]entity.LintFinding: Замечания линтера, не запретившие создание.
 //	error: Ошибка, если таковая имеется.
-func (m *Migrator) CreateMigration(ctx context.Context, name, description, script, rollbackScript string, userID int64) (int64, error) {
-	migrationID, err := m.repo.Create(ctx, name, description, script, rollbackScript, userID)
+func (m *Migrator) CreateMigration(ctx context.Context, targetID int64, name, description, script, rollbackScript string, executionMode entity.ExecutionMode, kind entity.MigrationKind, labels map[string]string, tenantScoped, templated bool, backfill *entity.BackfillSpec, userID int64) (int64, []entity.LintFinding, error) {
+	if executionMode == "" {
+		executionMode = entity.ExecutionModeTransactional
+	}
+	if !executionMode.Valid() {
+		return 0, nil, fmt.Errorf("%w: unknown execution mode %q", entity.ErrInvalidArgument, executionMode)
+	}
+	if kind == "" {
+		kind = entity.MigrationKindVersioned
+	}
+	if err := checkKind(kind, executionMode, rollbackScript, tenantScoped, backfill); err != nil {
+		return 0, nil, err
+	}
+	if tenantScoped && executionMode == entity.ExecutionModeNoTransaction {
+		return 0, nil, fmt.Errorf("%w: tenant-scoped migration must run in a transaction", entity.ErrInvalidArgument)
+	}
+	if err := entity.ValidateLabels(labels); err != nil {
+		return 0, nil, err
+	}
+
+	if kind == entity.MigrationKindBackfill {
+		// В скрипт миграции данных всегда подставляются курсор и размер пачки.
+		templated = true
+	}
+
+	report := m.linter.Lint(script, rollbackScript)
+	if kind == entity.MigrationKindRepeatable || kind == entity.MigrationKindBackfill {
+		report = withoutRollbackFindings(report)
+	}
+	if report.Blocked {
+		return 0, nil, &entity.LintError{Report: report}
+	}
+
+	migrationID, err := m.repo.Create(ctx, entity.MigrationInfo{
+		TargetID:       targetID,
+		Name:           name,
+		Description:    description,
+		Script:         script,
+		RollbackScript: rollbackScript,
+		Checksum:       entity.ScriptChecksum(script, rollbackScript),
+		ExecutionMode:  executionMode,
+		Kind:           kind,
+		Labels:         labels,
+		TenantScoped:   tenantScoped,
+		Templated:      templated,
+		Backfill:       backfill,
+		CreatedBy:      userID,
+	})
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 
-	return migrationID, nil
+	return migrationID, report.Findings, nil
 }
 
-// ApplyMigration применяет миграции.
+// ApplyMigration применяет миграции, удерживая блокировку целевой базы данных.
+// Повторяемые миграции применяются после версионных в порядке названий
+// (ожидающие повторяемые миграции добавляет к запросу PendingRepeatableMigrations
+// до проверки прав). Если целевая база данных относится к окружению, миграции должны пройти
+// правило продвижения из предыдущего окружения. Миграция без транзакции применяется только отдельным запросом, а на целевых
+// базах данных, которые не откатывают DDL (MySQL), миграции применяются
+// по одной вне транзакции. Перед выполнением в скрипты миграций с шаблонами подставляются переменные
+// шаблонов целевой базы данных. Каждая попытка, в том числе неудачная, записывается
+// в журнал выполнения вместе с выполненным скриптом, а миграция, скрипт которой
+// завершился ошибкой, получает статус failed и сохраненную ошибку базы данных.
+// Скрипт миграции, которую таблица истории целевой базы данных уже отмечает
+// примененной (не зафиксировались только метаданные прошлого применения),
+// повторно не выполняется: восстанавливаются только ее метаданные.
+// После применения сохраняется снимок схемы целевой базы данных для GetDatabaseStatus.
 // Аргументы:
 //
 //	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
 //	migrationIDs: []int64 - Уникальные идентификаторы миграций в соответствии с порядком применения.
 //	userID: int64 - Идентификатор пользователя, применяющего миграцию.
 //
 // Возвращает:
 //
+//	[]int64: Идентификаторы примененных миграций в порядке применения.
 //	time.Time: Дата и время применения миграции.
 //	error: Ошибка, если таковая имеется.
-func (m *Migrator) ApplyMigration(ctx context.Context, migrationIDs []int64, userID int64) (time.Time, error) {
-	var appliedAt time.Time
+func (m *Migrator) ApplyMigration(ctx context.Context, targetID int64, migrationIDs []int64, userID int64) (applied []int64, appliedAt time.Time, err error) {
+	migrationIDs, err = m.applyOrder(ctx, migrationIDs)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
 
-	err := m.repo.DoInTransaction(ctx, func(ctx context.Context) error {
-		var migrations []entity.MigrationInfo
+	history := newExecLog(entity.HistoryActionApply, targetID, userID, migrationIDs...)
+	defer func() { m.writeHistory(ctx, history, err) }()
+
+	ctx, unlock, err := m.locker.Lock(ctx, targetID, "apply")
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("m.locker.Lock: %w", err)
+	}
+	defer unlock()
+	defer m.recordSnapshot(ctx, history)
+
+	err = m.checkPromotion(ctx, targetID, migrationIDs)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	transactional, err := m.repo.TransactionalDDL(ctx, targetID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("m.repo.TransactionalDDL: %w", err)
+	}
+	if !transactional {
+		appliedAt, err = m.applyEachOutsideTransaction(ctx, targetID, migrationIDs, history)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return migrationIDs, appliedAt, nil
+	}
+
+	if len(migrationIDs) == 1 {
+		migration, err := m.repo.Get(ctx, migrationIDs[0])
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("m.repo.GetMigration: %w", err)
+		}
+		if migration.ExecutionMode == entity.ExecutionModeNoTransaction && !migration.TenantScoped {
+			appliedAt, err = m.applyOutsideTransaction(ctx, targetID, migration, history)
+			if err != nil {
+				return nil, time.Time{}, err
+			}
+			return migrationIDs, appliedAt, nil
+		}
+	}
+
+	var failedID int64
+
+	err = m.repo.DoInTransaction(ctx, targetID, func(ctx context.Context) error {
+		var (
+			migrations []entity.MigrationInfo
+			scripts    []string
+		)
 		for _, migrationID := range migrationIDs {
 			migration, err := m.repo.Get(ctx, migrationID)
 			if err != nil {
 				return fmt.Errorf("m.repo.GetMigration: %w", err)
 			}
 
-			if migration.Status != entity.StatusPending {
-				return fmt.Errorf("migration %d is not pending", migrationID)
+			if migration.TargetID != targetID {
+				return fmt.Errorf("%w: migration %d does not belong to target %d", entity.ErrInvalidArgument, migrationID, targetID)
+			}
+
+			if !migration.Status.Applicable() {
+				return fmt.Errorf("%w: migration %d cannot be applied in status %s", entity.ErrFailedPrecondition, migrationID, migration.Status)
+			}
+
+			if migration.TenantScoped {
+				return tenantScopedError(migrationID)
+			}
+
+			if migration.Kind == entity.MigrationKindBackfill {
+				return backfillApplyError(migrationID)
+			}
+
+			if migration.ExecutionMode == entity.ExecutionModeNoTransaction {
+				return fmt.Errorf("%w: migration %d runs outside a transaction and must be applied on its own", entity.ErrInvalidArgument, migrationID)
+			}
+
+			if err := migration.VerifyChecksum(); err != nil {
+				return err
+			}
+
+			script, err := m.renderScript(ctx, targetID, migration, migration.Script, history)
+			if err != nil {
+				return err
 			}
 
 			migrations = append(migrations, migration)
+			scripts = append(scripts, script)
 		}
 
-		for _, migration := range migrations {
-			err := m.repo.Apply(ctx, migration.Script)
+		for i, migration := range migrations {
+			// Скрипт уже выполнен, если прошлое применение зафиксировало транзакцию целевой
+			// базы данных, но не метаданные: тогда восстанавливаются только метаданные.
+			appliedOnTarget, err := m.repo.RecordedOnTarget(ctx, migration, entity.StatusApplied)
 			if err != nil {
-				return fmt.Errorf("m.repo.ApplyMigration: %w", err)
+				return fmt.Errorf("m.repo.RecordedOnTarget: %w", err)
 			}
 
+			history.start(migration.ID)
+			if appliedOnTarget {
+				logger.Warn("migration %d is already applied on target %d, restoring its metadata", migration.ID, targetID)
+			} else {
+				err = m.repo.Apply(ctx, scripts[i])
+				if err != nil {
+					failedID = migration.ID
+					history.finish(migration.ID, entity.OutcomeFailed, err)
+					return fmt.Errorf("m.repo.ApplyMigration %d: %w", migration.ID, err)
+				}
+			}
+			history.finish(migration.ID, entity.OutcomeSucceeded, nil)
+
 			err = m.repo.SetStatus(ctx, migration.ID, time.Now(), entity.StatusApplied)
 			if err != nil {
 				return fmt.Errorf("m.repo.SetApplied: %w", err)
 			}
+
+			err = m.repo.SetAppliedChecksum(ctx, migration.ID, migration.Checksum)
+			if err != nil {
+				return fmt.Errorf("m.repo.SetAppliedChecksum: %w", err)
+			}
+
+			err = m.repo.SetLastError(ctx, migration.ID, nil)
+			if err != nil {
+				return fmt.Errorf("m.repo.SetLastError: %w", err)
+			}
+
+			err = m.addRevision(ctx, migration, scripts[i], userID)
+			if err != nil {
+				return err
+			}
+
+			err = m.repo.RecordOnTarget(ctx, migration, entity.StatusApplied, time.Now())
+			if err != nil {
+				return fmt.Errorf("m.repo.RecordOnTarget: %w", err)
+			}
 		}
 
 		appliedAt = time.Now()
 		return nil
 	})
 	if err != nil {
-		return time.Time{}, fmt.Errorf("m.repo.DoInTransaction: %w", err)
+		if failedID != 0 {
+			m.recordFailure(ctx, failedID, entity.StatusFailed, err)
+		}
+		return nil, time.Time{}, fmt.Errorf("m.repo.DoInTransaction: %w", err)
+	}
+
+	return migrationIDs, appliedAt, nil
+}
+
+// checkPromotion проверяет правило продвижения окружения целевой базы данных для миграций.
+func (m *Migrator) checkPromotion(ctx context.Context, targetID int64, migrationIDs []int64) error {
+	migrations := make([]entity.MigrationInfo, 0, len(migrationIDs))
+	for _, migrationID := range migrationIDs {
+		migration, err := m.repo.Get(ctx, migrationID)
+		if err != nil {
+			return fmt.Errorf("m.repo.GetMigration: %w", err)
+		}
+		migrations = append(migrations, migration)
 	}
 
-	return appliedAt, nil
+	err := m.promotion.CheckPromotion(ctx, targetID, migrations)
+	if err != nil {
+		return fmt.Errorf("m.promotion.CheckPromotion: %w", err)
+	}
+	return nil
 }
 
-// RollbackMigration откатывает миграцию.
+// RollbackMigration откатывает миграцию, удерживая блокировку целевой базы данных.
 // Аргументы:
 //
 //	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
 //	migrationID: int64 - Уникальный идентификатор миграции для отката.
 //	userID: int64 - Идентификатор пользователя, выполняющего откат.
 //
@@ -116,32 +399,66 @@ func (m *Migrator) ApplyMigration(ctx context.Context, migrationIDs []int64, use
 //
 //	time.Time: Дата и время отката миграции.
 //	error: Ошибка, если таковая имеется.
-func (m *Migrator) RollbackMigration(ctx context.Context, migrationID, userID int64) (time.Time, error) {
-	var rolledBackAt time.Time
+func (m *Migrator) RollbackMigration(ctx context.Context, targetID, migrationID, userID int64) (rolledBackAt time.Time, err error) {
+	history := newExecLog(entity.HistoryActionRollback, targetID, userID, migrationID)
+	defer func() { m.writeHistory(ctx, history, err) }()
 
-	err := m.repo.DoInTransaction(ctx, func(ctx context.Context) error {
+	ctx, unlock, err := m.locker.Lock(ctx, targetID, "rollback")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.locker.Lock: %w", err)
+	}
+	defer unlock()
+	defer m.recordSnapshot(ctx, history)
+
+	migration, err := m.repo.Get(ctx, migrationID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.GetMigration: %w", err)
+	}
+
+	transactional, err := m.repo.TransactionalDDL(ctx, targetID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.TransactionalDDL: %w", err)
+	}
+	if migration.ExecutionMode == entity.ExecutionModeNoTransaction || !transactional {
+		return m.rollbackOutsideTransaction(ctx, targetID, migration, history)
+	}
+
+	var scriptFailed bool
+
+	err = m.repo.DoInTransaction(ctx, targetID, func(ctx context.Context) error {
 		migration, err := m.repo.Get(ctx, migrationID)
 		if err != nil {
 			return fmt.Errorf("m.repo.GetMigration: %w", err)
 		}
 
-		if migration.Status != entity.StatusApplied {
-			return fmt.Errorf("migration %d is not pending", migrationID)
+		if err := m.checkRollback(ctx, targetID, migration); err != nil {
+			return err
 		}
 
-		latestAppliedMigration, err := m.repo.GetLatestAppliedMigration(ctx)
+		script, err := m.renderScript(ctx, targetID, migration, migration.RollbackScript, history)
 		if err != nil {
-			return fmt.Errorf("m.repo.GetLatestAppliedMigration: %w", err)
+			return err
 		}
 
-		if latestAppliedMigration.ID != migrationID {
-			return fmt.Errorf("not last migration")
+		// Откат уже выполнен, если прошлый откат зафиксировал транзакцию целевой
+		// базы данных, но не метаданные: тогда восстанавливаются только метаданные.
+		rolledBackOnTarget, err := m.repo.RecordedOnTarget(ctx, migration, entity.StatusRolledBack)
+		if err != nil {
+			return fmt.Errorf("m.repo.RecordedOnTarget: %w", err)
 		}
 
-		err = m.repo.Apply(ctx, migration.RollbackScript)
-		if err != nil {
-			return fmt.Errorf("m.db.ApplyMigration: %w", err)
+		history.start(migration.ID)
+		if rolledBackOnTarget {
+			logger.Warn("migration %d is already rolled back on target %d, restoring its metadata", migration.ID, targetID)
+		} else {
+			err = m.repo.Apply(ctx, script)
+			if err != nil {
+				scriptFailed = true
+				history.finish(migration.ID, entity.OutcomeFailed, err)
+				return fmt.Errorf("m.db.ApplyMigration: %w", err)
+			}
 		}
+		history.finish(migration.ID, entity.OutcomeSucceeded, nil)
 
 		rolledBackAt = time.Now()
 
@@ -150,32 +467,298 @@ func (m *Migrator) RollbackMigration(ctx context.Context, migrationID, userID in
 			return fmt.Errorf("m.repo.SetStatus: %w", err)
 		}
 
-		return nil
+		err = m.repo.SetLastError(ctx, migration.ID, nil)
+		if err != nil {
+			return fmt.Errorf("m.repo.SetLastError: %w", err)
+		}
+
+		return m.repo.RecordOnTarget(ctx, migration, entity.StatusRolledBack, rolledBackAt)
 	})
 	if err != nil {
+		if scriptFailed {
+			m.recordFailure(ctx, migrationID, "", err)
+		}
 		return time.Time{}, fmt.Errorf("m.repo.DoInTransaction: %w", err)
 	}
 
 	return rolledBackAt, nil
 }
 
+// checkRollback проверяет, что миграцию можно откатить: она принадлежит целевой
+// базе данных, применена (в том числе частично), является последней примененной,
+// ее скрипты не изменялись и на целевой базе данных нет незавершенной миграции данных.
+func (m *Migrator) checkRollback(ctx context.Context, targetID int64, migration entity.MigrationInfo) error {
+	if migration.TargetID != targetID {
+		return fmt.Errorf("%w: migration %d does not belong to target %d", entity.ErrInvalidArgument, migration.ID, targetID)
+	}
+
+	if migration.TenantScoped {
+		return tenantScopedError(migration.ID)
+	}
+
+	if migration.Kind == entity.MigrationKindRepeatable {
+		return repeatableRollbackError(migration.ID)
+	}
+
+	if migration.Kind == entity.MigrationKindBackfill {
+		return backfillRollbackError(migration.ID)
+	}
+
+	if !migration.Status.Applied() {
+		return fmt.Errorf("%w: migration %d is not applied", entity.ErrFailedPrecondition, migration.ID)
+	}
+
+	if err := m.checkBackfills(ctx, targetID); err != nil {
+		return err
+	}
+
+	latestAppliedMigration, err := m.repo.GetLatestAppliedMigration(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("m.repo.GetLatestAppliedMigration: %w", err)
+	}
+
+	if latestAppliedMigration.ID != migration.ID {
+		return fmt.Errorf("%w, latest applied is %d", entity.ErrNotLastMigration, latestAppliedMigration.ID)
+	}
+
+	return migration.VerifyChecksum()
+}
+
+// RollbackToMigration откатывает все примененные миграции новее указанной
+// в обратном порядке в одной транзакции (на целевых базах данных, которые
+// не откатывают DDL, - по одной вне транзакции). Сама указанная миграция
+// остается примененной.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	migrationID: int64 - Уникальный идентификатор миграции, до которой выполняется откат.
+//	userID: int64 - Идентификатор пользователя, выполняющего откат.
+//	authorize: func - Проверка прав на откат каждой миграции; вызывается до выполнения скриптов.
+//
+// Возвращает:
+//
+//	[]int64: Идентификаторы откаченных миграций в порядке отката.
+//	time.Time: Дата и время отката.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) RollbackToMigration(
+	ctx context.Context,
+	targetID, migrationID, userID int64,
+	authorize func(ctx context.Context, migration entity.MigrationInfo) error,
+) (rolledBack []int64, rolledBackAt time.Time, err error) {
+	history := newExecLog(entity.HistoryActionRollback, targetID, userID)
+	defer func() { m.writeHistory(ctx, history, err) }()
+
+	ctx, unlock, err := m.locker.Lock(ctx, targetID, "rollback")
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("m.locker.Lock: %w", err)
+	}
+	defer unlock()
+	defer m.recordSnapshot(ctx, history)
+
+	transactional, err := m.repo.TransactionalDDL(ctx, targetID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("m.repo.TransactionalDDL: %w", err)
+	}
+	if !transactional {
+		return m.rollbackEachOutsideTransaction(ctx, targetID, migrationID, authorize, history)
+	}
+
+	var failedID int64
+
+	err = m.repo.DoInTransaction(ctx, targetID, func(ctx context.Context) error {
+		migration, err := m.repo.Get(ctx, migrationID)
+		if err != nil {
+			return fmt.Errorf("m.repo.GetMigration: %w", err)
+		}
+
+		if migration.TargetID != targetID {
+			return fmt.Errorf("%w: migration %d does not belong to target %d", entity.ErrInvalidArgument, migrationID, targetID)
+		}
+
+		if !migration.Status.Applied() {
+			return fmt.Errorf("%w: migration %d is not applied", entity.ErrFailedPrecondition, migrationID)
+		}
+
+		if err := m.checkBackfills(ctx, targetID); err != nil {
+			return err
+		}
+
+		migrations, err := m.repo.ListAppliedAfter(ctx, targetID, migrationID)
+		if err != nil {
+			return fmt.Errorf("m.repo.ListAppliedAfter: %w", err)
+		}
+
+		scripts := make([]string, 0, len(migrations))
+		for _, migration := range migrations {
+			if migration.ExecutionMode == entity.ExecutionModeNoTransaction {
+				return fmt.Errorf("%w: migration %d runs outside a transaction and must be rolled back on its own", entity.ErrInvalidArgument, migration.ID)
+			}
+
+			if migration.TenantScoped {
+				return tenantScopedError(migration.ID)
+			}
+
+			if authorize != nil {
+				if err := authorize(ctx, migration); err != nil {
+					return err
+				}
+			}
+
+			if err := migration.VerifyChecksum(); err != nil {
+				return err
+			}
+
+			script, err := m.renderScript(ctx, targetID, migration, migration.RollbackScript, history)
+			if err != nil {
+				return err
+			}
+			scripts = append(scripts, script)
+		}
+
+		rolledBackAt = time.Now()
+
+		for i, migration := range migrations {
+			// Откат уже выполнен, если прошлый откат зафиксировал транзакцию целевой
+			// базы данных, но не метаданные: тогда восстанавливаются только метаданные.
+			rolledBackOnTarget, err := m.repo.RecordedOnTarget(ctx, migration, entity.StatusRolledBack)
+			if err != nil {
+				return fmt.Errorf("m.repo.RecordedOnTarget: %w", err)
+			}
+
+			history.start(migration.ID)
+			if rolledBackOnTarget {
+				logger.Warn("migration %d is already rolled back on target %d, restoring its metadata", migration.ID, targetID)
+			} else {
+				err = m.repo.Apply(ctx, scripts[i])
+				if err != nil {
+					failedID = migration.ID
+					history.finish(migration.ID, entity.OutcomeFailed, err)
+					return fmt.Errorf("m.repo.ApplyMigration %d: %w", migration.ID, err)
+				}
+			}
+			history.finish(migration.ID, entity.OutcomeSucceeded, nil)
+
+			err = m.repo.SetStatus(ctx, migration.ID, rolledBackAt, entity.StatusRolledBack)
+			if err != nil {
+				return fmt.Errorf("m.repo.SetStatus: %w", err)
+			}
+
+			err = m.repo.SetLastError(ctx, migration.ID, nil)
+			if err != nil {
+				return fmt.Errorf("m.repo.SetLastError: %w", err)
+			}
+
+			err = m.repo.RecordOnTarget(ctx, migration, entity.StatusRolledBack, rolledBackAt)
+			if err != nil {
+				return fmt.Errorf("m.repo.RecordOnTarget: %w", err)
+			}
+
+			rolledBack = append(rolledBack, migration.ID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if failedID != 0 {
+			m.recordFailure(ctx, failedID, "", err)
+		}
+		return nil, time.Time{}, fmt.Errorf("m.repo.DoInTransaction: %w", err)
+	}
+
+	return rolledBack, rolledBackAt, nil
+}
+
+// recordFailure сохраняет ошибку скрипта миграции после отката транзакции,
+// в которой он выполнялся. Пустой status оставляет статус миграции прежним.
+// Ошибки сохранения только логируются, чтобы не скрыть исходную ошибку.
+func (m *Migrator) recordFailure(ctx context.Context, migrationID int64, status entity.MigrationStatus, err error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if status != "" {
+		if statusErr := m.repo.SetStatus(ctx, migrationID, time.Now(), status); statusErr != nil {
+			logger.Error(fmt.Errorf("record failure of migration %d: %w", migrationID, statusErr))
+		}
+	}
+
+	if lastErr := m.repo.SetLastError(ctx, migrationID, asScriptError(err)); lastErr != nil {
+		logger.Error(fmt.Errorf("record failure of migration %d: %w", migrationID, lastErr))
+	}
+}
+
+// asScriptError извлекает ошибку базы данных; прочие ошибки сохраняются только текстом.
+func asScriptError(err error) *entity.ScriptError {
+	var scriptErr *entity.ScriptError
+	if errors.As(err, &scriptErr) {
+		return scriptErr
+	}
+	return &entity.ScriptError{Message: err.Error()}
+}
+
 // ListMigrations возвращает список миграций.
 // Аргументы:
 //
 //	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
 //	statusFilter: string - Фильтр по статусу миграции.
+//	selector: entity.LabelSelector - Фильтр по меткам миграции.
 //
 // Возвращает:
 //
 //	[]entity.MigrationInfo: Список информации о миграциях.
 //	error: Ошибка, если таковая имеется.
-func (m *Migrator) ListMigrations(ctx context.Context, statusFilter string) ([]entity.MigrationInfo, error) {
-	migrations, err := m.repo.List(ctx, statusFilter)
+func (m *Migrator) ListMigrations(ctx context.Context, targetID int64, statusFilter string, selector entity.LabelSelector) ([]entity.MigrationInfo, error) {
+	migrations, err := m.repo.List(ctx, targetID, statusFilter)
 	if err != nil {
 		return nil, fmt.Errorf("m.repo.ListMigrations: %w", err)
 	}
 
-	return migrations, nil
+	if len(selector) == 0 {
+		return migrations, nil
+	}
+
+	matched := migrations[:0]
+	for _, migration := range migrations {
+		if selector.Matches(migration.Labels) {
+			matched = append(matched, migration)
+		}
+	}
+
+	return matched, nil
+}
+
+// SelectPendingMigrations возвращает миграции целевой базы данных, подходящие под селектор
+// меток и ожидающие применения (в том числе после ошибки), в порядке применения.
+// Миграции арендаторов и миграции данных не выбираются: они применяются отдельными запросами.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	selector: entity.LabelSelector - Селектор меток, например release=2026.10.
+//
+// Возвращает:
+//
+//	[]int64: Идентификаторы миграций в порядке применения.
+//	error: entity.ErrNotFound, если под селектор не подходит ни одна миграция.
+func (m *Migrator) SelectPendingMigrations(ctx context.Context, targetID int64, selector entity.LabelSelector) ([]int64, error) {
+	migrations, err := m.ListMigrations(ctx, targetID, "", selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrationIDs []int64
+	for _, migration := range migrations {
+		if migration.Status.Applicable() && !migration.TenantScoped && migration.Kind != entity.MigrationKindBackfill {
+			migrationIDs = append(migrationIDs, migration.ID)
+		}
+	}
+
+	if len(migrationIDs) == 0 {
+		return nil, fmt.Errorf("no pending migrations match selector %q: %w", selector, entity.ErrNotFound)
+	}
+
+	return migrationIDs, nil
 }
 
 // GetMigration возвращает миграцию по ее ID.
@@ -196,3 +779,40 @@ func (m *Migrator) GetMigration(ctx context.Context, migrationID int64) (entity.
 
 	return migration, nil
 }
+
+// VerifyMigrations проверяет контрольные суммы всех миграций целевой базы данных.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//
+// Возвращает:
+//
+//	[]entity.ChecksumViolation: Миграции, скрипты которых не совпадают с записанной контрольной суммой.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) VerifyMigrations(ctx context.Context, targetID int64) ([]entity.ChecksumViolation, error) {
+	migrations, err := m.repo.List(ctx, targetID, "")
+	if err != nil {
+		return nil, fmt.Errorf("m.repo.ListMigrations: %w", err)
+	}
+
+	var violations []entity.ChecksumViolation
+	for _, migration := range migrations {
+		err := migration.VerifyChecksum()
+		if err == nil {
+			continue
+		}
+
+		violations = append(violations, entity.ChecksumViolation{
+			MigrationID:     migration.ID,
+			Name:            migration.Name,
+			Status:          migration.Status,
+			Checksum:        migration.Checksum,
+			AppliedChecksum: migration.AppliedChecksum,
+			ActualChecksum:  entity.ScriptChecksum(migration.Script, migration.RollbackScript),
+			Reason:          err.Error(),
+		})
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,851 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/sqlscript"
+)
+
+const testTarget = int64(1)
+
+// memoryRepository - реестр миграций и целевая база данных в памяти.
+// Транзакция восстанавливает реестр, выполненные скрипты и таблицу истории
+// целевой базы данных при ошибке. Скрипт вне транзакции выполняется по операторам.
+// Схемы арендаторов обрабатываются параллельно, поэтому их состояние защищено mu.
+// Методы, которые тесты не вызывают, остаются у встроенного интерфейса.
+type memoryRepository struct {
+	migrationRepository
+	migrations map[int64]entity.MigrationInfo
+	executed   []string
+	onTarget   map[int64]entity.MigrationStatus
+	failing    map[string]bool
+	// failMetadataCommit - транзакция целевой базы данных фиксируется, а метаданных - нет.
+	failMetadataCommit bool
+	// nonTransactionalDDL - целевая база данных не откатывает DDL в транзакции.
+	nonTransactionalDDL bool
+
+	mu             sync.Mutex
+	tenants        []string
+	tenantStatuses map[string]entity.TenantStatus
+	failingTenants map[string]bool
+}
+
+func newMemoryRepository(migrations ...entity.MigrationInfo) *memoryRepository {
+	r := &memoryRepository{
+		migrations: make(map[int64]entity.MigrationInfo),
+		onTarget:   make(map[int64]entity.MigrationStatus),
+		failing:    make(map[string]bool),
+
+		tenantStatuses: make(map[string]entity.TenantStatus),
+		failingTenants: make(map[string]bool),
+	}
+	for _, migration := range migrations {
+		if migration.TargetID == 0 {
+			migration.TargetID = testTarget
+		}
+		if migration.Kind == "" {
+			migration.Kind = entity.MigrationKindVersioned
+		}
+		if migration.ExecutionMode == "" {
+			migration.ExecutionMode = entity.ExecutionModeTransactional
+		}
+		migration.Checksum = entity.ScriptChecksum(migration.Script, migration.RollbackScript)
+		r.migrations[migration.ID] = migration
+	}
+	return r
+}
+
+func (r *memoryRepository) Get(_ context.Context, migrationID int64) (entity.MigrationInfo, error) {
+	migration, ok := r.migrations[migrationID]
+	if !ok {
+		return entity.MigrationInfo{}, entity.ErrNotFound
+	}
+	return migration, nil
+}
+
+func (r *memoryRepository) sorted(keep func(entity.MigrationInfo) bool) []entity.MigrationInfo {
+	var migrations []entity.MigrationInfo
+	for _, migration := range r.migrations {
+		if keep(migration) {
+			migrations = append(migrations, migration)
+		}
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].ID < migrations[j].ID })
+	return migrations
+}
+
+func (r *memoryRepository) List(_ context.Context, targetID int64, statusFilter string) ([]entity.MigrationInfo, error) {
+	return r.sorted(func(migration entity.MigrationInfo) bool {
+		return migration.TargetID == targetID && (statusFilter == "" || string(migration.Status) == statusFilter)
+	}), nil
+}
+
+// isApplied - примененная (в том числе частично) версионная миграция, как в appliedStatuses репозитория.
+func isApplied(migration entity.MigrationInfo) bool {
+	return migration.Kind == entity.MigrationKindVersioned &&
+		(migration.Status == entity.StatusApplied || migration.Status == entity.StatusPartiallyApplied)
+}
+
+func (r *memoryRepository) GetLatestAppliedMigration(_ context.Context, targetID int64) (entity.MigrationInfo, error) {
+	migrations := r.sorted(func(migration entity.MigrationInfo) bool {
+		return migration.TargetID == targetID && isApplied(migration)
+	})
+	if len(migrations) == 0 {
+		return entity.MigrationInfo{}, nil
+	}
+	return migrations[len(migrations)-1], nil
+}
+
+func (r *memoryRepository) ListAppliedAfter(_ context.Context, targetID, migrationID int64) ([]entity.MigrationInfo, error) {
+	migrations := r.sorted(func(migration entity.MigrationInfo) bool {
+		return migration.TargetID == targetID && migration.ID > migrationID && isApplied(migration)
+	})
+	for i, j := 0, len(migrations)-1; i < j; i, j = i+1, j-1 {
+		migrations[i], migrations[j] = migrations[j], migrations[i]
+	}
+	return migrations, nil
+}
+
+func (r *memoryRepository) TransactionalDDL(_ context.Context, _ int64) (bool, error) {
+	return !r.nonTransactionalDDL, nil
+}
+
+func (r *memoryRepository) DoInTransaction(ctx context.Context, _ int64, f func(ctx context.Context) error) error {
+	migrations := make(map[int64]entity.MigrationInfo, len(r.migrations))
+	for id, migration := range r.migrations {
+		migrations[id] = migration
+	}
+	executed := append([]string(nil), r.executed...)
+	onTarget := make(map[int64]entity.MigrationStatus, len(r.onTarget))
+	for id, status := range r.onTarget {
+		onTarget[id] = status
+	}
+
+	if err := f(ctx); err != nil {
+		r.migrations = migrations
+		r.executed = executed
+		r.onTarget = onTarget
+		return err
+	}
+	if r.failMetadataCommit {
+		r.migrations = migrations
+		return errors.New("commit metadata transaction: connection reset")
+	}
+	return nil
+}
+
+func (r *memoryRepository) Apply(_ context.Context, script string) error {
+	if r.failing[script] {
+		return fmt.Errorf("script %q failed", script)
+	}
+	r.executed = append(r.executed, script)
+	return nil
+}
+
+func (r *memoryRepository) CountStatements(_ context.Context, _ int64, script string) (int, error) {
+	return len(sqlscript.Split(script)), nil
+}
+
+func (r *memoryRepository) ApplyOutsideTransaction(ctx context.Context, _ int64, script string) (int, error) {
+	for i, statement := range sqlscript.Split(script) {
+		if err := r.Apply(ctx, statement); err != nil {
+			return i, err
+		}
+	}
+	return len(sqlscript.Split(script)), nil
+}
+
+func (r *memoryRepository) update(migrationID int64, change func(*entity.MigrationInfo)) {
+	migration := r.migrations[migrationID]
+	change(&migration)
+	r.migrations[migrationID] = migration
+}
+
+func (r *memoryRepository) SetStatus(_ context.Context, migrationID int64, updatedAt time.Time, status entity.MigrationStatus) error {
+	r.update(migrationID, func(migration *entity.MigrationInfo) {
+		migration.Status = status
+		migration.StatusUpdatedAt = updatedAt
+	})
+	return nil
+}
+
+func (r *memoryRepository) SetAppliedChecksum(_ context.Context, migrationID int64, checksum string) error {
+	r.update(migrationID, func(migration *entity.MigrationInfo) { migration.AppliedChecksum = checksum })
+	return nil
+}
+
+func (r *memoryRepository) SetLastError(_ context.Context, migrationID int64, scriptErr *entity.ScriptError) error {
+	r.update(migrationID, func(migration *entity.MigrationInfo) { migration.LastError = scriptErr })
+	return nil
+}
+
+func (r *memoryRepository) Baseline(_ context.Context, migrationID, userID int64, _ time.Time) error {
+	r.update(migrationID, func(migration *entity.MigrationInfo) {
+		migration.Status = entity.StatusApplied
+		migration.Baselined = true
+		migration.BaselinedBy = userID
+	})
+	return nil
+}
+
+func (r *memoryRepository) AddRevision(_ context.Context, _ entity.MigrationRevision) (int64, error) {
+	return 1, nil
+}
+
+func (r *memoryRepository) RecordOnTarget(_ context.Context, migration entity.MigrationInfo, status entity.MigrationStatus, _ time.Time) error {
+	r.onTarget[migration.ID] = status
+	return nil
+}
+
+func (r *memoryRepository) RecordedOnTarget(_ context.Context, migration entity.MigrationInfo, status entity.MigrationStatus) (bool, error) {
+	return r.onTarget[migration.ID] == status, nil
+}
+
+func (r *memoryRepository) ListTenants(_ context.Context, _ int64) ([]string, error) {
+	return r.tenants, nil
+}
+
+func (r *memoryRepository) LockTarget(_ context.Context, _ int64) (func(), error) {
+	return func() {}, nil
+}
+
+func (r *memoryRepository) ApplyToTenant(_ context.Context, _ entity.MigrationInfo, tenant, script string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failingTenants[tenant] {
+		return fmt.Errorf("script %q failed in tenant %s", script, tenant)
+	}
+	r.executed = append(r.executed, tenant+": "+script)
+	return nil
+}
+
+func (r *memoryRepository) SetTenantStatus(_ context.Context, status entity.TenantStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tenantStatuses[status.Tenant] = status
+	return nil
+}
+
+func (r *memoryRepository) ListTenantStatuses(_ context.Context, _ int64) ([]entity.TenantStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var statuses []entity.TenantStatus
+	for _, status := range r.tenantStatuses {
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// fakeLocker - блокировка целевой базы данных, сообщающая, удерживается ли она.
+type fakeLocker struct {
+	held bool
+}
+
+func (l *fakeLocker) Lock(ctx context.Context, _ int64, _ string) (context.Context, func(), error) {
+	l.held = true
+	return ctx, func() { l.held = false }, nil
+}
+
+type fakeHistory struct {
+	entries []entity.HistoryEntry
+}
+
+func (h *fakeHistory) Add(_ context.Context, entries []entity.HistoryEntry) error {
+	h.entries = append(h.entries, entries...)
+	return nil
+}
+
+func (h *fakeHistory) List(_ context.Context, _ entity.HistoryFilter) ([]entity.HistoryEntry, error) {
+	return h.entries, nil
+}
+
+// fakePromotion запрещает продвижение перечисленных миграций.
+type fakePromotion struct {
+	blocked map[int64]bool
+}
+
+func (p *fakePromotion) CheckPromotion(_ context.Context, _ int64, migrations []entity.MigrationInfo) error {
+	for _, migration := range migrations {
+		if p.blocked[migration.ID] {
+			return fmt.Errorf("%w: migration %d", entity.ErrPromotionBlocked, migration.ID)
+		}
+	}
+	return nil
+}
+
+// fakeSchemas запоминает, удерживалась ли блокировка при чтении схемы для снимка.
+type fakeSchemas struct {
+	locker    *fakeLocker
+	snapshots []bool
+}
+
+func (s *fakeSchemas) Introspect(_ context.Context, _ int64) ([]entity.SchemaObject, error) {
+	return nil, nil
+}
+
+func (s *fakeSchemas) AddSnapshot(_ context.Context, _ entity.SchemaSnapshot) (int64, error) {
+	s.snapshots = append(s.snapshots, s.locker.held)
+	return int64(len(s.snapshots)), nil
+}
+
+func (s *fakeSchemas) GetLatestSnapshot(_ context.Context, _ int64) (entity.SchemaSnapshot, error) {
+	return entity.SchemaSnapshot{}, entity.ErrNotFound
+}
+
+type fakeBackfills struct {
+	active []entity.Backfill
+}
+
+func (b *fakeBackfills) ListActive(_ context.Context, _ int64) ([]entity.Backfill, error) {
+	return b.active, nil
+}
+
+// testMigrator - сервис миграций поверх заглушек.
+type testMigrator struct {
+	*Migrator
+	repo      *memoryRepository
+	locker    *fakeLocker
+	promotion *fakePromotion
+	schemas   *fakeSchemas
+	backfills *fakeBackfills
+}
+
+func newTestMigrator(migrations ...entity.MigrationInfo) *testMigrator {
+	tm := &testMigrator{
+		repo:      newMemoryRepository(migrations...),
+		locker:    &fakeLocker{},
+		promotion: &fakePromotion{blocked: make(map[int64]bool)},
+		backfills: &fakeBackfills{},
+	}
+	tm.schemas = &fakeSchemas{locker: tm.locker}
+	tm.Migrator = New(tm.repo, tm.locker, &fakeHistory{}, nil, tm.promotion, nil, tm.schemas, tm.backfills, 1)
+	return tm
+}
+
+func (tm *testMigrator) status(migrationID int64) entity.MigrationStatus {
+	return tm.repo.migrations[migrationID].Status
+}
+
+func TestApplyMigration(t *testing.T) {
+	migrations := []entity.MigrationInfo{
+		{ID: 1, Name: "1_init", Script: "create a", Status: entity.StatusPending},
+		{ID: 2, Name: "2_users", Script: "create b", Status: entity.StatusPending},
+		{ID: 3, Name: "b_view", Script: "view b", Status: entity.StatusPending, Kind: entity.MigrationKindRepeatable},
+		{ID: 4, Name: "a_view", Script: "view a", Status: entity.StatusPending, Kind: entity.MigrationKindRepeatable},
+		{ID: 5, Name: "5_done", Script: "create c", Status: entity.StatusApplied},
+		{ID: 6, Name: "6_other", Script: "create d", Status: entity.StatusPending, TargetID: 2},
+	}
+
+	tests := []struct {
+		name         string
+		migrationIDs []int64
+		failing      string
+		wantApplied  []int64
+		wantExecuted []string
+		wantErr      bool
+		wantStatus   map[int64]entity.MigrationStatus
+	}{
+		{
+			name:         "repeatables after versioned in name order",
+			migrationIDs: []int64{3, 4, 2, 1},
+			wantApplied:  []int64{2, 1, 4, 3},
+			wantExecuted: []string{"create b", "create a", "view a", "view b"},
+			wantStatus:   map[int64]entity.MigrationStatus{1: entity.StatusApplied, 3: entity.StatusApplied},
+		},
+		{
+			name:         "pending repeatables are not added",
+			migrationIDs: []int64{1},
+			wantApplied:  []int64{1},
+			wantExecuted: []string{"create a"},
+			wantStatus:   map[int64]entity.MigrationStatus{3: entity.StatusPending, 4: entity.StatusPending},
+		},
+		{
+			name:         "failed script rolls back the batch",
+			migrationIDs: []int64{1, 2},
+			failing:      "create b",
+			wantErr:      true,
+			wantStatus:   map[int64]entity.MigrationStatus{1: entity.StatusPending, 2: entity.StatusFailed},
+		},
+		{
+			name:         "already applied migration",
+			migrationIDs: []int64{1, 5},
+			wantErr:      true,
+			wantStatus:   map[int64]entity.MigrationStatus{1: entity.StatusPending},
+		},
+		{
+			name:         "migration of another target",
+			migrationIDs: []int64{6},
+			wantErr:      true,
+			wantStatus:   map[int64]entity.MigrationStatus{6: entity.StatusPending},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := newTestMigrator(migrations...)
+			if tt.failing != "" {
+				tm.repo.failing[tt.failing] = true
+			}
+
+			applied, _, err := tm.ApplyMigration(context.Background(), testTarget, tt.migrationIDs, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyMigration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(applied, tt.wantApplied) {
+				t.Errorf("ApplyMigration() applied = %v, want %v", applied, tt.wantApplied)
+			}
+			if !reflect.DeepEqual(tm.repo.executed, tt.wantExecuted) {
+				t.Errorf("executed scripts = %q, want %q", tm.repo.executed, tt.wantExecuted)
+			}
+			for migrationID, want := range tt.wantStatus {
+				if got := tm.status(migrationID); got != want {
+					t.Errorf("migration %d status = %s, want %s", migrationID, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPendingRepeatableMigrations(t *testing.T) {
+	tampered := entity.MigrationInfo{ID: 6, Name: "c_tampered", Script: "view c", Status: entity.StatusPending, Kind: entity.MigrationKindRepeatable}
+
+	tests := []struct {
+		name         string
+		migrationIDs []int64
+		blocked      []int64
+		want         []int64
+	}{
+		{name: "pending repeatables in name order", migrationIDs: []int64{1}, want: []int64{4, 3}},
+		{name: "requested repeatables are not repeated", migrationIDs: []int64{1, 3}, want: []int64{4}},
+		{name: "blocked by promotion", migrationIDs: []int64{1}, blocked: []int64{4}, want: []int64{3}},
+		{name: "migration outside a transaction is applied alone", migrationIDs: []int64{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := newTestMigrator(
+				entity.MigrationInfo{ID: 1, Name: "1_init", Script: "create a", Status: entity.StatusPending},
+				entity.MigrationInfo{ID: 2, Name: "2_index", Script: "create index", Status: entity.StatusPending, ExecutionMode: entity.ExecutionModeNoTransaction},
+				entity.MigrationInfo{ID: 3, Name: "b_view", Script: "view b", Status: entity.StatusPending, Kind: entity.MigrationKindRepeatable},
+				entity.MigrationInfo{ID: 4, Name: "a_view", Script: "view a", Status: entity.StatusPending, Kind: entity.MigrationKindRepeatable},
+				entity.MigrationInfo{ID: 5, Name: "0_applied", Script: "view d", Status: entity.StatusApplied, Kind: entity.MigrationKindRepeatable},
+				tampered,
+			)
+			tm.repo.update(tampered.ID, func(migration *entity.MigrationInfo) { migration.Script = "drop everything" })
+			for _, migrationID := range tt.blocked {
+				tm.promotion.blocked[migrationID] = true
+			}
+
+			pending, err := tm.PendingRepeatableMigrations(context.Background(), testTarget, tt.migrationIDs)
+			if err != nil {
+				t.Fatalf("PendingRepeatableMigrations() error = %v", err)
+			}
+
+			var got []int64
+			for _, migration := range pending {
+				got = append(got, migration.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PendingRepeatableMigrations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMigrationPromotionBlocked(t *testing.T) {
+	tm := newTestMigrator(entity.MigrationInfo{ID: 1, Name: "1_init", Script: "create a", Status: entity.StatusPending})
+	tm.promotion.blocked[1] = true
+
+	_, _, err := tm.ApplyMigration(context.Background(), testTarget, []int64{1}, 1)
+	if !errors.Is(err, entity.ErrPromotionBlocked) {
+		t.Fatalf("ApplyMigration() error = %v, want ErrPromotionBlocked", err)
+	}
+	if len(tm.repo.executed) != 0 {
+		t.Errorf("executed scripts = %q, want none", tm.repo.executed)
+	}
+}
+
+func TestRollbackToMigration(t *testing.T) {
+	tests := []struct {
+		name                string
+		anchor              entity.MigrationStatus
+		nonTransactionalDDL bool
+		wantRolledBack      []int64
+		wantErr             error
+	}{
+		{name: "applied anchor", anchor: entity.StatusApplied, wantRolledBack: []int64{3, 2}},
+		{name: "partially applied anchor", anchor: entity.StatusPartiallyApplied, wantRolledBack: []int64{3, 2}},
+		{name: "partially applied anchor without transactional DDL", anchor: entity.StatusPartiallyApplied, nonTransactionalDDL: true, wantRolledBack: []int64{3, 2}},
+		{name: "pending anchor", anchor: entity.StatusPending, wantErr: entity.ErrFailedPrecondition},
+		{name: "pending anchor without transactional DDL", anchor: entity.StatusPending, nonTransactionalDDL: true, wantErr: entity.ErrFailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := newTestMigrator(
+				entity.MigrationInfo{ID: 1, Name: "1_index", Script: "create index", RollbackScript: "drop index", Status: tt.anchor, ExecutionMode: entity.ExecutionModeNoTransaction},
+				entity.MigrationInfo{ID: 2, Name: "2_users", Script: "create b", RollbackScript: "drop b", Status: entity.StatusApplied},
+				entity.MigrationInfo{ID: 3, Name: "3_orders", Script: "create c", RollbackScript: "drop c", Status: entity.StatusApplied},
+			)
+			tm.repo.nonTransactionalDDL = tt.nonTransactionalDDL
+
+			rolledBack, _, err := tm.RollbackToMigration(context.Background(), testTarget, 1, 1, nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RollbackToMigration() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(tm.repo.executed) != 0 {
+					t.Errorf("executed scripts = %q, want none", tm.repo.executed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RollbackToMigration() error = %v", err)
+			}
+			if !reflect.DeepEqual(rolledBack, tt.wantRolledBack) {
+				t.Errorf("RollbackToMigration() rolled back = %v, want %v", rolledBack, tt.wantRolledBack)
+			}
+			if want := []string{"drop c", "drop b"}; !reflect.DeepEqual(tm.repo.executed, want) {
+				t.Errorf("executed scripts = %q, want %q", tm.repo.executed, want)
+			}
+			if got := tm.status(1); got != tt.anchor {
+				t.Errorf("anchor status = %s, want %s", got, tt.anchor)
+			}
+		})
+	}
+}
+
+func TestApplyMigrationAfterMetadataCommitFailure(t *testing.T) {
+	tm := newTestMigrator(
+		entity.MigrationInfo{ID: 1, Name: "1_init", Script: "create a", RollbackScript: "drop a", Status: entity.StatusPending},
+		entity.MigrationInfo{ID: 2, Name: "2_users", Script: "create b", RollbackScript: "drop b", Status: entity.StatusPending},
+	)
+	tm.repo.failMetadataCommit = true
+
+	if _, _, err := tm.ApplyMigration(context.Background(), testTarget, []int64{1, 2}, 1); err == nil {
+		t.Fatal("ApplyMigration() error = nil, want the metadata commit error")
+	}
+	if got := tm.status(1); got != entity.StatusPending {
+		t.Fatalf("migration 1 status = %s, want %s", got, entity.StatusPending)
+	}
+
+	tm.repo.failMetadataCommit = false
+
+	applied, _, err := tm.ApplyMigration(context.Background(), testTarget, []int64{1, 2}, 1)
+	if err != nil {
+		t.Fatalf("ApplyMigration() error = %v", err)
+	}
+	if !reflect.DeepEqual(applied, []int64{1, 2}) {
+		t.Errorf("ApplyMigration() applied = %v, want [1 2]", applied)
+	}
+	if want := []string{"create a", "create b"}; !reflect.DeepEqual(tm.repo.executed, want) {
+		t.Errorf("executed scripts = %q, want %q", tm.repo.executed, want)
+	}
+	for _, migrationID := range []int64{1, 2} {
+		if got := tm.status(migrationID); got != entity.StatusApplied {
+			t.Errorf("migration %d status = %s, want %s", migrationID, got, entity.StatusApplied)
+		}
+	}
+
+	tm.repo.failMetadataCommit = true
+	if _, err := tm.RollbackMigration(context.Background(), testTarget, 2, 1); err == nil {
+		t.Fatal("RollbackMigration() error = nil, want the metadata commit error")
+	}
+	tm.repo.failMetadataCommit = false
+
+	if _, err := tm.RollbackMigration(context.Background(), testTarget, 2, 1); err != nil {
+		t.Fatalf("RollbackMigration() error = %v", err)
+	}
+	if want := []string{"create a", "create b", "drop b"}; !reflect.DeepEqual(tm.repo.executed, want) {
+		t.Errorf("executed scripts = %q, want %q", tm.repo.executed, want)
+	}
+	if got := tm.status(2); got != entity.StatusRolledBack {
+		t.Errorf("migration 2 status = %s, want %s", got, entity.StatusRolledBack)
+	}
+}
+
+func TestSnapshotTakenUnderLock(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(tm *testMigrator) error
+	}{
+		{
+			name: "apply",
+			run: func(tm *testMigrator) error {
+				_, _, err := tm.ApplyMigration(context.Background(), testTarget, []int64{3}, 1)
+				return err
+			},
+		},
+		{
+			name: "rollback",
+			run: func(tm *testMigrator) error {
+				_, err := tm.RollbackMigration(context.Background(), testTarget, 2, 1)
+				return err
+			},
+		},
+		{
+			name: "rollback to",
+			run: func(tm *testMigrator) error {
+				_, _, err := tm.RollbackToMigration(context.Background(), testTarget, 1, 1, nil)
+				return err
+			},
+		},
+		{
+			name: "baseline",
+			run: func(tm *testMigrator) error {
+				_, _, err := tm.BaselineMigrations(context.Background(), testTarget, []int64{3}, 0, 0, 1)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := newTestMigrator(
+				entity.MigrationInfo{ID: 1, Name: "1_init", Script: "create a", RollbackScript: "drop a", Status: entity.StatusApplied},
+				entity.MigrationInfo{ID: 2, Name: "2_users", Script: "create b", RollbackScript: "drop b", Status: entity.StatusApplied},
+				entity.MigrationInfo{ID: 3, Name: "3_orders", Script: "create c", RollbackScript: "drop c", Status: entity.StatusPending},
+			)
+
+			if err := tt.run(tm); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !reflect.DeepEqual(tm.schemas.snapshots, []bool{true}) {
+				t.Errorf("snapshots taken with the lock held = %v, want [true]", tm.schemas.snapshots)
+			}
+			if tm.locker.held {
+				t.Error("lock is not released")
+			}
+		})
+	}
+}
+
+func TestRollbackRefusedWhileBackfillActive(t *testing.T) {
+	tm := newTestMigrator(
+		entity.MigrationInfo{ID: 1, Name: "1_init", Script: "create a", RollbackScript: "drop a", Status: entity.StatusApplied},
+		entity.MigrationInfo{ID: 2, Name: "2_users", Script: "create b", RollbackScript: "drop b", Status: entity.StatusApplied},
+	)
+	tm.backfills.active = []entity.Backfill{{MigrationID: 3, TargetID: testTarget, State: entity.BackfillStatePaused}}
+
+	if _, err := tm.RollbackMigration(context.Background(), testTarget, 2, 1); !errors.Is(err, entity.ErrBackfillActive) {
+		t.Errorf("RollbackMigration() error = %v, want ErrBackfillActive", err)
+	}
+	if _, _, err := tm.RollbackToMigration(context.Background(), testTarget, 1, 1, nil); !errors.Is(err, entity.ErrBackfillActive) {
+		t.Errorf("RollbackToMigration() error = %v, want ErrBackfillActive", err)
+	}
+	if len(tm.repo.executed) != 0 {
+		t.Errorf("executed scripts = %q, want none", tm.repo.executed)
+	}
+}
+
+func TestApplyMigrationOutsideTransaction(t *testing.T) {
+	index := entity.MigrationInfo{
+		ID: 1, Name: "1_index", Script: "create index a; create index b", RollbackScript: "drop index b; drop index a",
+		Status: entity.StatusPending, ExecutionMode: entity.ExecutionModeNoTransaction,
+	}
+	users := entity.MigrationInfo{ID: 2, Name: "2_users", Script: "create users", RollbackScript: "drop users", Status: entity.StatusPending}
+
+	tests := []struct {
+		name                string
+		migrationIDs        []int64
+		nonTransactionalDDL bool
+		failing             string
+		wantErr             error
+		wantExecuted        []string
+		wantStatus          map[int64]entity.MigrationStatus
+		wantLastError       bool
+	}{
+		{
+			name:         "applied on its own",
+			migrationIDs: []int64{1},
+			wantExecuted: []string{"create index a", "create index b"},
+			wantStatus:   map[int64]entity.MigrationStatus{1: entity.StatusApplied},
+		},
+		{
+			name:          "fails after the first statement",
+			migrationIDs:  []int64{1},
+			failing:       "create index b",
+			wantExecuted:  []string{"create index a"},
+			wantStatus:    map[int64]entity.MigrationStatus{1: entity.StatusPartiallyApplied},
+			wantLastError: true,
+		},
+		{
+			name:          "fails on the first statement",
+			migrationIDs:  []int64{1},
+			failing:       "create index a",
+			wantStatus:    map[int64]entity.MigrationStatus{1: entity.StatusFailed},
+			wantLastError: true,
+		},
+		{
+			name:         "requested with other migrations",
+			migrationIDs: []int64{2, 1},
+			wantErr:      entity.ErrInvalidArgument,
+			wantStatus:   map[int64]entity.MigrationStatus{1: entity.StatusPending, 2: entity.StatusPending},
+		},
+		{
+			name:                "each migration on its own without transactional DDL",
+			migrationIDs:        []int64{2, 1},
+			nonTransactionalDDL: true,
+			failing:             "create index b",
+			wantExecuted:        []string{"create users", "create index a"},
+			wantStatus:          map[int64]entity.MigrationStatus{1: entity.StatusPartiallyApplied, 2: entity.StatusApplied},
+			wantLastError:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := newTestMigrator(index, users)
+			tm.repo.nonTransactionalDDL = tt.nonTransactionalDDL
+			if tt.failing != "" {
+				tm.repo.failing[tt.failing] = true
+			}
+
+			_, _, err := tm.ApplyMigration(context.Background(), testTarget, tt.migrationIDs, 1)
+			switch {
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("ApplyMigration() error = %v, want %v", err, tt.wantErr)
+			case tt.wantErr == nil && (err != nil) != (tt.failing != ""):
+				t.Fatalf("ApplyMigration() error = %v, want error %v", err, tt.failing != "")
+			}
+			if !reflect.DeepEqual(tm.repo.executed, tt.wantExecuted) {
+				t.Errorf("executed statements = %q, want %q", tm.repo.executed, tt.wantExecuted)
+			}
+			for migrationID, want := range tt.wantStatus {
+				if got := tm.status(migrationID); got != want {
+					t.Errorf("migration %d status = %s, want %s", migrationID, got, want)
+				}
+			}
+			if got := tm.repo.migrations[1].LastError != nil; got != tt.wantLastError {
+				t.Errorf("migration 1 has last error = %v, want %v", got, tt.wantLastError)
+			}
+		})
+	}
+}
+
+func TestRollbackPartiallyAppliedMigration(t *testing.T) {
+	tm := newTestMigrator(entity.MigrationInfo{
+		ID: 1, Name: "1_index", Script: "create index a; create index b", RollbackScript: "drop index b; drop index a",
+		Status: entity.StatusPartiallyApplied, ExecutionMode: entity.ExecutionModeNoTransaction,
+	})
+	tm.repo.failing["drop index b"] = true
+
+	// Откат частично примененной миграции тоже может прерваться; она остается частично примененной
+	if _, err := tm.RollbackMigration(context.Background(), testTarget, 1, 1); err == nil {
+		t.Fatal("RollbackMigration() error = nil, want the statement error")
+	}
+	if got := tm.status(1); got != entity.StatusPartiallyApplied {
+		t.Fatalf("status = %s, want %s", got, entity.StatusPartiallyApplied)
+	}
+
+	delete(tm.repo.failing, "drop index b")
+	if _, err := tm.RollbackMigration(context.Background(), testTarget, 1, 1); err != nil {
+		t.Fatalf("RollbackMigration() error = %v", err)
+	}
+	if got := tm.status(1); got != entity.StatusRolledBack {
+		t.Errorf("status = %s, want %s", got, entity.StatusRolledBack)
+	}
+	if want := []string{"drop index b", "drop index a"}; !reflect.DeepEqual(tm.repo.executed, want) {
+		t.Errorf("executed statements = %q, want %q", tm.repo.executed, want)
+	}
+}
+
+func TestApplyTenantMigration(t *testing.T) {
+	tm := newTestMigrator(entity.MigrationInfo{
+		ID: 1, Name: "1_orders", Script: "create orders", Status: entity.StatusPending, TenantScoped: true,
+	})
+	tm.repo.tenants = []string{"tenant_a", "tenant_b", "tenant_c"}
+	tm.repo.failingTenants["tenant_b"] = true
+
+	// Ошибка в одной схеме не останавливает остальные, а миграция получает статус failed
+	result, err := tm.ApplyTenantMigration(context.Background(), testTarget, 1, nil, 2, 1)
+	if err != nil {
+		t.Fatalf("ApplyTenantMigration() error = %v", err)
+	}
+	sort.Strings(result.Applied)
+	if want := []string{"tenant_a", "tenant_c"}; !reflect.DeepEqual(result.Applied, want) {
+		t.Errorf("applied tenants = %q, want %q", result.Applied, want)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Tenant != "tenant_b" || result.Failed[0].Status != entity.StatusFailed {
+		t.Errorf("failed tenants = %+v, want tenant_b", result.Failed)
+	}
+	if got := tm.status(1); got != entity.StatusFailed {
+		t.Fatalf("status = %s, want %s", got, entity.StatusFailed)
+	}
+	if got := tm.repo.tenantStatuses["tenant_b"].Status; got != entity.StatusFailed {
+		t.Errorf("tenant_b status = %s, want %s", got, entity.StatusFailed)
+	}
+
+	// Повторный вызов применяет миграцию только к схеме, в которой она завершилась ошибкой
+	delete(tm.repo.failingTenants, "tenant_b")
+	tm.repo.executed = nil
+
+	result, err = tm.ApplyTenantMigration(context.Background(), testTarget, 1, nil, 2, 1)
+	if err != nil {
+		t.Fatalf("second ApplyTenantMigration() error = %v", err)
+	}
+	if want := []string{"tenant_b"}; !reflect.DeepEqual(result.Applied, want) {
+		t.Errorf("applied tenants = %q, want %q", result.Applied, want)
+	}
+	if want := []string{"tenant_a", "tenant_c"}; !reflect.DeepEqual(result.Skipped, want) {
+		t.Errorf("skipped tenants = %q, want %q", result.Skipped, want)
+	}
+	if want := []string{"tenant_b: create orders"}; !reflect.DeepEqual(tm.repo.executed, want) {
+		t.Errorf("executed scripts = %q, want %q", tm.repo.executed, want)
+	}
+	if got := tm.status(1); got != entity.StatusApplied {
+		t.Errorf("status = %s, want %s", got, entity.StatusApplied)
+	}
+}
+
+func TestApplyTenantMigrationRejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		migration entity.MigrationInfo
+		tenants   []string
+		wantErr   error
+	}{
+		{
+			name:      "not tenant-scoped",
+			migration: entity.MigrationInfo{ID: 1, Script: "create orders", Status: entity.StatusPending},
+			wantErr:   entity.ErrInvalidArgument,
+		},
+		{
+			name:      "rolled back",
+			migration: entity.MigrationInfo{ID: 1, Script: "create orders", Status: entity.StatusRolledBack, TenantScoped: true},
+			wantErr:   entity.ErrFailedPrecondition,
+		},
+		{
+			name:      "unknown tenant",
+			migration: entity.MigrationInfo{ID: 1, Script: "create orders", Status: entity.StatusPending, TenantScoped: true},
+			tenants:   []string{"tenant_z"},
+			wantErr:   entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := newTestMigrator(tt.migration)
+			tm.repo.tenants = []string{"tenant_a"}
+
+			_, err := tm.ApplyTenantMigration(context.Background(), testTarget, 1, tt.tenants, 0, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApplyTenantMigration() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(tm.repo.executed) != 0 {
+				t.Errorf("executed scripts = %q, want none", tm.repo.executed)
+			}
+		})
+	}
+}
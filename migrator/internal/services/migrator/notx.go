@@ -0,0 +1,268 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+)
+
+// applyOutsideTransaction применяет миграцию без транзакции: операторы скрипта
+// выполняются по одному, а статус меняется отдельными запросами. На время
+// выполнения миграция помечается частично примененной, чтобы аварийное
+// завершение реплики не оставило ее в статусе pending. Если ни один оператор
+// не выполнен, миграция получает статус failed.
+func (m *Migrator) applyOutsideTransaction(ctx context.Context, targetID int64, migration entity.MigrationInfo, history *execLog) (time.Time, error) {
+	if err := checkApplicable(targetID, migration); err != nil {
+		return time.Time{}, err
+	}
+
+	script, err := m.renderScript(ctx, targetID, migration, migration.Script, history)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	statements, err := m.repo.CountStatements(ctx, targetID, script)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.CountStatements: %w", err)
+	}
+	if statements == 0 {
+		return time.Time{}, fmt.Errorf("%w: migration %d has no statements", entity.ErrInvalidArgument, migration.ID)
+	}
+
+	err = m.repo.SetStatus(ctx, migration.ID, time.Now(), entity.StatusPartiallyApplied)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.SetStatus: %w", err)
+	}
+
+	history.start(migration.ID)
+	executed, err := m.repo.ApplyOutsideTransaction(ctx, targetID, script)
+	if err != nil {
+		if executed == 0 {
+			history.finish(migration.ID, entity.OutcomeFailed, err)
+			m.recordFailure(ctx, migration.ID, entity.StatusFailed, err)
+			return time.Time{}, fmt.Errorf("m.repo.ApplyOutsideTransaction: %w", err)
+		}
+		err = fmt.Errorf("migration %d is partially applied (%d of %d statements): %w",
+			migration.ID, executed, statements, err)
+		history.finish(migration.ID, entity.OutcomePartiallyApplied, err)
+		m.recordFailure(ctx, migration.ID, "", err)
+		return time.Time{}, err
+	}
+
+	appliedAt := time.Now()
+
+	err = m.repo.SetStatus(ctx, migration.ID, appliedAt, entity.StatusApplied)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.SetStatus: %w", err)
+	}
+
+	err = m.repo.SetAppliedChecksum(ctx, migration.ID, migration.Checksum)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.SetAppliedChecksum: %w", err)
+	}
+
+	err = m.repo.SetLastError(ctx, migration.ID, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.SetLastError: %w", err)
+	}
+
+	err = m.addRevision(ctx, migration, script, history.userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	history.finish(migration.ID, entity.OutcomeSucceeded, nil)
+
+	m.recordOnTarget(ctx, migration, entity.StatusApplied, appliedAt)
+
+	return appliedAt, nil
+}
+
+// rollbackOutsideTransaction откатывает миграцию без транзакции. Если откат
+// прерывается после выполнения части операторов, миграция остается
+// частично примененной.
+func (m *Migrator) rollbackOutsideTransaction(ctx context.Context, targetID int64, migration entity.MigrationInfo, history *execLog) (time.Time, error) {
+	if err := m.checkRollback(ctx, targetID, migration); err != nil {
+		return time.Time{}, err
+	}
+
+	script, err := m.renderScript(ctx, targetID, migration, migration.RollbackScript, history)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	statements, err := m.repo.CountStatements(ctx, targetID, script)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.CountStatements: %w", err)
+	}
+
+	history.start(migration.ID)
+	executed, err := m.repo.ApplyOutsideTransaction(ctx, targetID, script)
+	if err != nil {
+		err = fmt.Errorf("migration %d rollback failed after %d of %d statements: %w",
+			migration.ID, executed, statements, err)
+		outcome := entity.OutcomeFailed
+		if executed > 0 {
+			outcome = entity.OutcomePartiallyApplied
+		}
+		history.finish(migration.ID, outcome, err)
+
+		var status entity.MigrationStatus
+		if executed > 0 {
+			status = entity.StatusPartiallyApplied
+		}
+		m.recordFailure(ctx, migration.ID, status, err)
+		return time.Time{}, err
+	}
+
+	rolledBackAt := time.Now()
+
+	err = m.repo.SetStatus(ctx, migration.ID, rolledBackAt, entity.StatusRolledBack)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.SetStatus: %w", err)
+	}
+
+	err = m.repo.SetLastError(ctx, migration.ID, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("m.repo.SetLastError: %w", err)
+	}
+	history.finish(migration.ID, entity.OutcomeSucceeded, nil)
+
+	m.recordOnTarget(ctx, migration, entity.StatusRolledBack, rolledBackAt)
+
+	return rolledBackAt, nil
+}
+
+// applyEachOutsideTransaction применяет миграции по одной вне транзакции на целевых
+// базах данных, которые не откатывают DDL: транзакция не защитила бы от частичного
+// применения, поэтому статус каждой миграции фиксируется сразу после ее выполнения.
+// Все миграции, включая подстановку переменных шаблонов, проверяются до выполнения
+// первой, применение останавливается на первой ошибке.
+func (m *Migrator) applyEachOutsideTransaction(ctx context.Context, targetID int64, migrationIDs []int64, history *execLog) (time.Time, error) {
+	migrations := make([]entity.MigrationInfo, 0, len(migrationIDs))
+	for _, migrationID := range migrationIDs {
+		migration, err := m.repo.Get(ctx, migrationID)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("m.repo.GetMigration: %w", err)
+		}
+
+		if err := checkApplicable(targetID, migration); err != nil {
+			return time.Time{}, err
+		}
+
+		if _, err := m.renderScript(ctx, targetID, migration, migration.Script, nil); err != nil {
+			return time.Time{}, err
+		}
+
+		migrations = append(migrations, migration)
+	}
+
+	var appliedAt time.Time
+	for _, migration := range migrations {
+		var err error
+		appliedAt, err = m.applyOutsideTransaction(ctx, targetID, migration, history)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return appliedAt, nil
+}
+
+// rollbackEachOutsideTransaction откатывает миграции, примененные после указанной,
+// по одной вне транзакции в обратном порядке. Права и контрольные суммы
+// проверяются для всех миграций до отката первой.
+func (m *Migrator) rollbackEachOutsideTransaction(
+	ctx context.Context,
+	targetID, migrationID int64,
+	authorize func(ctx context.Context, migration entity.MigrationInfo) error,
+	history *execLog,
+) ([]int64, time.Time, error) {
+	migration, err := m.repo.Get(ctx, migrationID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("m.repo.GetMigration: %w", err)
+	}
+
+	if migration.TargetID != targetID {
+		return nil, time.Time{}, fmt.Errorf("%w: migration %d does not belong to target %d", entity.ErrInvalidArgument, migrationID, targetID)
+	}
+
+	if !migration.Status.Applied() {
+		return nil, time.Time{}, fmt.Errorf("%w: migration %d is not applied", entity.ErrFailedPrecondition, migrationID)
+	}
+
+	if err := m.checkBackfills(ctx, targetID); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	migrations, err := m.repo.ListAppliedAfter(ctx, targetID, migrationID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("m.repo.ListAppliedAfter: %w", err)
+	}
+
+	for _, migration := range migrations {
+		if migration.TenantScoped {
+			return nil, time.Time{}, tenantScopedError(migration.ID)
+		}
+
+		if authorize != nil {
+			if err := authorize(ctx, migration); err != nil {
+				return nil, time.Time{}, err
+			}
+		}
+
+		if err := migration.VerifyChecksum(); err != nil {
+			return nil, time.Time{}, err
+		}
+
+		if _, err := m.renderScript(ctx, targetID, migration, migration.RollbackScript, nil); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+
+	var (
+		rolledBack   []int64
+		rolledBackAt time.Time
+	)
+	for _, migration := range migrations {
+		rolledBackAt, err = m.rollbackOutsideTransaction(ctx, targetID, migration, history)
+		if err != nil {
+			return rolledBack, time.Time{}, err
+		}
+		rolledBack = append(rolledBack, migration.ID)
+	}
+
+	return rolledBack, rolledBackAt, nil
+}
+
+// checkApplicable проверяет, что миграцию можно применить к целевой базе данных.
+func checkApplicable(targetID int64, migration entity.MigrationInfo) error {
+	if migration.TargetID != targetID {
+		return fmt.Errorf("%w: migration %d does not belong to target %d", entity.ErrInvalidArgument, migration.ID, targetID)
+	}
+
+	if !migration.Status.Applicable() {
+		return fmt.Errorf("%w: migration %d cannot be applied in status %s", entity.ErrFailedPrecondition, migration.ID, migration.Status)
+	}
+
+	if migration.TenantScoped {
+		return tenantScopedError(migration.ID)
+	}
+
+	if migration.Kind == entity.MigrationKindBackfill {
+		return backfillApplyError(migration.ID)
+	}
+
+	return migration.VerifyChecksum()
+}
+
+// recordOnTarget отражает состояние миграции, выполненной вне транзакции,
+// в таблице истории целевой базы данных. Миграция к этому моменту уже выполнена,
+// поэтому ошибка только логируется.
+func (m *Migrator) recordOnTarget(ctx context.Context, migration entity.MigrationInfo, status entity.MigrationStatus, updatedAt time.Time) {
+	if err := m.repo.RecordOnTarget(ctx, migration, status, updatedAt); err != nil {
+		logger.Error(fmt.Errorf("record migration %d on target: %w", migration.ID, err))
+	}
+}
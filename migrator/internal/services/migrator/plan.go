@@ -0,0 +1,252 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+// errPlanRollback прерывает транзакцию плана, чтобы откатить все сделанные изменения.
+var errPlanRollback = errors.New("plan is always rolled back")
+
+// nonTransactionalReason - причина отклонения плана на целевой базе данных,
+// которая не откатывает DDL (MySQL): пробное применение изменило бы схему.
+const nonTransactionalReason = "target database does not roll back DDL, so the migration cannot be planned"
+
+// PlanApplyMigration пробно применяет миграции: скрипты выполняются в транзакции,
+// которая всегда откатывается, а по каждой миграции возвращается отчет.
+// В отличие от ApplyMigration, выполнение не прерывается на первой ошибке:
+// каждая миграция выполняется в своей точке сохранения, а повторяемые миграции
+// выполняются после версионных, как при применении. На целевых базах данных,
+// которые не откатывают DDL, все миграции отклоняются.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	migrationIDs: []int64 - Уникальные идентификаторы миграций в соответствии с порядком применения.
+//	userID: int64 - Идентификатор пользователя, запросившего план.
+//
+// Возвращает:
+//
+//	entity.Plan: Отчет о пробном применении.
+//	error: Ошибка, если план не удалось построить.
+func (m *Migrator) PlanApplyMigration(ctx context.Context, targetID int64, migrationIDs []int64, userID int64) (entity.Plan, error) {
+	plan := entity.Plan{
+		Action:   entity.PlanActionApply,
+		TargetID: targetID,
+	}
+
+	migrationIDs, err := m.applyOrder(ctx, migrationIDs)
+	if err != nil {
+		return entity.Plan{}, err
+	}
+
+	transactional, err := m.repo.TransactionalDDL(ctx, targetID)
+	if err != nil {
+		return entity.Plan{}, fmt.Errorf("m.repo.TransactionalDDL: %w", err)
+	}
+	if !transactional {
+		for i, migrationID := range migrationIDs {
+			item := entity.PlanItem{MigrationID: migrationID, Order: i + 1}
+			plan.Items = append(plan.Items, reject(item, nonTransactionalReason))
+		}
+		return plan, nil
+	}
+
+	err = m.repo.DoInTransaction(ctx, targetID, func(ctx context.Context) error {
+		for i, migrationID := range migrationIDs {
+			item := entity.PlanItem{
+				MigrationID: migrationID,
+				Order:       i + 1,
+			}
+
+			migration, err := m.repo.Get(ctx, migrationID)
+			if err != nil {
+				if !errors.Is(err, entity.ErrNotFound) {
+					return fmt.Errorf("m.repo.GetMigration: %w", err)
+				}
+				plan.Items = append(plan.Items, reject(item, "migration not found"))
+				continue
+			}
+
+			item.Name = migration.Name
+			item.Status = migration.Status
+			checksumErr := migration.VerifyChecksum()
+
+			switch {
+			case migration.TargetID != targetID:
+				item = reject(item, fmt.Sprintf("migration does not belong to target %d", targetID))
+			case !migration.Status.Applicable():
+				item = reject(item, fmt.Sprintf("migration cannot be applied in status %s", migration.Status))
+			case migration.ExecutionMode == entity.ExecutionModeNoTransaction:
+				item = reject(item, "migration runs outside a transaction and cannot be planned")
+			case migration.TenantScoped:
+				item = reject(item, "migration is tenant-scoped and cannot be planned")
+			case migration.Kind == entity.MigrationKindBackfill:
+				item = reject(item, "backfill migration runs in batches and cannot be planned")
+			case checksumErr != nil:
+				item = reject(item, checksumErr.Error())
+			default:
+				err := m.promotion.CheckPromotion(ctx, targetID, []entity.MigrationInfo{migration})
+				if errors.Is(err, entity.ErrPromotionBlocked) {
+					item = reject(item, err.Error())
+					break
+				}
+				if err != nil {
+					return fmt.Errorf("m.promotion.CheckPromotion: %w", err)
+				}
+
+				script, err := m.renderScript(ctx, targetID, migration, migration.Script, nil)
+				if errors.Is(err, entity.ErrTemplate) {
+					item = reject(item, err.Error())
+					break
+				}
+				if err != nil {
+					return err
+				}
+
+				item = m.tryApply(ctx, item, script)
+				if item.Success {
+					err = m.repo.SetStatus(ctx, migration.ID, time.Now(), entity.StatusApplied)
+					if err != nil {
+						return fmt.Errorf("m.repo.SetStatus: %w", err)
+					}
+				}
+			}
+
+			plan.Items = append(plan.Items, item)
+		}
+
+		return errPlanRollback
+	})
+	if err != nil && !errors.Is(err, errPlanRollback) {
+		return entity.Plan{}, fmt.Errorf("m.repo.DoInTransaction: %w", err)
+	}
+
+	return plan, nil
+}
+
+// PlanRollbackMigration пробно откатывает миграцию в транзакции,
+// которая всегда откатывается.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	migrationID: int64 - Уникальный идентификатор миграции для отката.
+//	userID: int64 - Идентификатор пользователя, запросившего план.
+//
+// Возвращает:
+//
+//	entity.Plan: Отчет о пробном откате.
+//	error: Ошибка, если план не удалось построить.
+func (m *Migrator) PlanRollbackMigration(ctx context.Context, targetID, migrationID, userID int64) (entity.Plan, error) {
+	plan := entity.Plan{
+		Action:   entity.PlanActionRollback,
+		TargetID: targetID,
+	}
+
+	transactional, err := m.repo.TransactionalDDL(ctx, targetID)
+	if err != nil {
+		return entity.Plan{}, fmt.Errorf("m.repo.TransactionalDDL: %w", err)
+	}
+	if !transactional {
+		item := entity.PlanItem{MigrationID: migrationID, Order: 1}
+		plan.Items = append(plan.Items, reject(item, nonTransactionalReason))
+		return plan, nil
+	}
+
+	err = m.repo.DoInTransaction(ctx, targetID, func(ctx context.Context) error {
+		item := entity.PlanItem{
+			MigrationID: migrationID,
+			Order:       1,
+		}
+
+		migration, err := m.repo.Get(ctx, migrationID)
+		if err != nil {
+			if !errors.Is(err, entity.ErrNotFound) {
+				return fmt.Errorf("m.repo.GetMigration: %w", err)
+			}
+			plan.Items = append(plan.Items, reject(item, "migration not found"))
+			return errPlanRollback
+		}
+
+		item.Name = migration.Name
+		item.Status = migration.Status
+		checksumErr := migration.VerifyChecksum()
+
+		latestAppliedMigration, err := m.repo.GetLatestAppliedMigration(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("m.repo.GetLatestAppliedMigration: %w", err)
+		}
+
+		backfillErr := m.checkBackfills(ctx, targetID)
+		if backfillErr != nil && !errors.Is(backfillErr, entity.ErrBackfillActive) {
+			return backfillErr
+		}
+
+		switch {
+		case migration.TargetID != targetID:
+			item = reject(item, fmt.Sprintf("migration does not belong to target %d", targetID))
+		case !migration.Status.Applied():
+			item = reject(item, fmt.Sprintf("migration is not applied: %s", migration.Status))
+		case migration.ExecutionMode == entity.ExecutionModeNoTransaction:
+			item = reject(item, "migration runs outside a transaction and cannot be planned")
+		case migration.TenantScoped:
+			item = reject(item, "migration is tenant-scoped and cannot be planned")
+		case migration.Kind == entity.MigrationKindRepeatable:
+			item = reject(item, "repeatable migrations cannot be rolled back")
+		case migration.Kind == entity.MigrationKindBackfill:
+			item = reject(item, "backfill migrations cannot be rolled back")
+		case latestAppliedMigration.ID != migrationID:
+			item = reject(item, fmt.Sprintf("not last migration, latest applied is %d", latestAppliedMigration.ID))
+		case checksumErr != nil:
+			item = reject(item, checksumErr.Error())
+		case backfillErr != nil:
+			item = reject(item, backfillErr.Error())
+		default:
+			script, err := m.renderScript(ctx, targetID, migration, migration.RollbackScript, nil)
+			if errors.Is(err, entity.ErrTemplate) {
+				item = reject(item, err.Error())
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			item = m.tryApply(ctx, item, script)
+		}
+
+		plan.Items = append(plan.Items, item)
+
+		return errPlanRollback
+	})
+	if err != nil && !errors.Is(err, errPlanRollback) {
+		return entity.Plan{}, fmt.Errorf("m.repo.DoInTransaction: %w", err)
+	}
+
+	return plan, nil
+}
+
+func (m *Migrator) tryApply(ctx context.Context, item entity.PlanItem, script string) entity.PlanItem {
+	start := time.Now()
+	rowsAffected, err := m.repo.TryApply(ctx, script)
+	item.Duration = time.Since(start)
+
+	if err != nil {
+		item.Error = err.Error()
+		return item
+	}
+
+	item.Success = true
+	item.RowsAffected = rowsAffected
+	return item
+}
+
+func reject(item entity.PlanItem, reason string) entity.PlanItem {
+	item.Rejected = true
+	item.RejectReason = reason
+	return item
+}
@@ -0,0 +1,263 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"migrator/internal/entity"
+)
+
+// checkKind проверяет, что вид миграции совместим с остальными ее параметрами:
+// повторяемая миграция и миграция данных выполняются в транзакции, не откатываются
+// и не применяются к схемам арендаторов, а параметры пачек задаются только
+// у миграции данных.
+func checkKind(kind entity.MigrationKind, executionMode entity.ExecutionMode, rollbackScript string, tenantScoped bool, backfill *entity.BackfillSpec) error {
+	if !kind.Valid() {
+		return fmt.Errorf("%w: unknown migration kind %q", entity.ErrInvalidArgument, kind)
+	}
+
+	if kind != entity.MigrationKindBackfill && backfill != nil {
+		return fmt.Errorf("%w: backfill parameters are allowed only for backfill migrations", entity.ErrInvalidArgument)
+	}
+
+	switch kind {
+	case entity.MigrationKindVersioned:
+		return nil
+	case entity.MigrationKindBackfill:
+		if backfill == nil {
+			return fmt.Errorf("%w: backfill migration requires backfill parameters", entity.ErrInvalidArgument)
+		}
+		if err := backfill.Validate(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case executionMode == entity.ExecutionModeNoTransaction:
+		return fmt.Errorf("%w: %s migration must run in a transaction", entity.ErrInvalidArgument, kind)
+	case rollbackScript != "":
+		return fmt.Errorf("%w: %s migration has no rollback script", entity.ErrInvalidArgument, kind)
+	case tenantScoped:
+		return fmt.Errorf("%w: %s migration cannot be tenant-scoped", entity.ErrInvalidArgument, kind)
+	}
+	return nil
+}
+
+// repeatableRollbackError - повторяемую миграцию нельзя откатить: предыдущая
+// версия объектов восстанавливается применением предыдущего скрипта.
+func repeatableRollbackError(migrationID int64) error {
+	return fmt.Errorf("%w: migration %d is repeatable and cannot be rolled back", entity.ErrInvalidArgument, migrationID)
+}
+
+// applyOrder упорядочивает миграции запроса на применение: версионные
+// в переданном порядке, затем повторяемые в порядке названий. Ненайденные
+// миграции остаются среди версионных, чтобы об их отсутствии сообщила
+// проверка при применении.
+func (m *Migrator) applyOrder(ctx context.Context, migrationIDs []int64) ([]int64, error) {
+	var (
+		versioned  []int64
+		repeatable []entity.MigrationInfo
+	)
+	for _, migrationID := range migrationIDs {
+		migration, err := m.repo.Get(ctx, migrationID)
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
+			return nil, fmt.Errorf("m.repo.GetMigration: %w", err)
+		}
+
+		if err == nil && migration.Kind == entity.MigrationKindRepeatable {
+			repeatable = append(repeatable, migration)
+			continue
+		}
+		versioned = append(versioned, migrationID)
+	}
+
+	sort.SliceStable(repeatable, func(i, j int) bool {
+		return repeatable[i].Name < repeatable[j].Name
+	})
+
+	ordered := versioned
+	for _, migration := range repeatable {
+		ordered = append(ordered, migration.ID)
+	}
+
+	return ordered, nil
+}
+
+// PendingRepeatableMigrations возвращает повторяемые миграции целевой базы данных,
+// ожидающие применения после изменения скрипта, которые можно выполнить вместе
+// с миграциями запроса на применение. Миграции запроса в результат не входят.
+// Миграция без транзакции применяется только отдельным запросом, поэтому
+// к нему повторяемые миграции не добавляются. Миграции с измененными в обход
+// сервиса скриптами и не прошедшие правило продвижения окружения пропускаются:
+// они остаются ожидающими и не мешают применению остальных.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	migrationIDs: []int64 - Уникальные идентификаторы миграций запроса.
+//
+// Возвращает:
+//
+//	[]entity.MigrationInfo: Ожидающие повторяемые миграции в порядке названий.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) PendingRepeatableMigrations(ctx context.Context, targetID int64, migrationIDs []int64) ([]entity.MigrationInfo, error) {
+	requested := make(map[int64]bool, len(migrationIDs))
+	for _, migrationID := range migrationIDs {
+		requested[migrationID] = true
+
+		migration, err := m.repo.Get(ctx, migrationID)
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
+			return nil, fmt.Errorf("m.repo.GetMigration: %w", err)
+		}
+		if err == nil && migration.ExecutionMode == entity.ExecutionModeNoTransaction {
+			return nil, nil
+		}
+	}
+
+	pending, err := m.repo.List(ctx, targetID, string(entity.StatusPending))
+	if err != nil {
+		return nil, fmt.Errorf("m.repo.ListMigrations: %w", err)
+	}
+
+	var repeatable []entity.MigrationInfo
+	for _, migration := range pending {
+		if migration.Kind != entity.MigrationKindRepeatable || requested[migration.ID] {
+			continue
+		}
+		if migration.VerifyChecksum() != nil {
+			continue
+		}
+
+		err := m.promotion.CheckPromotion(ctx, targetID, []entity.MigrationInfo{migration})
+		if errors.Is(err, entity.ErrPromotionBlocked) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("m.promotion.CheckPromotion: %w", err)
+		}
+
+		repeatable = append(repeatable, migration)
+	}
+
+	sort.SliceStable(repeatable, func(i, j int) bool {
+		return repeatable[i].Name < repeatable[j].Name
+	})
+
+	return repeatable, nil
+}
+
+// withoutRollbackFindings убирает из отчета линтера замечания к скрипту отката:
+// у повторяемой миграции и миграции данных его нет.
+func withoutRollbackFindings(report entity.LintReport) entity.LintReport {
+	var result entity.LintReport
+	for _, finding := range report.Findings {
+		if finding.Rollback {
+			continue
+		}
+		if finding.Blocking {
+			result.Blocked = true
+		}
+		result.Findings = append(result.Findings, finding)
+	}
+	return result
+}
+
+// addRevision записывает выполненный скрипт повторяемой миграции
+// как ее очередную ревизию. Для версионных миграций ничего не делает.
+func (m *Migrator) addRevision(ctx context.Context, migration entity.MigrationInfo, script string, userID int64) error {
+	if migration.Kind != entity.MigrationKindRepeatable {
+		return nil
+	}
+
+	_, err := m.repo.AddRevision(ctx, entity.MigrationRevision{
+		MigrationID: migration.ID,
+		Checksum:    migration.Checksum,
+		Script:      script,
+		AppliedBy:   userID,
+		AppliedAt:   time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("m.repo.AddRevision: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRepeatableMigration заменяет скрипт повторяемой миграции. Скрипт
+// проверяется линтером так же, как при создании. Если новый скрипт отличается
+// от последнего выполненного, миграция снова ожидает применения и будет
+// выполнена следующим запросом на применение.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	migrationID: int64 - Уникальный идентификатор повторяемой миграции.
+//	script: string - Новый текст скрипта миграции.
+//
+// Возвращает:
+//
+//	bool: Миграция ожидает применения нового скрипта.
+//	[]entity.LintFinding: Замечания линтера, не запретившие изменение.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) UpdateRepeatableMigration(ctx context.Context, migrationID int64, script string) (bool, []entity.LintFinding, error) {
+	migration, err := m.repo.Get(ctx, migrationID)
+	if err != nil {
+		return false, nil, fmt.Errorf("m.repo.GetMigration: %w", err)
+	}
+
+	if migration.Kind != entity.MigrationKindRepeatable {
+		return false, nil, fmt.Errorf("%w: migration %d is not repeatable, its scripts cannot be changed", entity.ErrInvalidArgument, migrationID)
+	}
+
+	report := withoutRollbackFindings(m.linter.Lint(script, ""))
+	if report.Blocked {
+		return false, nil, &entity.LintError{Report: report}
+	}
+
+	ctx, unlock, err := m.locker.Lock(ctx, migration.TargetID, "update")
+	if err != nil {
+		return false, nil, fmt.Errorf("m.locker.Lock: %w", err)
+	}
+	defer unlock()
+
+	migration.Script = script
+	migration.Checksum = entity.ScriptChecksum(script, "")
+	migration.StatusUpdatedAt = time.Now()
+
+	err = m.repo.UpdateScript(ctx, migration)
+	if err != nil {
+		return false, nil, fmt.Errorf("m.repo.UpdateScript: %w", err)
+	}
+
+	return migration.Checksum != migration.AppliedChecksum, report.Findings, nil
+}
+
+// ListMigrationRevisions возвращает выполненные ревизии повторяемой миграции.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	migrationID: int64 - Уникальный идентификатор повторяемой миграции.
+//
+// Возвращает:
+//
+//	[]entity.MigrationRevision: Ревизии, начиная с последней выполненной.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) ListMigrationRevisions(ctx context.Context, migrationID int64) ([]entity.MigrationRevision, error) {
+	migration, err := m.repo.Get(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("m.repo.GetMigration: %w", err)
+	}
+
+	if migration.Kind != entity.MigrationKindRepeatable {
+		return nil, fmt.Errorf("%w: migration %d is not repeatable", entity.ErrInvalidArgument, migrationID)
+	}
+
+	revisions, err := m.repo.ListRevisions(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("m.repo.ListRevisions: %w", err)
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,90 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+)
+
+// snapshotTimeout - время на чтение и сохранение схемы после применения или отката.
+const snapshotTimeout = 30 * time.Second
+
+// recordSnapshot сохраняет снимок схемы целевой базы данных, если запрос изменил
+// ее: хотя бы один скрипт выполнен и зафиксирован, в том числе частично.
+// Снимок - точка отсчета для поиска изменений в обход сервиса, поэтому
+// ошибка чтения схемы не отменяет результат операции и только логируется.
+// Вызывается до освобождения блокировки целевой базы данных, иначе в снимок
+// попадут изменения следующей операции.
+func (m *Migrator) recordSnapshot(ctx context.Context, l *execLog) {
+	changed := false
+	for _, entry := range l.entries {
+		if entry.Outcome == entity.OutcomeSucceeded || entry.Outcome == entity.OutcomePartiallyApplied {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), snapshotTimeout)
+	defer cancel()
+
+	objects, err := m.schemas.Introspect(ctx, l.targetID)
+	if err != nil {
+		logger.Error(fmt.Errorf("snapshot schema of target %d: %w", l.targetID, err))
+		return
+	}
+
+	_, err = m.schemas.AddSnapshot(ctx, entity.SchemaSnapshot{
+		TargetID: l.targetID,
+		Action:   l.action,
+		UserID:   l.userID,
+		Objects:  objects,
+		TakenAt:  time.Now(),
+	})
+	if err != nil {
+		logger.Error(fmt.Errorf("snapshot schema of target %d: %w", l.targetID, err))
+	}
+}
+
+// GetDatabaseStatus читает текущую схему целевой базы данных из ее системного
+// каталога и сравнивает с последним снимком, сохраненным после применения или
+// отката миграций. Расхождения - объекты, добавленные, удаленные или измененные
+// в обход сервиса миграций. Если снимков еще нет, расхождения не ищутся.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//
+// Возвращает:
+//
+//	entity.DatabaseStatus: Текущие объекты схемы, последний снимок и расхождения с ним.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) GetDatabaseStatus(ctx context.Context, targetID int64) (entity.DatabaseStatus, error) {
+	objects, err := m.schemas.Introspect(ctx, targetID)
+	if err != nil {
+		return entity.DatabaseStatus{}, fmt.Errorf("m.schemas.Introspect: %w", err)
+	}
+
+	status := entity.DatabaseStatus{
+		TargetID: targetID,
+		Objects:  objects,
+	}
+
+	snapshot, err := m.schemas.GetLatestSnapshot(ctx, targetID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return entity.DatabaseStatus{}, fmt.Errorf("m.schemas.GetLatestSnapshot: %w", err)
+	}
+
+	status.Snapshot = &snapshot
+	status.Drift = entity.DiffSchema(snapshot.Objects, objects)
+
+	return status, nil
+}
@@ -0,0 +1,34 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+
+	"migrator/internal/entity"
+)
+
+// renderScript подставляет в скрипт миграции переменные шаблонов целевой базы данных,
+// если миграция создана с шаблонами; скрипты остальных миграций выполняются как есть.
+// Контрольная сумма считается по шаблону, поэтому изменение переменных
+// не считается изменением скриптов. Если history не nil, выполняемый скрипт
+// и использованные переменные записываются в журнал выполнения.
+func (m *Migrator) renderScript(ctx context.Context, targetID int64, migration entity.MigrationInfo, script string, history *execLog) (string, error) {
+	rendered := entity.RenderedScript{SQL: script}
+	if migration.Templated {
+		templateContext, err := m.templates.TemplateContext(ctx, targetID)
+		if err != nil {
+			return "", fmt.Errorf("m.templates.TemplateContext: %w", err)
+		}
+
+		rendered, err = templateContext.Render(script)
+		if err != nil {
+			return "", fmt.Errorf("migration %d: %w", migration.ID, err)
+		}
+	}
+
+	if history != nil {
+		history.script(migration.ID, rendered)
+	}
+
+	return rendered.SQL, nil
+}
@@ -0,0 +1,372 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"migrator/internal/entity"
+	"migrator/pkg/logger"
+)
+
+// tenantRun - выполнение скрипта миграции в схеме одного арендатора.
+type tenantRun struct {
+	tenant     string
+	script     entity.RenderedScript
+	startedAt  time.Time
+	finishedAt time.Time
+	err        error
+}
+
+// ApplyTenantMigration применяет миграцию арендаторов к схемам арендаторов целевой
+// базы данных (PostgreSQL). Схемы находятся настроенным запросом, скрипт выполняется
+// в каждой схеме в отдельной транзакции с search_path этой схемы, не более чем
+// в concurrency схемах одновременно. Схемы, к которым миграция уже применена,
+// пропускаются, поэтому повторный вызов применяет миграцию только к схемам,
+// в которых она завершилась ошибкой, и к новым схемам. Состояние каждой схемы
+// сохраняется, а в журнал выполнения записывается попытка по каждой схеме и итог.
+// Миграция получает статус applied, когда применена во всех схемах, и failed,
+// если хотя бы в одной схеме скрипт завершился ошибкой.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	migrationID: int64 - Уникальный идентификатор миграции арендаторов.
+//	tenants: []string - Схемы, к которым нужно применить миграцию; пустой список - все схемы.
+//	concurrency: int - Число схем, обрабатываемых одновременно; 0 - значение из конфигурации.
+//	userID: int64 - Идентификатор пользователя, применяющего миграцию.
+//
+// Возвращает:
+//
+//	entity.TenantApplyResult: Примененные, пропущенные и неудачные схемы.
+//	error: Ошибка, если миграцию не удалось применить ни к одной схеме.
+func (m *Migrator) ApplyTenantMigration(ctx context.Context, targetID, migrationID int64, tenants []string, concurrency int, userID int64) (result entity.TenantApplyResult, err error) {
+	history := newExecLog(entity.HistoryActionApply, targetID, userID, migrationID)
+	defer func() { m.writeHistory(ctx, history, err) }()
+
+	ctx, unlock, err := m.locker.Lock(ctx, targetID, "apply")
+	if err != nil {
+		return entity.TenantApplyResult{}, fmt.Errorf("m.locker.Lock: %w", err)
+	}
+	defer unlock()
+	defer m.recordSnapshot(ctx, history)
+
+	migration, err := m.repo.Get(ctx, migrationID)
+	if err != nil {
+		return entity.TenantApplyResult{}, fmt.Errorf("m.repo.GetMigration: %w", err)
+	}
+
+	if err := checkTenantApplicable(targetID, migration); err != nil {
+		return entity.TenantApplyResult{}, err
+	}
+
+	if err := m.checkPromotion(ctx, targetID, []int64{migrationID}); err != nil {
+		return entity.TenantApplyResult{}, err
+	}
+
+	discovered, err := m.repo.ListTenants(ctx, targetID)
+	if err != nil {
+		return entity.TenantApplyResult{}, fmt.Errorf("m.repo.ListTenants: %w", err)
+	}
+	if len(discovered) == 0 {
+		return entity.TenantApplyResult{}, fmt.Errorf("no tenant schemas found in target %d: %w", targetID, entity.ErrNotFound)
+	}
+
+	statuses, err := m.repo.ListTenantStatuses(ctx, migrationID)
+	if err != nil {
+		return entity.TenantApplyResult{}, fmt.Errorf("m.repo.ListTenantStatuses: %w", err)
+	}
+	applied := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		applied[status.Tenant] = status.Status == entity.StatusApplied
+	}
+
+	selected, err := selectTenants(discovered, tenants)
+	if err != nil {
+		return entity.TenantApplyResult{}, err
+	}
+
+	result.MigrationID = migrationID
+
+	var runs []*tenantRun
+	for _, tenant := range selected {
+		if applied[tenant] {
+			result.Skipped = append(result.Skipped, tenant)
+			continue
+		}
+
+		script, err := m.renderTenantScript(ctx, targetID, migration, tenant)
+		if err != nil {
+			return entity.TenantApplyResult{}, err
+		}
+		runs = append(runs, &tenantRun{tenant: tenant, script: script})
+	}
+
+	if len(runs) > 0 {
+		release, err := m.repo.LockTarget(ctx, targetID)
+		if err != nil {
+			return entity.TenantApplyResult{}, fmt.Errorf("m.repo.LockTarget: %w", err)
+		}
+		defer release()
+
+		history.start(migrationID)
+		m.fanOut(ctx, migration, runs, m.tenantConcurrency(concurrency))
+	}
+
+	for _, run := range runs {
+		history.tenant(migrationID, run)
+
+		if run.err != nil {
+			result.Failed = append(result.Failed, entity.TenantStatus{
+				MigrationID: migrationID,
+				Tenant:      run.tenant,
+				Status:      entity.StatusFailed,
+				Error:       run.err.Error(),
+				UpdatedAt:   run.finishedAt,
+			})
+			continue
+		}
+		result.Applied = append(result.Applied, run.tenant)
+		applied[run.tenant] = true
+	}
+
+	result.FinishedAt = time.Now()
+
+	if len(runs) > 0 {
+		var summaryErr error
+		if len(result.Failed) > 0 {
+			summaryErr = fmt.Errorf("migration %d failed in %d of %d tenant schemas: %s",
+				migrationID, len(result.Failed), len(runs), failedTenants(result.Failed))
+			history.finish(migrationID, entity.OutcomeFailed, summaryErr)
+		} else {
+			history.finish(migrationID, entity.OutcomeSucceeded, nil)
+		}
+
+		if err := m.setTenantMigrationStatus(ctx, migration, discovered, applied, summaryErr, result.FinishedAt); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// tenantScopedError - миграцию арендаторов нельзя применить или откатить как обычную:
+// она применяется к схемам арендаторов через ApplyTenantMigration и не откатывается.
+func tenantScopedError(migrationID int64) error {
+	return fmt.Errorf("%w: migration %d is tenant-scoped and is applied only with ApplyTenantMigration", entity.ErrInvalidArgument, migrationID)
+}
+
+// checkTenantApplicable проверяет, что миграцию арендаторов можно применить к целевой базе данных.
+// Уже примененную миграцию можно применить к схемам, появившимся после ее применения.
+func checkTenantApplicable(targetID int64, migration entity.MigrationInfo) error {
+	if migration.TargetID != targetID {
+		return fmt.Errorf("%w: migration %d does not belong to target %d", entity.ErrInvalidArgument, migration.ID, targetID)
+	}
+
+	if !migration.TenantScoped {
+		return fmt.Errorf("%w: migration %d is not tenant-scoped", entity.ErrInvalidArgument, migration.ID)
+	}
+
+	if !migration.Status.Applicable() && migration.Status != entity.StatusApplied {
+		return fmt.Errorf("%w: migration %d cannot be applied in status %s", entity.ErrFailedPrecondition, migration.ID, migration.Status)
+	}
+
+	return migration.VerifyChecksum()
+}
+
+// selectTenants оставляет из найденных схем запрошенные; пустой запрос - все схемы.
+func selectTenants(discovered, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return discovered, nil
+	}
+
+	known := make(map[string]bool, len(discovered))
+	for _, tenant := range discovered {
+		known[tenant] = true
+	}
+
+	var selected []string
+	for _, tenant := range requested {
+		if !known[tenant] {
+			return nil, fmt.Errorf("%w: tenant schema %q is not found", entity.ErrInvalidArgument, tenant)
+		}
+		selected = append(selected, tenant)
+	}
+
+	return selected, nil
+}
+
+// renderTenantScript подставляет в скрипт миграции с шаблонами переменные целевой
+// базы данных и схему арендатора (переменная tenant).
+func (m *Migrator) renderTenantScript(ctx context.Context, targetID int64, migration entity.MigrationInfo, tenant string) (entity.RenderedScript, error) {
+	if !migration.Templated {
+		return entity.RenderedScript{SQL: migration.Script}, nil
+	}
+
+	templateContext, err := m.templates.TemplateContext(ctx, targetID)
+	if err != nil {
+		return entity.RenderedScript{}, fmt.Errorf("m.templates.TemplateContext: %w", err)
+	}
+
+	variables := make(map[string]string, len(templateContext.Variables)+1)
+	for name, value := range templateContext.Variables {
+		variables[name] = value
+	}
+	variables[entity.TenantVariable] = tenant
+	templateContext.Variables = variables
+
+	rendered, err := templateContext.Render(migration.Script)
+	if err != nil {
+		return entity.RenderedScript{}, fmt.Errorf("migration %d, tenant %s: %w", migration.ID, tenant, err)
+	}
+
+	return rendered, nil
+}
+
+// tenantConcurrency ограничивает число одновременно обрабатываемых схем значением из конфигурации.
+func (m *Migrator) tenantConcurrency(requested int) int {
+	if requested <= 0 || requested > m.maxTenantConcurrency {
+		return m.maxTenantConcurrency
+	}
+	return requested
+}
+
+// fanOut выполняет скрипт миграции в схемах арендаторов, не более чем в concurrency
+// схемах одновременно, и сохраняет состояние каждой схемы сразу после ее обработки.
+// Ошибка в одной схеме не останавливает обработку остальных.
+func (m *Migrator) fanOut(ctx context.Context, migration entity.MigrationInfo, runs []*tenantRun, concurrency int) {
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for _, run := range runs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(run *tenantRun) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			run.startedAt = time.Now()
+			run.err = m.repo.ApplyToTenant(ctx, migration, run.tenant, run.script.SQL)
+			run.finishedAt = time.Now()
+
+			status := entity.TenantStatus{
+				MigrationID: migration.ID,
+				Tenant:      run.tenant,
+				Status:      entity.StatusApplied,
+				UpdatedAt:   run.finishedAt,
+			}
+			if run.err != nil {
+				status.Status = entity.StatusFailed
+				status.Error = run.err.Error()
+			}
+
+			// Скрипт уже выполнен или откатился, поэтому ошибка сохранения состояния только логируется
+			statusCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer cancel()
+			if err := m.repo.SetTenantStatus(statusCtx, status); err != nil {
+				logger.Error(fmt.Errorf("save status of migration %d in tenant %s: %w", migration.ID, run.tenant, err))
+			}
+		}(run)
+	}
+
+	wg.Wait()
+}
+
+// setTenantMigrationStatus обновляет статус миграции арендаторов: failed, если в каких-то
+// схемах скрипт завершился ошибкой, и applied, если миграция применена во всех найденных схемах.
+func (m *Migrator) setTenantMigrationStatus(ctx context.Context, migration entity.MigrationInfo, discovered []string, applied map[string]bool, summaryErr error, updatedAt time.Time) error {
+	if summaryErr != nil {
+		m.recordFailure(ctx, migration.ID, entity.StatusFailed, summaryErr)
+		return nil
+	}
+
+	for _, tenant := range discovered {
+		if !applied[tenant] {
+			return nil
+		}
+	}
+
+	if migration.Status != entity.StatusApplied {
+		err := m.repo.SetStatus(ctx, migration.ID, updatedAt, entity.StatusApplied)
+		if err != nil {
+			return fmt.Errorf("m.repo.SetStatus: %w", err)
+		}
+	}
+
+	err := m.repo.SetAppliedChecksum(ctx, migration.ID, migration.Checksum)
+	if err != nil {
+		return fmt.Errorf("m.repo.SetAppliedChecksum: %w", err)
+	}
+
+	err = m.repo.SetLastError(ctx, migration.ID, nil)
+	if err != nil {
+		return fmt.Errorf("m.repo.SetLastError: %w", err)
+	}
+
+	return nil
+}
+
+// failedTenants перечисляет схемы, в которых скрипт завершился ошибкой.
+func failedTenants(failed []entity.TenantStatus) string {
+	tenants := make([]string, len(failed))
+	for i, status := range failed {
+		tenants[i] = status.Tenant
+	}
+	return strings.Join(tenants, ", ")
+}
+
+// ListTenantStatuses возвращает состояние миграции арендаторов во всех схемах арендаторов:
+// схемы, к которым миграция еще не применялась, возвращаются со статусом pending.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	migrationID: int64 - Уникальный идентификатор миграции арендаторов.
+//
+// Возвращает:
+//
+//	[]entity.TenantStatus: Состояния миграции в порядке названий схем.
+//	error: Ошибка, если таковая имеется.
+func (m *Migrator) ListTenantStatuses(ctx context.Context, migrationID int64) ([]entity.TenantStatus, error) {
+	migration, err := m.repo.Get(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("m.repo.GetMigration: %w", err)
+	}
+
+	if !migration.TenantScoped {
+		return nil, fmt.Errorf("%w: migration %d is not tenant-scoped", entity.ErrInvalidArgument, migrationID)
+	}
+
+	statuses, err := m.repo.ListTenantStatuses(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("m.repo.ListTenantStatuses: %w", err)
+	}
+
+	discovered, err := m.repo.ListTenants(ctx, migration.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("m.repo.ListTenants: %w", err)
+	}
+
+	known := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		known[status.Tenant] = true
+	}
+	for _, tenant := range discovered {
+		if !known[tenant] {
+			statuses = append(statuses, entity.TenantStatus{
+				MigrationID: migrationID,
+				Tenant:      tenant,
+				Status:      entity.StatusPending,
+			})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Tenant < statuses[j].Tenant
+	})
+
+	return statuses, nil
+}
@@ -0,0 +1,161 @@
+// Package target содержит бизнес-логику для управления реестром целевых баз данных.
+package target
+
+import (
+	"context"
+	"fmt"
+
+	"migrator/internal/entity"
+)
+
+type targetRepository interface {
+	Create(ctx context.Context, name, description, url string, environmentID int64, variables map[string]string, userID int64) (int64, error)
+	Get(ctx context.Context, targetID int64) (entity.Target, error)
+	List(ctx context.Context) ([]entity.Target, error)
+	Update(ctx context.Context, targetID int64, name, description, url string, environmentID int64, variables map[string]string) error
+	Delete(ctx context.Context, targetID int64) error
+}
+
+type poolInvalidator interface {
+	Invalidate(targetID int64)
+}
+
+// Service - сервис реестра целевых баз данных.
+type Service struct {
+	repo  targetRepository
+	pools poolInvalidator
+}
+
+// New - конструктор сервиса реестра целевых баз данных.
+func New(repo targetRepository, pools poolInvalidator) *Service {
+	return &Service{
+		repo:  repo,
+		pools: pools,
+	}
+}
+
+// CreateTarget регистрирует новую целевую базу данных.
+// СУБД определяется по схеме строки подключения: PostgreSQL, MySQL или SQLite.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	name: string - Уникальное название целевой базы данных.
+//	description: string - Описание целевой базы данных.
+//	url: string - Строка подключения к целевой базе данных.
+//	environmentID: int64 - Окружение, к которому относится база данных; 0 - вне конвейера.
+//	variables: map[string]string - Переменные шаблонов скриптов миграций.
+//	userID: int64 - Идентификатор пользователя, регистрирующего базу данных.
+//
+// Возвращает:
+//
+//	int64: Уникальный идентификатор целевой базы данных.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) CreateTarget(ctx context.Context, name, description, url string, environmentID int64, variables map[string]string, userID int64) (int64, error) {
+	if _, err := entity.DriverFromURL(url); err != nil {
+		return 0, err
+	}
+	if err := entity.ValidateVariables(variables); err != nil {
+		return 0, err
+	}
+
+	targetID, err := s.repo.Create(ctx, name, description, url, environmentID, variables, userID)
+	if err != nil {
+		return 0, fmt.Errorf("s.repo.Create: %w", err)
+	}
+
+	return targetID, nil
+}
+
+// GetTarget возвращает целевую базу данных по ее ID.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//
+// Возвращает:
+//
+//	entity.Target: Информация о целевой базе данных.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) GetTarget(ctx context.Context, targetID int64) (entity.Target, error) {
+	target, err := s.repo.Get(ctx, targetID)
+	if err != nil {
+		return entity.Target{}, fmt.Errorf("s.repo.Get: %w", err)
+	}
+
+	return target, nil
+}
+
+// ListTargets возвращает список зарегистрированных целевых баз данных.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//
+// Возвращает:
+//
+//	[]entity.Target: Список целевых баз данных.
+//	error: Ошибка, если таковая имеется.
+func (s *Service) ListTargets(ctx context.Context) ([]entity.Target, error) {
+	targets, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.List: %w", err)
+	}
+
+	return targets, nil
+}
+
+// UpdateTarget изменяет настройки целевой базы данных.
+// Открытый пул подключений к ней закрывается, чтобы следующие
+// операции использовали новые настройки.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	name: string - Новое название.
+//	description: string - Новое описание.
+//	url: string - Новая строка подключения.
+//	environmentID: int64 - Окружение, к которому относится база данных; 0 - вне конвейера.
+//	variables: map[string]string - Переменные шаблонов скриптов миграций.
+//	userID: int64 - Идентификатор пользователя, изменяющего базу данных.
+//
+// Возвращает:
+//
+//	error: Ошибка, если таковая имеется.
+func (s *Service) UpdateTarget(ctx context.Context, targetID int64, name, description, url string, environmentID int64, variables map[string]string, userID int64) error {
+	if _, err := entity.DriverFromURL(url); err != nil {
+		return err
+	}
+	if err := entity.ValidateVariables(variables); err != nil {
+		return err
+	}
+
+	err := s.repo.Update(ctx, targetID, name, description, url, environmentID, variables)
+	if err != nil {
+		return fmt.Errorf("s.repo.Update: %w", err)
+	}
+
+	s.pools.Invalidate(targetID)
+
+	return nil
+}
+
+// DeleteTarget удаляет целевую базу данных из реестра.
+// Базу данных, для которой уже созданы миграции, удалить нельзя.
+// Аргументы:
+//
+//	ctx: context.Context - Контекст запроса.
+//	targetID: int64 - Идентификатор целевой базы данных.
+//	userID: int64 - Идентификатор пользователя, удаляющего базу данных.
+//
+// Возвращает:
+//
+//	error: Ошибка, если таковая имеется.
+func (s *Service) DeleteTarget(ctx context.Context, targetID, userID int64) error {
+	err := s.repo.Delete(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("s.repo.Delete: %w", err)
+	}
+
+	s.pools.Invalidate(targetID)
+
+	return nil
+}
@@ -28,26 +28,32 @@ type Permission int32
 
 const (
 	Permission_PERMISSION_NONE           Permission = 0
-	Permission_PERMISSION_CREATE         Permission = 1 // Право на создание сущностей.
-	Permission_PERMISSION_APPLY          Permission = 2 // Право на применение изменений.
-	Permission_PERMISSION_ROLLBACK       Permission = 3 // Право на откат изменений.
-	Permission_PERMISSION_LIST           Permission = 4 // Право на просмотр списков.
-	Permission_PERMISSION_GET            Permission = 5 // Право на получение конкретной сущности.
-	Permission_PERMISSION_APPLY_OTHER    Permission = 6 // Право на применение изменений, созданных другими.
-	Permission_PERMISSION_ROLLBACK_OTHER Permission = 7 // Право на откат изменений, созданных другими.
+	Permission_PERMISSION_CREATE         Permission = 1  // Право на создание сущностей.
+	Permission_PERMISSION_APPLY          Permission = 2  // Право на применение изменений.
+	Permission_PERMISSION_ROLLBACK       Permission = 3  // Право на откат изменений.
+	Permission_PERMISSION_LIST           Permission = 4  // Право на просмотр списков.
+	Permission_PERMISSION_GET            Permission = 5  // Право на получение конкретной сущности.
+	Permission_PERMISSION_APPLY_OTHER    Permission = 6  // Право на применение изменений, созданных другими.
+	Permission_PERMISSION_ROLLBACK_OTHER Permission = 7  // Право на откат изменений, созданных другими.
+	Permission_PERMISSION_MANAGE_TARGETS Permission = 8  // Право на управление реестром целевых баз данных.
+	Permission_PERMISSION_BASELINE       Permission = 9  // Право на отметку миграций примененными без выполнения скриптов.
+	Permission_PERMISSION_AUDIT          Permission = 10 // Право на просмотр журнала аудита.
 )
 
 // Enum value maps for Permission.
 var (
 	Permission_name = map[int32]string{
-		0: "PERMISSION_NONE",
-		1: "PERMISSION_CREATE",
-		2: "PERMISSION_APPLY",
-		3: "PERMISSION_ROLLBACK",
-		4: "PERMISSION_LIST",
-		5: "PERMISSION_GET",
-		6: "PERMISSION_APPLY_OTHER",
-		7: "PERMISSION_ROLLBACK_OTHER",
+		0:  "PERMISSION_NONE",
+		1:  "PERMISSION_CREATE",
+		2:  "PERMISSION_APPLY",
+		3:  "PERMISSION_ROLLBACK",
+		4:  "PERMISSION_LIST",
+		5:  "PERMISSION_GET",
+		6:  "PERMISSION_APPLY_OTHER",
+		7:  "PERMISSION_ROLLBACK_OTHER",
+		8:  "PERMISSION_MANAGE_TARGETS",
+		9:  "PERMISSION_BASELINE",
+		10: "PERMISSION_AUDIT",
 	}
 	Permission_value = map[string]int32{
 		"PERMISSION_NONE":           0,
@@ -58,6 +64,9 @@ var (
 		"PERMISSION_GET":            5,
 		"PERMISSION_APPLY_OTHER":    6,
 		"PERMISSION_ROLLBACK_OTHER": 7,
+		"PERMISSION_MANAGE_TARGETS": 8,
+		"PERMISSION_BASELINE":       9,
+		"PERMISSION_AUDIT":          10,
 	}
 )
 
@@ -374,6 +383,104 @@ func (x *LogoutResponse) GetSuccess() bool {
 	return false
 }
 
+// Запрос для проверки токена доступа
+type ValidateTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // Токен для авторизации.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTokenRequest) Reset() {
+	*x = ValidateTokenRequest{}
+	mi := &file_auth_auth_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTokenRequest) ProtoMessage() {}
+
+func (x *ValidateTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTokenRequest.ProtoReflect.Descriptor instead.
+func (*ValidateTokenRequest) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ValidateTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// Ответ на запрос для проверки токена доступа
+type ValidateTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Айди пользователя, которому выдан токен.
+	Login         string                 `protobuf:"bytes,2,opt,name=login,proto3" json:"login,omitempty"`                  // Логин пользователя.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTokenResponse) Reset() {
+	*x = ValidateTokenResponse{}
+	mi := &file_auth_auth_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTokenResponse) ProtoMessage() {}
+
+func (x *ValidateTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTokenResponse.ProtoReflect.Descriptor instead.
+func (*ValidateTokenResponse) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ValidateTokenResponse) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ValidateTokenResponse) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
 // Запрос для проверки прав пользователя
 type PermissionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -385,7 +492,7 @@ type PermissionRequest struct {
 
 func (x *PermissionRequest) Reset() {
 	*x = PermissionRequest{}
-	mi := &file_auth_auth_proto_msgTypes[6]
+	mi := &file_auth_auth_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -397,7 +504,7 @@ func (x *PermissionRequest) String() string {
 func (*PermissionRequest) ProtoMessage() {}
 
 func (x *PermissionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_auth_proto_msgTypes[6]
+	mi := &file_auth_auth_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -410,7 +517,7 @@ func (x *PermissionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PermissionRequest.ProtoReflect.Descriptor instead.
 func (*PermissionRequest) Descriptor() ([]byte, []int) {
-	return file_auth_auth_proto_rawDescGZIP(), []int{6}
+	return file_auth_auth_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *PermissionRequest) GetUserId() int64 {
@@ -437,7 +544,7 @@ type PermissionResponse struct {
 
 func (x *PermissionResponse) Reset() {
 	*x = PermissionResponse{}
-	mi := &file_auth_auth_proto_msgTypes[7]
+	mi := &file_auth_auth_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -449,7 +556,7 @@ func (x *PermissionResponse) String() string {
 func (*PermissionResponse) ProtoMessage() {}
 
 func (x *PermissionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_auth_proto_msgTypes[7]
+	mi := &file_auth_auth_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -462,7 +569,7 @@ func (x *PermissionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PermissionResponse.ProtoReflect.Descriptor instead.
 func (*PermissionResponse) Descriptor() ([]byte, []int) {
-	return file_auth_auth_proto_rawDescGZIP(), []int{7}
+	return file_auth_auth_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *PermissionResponse) GetHavePermission() bool {
@@ -472,6 +579,486 @@ func (x *PermissionResponse) GetHavePermission() bool {
 	return false
 }
 
+// Запрос для проверки нескольких прав пользователя
+type CheckPermissionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                         // Айди пользователя для проверки прав.
+	Permissions   []Permission           `protobuf:"varint,2,rep,packed,name=permissions,proto3,enum=auth.Permission" json:"permissions,omitempty"` // Типы прав для проверки.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckPermissionsRequest) Reset() {
+	*x = CheckPermissionsRequest{}
+	mi := &file_auth_auth_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckPermissionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckPermissionsRequest) ProtoMessage() {}
+
+func (x *CheckPermissionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckPermissionsRequest.ProtoReflect.Descriptor instead.
+func (*CheckPermissionsRequest) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CheckPermissionsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CheckPermissionsRequest) GetPermissions() []Permission {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+// Решение по одному праву
+type PermissionDecision struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Permission     Permission             `protobuf:"varint,1,opt,name=permission,proto3,enum=auth.Permission" json:"permission,omitempty"`          // Тип права.
+	HavePermission bool                   `protobuf:"varint,2,opt,name=have_permission,json=havePermission,proto3" json:"have_permission,omitempty"` // Есть ли у пользователя это право.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PermissionDecision) Reset() {
+	*x = PermissionDecision{}
+	mi := &file_auth_auth_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionDecision) ProtoMessage() {}
+
+func (x *PermissionDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionDecision.ProtoReflect.Descriptor instead.
+func (*PermissionDecision) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PermissionDecision) GetPermission() Permission {
+	if x != nil {
+		return x.Permission
+	}
+	return Permission_PERMISSION_NONE
+}
+
+func (x *PermissionDecision) GetHavePermission() bool {
+	if x != nil {
+		return x.HavePermission
+	}
+	return false
+}
+
+// Ответ на запрос для проверки нескольких прав пользователя
+type CheckPermissionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Decisions     []*PermissionDecision  `protobuf:"bytes,1,rep,name=decisions,proto3" json:"decisions,omitempty"` // Решения в порядке запроса.
+	Revision      int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`  // Ревизия прав, на которой приняты решения.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckPermissionsResponse) Reset() {
+	*x = CheckPermissionsResponse{}
+	mi := &file_auth_auth_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckPermissionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckPermissionsResponse) ProtoMessage() {}
+
+func (x *CheckPermissionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckPermissionsResponse.ProtoReflect.Descriptor instead.
+func (*CheckPermissionsResponse) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CheckPermissionsResponse) GetDecisions() []*PermissionDecision {
+	if x != nil {
+		return x.Decisions
+	}
+	return nil
+}
+
+func (x *CheckPermissionsResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// Запрос для получения ревизии прав
+type GetPermissionsRevisionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPermissionsRevisionRequest) Reset() {
+	*x = GetPermissionsRevisionRequest{}
+	mi := &file_auth_auth_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPermissionsRevisionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPermissionsRevisionRequest) ProtoMessage() {}
+
+func (x *GetPermissionsRevisionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPermissionsRevisionRequest.ProtoReflect.Descriptor instead.
+func (*GetPermissionsRevisionRequest) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{13}
+}
+
+// Ответ на запрос для получения ревизии прав
+type GetPermissionsRevisionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      int64                  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"` // Текущая ревизия прав.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPermissionsRevisionResponse) Reset() {
+	*x = GetPermissionsRevisionResponse{}
+	mi := &file_auth_auth_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPermissionsRevisionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPermissionsRevisionResponse) ProtoMessage() {}
+
+func (x *GetPermissionsRevisionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPermissionsRevisionResponse.ProtoReflect.Descriptor instead.
+func (*GetPermissionsRevisionResponse) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetPermissionsRevisionResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// Запись журнала аудита
+type AuditEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                               // Уникальный идентификатор записи.
+	ActorId       int64                  `protobuf:"varint,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`      // Айди пользователя, выполнившего действие (0 - пользователь не установлен).
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`                        // Действие (метод API).
+	Resource      string                 `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`                    // Объект действия.
+	Ip            string                 `protobuf:"bytes,5,opt,name=ip,proto3" json:"ip,omitempty"`                                // IP-адрес клиента.
+	UserAgent     string                 `protobuf:"bytes,6,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"` // User-Agent клиента.
+	Outcome       string                 `protobuf:"bytes,7,opt,name=outcome,proto3" json:"outcome,omitempty"`                      // Итог действия (succeeded, denied, failed).
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`                          // Текст ошибки.
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Дата и время действия.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_auth_auth_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AuditEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// Запрос для получения журнала аудита
+type ListAuditEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorId       int64                  `protobuf:"varint,1,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`      // Фильтр по пользователю.
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`                            // Начало периода в формате RFC 3339 (включительно).
+	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`                                // Конец периода в формате RFC 3339 (не включительно).
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Размер страницы (по умолчанию 50, не более 500).
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // Токен следующей страницы из предыдущего ответа.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_auth_auth_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListAuditEventsRequest) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// Ответ на запрос для получения журнала аудита
+type ListAuditEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`                                      // Записи журнала от новых к старым.
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Токен следующей страницы; пустой, если записей больше нет.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_auth_auth_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsResponse) ProtoMessage() {}
+
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_auth_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_auth_auth_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListAuditEventsResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 var File_auth_auth_proto protoreflect.FileDescriptor
 
 const file_auth_auth_proto_rawDesc = "" +
@@ -490,14 +1077,56 @@ const file_auth_auth_proto_rawDesc = "" +
 	"\rLogoutRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\"*\n" +
 	"\x0eLogoutResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"^\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\",\n" +
+	"\x14ValidateTokenRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"F\n" +
+	"\x15ValidateTokenResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05login\x18\x02 \x01(\tR\x05login\"^\n" +
 	"\x11PermissionRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x03R\x06userId\x120\n" +
 	"\n" +
 	"permission\x18\x02 \x01(\x0e2\x10.auth.PermissionR\n" +
 	"permission\"=\n" +
 	"\x12PermissionResponse\x12'\n" +
-	"\x0fhave_permission\x18\x01 \x01(\bR\x0ehavePermission*\xcb\x01\n" +
+	"\x0fhave_permission\x18\x01 \x01(\bR\x0ehavePermission\"f\n" +
+	"\x17CheckPermissionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x122\n" +
+	"\vpermissions\x18\x02 \x03(\x0e2\x10.auth.PermissionR\vpermissions\"o\n" +
+	"\x12PermissionDecision\x120\n" +
+	"\n" +
+	"permission\x18\x01 \x01(\x0e2\x10.auth.PermissionR\n" +
+	"permission\x12'\n" +
+	"\x0fhave_permission\x18\x02 \x01(\bR\x0ehavePermission\"n\n" +
+	"\x18CheckPermissionsResponse\x126\n" +
+	"\tdecisions\x18\x01 \x03(\v2\x18.auth.PermissionDecisionR\tdecisions\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision\"\x1f\n" +
+	"\x1dGetPermissionsRevisionRequest\"<\n" +
+	"\x1eGetPermissionsRevisionResponse\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\"\xe9\x01\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\x03R\aactorId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1a\n" +
+	"\bresource\x18\x04 \x01(\tR\bresource\x12\x0e\n" +
+	"\x02ip\x18\x05 \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x06 \x01(\tR\tuserAgent\x12\x18\n" +
+	"\aoutcome\x18\a \x01(\tR\aoutcome\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"\x93\x01\n" +
+	"\x16ListAuditEventsRequest\x12\x19\n" +
+	"\bactor_id\x18\x01 \x01(\x03R\aactorId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"k\n" +
+	"\x17ListAuditEventsResponse\x12(\n" +
+	"\x06events\x18\x01 \x03(\v2\x10.auth.AuditEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*\x99\x02\n" +
 	"\n" +
 	"Permission\x12\x13\n" +
 	"\x0fPERMISSION_NONE\x10\x00\x12\x15\n" +
@@ -507,13 +1136,21 @@ const file_auth_auth_proto_rawDesc = "" +
 	"\x0fPERMISSION_LIST\x10\x04\x12\x12\n" +
 	"\x0ePERMISSION_GET\x10\x05\x12\x1a\n" +
 	"\x16PERMISSION_APPLY_OTHER\x10\x06\x12\x1d\n" +
-	"\x19PERMISSION_ROLLBACK_OTHER\x10\a2\xe5\x02\n" +
+	"\x19PERMISSION_ROLLBACK_OTHER\x10\a\x12\x1d\n" +
+	"\x19PERMISSION_MANAGE_TARGETS\x10\b\x12\x17\n" +
+	"\x13PERMISSION_BASELINE\x10\t\x12\x14\n" +
+	"\x10PERMISSION_AUDIT\x10\n" +
+	"2\xbf\x06\n" +
 	"\x04Auth\x12R\n" +
 	"\bRegister\x12\x15.auth.RegisterRequest\x1a\x16.auth.RegisterResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/register\x12F\n" +
 	"\x05Login\x12\x12.auth.LoginRequest\x1a\x13.auth.LoginResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/login\x12J\n" +
 	"\x06Logout\x12\x13.auth.LogoutRequest\x1a\x14.auth.LogoutResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
-	"/v1/logout\x12u\n" +
-	"\x0fCheckPermission\x12\x17.auth.PermissionRequest\x1a\x18.auth.PermissionResponse\"/\x82\xd3\xe4\x93\x02):\x01*\"$/v1/users/{user_id}/check-permissionB\"\x92A\x10\x1a\x0elocalhost:8081Z\rauth/api/authb\x06proto3"
+	"/v1/logout\x12g\n" +
+	"\rValidateToken\x12\x1a.auth.ValidateTokenRequest\x1a\x1b.auth.ValidateTokenResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/validate-token\x12u\n" +
+	"\x0fCheckPermission\x12\x17.auth.PermissionRequest\x1a\x18.auth.PermissionResponse\"/\x82\xd3\xe4\x93\x02):\x01*\"$/v1/users/{user_id}/check-permission\x12\x83\x01\n" +
+	"\x10CheckPermissions\x12\x1d.auth.CheckPermissionsRequest\x1a\x1e.auth.CheckPermissionsResponse\"0\x82\xd3\xe4\x93\x02*:\x01*\"%/v1/users/{user_id}/check-permissions\x12\x85\x01\n" +
+	"\x16GetPermissionsRevision\x12#.auth.GetPermissionsRevisionRequest\x1a$.auth.GetPermissionsRevisionResponse\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/v1/permissions/revision\x12a\n" +
+	"\x0fListAuditEvents\x12\x1c.auth.ListAuditEventsRequest\x1a\x1d.auth.ListAuditEventsResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/auditB\"\x92A\x10\x1a\x0elocalhost:8081Z\rauth/api/authb\x06proto3"
 
 var (
 	file_auth_auth_proto_rawDescOnce sync.Once
@@ -528,33 +1165,55 @@ func file_auth_auth_proto_rawDescGZIP() []byte {
 }
 
 var file_auth_auth_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_auth_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_auth_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_auth_auth_proto_goTypes = []any{
-	(Permission)(0),            // 0: auth.Permission
-	(*RegisterRequest)(nil),    // 1: auth.RegisterRequest
-	(*RegisterResponse)(nil),   // 2: auth.RegisterResponse
-	(*LoginRequest)(nil),       // 3: auth.LoginRequest
-	(*LoginResponse)(nil),      // 4: auth.LoginResponse
-	(*LogoutRequest)(nil),      // 5: auth.LogoutRequest
-	(*LogoutResponse)(nil),     // 6: auth.LogoutResponse
-	(*PermissionRequest)(nil),  // 7: auth.PermissionRequest
-	(*PermissionResponse)(nil), // 8: auth.PermissionResponse
+	(Permission)(0),                        // 0: auth.Permission
+	(*RegisterRequest)(nil),                // 1: auth.RegisterRequest
+	(*RegisterResponse)(nil),               // 2: auth.RegisterResponse
+	(*LoginRequest)(nil),                   // 3: auth.LoginRequest
+	(*LoginResponse)(nil),                  // 4: auth.LoginResponse
+	(*LogoutRequest)(nil),                  // 5: auth.LogoutRequest
+	(*LogoutResponse)(nil),                 // 6: auth.LogoutResponse
+	(*ValidateTokenRequest)(nil),           // 7: auth.ValidateTokenRequest
+	(*ValidateTokenResponse)(nil),          // 8: auth.ValidateTokenResponse
+	(*PermissionRequest)(nil),              // 9: auth.PermissionRequest
+	(*PermissionResponse)(nil),             // 10: auth.PermissionResponse
+	(*CheckPermissionsRequest)(nil),        // 11: auth.CheckPermissionsRequest
+	(*PermissionDecision)(nil),             // 12: auth.PermissionDecision
+	(*CheckPermissionsResponse)(nil),       // 13: auth.CheckPermissionsResponse
+	(*GetPermissionsRevisionRequest)(nil),  // 14: auth.GetPermissionsRevisionRequest
+	(*GetPermissionsRevisionResponse)(nil), // 15: auth.GetPermissionsRevisionResponse
+	(*AuditEvent)(nil),                     // 16: auth.AuditEvent
+	(*ListAuditEventsRequest)(nil),         // 17: auth.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil),        // 18: auth.ListAuditEventsResponse
 }
 var file_auth_auth_proto_depIdxs = []int32{
-	0, // 0: auth.PermissionRequest.permission:type_name -> auth.Permission
-	1, // 1: auth.Auth.Register:input_type -> auth.RegisterRequest
-	3, // 2: auth.Auth.Login:input_type -> auth.LoginRequest
-	5, // 3: auth.Auth.Logout:input_type -> auth.LogoutRequest
-	7, // 4: auth.Auth.CheckPermission:input_type -> auth.PermissionRequest
-	2, // 5: auth.Auth.Register:output_type -> auth.RegisterResponse
-	4, // 6: auth.Auth.Login:output_type -> auth.LoginResponse
-	6, // 7: auth.Auth.Logout:output_type -> auth.LogoutResponse
-	8, // 8: auth.Auth.CheckPermission:output_type -> auth.PermissionResponse
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: auth.PermissionRequest.permission:type_name -> auth.Permission
+	0,  // 1: auth.CheckPermissionsRequest.permissions:type_name -> auth.Permission
+	0,  // 2: auth.PermissionDecision.permission:type_name -> auth.Permission
+	12, // 3: auth.CheckPermissionsResponse.decisions:type_name -> auth.PermissionDecision
+	16, // 4: auth.ListAuditEventsResponse.events:type_name -> auth.AuditEvent
+	1,  // 5: auth.Auth.Register:input_type -> auth.RegisterRequest
+	3,  // 6: auth.Auth.Login:input_type -> auth.LoginRequest
+	5,  // 7: auth.Auth.Logout:input_type -> auth.LogoutRequest
+	7,  // 8: auth.Auth.ValidateToken:input_type -> auth.ValidateTokenRequest
+	9,  // 9: auth.Auth.CheckPermission:input_type -> auth.PermissionRequest
+	11, // 10: auth.Auth.CheckPermissions:input_type -> auth.CheckPermissionsRequest
+	14, // 11: auth.Auth.GetPermissionsRevision:input_type -> auth.GetPermissionsRevisionRequest
+	17, // 12: auth.Auth.ListAuditEvents:input_type -> auth.ListAuditEventsRequest
+	2,  // 13: auth.Auth.Register:output_type -> auth.RegisterResponse
+	4,  // 14: auth.Auth.Login:output_type -> auth.LoginResponse
+	6,  // 15: auth.Auth.Logout:output_type -> auth.LogoutResponse
+	8,  // 16: auth.Auth.ValidateToken:output_type -> auth.ValidateTokenResponse
+	10, // 17: auth.Auth.CheckPermission:output_type -> auth.PermissionResponse
+	13, // 18: auth.Auth.CheckPermissions:output_type -> auth.CheckPermissionsResponse
+	15, // 19: auth.Auth.GetPermissionsRevision:output_type -> auth.GetPermissionsRevisionResponse
+	18, // 20: auth.Auth.ListAuditEvents:output_type -> auth.ListAuditEventsResponse
+	13, // [13:21] is the sub-list for method output_type
+	5,  // [5:13] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_auth_auth_proto_init() }
@@ -568,7 +1227,7 @@ func file_auth_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_auth_proto_rawDesc), len(file_auth_auth_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   8,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
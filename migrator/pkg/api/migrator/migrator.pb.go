@@ -30,9 +30,17 @@ type CreateMigrationRequest struct {
 	Description    string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`                             // Описание миграции
 	Script         string                 `protobuf:"bytes,3,opt,name=script,proto3" json:"script,omitempty"`                                       // Текст скрипта миграции
 	RollbackScript string                 `protobuf:"bytes,4,opt,name=rollback_script,json=rollbackScript,proto3" json:"rollback_script,omitempty"` // Текст скрипта отката миграции
-	UserId         int64                  `protobuf:"varint,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                        // Идентификатор пользователя, создающего миграцию
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Deprecated: Marked as deprecated in migrator/migrator.proto.
+	UserId        int64             `protobuf:"varint,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                                            // Устарело и игнорируется: пользователь определяется по токену доступа
+	TargetId      int64             `protobuf:"varint,6,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`                                                      // Идентификатор целевой базы данных
+	ExecutionMode string            `protobuf:"bytes,7,opt,name=execution_mode,json=executionMode,proto3" json:"execution_mode,omitempty"`                                        // Режим выполнения: transactional (по умолчанию) или no_transaction
+	Labels        map[string]string `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Метки миграции, например release=2026.10, team=billing
+	TenantScoped  bool              `protobuf:"varint,9,opt,name=tenant_scoped,json=tenantScoped,proto3" json:"tenant_scoped,omitempty"`                                          // Миграция применяется к каждой схеме арендатора через ApplyTenantMigration
+	Kind          string            `protobuf:"bytes,10,opt,name=kind,proto3" json:"kind,omitempty"`                                                                              // Вид миграции: versioned (по умолчанию), repeatable или backfill
+	Backfill      *BackfillSpec     `protobuf:"bytes,11,opt,name=backfill,proto3" json:"backfill,omitempty"`                                                                      // Параметры пачек миграции данных; только для вида backfill
+	Templated     bool              `protobuf:"varint,12,opt,name=templated,proto3" json:"templated,omitempty"`                                                                   // Подставлять в скрипты значения {{ ... }} при применении и откате
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateMigrationRequest) Reset() {
@@ -93,6 +101,7 @@ func (x *CreateMigrationRequest) GetRollbackScript() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in migrator/migrator.proto.
 func (x *CreateMigrationRequest) GetUserId() int64 {
 	if x != nil {
 		return x.UserId
@@ -100,10 +109,60 @@ func (x *CreateMigrationRequest) GetUserId() int64 {
 	return 0
 }
 
+func (x *CreateMigrationRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *CreateMigrationRequest) GetExecutionMode() string {
+	if x != nil {
+		return x.ExecutionMode
+	}
+	return ""
+}
+
+func (x *CreateMigrationRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateMigrationRequest) GetTenantScoped() bool {
+	if x != nil {
+		return x.TenantScoped
+	}
+	return false
+}
+
+func (x *CreateMigrationRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *CreateMigrationRequest) GetBackfill() *BackfillSpec {
+	if x != nil {
+		return x.Backfill
+	}
+	return nil
+}
+
+func (x *CreateMigrationRequest) GetTemplated() bool {
+	if x != nil {
+		return x.Templated
+	}
+	return false
+}
+
 // Ответ на запрос для создания миграции
 type CreateMigrationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор созданной миграции
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"`   // Уникальный идентификатор созданной миграции
+	LintFindings  []*LintFinding         `protobuf:"bytes,2,rep,name=lint_findings,json=lintFindings,proto3" json:"lint_findings,omitempty"` // Замечания линтера, не запретившие создание
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -145,29 +204,37 @@ func (x *CreateMigrationResponse) GetMigrationId() int64 {
 	return 0
 }
 
-// Запрос для применения миграций
-type ApplyMigrationRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MigrationIds  []int64                `protobuf:"varint,1,rep,packed,name=migration_ids,json=migrationIds,proto3" json:"migration_ids,omitempty"` // Уникальные идентификаторы миграций в соответствии с порядком применения
-	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                          // Идентификатор пользователя, применяющего миграцию
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *CreateMigrationResponse) GetLintFindings() []*LintFinding {
+	if x != nil {
+		return x.LintFindings
+	}
+	return nil
 }
 
-func (x *ApplyMigrationRequest) Reset() {
-	*x = ApplyMigrationRequest{}
+// Запрос для проверки скриптов миграции линтером
+type LintMigrationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId    int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"`         // Идентификатор сохраненной миграции; если 0, проверяются переданные скрипты
+	Script         string                 `protobuf:"bytes,2,opt,name=script,proto3" json:"script,omitempty"`                                       // Текст скрипта миграции
+	RollbackScript string                 `protobuf:"bytes,3,opt,name=rollback_script,json=rollbackScript,proto3" json:"rollback_script,omitempty"` // Текст скрипта отката миграции
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *LintMigrationRequest) Reset() {
+	*x = LintMigrationRequest{}
 	mi := &file_migrator_migrator_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ApplyMigrationRequest) String() string {
+func (x *LintMigrationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ApplyMigrationRequest) ProtoMessage() {}
+func (*LintMigrationRequest) ProtoMessage() {}
 
-func (x *ApplyMigrationRequest) ProtoReflect() protoreflect.Message {
+func (x *LintMigrationRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_migrator_migrator_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -179,47 +246,56 @@ func (x *ApplyMigrationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ApplyMigrationRequest.ProtoReflect.Descriptor instead.
-func (*ApplyMigrationRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use LintMigrationRequest.ProtoReflect.Descriptor instead.
+func (*LintMigrationRequest) Descriptor() ([]byte, []int) {
 	return file_migrator_migrator_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *ApplyMigrationRequest) GetMigrationIds() []int64 {
+func (x *LintMigrationRequest) GetMigrationId() int64 {
 	if x != nil {
-		return x.MigrationIds
+		return x.MigrationId
 	}
-	return nil
+	return 0
 }
 
-func (x *ApplyMigrationRequest) GetUserId() int64 {
+func (x *LintMigrationRequest) GetScript() string {
 	if x != nil {
-		return x.UserId
+		return x.Script
 	}
-	return 0
+	return ""
 }
 
-// Ответ на запрос для применения миграций
-type ApplyMigrationResponse struct {
+func (x *LintMigrationRequest) GetRollbackScript() string {
+	if x != nil {
+		return x.RollbackScript
+	}
+	return ""
+}
+
+// Результат проверки скриптов миграции линтером.
+// Также передается в деталях gRPC-ошибки создания миграции, запрещенного линтером.
+type LintMigrationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AppliedAt     string                 `protobuf:"bytes,1,opt,name=applied_at,json=appliedAt,proto3" json:"applied_at,omitempty"` // Дата и время применения миграции
+	Findings      []*LintFinding         `protobuf:"bytes,1,rep,name=findings,proto3" json:"findings,omitempty"` // Замечания линтера
+	Blocked       bool                   `protobuf:"varint,2,opt,name=blocked,proto3" json:"blocked,omitempty"`  // Замечания запрещают создание миграции
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ApplyMigrationResponse) Reset() {
-	*x = ApplyMigrationResponse{}
+func (x *LintMigrationResponse) Reset() {
+	*x = LintMigrationResponse{}
 	mi := &file_migrator_migrator_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ApplyMigrationResponse) String() string {
+func (x *LintMigrationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ApplyMigrationResponse) ProtoMessage() {}
+func (*LintMigrationResponse) ProtoMessage() {}
 
-func (x *ApplyMigrationResponse) ProtoReflect() protoreflect.Message {
+func (x *LintMigrationResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_migrator_migrator_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -231,41 +307,53 @@ func (x *ApplyMigrationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ApplyMigrationResponse.ProtoReflect.Descriptor instead.
-func (*ApplyMigrationResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use LintMigrationResponse.ProtoReflect.Descriptor instead.
+func (*LintMigrationResponse) Descriptor() ([]byte, []int) {
 	return file_migrator_migrator_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ApplyMigrationResponse) GetAppliedAt() string {
+func (x *LintMigrationResponse) GetFindings() []*LintFinding {
 	if x != nil {
-		return x.AppliedAt
+		return x.Findings
 	}
-	return ""
+	return nil
 }
 
-// Запрос для отката миграции
-type RollbackMigrationRequest struct {
+func (x *LintMigrationResponse) GetBlocked() bool {
+	if x != nil {
+		return x.Blocked
+	}
+	return false
+}
+
+// Замечание линтера к скрипту миграции
+type LintFinding struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции
-	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // Идентификатор пользователя, выполняющего откат
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`           // Правило, например drop_table
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`   // Важность: info, warning или error
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`     // Описание замечания
+	Rollback      bool                   `protobuf:"varint,4,opt,name=rollback,proto3" json:"rollback,omitempty"`  // Замечание относится к скрипту отката
+	Line          int32                  `protobuf:"varint,5,opt,name=line,proto3" json:"line,omitempty"`          // Номер строки скрипта, 0 если относится ко всему скрипту
+	Statement     string                 `protobuf:"bytes,6,opt,name=statement,proto3" json:"statement,omitempty"` // Оператор скрипта
+	Blocking      bool                   `protobuf:"varint,7,opt,name=blocking,proto3" json:"blocking,omitempty"`  // Замечание запрещает создание миграции
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RollbackMigrationRequest) Reset() {
-	*x = RollbackMigrationRequest{}
+func (x *LintFinding) Reset() {
+	*x = LintFinding{}
 	mi := &file_migrator_migrator_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RollbackMigrationRequest) String() string {
+func (x *LintFinding) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RollbackMigrationRequest) ProtoMessage() {}
+func (*LintFinding) ProtoMessage() {}
 
-func (x *RollbackMigrationRequest) ProtoReflect() protoreflect.Message {
+func (x *LintFinding) ProtoReflect() protoreflect.Message {
 	mi := &file_migrator_migrator_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -277,47 +365,86 @@ func (x *RollbackMigrationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RollbackMigrationRequest.ProtoReflect.Descriptor instead.
-func (*RollbackMigrationRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use LintFinding.ProtoReflect.Descriptor instead.
+func (*LintFinding) Descriptor() ([]byte, []int) {
 	return file_migrator_migrator_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RollbackMigrationRequest) GetMigrationId() int64 {
+func (x *LintFinding) GetRule() string {
 	if x != nil {
-		return x.MigrationId
+		return x.Rule
 	}
-	return 0
+	return ""
 }
 
-func (x *RollbackMigrationRequest) GetUserId() int64 {
+func (x *LintFinding) GetSeverity() string {
 	if x != nil {
-		return x.UserId
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *LintFinding) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LintFinding) GetRollback() bool {
+	if x != nil {
+		return x.Rollback
+	}
+	return false
+}
+
+func (x *LintFinding) GetLine() int32 {
+	if x != nil {
+		return x.Line
 	}
 	return 0
 }
 
-// Ответ на запрос для отката миграции
-type RollbackMigrationResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RolledBackAt  string                 `protobuf:"bytes,1,opt,name=rolled_back_at,json=rolledBackAt,proto3" json:"rolled_back_at,omitempty"` // Дата и время отката миграции
+func (x *LintFinding) GetStatement() string {
+	if x != nil {
+		return x.Statement
+	}
+	return ""
+}
+
+func (x *LintFinding) GetBlocking() bool {
+	if x != nil {
+		return x.Blocking
+	}
+	return false
+}
+
+// Запрос для применения миграций
+type ApplyMigrationRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	MigrationIds []int64                `protobuf:"varint,1,rep,packed,name=migration_ids,json=migrationIds,proto3" json:"migration_ids,omitempty"` // Уникальные идентификаторы миграций в соответствии с порядком применения
+	// Deprecated: Marked as deprecated in migrator/migrator.proto.
+	UserId        int64  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // Устарело и игнорируется: пользователь определяется по токену доступа
+	TargetId      int64  `protobuf:"varint,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	Selector      string `protobuf:"bytes,4,opt,name=selector,proto3" json:"selector,omitempty"`                  // Селектор меток вместо migration_ids: применяются подходящие миграции, ожидающие применения, в порядке создания
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RollbackMigrationResponse) Reset() {
-	*x = RollbackMigrationResponse{}
+func (x *ApplyMigrationRequest) Reset() {
+	*x = ApplyMigrationRequest{}
 	mi := &file_migrator_migrator_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RollbackMigrationResponse) String() string {
+func (x *ApplyMigrationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RollbackMigrationResponse) ProtoMessage() {}
+func (*ApplyMigrationRequest) ProtoMessage() {}
 
-func (x *RollbackMigrationResponse) ProtoReflect() protoreflect.Message {
+func (x *ApplyMigrationRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_migrator_migrator_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -329,40 +456,63 @@ func (x *RollbackMigrationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RollbackMigrationResponse.ProtoReflect.Descriptor instead.
-func (*RollbackMigrationResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ApplyMigrationRequest.ProtoReflect.Descriptor instead.
+func (*ApplyMigrationRequest) Descriptor() ([]byte, []int) {
 	return file_migrator_migrator_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *RollbackMigrationResponse) GetRolledBackAt() string {
+func (x *ApplyMigrationRequest) GetMigrationIds() []int64 {
 	if x != nil {
-		return x.RolledBackAt
+		return x.MigrationIds
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in migrator/migrator.proto.
+func (x *ApplyMigrationRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ApplyMigrationRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *ApplyMigrationRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
 	}
 	return ""
 }
 
-// Запрос для получения списка миграций
-type ListMigrationsRequest struct {
+// Ответ на запрос для применения миграций
+type ApplyMigrationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // Фильтр по статусу (например, "pending", "applied", "rolled_back")
+	AppliedAt     string                 `protobuf:"bytes,1,opt,name=applied_at,json=appliedAt,proto3" json:"applied_at,omitempty"`                  // Дата и время применения миграции
+	MigrationIds  []int64                `protobuf:"varint,2,rep,packed,name=migration_ids,json=migrationIds,proto3" json:"migration_ids,omitempty"` // Идентификаторы примененных миграций в порядке применения
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListMigrationsRequest) Reset() {
-	*x = ListMigrationsRequest{}
+func (x *ApplyMigrationResponse) Reset() {
+	*x = ApplyMigrationResponse{}
 	mi := &file_migrator_migrator_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListMigrationsRequest) String() string {
+func (x *ApplyMigrationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListMigrationsRequest) ProtoMessage() {}
+func (*ApplyMigrationResponse) ProtoMessage() {}
 
-func (x *ListMigrationsRequest) ProtoReflect() protoreflect.Message {
+func (x *ApplyMigrationResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_migrator_migrator_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -374,47 +524,50 @@ func (x *ListMigrationsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListMigrationsRequest.ProtoReflect.Descriptor instead.
-func (*ListMigrationsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ApplyMigrationResponse.ProtoReflect.Descriptor instead.
+func (*ApplyMigrationResponse) Descriptor() ([]byte, []int) {
 	return file_migrator_migrator_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ListMigrationsRequest) GetStatus() string {
+func (x *ApplyMigrationResponse) GetAppliedAt() string {
 	if x != nil {
-		return x.Status
+		return x.AppliedAt
 	}
 	return ""
 }
 
-// Информация о миграции
-type MigrationInfo struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                                   // Уникальный идентификатор миграции
-	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                                // Название миграции
-	Description     string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`                                  // Описание миграции
-	Script          string                 `protobuf:"bytes,4,opt,name=script,proto3" json:"script,omitempty"`                                            // Текст скрипта миграции
-	RollbackScript  string                 `protobuf:"bytes,5,opt,name=rollback_script,json=rollbackScript,proto3" json:"rollback_script,omitempty"`      // Текст скрипта отката миграции
-	Status          string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`                                            // Текущий статус миграции
-	CreatedBy       int64                  `protobuf:"varint,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`                    // Идентификатор пользователя, применившего миграцию
-	StatusUpdatedAt string                 `protobuf:"bytes,8,opt,name=status_updated_at,json=statusUpdatedAt,proto3" json:"status_updated_at,omitempty"` // Дата и время обновления статуса миграции
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *ApplyMigrationResponse) GetMigrationIds() []int64 {
+	if x != nil {
+		return x.MigrationIds
+	}
+	return nil
 }
 
-func (x *MigrationInfo) Reset() {
-	*x = MigrationInfo{}
+// Запрос для отката миграции
+type RollbackMigrationRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции
+	// Deprecated: Marked as deprecated in migrator/migrator.proto.
+	UserId        int64 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // Устарело и игнорируется: пользователь определяется по токену доступа
+	TargetId      int64 `protobuf:"varint,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackMigrationRequest) Reset() {
+	*x = RollbackMigrationRequest{}
 	mi := &file_migrator_migrator_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MigrationInfo) String() string {
+func (x *RollbackMigrationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MigrationInfo) ProtoMessage() {}
+func (*RollbackMigrationRequest) ProtoMessage() {}
 
-func (x *MigrationInfo) ProtoReflect() protoreflect.Message {
+func (x *RollbackMigrationRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_migrator_migrator_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -426,90 +579,4842 @@ func (x *MigrationInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MigrationInfo.ProtoReflect.Descriptor instead.
-func (*MigrationInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use RollbackMigrationRequest.ProtoReflect.Descriptor instead.
+func (*RollbackMigrationRequest) Descriptor() ([]byte, []int) {
 	return file_migrator_migrator_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *MigrationInfo) GetId() int64 {
+func (x *RollbackMigrationRequest) GetMigrationId() int64 {
 	if x != nil {
-		return x.Id
+		return x.MigrationId
 	}
 	return 0
 }
 
-func (x *MigrationInfo) GetName() string {
+// Deprecated: Marked as deprecated in migrator/migrator.proto.
+func (x *RollbackMigrationRequest) GetUserId() int64 {
 	if x != nil {
-		return x.Name
+		return x.UserId
 	}
-	return ""
+	return 0
 }
 
-func (x *MigrationInfo) GetDescription() string {
+func (x *RollbackMigrationRequest) GetTargetId() int64 {
 	if x != nil {
-		return x.Description
+		return x.TargetId
 	}
-	return ""
+	return 0
 }
 
-func (x *MigrationInfo) GetScript() string {
-	if x != nil {
-		return x.Script
-	}
-	return ""
+// Ответ на запрос для отката миграции
+type RollbackMigrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RolledBackAt  string                 `protobuf:"bytes,1,opt,name=rolled_back_at,json=rolledBackAt,proto3" json:"rolled_back_at,omitempty"` // Дата и время отката миграции
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MigrationInfo) GetRollbackScript() string {
-	if x != nil {
-		return x.RollbackScript
-	}
-	return ""
+func (x *RollbackMigrationResponse) Reset() {
+	*x = RollbackMigrationResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackMigrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackMigrationResponse) ProtoMessage() {}
+
+func (x *RollbackMigrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackMigrationResponse.ProtoReflect.Descriptor instead.
+func (*RollbackMigrationResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RollbackMigrationResponse) GetRolledBackAt() string {
+	if x != nil {
+		return x.RolledBackAt
+	}
+	return ""
+}
+
+// Запрос для отката до миграции
+type RollbackToMigrationRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции, которая останется последней примененной
+	// Deprecated: Marked as deprecated in migrator/migrator.proto.
+	UserId        int64 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // Устарело и игнорируется: пользователь определяется по токену доступа
+	TargetId      int64 `protobuf:"varint,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackToMigrationRequest) Reset() {
+	*x = RollbackToMigrationRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackToMigrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackToMigrationRequest) ProtoMessage() {}
+
+func (x *RollbackToMigrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackToMigrationRequest.ProtoReflect.Descriptor instead.
+func (*RollbackToMigrationRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RollbackToMigrationRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in migrator/migrator.proto.
+func (x *RollbackToMigrationRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RollbackToMigrationRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+// Ответ на запрос для отката до миграции
+type RollbackToMigrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RolledBackIds []int64                `protobuf:"varint,1,rep,packed,name=rolled_back_ids,json=rolledBackIds,proto3" json:"rolled_back_ids,omitempty"` // Идентификаторы откаченных миграций в порядке отката
+	RolledBackAt  string                 `protobuf:"bytes,2,opt,name=rolled_back_at,json=rolledBackAt,proto3" json:"rolled_back_at,omitempty"`            // Дата и время отката
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackToMigrationResponse) Reset() {
+	*x = RollbackToMigrationResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackToMigrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackToMigrationResponse) ProtoMessage() {}
+
+func (x *RollbackToMigrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackToMigrationResponse.ProtoReflect.Descriptor instead.
+func (*RollbackToMigrationResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RollbackToMigrationResponse) GetRolledBackIds() []int64 {
+	if x != nil {
+		return x.RolledBackIds
+	}
+	return nil
+}
+
+func (x *RollbackToMigrationResponse) GetRolledBackAt() string {
+	if x != nil {
+		return x.RolledBackAt
+	}
+	return ""
+}
+
+// Запрос для отметки миграций примененными без выполнения скриптов.
+// Миграции задаются списком migration_ids или диапазоном from_migration_id..to_migration_id
+type BaselineMigrationsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TargetId        int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`                        // Идентификатор целевой базы данных
+	MigrationIds    []int64                `protobuf:"varint,2,rep,packed,name=migration_ids,json=migrationIds,proto3" json:"migration_ids,omitempty"`     // Уникальные идентификаторы миграций
+	FromMigrationId int64                  `protobuf:"varint,3,opt,name=from_migration_id,json=fromMigrationId,proto3" json:"from_migration_id,omitempty"` // Первый идентификатор диапазона (включительно)
+	ToMigrationId   int64                  `protobuf:"varint,4,opt,name=to_migration_id,json=toMigrationId,proto3" json:"to_migration_id,omitempty"`       // Последний идентификатор диапазона (включительно)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BaselineMigrationsRequest) Reset() {
+	*x = BaselineMigrationsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BaselineMigrationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BaselineMigrationsRequest) ProtoMessage() {}
+
+func (x *BaselineMigrationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BaselineMigrationsRequest.ProtoReflect.Descriptor instead.
+func (*BaselineMigrationsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BaselineMigrationsRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *BaselineMigrationsRequest) GetMigrationIds() []int64 {
+	if x != nil {
+		return x.MigrationIds
+	}
+	return nil
+}
+
+func (x *BaselineMigrationsRequest) GetFromMigrationId() int64 {
+	if x != nil {
+		return x.FromMigrationId
+	}
+	return 0
+}
+
+func (x *BaselineMigrationsRequest) GetToMigrationId() int64 {
+	if x != nil {
+		return x.ToMigrationId
+	}
+	return 0
+}
+
+// Ответ на запрос для отметки миграций примененными
+type BaselineMigrationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaselinedIds  []int64                `protobuf:"varint,1,rep,packed,name=baselined_ids,json=baselinedIds,proto3" json:"baselined_ids,omitempty"` // Идентификаторы отмеченных миграций
+	BaselinedAt   string                 `protobuf:"bytes,2,opt,name=baselined_at,json=baselinedAt,proto3" json:"baselined_at,omitempty"`            // Дата и время отметки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BaselineMigrationsResponse) Reset() {
+	*x = BaselineMigrationsResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BaselineMigrationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BaselineMigrationsResponse) ProtoMessage() {}
+
+func (x *BaselineMigrationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BaselineMigrationsResponse.ProtoReflect.Descriptor instead.
+func (*BaselineMigrationsResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BaselineMigrationsResponse) GetBaselinedIds() []int64 {
+	if x != nil {
+		return x.BaselinedIds
+	}
+	return nil
+}
+
+func (x *BaselineMigrationsResponse) GetBaselinedAt() string {
+	if x != nil {
+		return x.BaselinedAt
+	}
+	return ""
+}
+
+// Запрос для замены скрипта повторяемой миграции
+type UpdateRepeatableMigrationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор повторяемой миграции
+	Script        string                 `protobuf:"bytes,2,opt,name=script,proto3" json:"script,omitempty"`                               // Новый текст скрипта миграции
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRepeatableMigrationRequest) Reset() {
+	*x = UpdateRepeatableMigrationRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRepeatableMigrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRepeatableMigrationRequest) ProtoMessage() {}
+
+func (x *UpdateRepeatableMigrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRepeatableMigrationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRepeatableMigrationRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateRepeatableMigrationRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *UpdateRepeatableMigrationRequest) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+// Ответ на запрос для замены скрипта повторяемой миграции
+type UpdateRepeatableMigrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pending       bool                   `protobuf:"varint,1,opt,name=pending,proto3" json:"pending,omitempty"`                              // Скрипт отличается от выполненного, миграция ожидает применения
+	LintFindings  []*LintFinding         `protobuf:"bytes,2,rep,name=lint_findings,json=lintFindings,proto3" json:"lint_findings,omitempty"` // Замечания линтера, не запретившие изменение
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRepeatableMigrationResponse) Reset() {
+	*x = UpdateRepeatableMigrationResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRepeatableMigrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRepeatableMigrationResponse) ProtoMessage() {}
+
+func (x *UpdateRepeatableMigrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRepeatableMigrationResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRepeatableMigrationResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateRepeatableMigrationResponse) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+func (x *UpdateRepeatableMigrationResponse) GetLintFindings() []*LintFinding {
+	if x != nil {
+		return x.LintFindings
+	}
+	return nil
+}
+
+// Выполненная ревизия скрипта повторяемой миграции
+type MigrationRevision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      int64                  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`                    // Порядковый номер выполнения, начиная с 1
+	Checksum      string                 `protobuf:"bytes,2,opt,name=checksum,proto3" json:"checksum,omitempty"`                     // Контрольная сумма скрипта
+	Script        string                 `protobuf:"bytes,3,opt,name=script,proto3" json:"script,omitempty"`                         // Выполненный скрипт после подстановки переменных шаблона
+	AppliedBy     int64                  `protobuf:"varint,4,opt,name=applied_by,json=appliedBy,proto3" json:"applied_by,omitempty"` // Идентификатор пользователя, применившего ревизию
+	AppliedAt     string                 `protobuf:"bytes,5,opt,name=applied_at,json=appliedAt,proto3" json:"applied_at,omitempty"`  // Дата и время применения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrationRevision) Reset() {
+	*x = MigrationRevision{}
+	mi := &file_migrator_migrator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrationRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrationRevision) ProtoMessage() {}
+
+func (x *MigrationRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrationRevision.ProtoReflect.Descriptor instead.
+func (*MigrationRevision) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *MigrationRevision) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *MigrationRevision) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *MigrationRevision) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+func (x *MigrationRevision) GetAppliedBy() int64 {
+	if x != nil {
+		return x.AppliedBy
+	}
+	return 0
+}
+
+func (x *MigrationRevision) GetAppliedAt() string {
+	if x != nil {
+		return x.AppliedAt
+	}
+	return ""
+}
+
+// Запрос для получения ревизий повторяемой миграции
+type ListMigrationRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор повторяемой миграции
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMigrationRevisionsRequest) Reset() {
+	*x = ListMigrationRevisionsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMigrationRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMigrationRevisionsRequest) ProtoMessage() {}
+
+func (x *ListMigrationRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMigrationRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListMigrationRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListMigrationRevisionsRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+// Ответ на запрос для получения ревизий повторяемой миграции
+type ListMigrationRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*MigrationRevision   `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"` // Ревизии, начиная с последней выполненной
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMigrationRevisionsResponse) Reset() {
+	*x = ListMigrationRevisionsResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMigrationRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMigrationRevisionsResponse) ProtoMessage() {}
+
+func (x *ListMigrationRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMigrationRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListMigrationRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListMigrationRevisionsResponse) GetRevisions() []*MigrationRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+// Запрос для применения миграции арендаторов
+type ApplyTenantMigrationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции арендаторов
+	TargetId      int64                  `protobuf:"varint,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`          // Идентификатор целевой базы данных
+	Tenants       []string               `protobuf:"bytes,3,rep,name=tenants,proto3" json:"tenants,omitempty"`                             // Схемы арендаторов; если не заданы, все найденные схемы
+	Concurrency   int32                  `protobuf:"varint,4,opt,name=concurrency,proto3" json:"concurrency,omitempty"`                    // Число схем, обрабатываемых одновременно; 0 - значение из конфигурации
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyTenantMigrationRequest) Reset() {
+	*x = ApplyTenantMigrationRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyTenantMigrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyTenantMigrationRequest) ProtoMessage() {}
+
+func (x *ApplyTenantMigrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyTenantMigrationRequest.ProtoReflect.Descriptor instead.
+func (*ApplyTenantMigrationRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ApplyTenantMigrationRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *ApplyTenantMigrationRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *ApplyTenantMigrationRequest) GetTenants() []string {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+func (x *ApplyTenantMigrationRequest) GetConcurrency() int32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+// Состояние миграции арендаторов в схеме арендатора
+type TenantStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        string                 `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`                        // Схема арендатора
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                        // Статус: pending, applied или failed
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`                          // Текст ошибки последнего выполнения
+	UpdatedAt     string                 `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Дата и время последнего выполнения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TenantStatus) Reset() {
+	*x = TenantStatus{}
+	mi := &file_migrator_migrator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TenantStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenantStatus) ProtoMessage() {}
+
+func (x *TenantStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenantStatus.ProtoReflect.Descriptor instead.
+func (*TenantStatus) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TenantStatus) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *TenantStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TenantStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TenantStatus) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+// Ответ на запрос для применения миграции арендаторов
+type ApplyTenantMigrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Applied       []string               `protobuf:"bytes,1,rep,name=applied,proto3" json:"applied,omitempty"`                         // Схемы, к которым миграция применена
+	Skipped       []string               `protobuf:"bytes,2,rep,name=skipped,proto3" json:"skipped,omitempty"`                         // Схемы, к которым миграция была применена ранее
+	Failed        []*TenantStatus        `protobuf:"bytes,3,rep,name=failed,proto3" json:"failed,omitempty"`                           // Схемы, в которых скрипт завершился ошибкой; их можно применить повторно
+	FinishedAt    string                 `protobuf:"bytes,4,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"` // Дата и время окончания применения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyTenantMigrationResponse) Reset() {
+	*x = ApplyTenantMigrationResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyTenantMigrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyTenantMigrationResponse) ProtoMessage() {}
+
+func (x *ApplyTenantMigrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyTenantMigrationResponse.ProtoReflect.Descriptor instead.
+func (*ApplyTenantMigrationResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ApplyTenantMigrationResponse) GetApplied() []string {
+	if x != nil {
+		return x.Applied
+	}
+	return nil
+}
+
+func (x *ApplyTenantMigrationResponse) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+func (x *ApplyTenantMigrationResponse) GetFailed() []*TenantStatus {
+	if x != nil {
+		return x.Failed
+	}
+	return nil
+}
+
+func (x *ApplyTenantMigrationResponse) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+// Параметры миграции данных. Запрос курсора выбирает ключи следующей пачки после
+// {{ cursor }} в порядке возрастания, не больше {{ batch_size }}; скрипт миграции
+// обрабатывает строки с ключами от {{ cursor }} (не включая) до {{ next_cursor }} (включая)
+// и должен быть идемпотентным: после сбоя пачка может выполниться повторно.
+type BackfillSpec struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CursorQuery   string                 `protobuf:"bytes,1,opt,name=cursor_query,json=cursorQuery,proto3" json:"cursor_query,omitempty"`       // Запрос ключей следующей пачки строк
+	EstimateQuery string                 `protobuf:"bytes,2,opt,name=estimate_query,json=estimateQuery,proto3" json:"estimate_query,omitempty"` // Запрос оценки общего числа строк; необязателен
+	BatchSize     int32                  `protobuf:"varint,3,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`            // Начальный размер пачки
+	StartCursor   string                 `protobuf:"bytes,4,opt,name=start_cursor,json=startCursor,proto3" json:"start_cursor,omitempty"`       // Курсор перед первой пачкой
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackfillSpec) Reset() {
+	*x = BackfillSpec{}
+	mi := &file_migrator_migrator_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackfillSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackfillSpec) ProtoMessage() {}
+
+func (x *BackfillSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackfillSpec.ProtoReflect.Descriptor instead.
+func (*BackfillSpec) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BackfillSpec) GetCursorQuery() string {
+	if x != nil {
+		return x.CursorQuery
+	}
+	return ""
+}
+
+func (x *BackfillSpec) GetEstimateQuery() string {
+	if x != nil {
+		return x.EstimateQuery
+	}
+	return ""
+}
+
+func (x *BackfillSpec) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *BackfillSpec) GetStartCursor() string {
+	if x != nil {
+		return x.StartCursor
+	}
+	return ""
+}
+
+// Ход выполнения миграции данных
+type BackfillInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId    int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"`          // Уникальный идентификатор миграции данных
+	TargetId       int64                  `protobuf:"varint,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`                   // Идентификатор целевой базы данных
+	State          string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`                                          // Состояние: running, paused, completed или failed
+	Cursor         string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`                                        // Ключ последней обработанной строки
+	RowsDone       int64                  `protobuf:"varint,5,opt,name=rows_done,json=rowsDone,proto3" json:"rows_done,omitempty"`                   // Число обработанных строк
+	EstimatedTotal int64                  `protobuf:"varint,6,opt,name=estimated_total,json=estimatedTotal,proto3" json:"estimated_total,omitempty"` // Оценка общего числа строк; -1, если неизвестна
+	BatchSize      int32                  `protobuf:"varint,7,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`                // Текущий размер пачки
+	PauseMs        int64                  `protobuf:"varint,8,opt,name=pause_ms,json=pauseMs,proto3" json:"pause_ms,omitempty"`                      // Пауза между пачками в миллисекундах
+	Error          string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`                                          // Ошибка последней пачки
+	Owner          string                 `protobuf:"bytes,10,opt,name=owner,proto3" json:"owner,omitempty"`                                         // Реплика сервиса, выполняющая пачки
+	StartedBy      int64                  `protobuf:"varint,11,opt,name=started_by,json=startedBy,proto3" json:"started_by,omitempty"`               // Идентификатор пользователя, запустившего миграцию данных
+	StartedAt      string                 `protobuf:"bytes,12,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`                // Дата и время запуска
+	UpdatedAt      string                 `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                // Дата и время последнего изменения
+	FinishedAt     string                 `protobuf:"bytes,14,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`             // Дата и время завершения
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BackfillInfo) Reset() {
+	*x = BackfillInfo{}
+	mi := &file_migrator_migrator_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackfillInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackfillInfo) ProtoMessage() {}
+
+func (x *BackfillInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackfillInfo.ProtoReflect.Descriptor instead.
+func (*BackfillInfo) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BackfillInfo) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *BackfillInfo) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *BackfillInfo) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *BackfillInfo) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *BackfillInfo) GetRowsDone() int64 {
+	if x != nil {
+		return x.RowsDone
+	}
+	return 0
+}
+
+func (x *BackfillInfo) GetEstimatedTotal() int64 {
+	if x != nil {
+		return x.EstimatedTotal
+	}
+	return 0
+}
+
+func (x *BackfillInfo) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *BackfillInfo) GetPauseMs() int64 {
+	if x != nil {
+		return x.PauseMs
+	}
+	return 0
+}
+
+func (x *BackfillInfo) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BackfillInfo) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *BackfillInfo) GetStartedBy() int64 {
+	if x != nil {
+		return x.StartedBy
+	}
+	return 0
+}
+
+func (x *BackfillInfo) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *BackfillInfo) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *BackfillInfo) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+// Запрос для запуска миграции данных
+type StartBackfillRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartBackfillRequest) Reset() {
+	*x = StartBackfillRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartBackfillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartBackfillRequest) ProtoMessage() {}
+
+func (x *StartBackfillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartBackfillRequest.ProtoReflect.Descriptor instead.
+func (*StartBackfillRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StartBackfillRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+// Запрос для приостановки миграции данных
+type PauseBackfillRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseBackfillRequest) Reset() {
+	*x = PauseBackfillRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseBackfillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseBackfillRequest) ProtoMessage() {}
+
+func (x *PauseBackfillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseBackfillRequest.ProtoReflect.Descriptor instead.
+func (*PauseBackfillRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PauseBackfillRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+// Запрос для продолжения миграции данных
+type ResumeBackfillRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeBackfillRequest) Reset() {
+	*x = ResumeBackfillRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeBackfillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeBackfillRequest) ProtoMessage() {}
+
+func (x *ResumeBackfillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeBackfillRequest.ProtoReflect.Descriptor instead.
+func (*ResumeBackfillRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ResumeBackfillRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+// Запрос для изменения скорости миграции данных
+type ThrottleBackfillRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции данных
+	BatchSize     int32                  `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`       // Новый размер пачки; 0 оставляет текущий
+	PauseMs       int64                  `protobuf:"varint,3,opt,name=pause_ms,json=pauseMs,proto3" json:"pause_ms,omitempty"`             // Пауза между пачками в миллисекундах
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThrottleBackfillRequest) Reset() {
+	*x = ThrottleBackfillRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThrottleBackfillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThrottleBackfillRequest) ProtoMessage() {}
+
+func (x *ThrottleBackfillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThrottleBackfillRequest.ProtoReflect.Descriptor instead.
+func (*ThrottleBackfillRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ThrottleBackfillRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *ThrottleBackfillRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *ThrottleBackfillRequest) GetPauseMs() int64 {
+	if x != nil {
+		return x.PauseMs
+	}
+	return 0
+}
+
+// Запрос для получения хода выполнения миграции данных
+type GetBackfillRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBackfillRequest) Reset() {
+	*x = GetBackfillRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBackfillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBackfillRequest) ProtoMessage() {}
+
+func (x *GetBackfillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBackfillRequest.ProtoReflect.Descriptor instead.
+func (*GetBackfillRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetBackfillRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+// Ответ с ходом выполнения миграции данных
+type BackfillResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backfill      *BackfillInfo          `protobuf:"bytes,1,opt,name=backfill,proto3" json:"backfill,omitempty"` // Ход выполнения миграции данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackfillResponse) Reset() {
+	*x = BackfillResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackfillResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackfillResponse) ProtoMessage() {}
+
+func (x *BackfillResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackfillResponse.ProtoReflect.Descriptor instead.
+func (*BackfillResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *BackfillResponse) GetBackfill() *BackfillInfo {
+	if x != nil {
+		return x.Backfill
+	}
+	return nil
+}
+
+// Запрос для получения состояния миграции арендаторов
+type ListTenantStatusesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции арендаторов
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantStatusesRequest) Reset() {
+	*x = ListTenantStatusesRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantStatusesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantStatusesRequest) ProtoMessage() {}
+
+func (x *ListTenantStatusesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantStatusesRequest.ProtoReflect.Descriptor instead.
+func (*ListTenantStatusesRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListTenantStatusesRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+// Ответ на запрос для получения состояния миграции арендаторов
+type ListTenantStatusesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenants       []*TenantStatus        `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"` // Состояние миграции в схемах в порядке названий
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantStatusesResponse) Reset() {
+	*x = ListTenantStatusesResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantStatusesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantStatusesResponse) ProtoMessage() {}
+
+func (x *ListTenantStatusesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantStatusesResponse.ProtoReflect.Descriptor instead.
+func (*ListTenantStatusesResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListTenantStatusesResponse) GetTenants() []*TenantStatus {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+// Результат пробного выполнения одной миграции
+type MigrationPlanItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"`     // Уникальный идентификатор миграции
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                       // Название миграции
+	Order         int32                  `protobuf:"varint,3,opt,name=order,proto3" json:"order,omitempty"`                                    // Порядковый номер выполнения в плане
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                   // Текущий статус миграции
+	Rejected      bool                   `protobuf:"varint,5,opt,name=rejected,proto3" json:"rejected,omitempty"`                              // Миграция была бы отклонена без выполнения
+	RejectReason  string                 `protobuf:"bytes,6,opt,name=reject_reason,json=rejectReason,proto3" json:"reject_reason,omitempty"`   // Причина отклонения
+	Success       bool                   `protobuf:"varint,7,opt,name=success,proto3" json:"success,omitempty"`                                // Скрипт выполнен без ошибок
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`                                     // Текст ошибки выполнения скрипта
+	DurationMs    int64                  `protobuf:"varint,9,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`        // Длительность выполнения скрипта в миллисекундах
+	RowsAffected  int64                  `protobuf:"varint,10,opt,name=rows_affected,json=rowsAffected,proto3" json:"rows_affected,omitempty"` // Число затронутых строк
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrationPlanItem) Reset() {
+	*x = MigrationPlanItem{}
+	mi := &file_migrator_migrator_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrationPlanItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrationPlanItem) ProtoMessage() {}
+
+func (x *MigrationPlanItem) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrationPlanItem.ProtoReflect.Descriptor instead.
+func (*MigrationPlanItem) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MigrationPlanItem) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *MigrationPlanItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MigrationPlanItem) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+func (x *MigrationPlanItem) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MigrationPlanItem) GetRejected() bool {
+	if x != nil {
+		return x.Rejected
+	}
+	return false
+}
+
+func (x *MigrationPlanItem) GetRejectReason() string {
+	if x != nil {
+		return x.RejectReason
+	}
+	return ""
+}
+
+func (x *MigrationPlanItem) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MigrationPlanItem) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *MigrationPlanItem) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *MigrationPlanItem) GetRowsAffected() int64 {
+	if x != nil {
+		return x.RowsAffected
+	}
+	return 0
+}
+
+// Ответ на запрос пробного применения или отката миграций
+type MigrationPlanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`                      // Действие: "apply" или "rollback"
+	TargetId      int64                  `protobuf:"varint,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	Success       bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`                   // Все миграции плана выполнены бы успешно
+	Items         []*MigrationPlanItem   `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`                        // Отчет по каждой миграции в порядке выполнения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrationPlanResponse) Reset() {
+	*x = MigrationPlanResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrationPlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrationPlanResponse) ProtoMessage() {}
+
+func (x *MigrationPlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrationPlanResponse.ProtoReflect.Descriptor instead.
+func (*MigrationPlanResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *MigrationPlanResponse) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *MigrationPlanResponse) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *MigrationPlanResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MigrationPlanResponse) GetItems() []*MigrationPlanItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Запрос для получения списка миграций
+type ListMigrationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`                      // Фильтр по статусу (например, "pending", "applied", "rolled_back")
+	TargetId      int64                  `protobuf:"varint,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	Selector      string                 `protobuf:"bytes,3,opt,name=selector,proto3" json:"selector,omitempty"`                  // Селектор меток, например "release=2026.10,team!=billing,!experimental"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMigrationsRequest) Reset() {
+	*x = ListMigrationsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMigrationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMigrationsRequest) ProtoMessage() {}
+
+func (x *ListMigrationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMigrationsRequest.ProtoReflect.Descriptor instead.
+func (*ListMigrationsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListMigrationsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListMigrationsRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *ListMigrationsRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+// Информация о миграции
+type MigrationInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                                                                   // Уникальный идентификатор миграции
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                                                                // Название миграции
+	Description     string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`                                                                  // Описание миграции
+	Script          string                 `protobuf:"bytes,4,opt,name=script,proto3" json:"script,omitempty"`                                                                            // Текст скрипта миграции
+	RollbackScript  string                 `protobuf:"bytes,5,opt,name=rollback_script,json=rollbackScript,proto3" json:"rollback_script,omitempty"`                                      // Текст скрипта отката миграции
+	Status          string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`                                                                            // Текущий статус миграции
+	CreatedBy       int64                  `protobuf:"varint,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`                                                    // Идентификатор пользователя, применившего миграцию
+	StatusUpdatedAt string                 `protobuf:"bytes,8,opt,name=status_updated_at,json=statusUpdatedAt,proto3" json:"status_updated_at,omitempty"`                                 // Дата и время обновления статуса миграции
+	TargetId        int64                  `protobuf:"varint,9,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`                                                       // Идентификатор целевой базы данных
+	Checksum        string                 `protobuf:"bytes,10,opt,name=checksum,proto3" json:"checksum,omitempty"`                                                                       // Контрольная сумма скриптов, записанная при создании
+	AppliedChecksum string                 `protobuf:"bytes,11,opt,name=applied_checksum,json=appliedChecksum,proto3" json:"applied_checksum,omitempty"`                                  // Контрольная сумма скриптов, записанная при применении
+	ExecutionMode   string                 `protobuf:"bytes,12,opt,name=execution_mode,json=executionMode,proto3" json:"execution_mode,omitempty"`                                        // Режим выполнения: transactional или no_transaction
+	LastError       *ScriptError           `protobuf:"bytes,13,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`                                                    // Ошибка базы данных при последнем выполнении скрипта
+	Baselined       bool                   `protobuf:"varint,14,opt,name=baselined,proto3" json:"baselined,omitempty"`                                                                    // Миграция отмечена примененной без выполнения скрипта
+	BaselinedBy     int64                  `protobuf:"varint,15,opt,name=baselined_by,json=baselinedBy,proto3" json:"baselined_by,omitempty"`                                             // Идентификатор пользователя, отметившего миграцию
+	Labels          map[string]string      `protobuf:"bytes,16,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Метки миграции
+	TenantScoped    bool                   `protobuf:"varint,17,opt,name=tenant_scoped,json=tenantScoped,proto3" json:"tenant_scoped,omitempty"`                                          // Миграция применяется к схемам арендаторов
+	Kind            string                 `protobuf:"bytes,18,opt,name=kind,proto3" json:"kind,omitempty"`                                                                               // Вид миграции: versioned, repeatable или backfill
+	Backfill        *BackfillSpec          `protobuf:"bytes,19,opt,name=backfill,proto3" json:"backfill,omitempty"`                                                                       // Параметры пачек миграции данных
+	Templated       bool                   `protobuf:"varint,20,opt,name=templated,proto3" json:"templated,omitempty"`                                                                    // В скрипты подставляются значения {{ ... }}
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MigrationInfo) Reset() {
+	*x = MigrationInfo{}
+	mi := &file_migrator_migrator_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrationInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrationInfo) ProtoMessage() {}
+
+func (x *MigrationInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrationInfo.ProtoReflect.Descriptor instead.
+func (*MigrationInfo) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *MigrationInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MigrationInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MigrationInfo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *MigrationInfo) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+func (x *MigrationInfo) GetRollbackScript() string {
+	if x != nil {
+		return x.RollbackScript
+	}
+	return ""
 }
 
 func (x *MigrationInfo) GetStatus() string {
 	if x != nil {
-		return x.Status
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MigrationInfo) GetCreatedBy() int64 {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return 0
+}
+
+func (x *MigrationInfo) GetStatusUpdatedAt() string {
+	if x != nil {
+		return x.StatusUpdatedAt
+	}
+	return ""
+}
+
+func (x *MigrationInfo) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *MigrationInfo) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *MigrationInfo) GetAppliedChecksum() string {
+	if x != nil {
+		return x.AppliedChecksum
+	}
+	return ""
+}
+
+func (x *MigrationInfo) GetExecutionMode() string {
+	if x != nil {
+		return x.ExecutionMode
+	}
+	return ""
+}
+
+func (x *MigrationInfo) GetLastError() *ScriptError {
+	if x != nil {
+		return x.LastError
+	}
+	return nil
+}
+
+func (x *MigrationInfo) GetBaselined() bool {
+	if x != nil {
+		return x.Baselined
+	}
+	return false
+}
+
+func (x *MigrationInfo) GetBaselinedBy() int64 {
+	if x != nil {
+		return x.BaselinedBy
+	}
+	return 0
+}
+
+func (x *MigrationInfo) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *MigrationInfo) GetTenantScoped() bool {
+	if x != nil {
+		return x.TenantScoped
+	}
+	return false
+}
+
+func (x *MigrationInfo) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *MigrationInfo) GetBackfill() *BackfillSpec {
+	if x != nil {
+		return x.Backfill
+	}
+	return nil
+}
+
+func (x *MigrationInfo) GetTemplated() bool {
+	if x != nil {
+		return x.Templated
+	}
+	return false
+}
+
+// Ошибка базы данных при выполнении скрипта миграции.
+// Также передается в деталях gRPC-ошибки применения и отката.
+type ScriptError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sqlstate      string                 `protobuf:"bytes,1,opt,name=sqlstate,proto3" json:"sqlstate,omitempty"`   // Код ошибки: SQLSTATE (PostgreSQL, MySQL) или код результата SQLite
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`     // Сообщение об ошибке
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`       // Подробности ошибки
+	Hint          string                 `protobuf:"bytes,4,opt,name=hint,proto3" json:"hint,omitempty"`           // Подсказка по исправлению
+	Position      int32                  `protobuf:"varint,5,opt,name=position,proto3" json:"position,omitempty"`  // Позиция ошибки в символах от начала выполненного текста
+	Line          int32                  `protobuf:"varint,6,opt,name=line,proto3" json:"line,omitempty"`          // Номер строки скрипта миграции
+	Statement     string                 `protobuf:"bytes,7,opt,name=statement,proto3" json:"statement,omitempty"` // Оператор, на котором произошла ошибка
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptError) Reset() {
+	*x = ScriptError{}
+	mi := &file_migrator_migrator_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptError) ProtoMessage() {}
+
+func (x *ScriptError) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptError.ProtoReflect.Descriptor instead.
+func (*ScriptError) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ScriptError) GetSqlstate() string {
+	if x != nil {
+		return x.Sqlstate
+	}
+	return ""
+}
+
+func (x *ScriptError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ScriptError) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *ScriptError) GetHint() string {
+	if x != nil {
+		return x.Hint
+	}
+	return ""
+}
+
+func (x *ScriptError) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *ScriptError) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ScriptError) GetStatement() string {
+	if x != nil {
+		return x.Statement
+	}
+	return ""
+}
+
+// Ответ на запрос для получения списка миграций
+type ListMigrationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Migrations    []*MigrationInfo       `protobuf:"bytes,1,rep,name=migrations,proto3" json:"migrations,omitempty"` // Список миграций
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMigrationsResponse) Reset() {
+	*x = ListMigrationsResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMigrationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMigrationsResponse) ProtoMessage() {}
+
+func (x *ListMigrationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMigrationsResponse.ProtoReflect.Descriptor instead.
+func (*ListMigrationsResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListMigrationsResponse) GetMigrations() []*MigrationInfo {
+	if x != nil {
+		return x.Migrations
+	}
+	return nil
+}
+
+// Запрос для получения миграции
+type GetMigrationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMigrationRequest) Reset() {
+	*x = GetMigrationRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMigrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMigrationRequest) ProtoMessage() {}
+
+func (x *GetMigrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMigrationRequest.ProtoReflect.Descriptor instead.
+func (*GetMigrationRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetMigrationRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+// Ответ на запрос для получения миграции
+type GetMigrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Migration     *MigrationInfo         `protobuf:"bytes,1,opt,name=migration,proto3" json:"migration,omitempty"` // Миграция
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMigrationResponse) Reset() {
+	*x = GetMigrationResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMigrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMigrationResponse) ProtoMessage() {}
+
+func (x *GetMigrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMigrationResponse.ProtoReflect.Descriptor instead.
+func (*GetMigrationResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetMigrationResponse) GetMigration() *MigrationInfo {
+	if x != nil {
+		return x.Migration
+	}
+	return nil
+}
+
+// Запрос для импорта миграций
+type ImportMigrationsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TargetId int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	// Deprecated: Marked as deprecated in migrator/migrator.proto.
+	UserId        int64  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Устарело и игнорируется: пользователь определяется по токену доступа
+	Archive       []byte `protobuf:"bytes,3,opt,name=archive,proto3" json:"archive,omitempty"`              // Архив zip, tar или tar.gz с файлами миграций
+	Format        string `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`                // Раскладка: auto (по умолчанию), golang-migrate, goose или flyway
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportMigrationsRequest) Reset() {
+	*x = ImportMigrationsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportMigrationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportMigrationsRequest) ProtoMessage() {}
+
+func (x *ImportMigrationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportMigrationsRequest.ProtoReflect.Descriptor instead.
+func (*ImportMigrationsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ImportMigrationsRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in migrator/migrator.proto.
+func (x *ImportMigrationsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ImportMigrationsRequest) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+func (x *ImportMigrationsRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// Результат импорта одной миграции или файла
+type ImportItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`                             // Версия миграции из имени файла
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                   // Название миграции
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`                               // Путь к файлу в архиве
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                               // Результат: created, duplicate или skipped
+	MigrationId   int64                  `protobuf:"varint,5,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Идентификатор созданной или уже существующей миграции
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`                               // Причина пропуска или пояснение к дубликату
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportItem) Reset() {
+	*x = ImportItem{}
+	mi := &file_migrator_migrator_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportItem) ProtoMessage() {}
+
+func (x *ImportItem) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportItem.ProtoReflect.Descriptor instead.
+func (*ImportItem) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ImportItem) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ImportItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImportItem) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ImportItem) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ImportItem) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *ImportItem) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Ответ на запрос для импорта миграций
+type ImportMigrationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ImportItem          `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"` // Отчет в порядке версий миграций
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportMigrationsResponse) Reset() {
+	*x = ImportMigrationsResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportMigrationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportMigrationsResponse) ProtoMessage() {}
+
+func (x *ImportMigrationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportMigrationsResponse.ProtoReflect.Descriptor instead.
+func (*ImportMigrationsResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ImportMigrationsResponse) GetItems() []*ImportItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Запрос для выгрузки реестра миграций
+type ExportMigrationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetId      int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных; 0 выгружает все базы данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportMigrationsRequest) Reset() {
+	*x = ExportMigrationsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportMigrationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportMigrationsRequest) ProtoMessage() {}
+
+func (x *ExportMigrationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportMigrationsRequest.ProtoReflect.Descriptor instead.
+func (*ExportMigrationsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ExportMigrationsRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+// Часть архива выгрузки
+type ExportChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"` // Очередные байты архива tar.gz
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	mi := &file_migrator_migrator_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Запись журнала выполнения миграций
+type HistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                                                                         // Уникальный идентификатор записи
+	MigrationId   int64                  `protobuf:"varint,2,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"`                                                    // Идентификатор миграции
+	TargetId      int64                  `protobuf:"varint,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`                                                             // Идентификатор целевой базы данных
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`                                                                                  // Действие: apply, rollback, baseline или backfill
+	UserId        int64                  `protobuf:"varint,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                                                   // Идентификатор пользователя, выполнившего действие
+	StartedAt     string                 `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`                                                           // Дата и время начала выполнения
+	FinishedAt    string                 `protobuf:"bytes,7,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`                                                        // Дата и время окончания выполнения
+	DurationMs    int64                  `protobuf:"varint,8,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`                                                       // Длительность выполнения в миллисекундах
+	Outcome       string                 `protobuf:"bytes,9,opt,name=outcome,proto3" json:"outcome,omitempty"`                                                                                // Результат: succeeded, failed, aborted или partially_applied
+	Error         string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`                                                                                   // Текст ошибки
+	Script        string                 `protobuf:"bytes,11,opt,name=script,proto3" json:"script,omitempty"`                                                                                 // Выполненный скрипт после подстановки переменных шаблона
+	Variables     map[string]string      `protobuf:"bytes,12,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Переменные шаблона, использованные в скрипте
+	Tenant        string                 `protobuf:"bytes,13,opt,name=tenant,proto3" json:"tenant,omitempty"`                                                                                 // Схема арендатора; пусто для записей о самой целевой базе данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryEntry) Reset() {
+	*x = HistoryEntry{}
+	mi := &file_migrator_migrator_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryEntry) ProtoMessage() {}
+
+func (x *HistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryEntry.ProtoReflect.Descriptor instead.
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *HistoryEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *HistoryEntry) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+// Запрос для получения журнала выполнения миграций
+type ListMigrationHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetId      int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`          // Фильтр по целевой базе данных
+	MigrationId   int64                  `protobuf:"varint,2,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Фильтр по миграции
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // Фильтр по пользователю
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`                               // Фильтр по действию
+	Outcome       string                 `protobuf:"bytes,5,opt,name=outcome,proto3" json:"outcome,omitempty"`                             // Фильтр по результату
+	PageSize      int32                  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`          // Размер страницы (по умолчанию 50, не более 500)
+	PageToken     string                 `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`        // Токен следующей страницы из предыдущего ответа
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMigrationHistoryRequest) Reset() {
+	*x = ListMigrationHistoryRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMigrationHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMigrationHistoryRequest) ProtoMessage() {}
+
+func (x *ListMigrationHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMigrationHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListMigrationHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ListMigrationHistoryRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *ListMigrationHistoryRequest) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *ListMigrationHistoryRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListMigrationHistoryRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListMigrationHistoryRequest) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *ListMigrationHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListMigrationHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// Ответ на запрос для получения журнала выполнения миграций
+type ListMigrationHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*HistoryEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`                                    // Записи журнала от новых к старым
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Токен следующей страницы; пустой, если записей больше нет
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMigrationHistoryResponse) Reset() {
+	*x = ListMigrationHistoryResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMigrationHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMigrationHistoryResponse) ProtoMessage() {}
+
+func (x *ListMigrationHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMigrationHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListMigrationHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListMigrationHistoryResponse) GetEntries() []*HistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ListMigrationHistoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Запрос для проверки контрольных сумм миграций
+type VerifyMigrationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetId      int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyMigrationsRequest) Reset() {
+	*x = VerifyMigrationsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyMigrationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyMigrationsRequest) ProtoMessage() {}
+
+func (x *VerifyMigrationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyMigrationsRequest.ProtoReflect.Descriptor instead.
+func (*VerifyMigrationsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *VerifyMigrationsRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+// Миграция, скрипты которой не совпадают с записанной контрольной суммой
+type ChecksumViolation struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId     int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"`            // Уникальный идентификатор миграции
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                              // Название миграции
+	Status          string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                                          // Текущий статус миграции
+	Checksum        string                 `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"`                                      // Контрольная сумма, записанная при создании
+	AppliedChecksum string                 `protobuf:"bytes,5,opt,name=applied_checksum,json=appliedChecksum,proto3" json:"applied_checksum,omitempty"` // Контрольная сумма, записанная при применении
+	ActualChecksum  string                 `protobuf:"bytes,6,opt,name=actual_checksum,json=actualChecksum,proto3" json:"actual_checksum,omitempty"`    // Контрольная сумма хранимых сейчас скриптов
+	Reason          string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`                                          // Описание расхождения
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChecksumViolation) Reset() {
+	*x = ChecksumViolation{}
+	mi := &file_migrator_migrator_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChecksumViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecksumViolation) ProtoMessage() {}
+
+func (x *ChecksumViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecksumViolation.ProtoReflect.Descriptor instead.
+func (*ChecksumViolation) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ChecksumViolation) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *ChecksumViolation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChecksumViolation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ChecksumViolation) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *ChecksumViolation) GetAppliedChecksum() string {
+	if x != nil {
+		return x.AppliedChecksum
+	}
+	return ""
+}
+
+func (x *ChecksumViolation) GetActualChecksum() string {
+	if x != nil {
+		return x.ActualChecksum
+	}
+	return ""
+}
+
+func (x *ChecksumViolation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Ответ на запрос для проверки контрольных сумм миграций
+type VerifyMigrationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Violations    []*ChecksumViolation   `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"` // Миграции с нарушенной контрольной суммой
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyMigrationsResponse) Reset() {
+	*x = VerifyMigrationsResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyMigrationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyMigrationsResponse) ProtoMessage() {}
+
+func (x *VerifyMigrationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyMigrationsResponse.ProtoReflect.Descriptor instead.
+func (*VerifyMigrationsResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *VerifyMigrationsResponse) GetViolations() []*ChecksumViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+// Объект схемы целевой базы данных
+type SchemaObject struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`             // Вид объекта (table, view, column, index, constraint, function, trigger)
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`             // Полное имя объекта
+	Definition    string                 `protobuf:"bytes,3,opt,name=definition,proto3" json:"definition,omitempty"` // Определение объекта
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchemaObject) Reset() {
+	*x = SchemaObject{}
+	mi := &file_migrator_migrator_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchemaObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaObject) ProtoMessage() {}
+
+func (x *SchemaObject) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaObject.ProtoReflect.Descriptor instead.
+func (*SchemaObject) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *SchemaObject) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SchemaObject) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SchemaObject) GetDefinition() string {
+	if x != nil {
+		return x.Definition
+	}
+	return ""
+}
+
+// Снимок схемы целевой базы данных
+type SchemaSnapshotInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                         // Уникальный идентификатор снимка
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`                                  // Действие, после которого снят снимок (apply, rollback, baseline)
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                   // Идентификатор пользователя, выполнившего действие
+	TakenAt       string                 `protobuf:"bytes,4,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`                 // Дата и время снимка
+	ObjectsCount  int64                  `protobuf:"varint,5,opt,name=objects_count,json=objectsCount,proto3" json:"objects_count,omitempty"` // Количество объектов в снимке
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchemaSnapshotInfo) Reset() {
+	*x = SchemaSnapshotInfo{}
+	mi := &file_migrator_migrator_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchemaSnapshotInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaSnapshotInfo) ProtoMessage() {}
+
+func (x *SchemaSnapshotInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaSnapshotInfo.ProtoReflect.Descriptor instead.
+func (*SchemaSnapshotInfo) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *SchemaSnapshotInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SchemaSnapshotInfo) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *SchemaSnapshotInfo) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SchemaSnapshotInfo) GetTakenAt() string {
+	if x != nil {
+		return x.TakenAt
+	}
+	return ""
+}
+
+func (x *SchemaSnapshotInfo) GetObjectsCount() int64 {
+	if x != nil {
+		return x.ObjectsCount
+	}
+	return 0
+}
+
+// Объект схемы, измененный в обход сервиса миграций
+type SchemaDrift struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`         // Вид объекта
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`         // Полное имя объекта
+	Change        string                 `protobuf:"bytes,3,opt,name=change,proto3" json:"change,omitempty"`     // Вид расхождения (added, removed, changed)
+	Recorded      string                 `protobuf:"bytes,4,opt,name=recorded,proto3" json:"recorded,omitempty"` // Определение объекта в последнем снимке
+	Live          string                 `protobuf:"bytes,5,opt,name=live,proto3" json:"live,omitempty"`         // Определение объекта на целевой базе данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchemaDrift) Reset() {
+	*x = SchemaDrift{}
+	mi := &file_migrator_migrator_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchemaDrift) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaDrift) ProtoMessage() {}
+
+func (x *SchemaDrift) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaDrift.ProtoReflect.Descriptor instead.
+func (*SchemaDrift) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SchemaDrift) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SchemaDrift) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SchemaDrift) GetChange() string {
+	if x != nil {
+		return x.Change
+	}
+	return ""
+}
+
+func (x *SchemaDrift) GetRecorded() string {
+	if x != nil {
+		return x.Recorded
+	}
+	return ""
+}
+
+func (x *SchemaDrift) GetLive() string {
+	if x != nil {
+		return x.Live
+	}
+	return ""
+}
+
+// Запрос для получения состояния схемы целевой базы данных
+type GetDatabaseStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetId      int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatabaseStatusRequest) Reset() {
+	*x = GetDatabaseStatusRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatabaseStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatabaseStatusRequest) ProtoMessage() {}
+
+func (x *GetDatabaseStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatabaseStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDatabaseStatusRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetDatabaseStatusRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+// Ответ на запрос для получения состояния схемы целевой базы данных
+type GetDatabaseStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetId      int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	Objects       []*SchemaObject        `protobuf:"bytes,2,rep,name=objects,proto3" json:"objects,omitempty"`                    // Текущие объекты схемы
+	Snapshot      *SchemaSnapshotInfo    `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`                  // Последний снимок (отсутствует, если снимков еще нет)
+	Drift         []*SchemaDrift         `protobuf:"bytes,4,rep,name=drift,proto3" json:"drift,omitempty"`                        // Расхождения с последним снимком
+	Drifted       bool                   `protobuf:"varint,5,opt,name=drifted,proto3" json:"drifted,omitempty"`                   // Схема изменена в обход сервиса миграций
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatabaseStatusResponse) Reset() {
+	*x = GetDatabaseStatusResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatabaseStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatabaseStatusResponse) ProtoMessage() {}
+
+func (x *GetDatabaseStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatabaseStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetDatabaseStatusResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetDatabaseStatusResponse) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *GetDatabaseStatusResponse) GetObjects() []*SchemaObject {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *GetDatabaseStatusResponse) GetSnapshot() *SchemaSnapshotInfo {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *GetDatabaseStatusResponse) GetDrift() []*SchemaDrift {
+	if x != nil {
+		return x.Drift
+	}
+	return nil
+}
+
+func (x *GetDatabaseStatusResponse) GetDrifted() bool {
+	if x != nil {
+		return x.Drifted
+	}
+	return false
+}
+
+// Запись журнала аудита вызовов API
+type AuditEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                               // Уникальный идентификатор записи
+	ActorId       int64                  `protobuf:"varint,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`      // Идентификатор пользователя, вызвавшего метод
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`                        // Вызванный метод
+	Resource      string                 `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`                    // Объекты действия, например migration:12
+	Ip            string                 `protobuf:"bytes,5,opt,name=ip,proto3" json:"ip,omitempty"`                                // IP-адрес клиента
+	UserAgent     string                 `protobuf:"bytes,6,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"` // User-Agent клиента
+	Outcome       string                 `protobuf:"bytes,7,opt,name=outcome,proto3" json:"outcome,omitempty"`                      // Итог вызова (succeeded, denied, failed)
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`                          // Текст ошибки
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Дата и время вызова
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_migrator_migrator_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *AuditEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// Запрос для получения журнала аудита
+type ListAuditEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorId       int64                  `protobuf:"varint,1,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`      // Фильтр по пользователю
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`                            // Начало периода в формате RFC 3339 (включительно)
+	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`                                // Конец периода в формате RFC 3339 (не включительно)
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Размер страницы (по умолчанию 50, не более 500)
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // Токен следующей страницы из предыдущего ответа
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ListAuditEventsRequest) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// Ответ на запрос для получения журнала аудита
+type ListAuditEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`                                      // Записи журнала от новых к старым
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Токен следующей страницы; пустой, если записей больше нет
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsResponse) ProtoMessage() {}
+
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListAuditEventsResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Информация о целевой базе данных
+type TargetInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                                                                        // Уникальный идентификатор целевой базы данных
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                                                                     // Название целевой базы данных
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`                                                                       // Описание целевой базы данных
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`                                                                                       // Строка подключения (пароль скрыт)
+	CreatedBy     int64                  `protobuf:"varint,5,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`                                                         // Идентификатор пользователя, зарегистрировавшего базу данных
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                                                          // Дата и время регистрации
+	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                                                          // Дата и время последнего изменения
+	EnvironmentId int64                  `protobuf:"varint,8,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`                                             // Окружение, к которому относится база данных (0 - вне конвейера)
+	Variables     map[string]string      `protobuf:"bytes,9,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Переменные шаблонов скриптов; переопределяют переменные окружения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TargetInfo) Reset() {
+	*x = TargetInfo{}
+	mi := &file_migrator_migrator_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TargetInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetInfo) ProtoMessage() {}
+
+func (x *TargetInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetInfo.ProtoReflect.Descriptor instead.
+func (*TargetInfo) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *TargetInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TargetInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TargetInfo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TargetInfo) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *TargetInfo) GetCreatedBy() int64 {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return 0
+}
+
+func (x *TargetInfo) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *TargetInfo) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *TargetInfo) GetEnvironmentId() int64 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *TargetInfo) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+// Запрос для регистрации целевой базы данных
+type CreateTargetRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`               // Уникальное название целевой базы данных
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"` // Описание целевой базы данных
+	Url         string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`                 // Строка подключения: postgres://, mysql:// или sqlite:путь
+	// Deprecated: Marked as deprecated in migrator/migrator.proto.
+	UserId        int64             `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                                                  // Устарело и игнорируется: пользователь определяется по токену доступа
+	EnvironmentId int64             `protobuf:"varint,5,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`                                             // Окружение, к которому относится база данных (0 - вне конвейера)
+	Variables     map[string]string `protobuf:"bytes,6,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Переменные шаблонов скриптов, например schema=billing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTargetRequest) Reset() {
+	*x = CreateTargetRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTargetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTargetRequest) ProtoMessage() {}
+
+func (x *CreateTargetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTargetRequest.ProtoReflect.Descriptor instead.
+func (*CreateTargetRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *CreateTargetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateTargetRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateTargetRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in migrator/migrator.proto.
+func (x *CreateTargetRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateTargetRequest) GetEnvironmentId() int64 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *CreateTargetRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+// Ответ на запрос для регистрации целевой базы данных
+type CreateTargetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetId      int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Уникальный идентификатор целевой базы данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTargetResponse) Reset() {
+	*x = CreateTargetResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTargetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTargetResponse) ProtoMessage() {}
+
+func (x *CreateTargetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTargetResponse.ProtoReflect.Descriptor instead.
+func (*CreateTargetResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *CreateTargetResponse) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+// Запрос для получения целевой базы данных
+type GetTargetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetId      int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Уникальный идентификатор целевой базы данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTargetRequest) Reset() {
+	*x = GetTargetRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTargetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTargetRequest) ProtoMessage() {}
+
+func (x *GetTargetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTargetRequest.ProtoReflect.Descriptor instead.
+func (*GetTargetRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetTargetRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+// Ответ на запрос для получения целевой базы данных
+type GetTargetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        *TargetInfo            `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"` // Целевая база данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTargetResponse) Reset() {
+	*x = GetTargetResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTargetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTargetResponse) ProtoMessage() {}
+
+func (x *GetTargetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTargetResponse.ProtoReflect.Descriptor instead.
+func (*GetTargetResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetTargetResponse) GetTarget() *TargetInfo {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+// Запрос для получения списка целевых баз данных
+type ListTargetsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTargetsRequest) Reset() {
+	*x = ListTargetsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTargetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTargetsRequest) ProtoMessage() {}
+
+func (x *ListTargetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTargetsRequest.ProtoReflect.Descriptor instead.
+func (*ListTargetsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{63}
+}
+
+// Ответ на запрос для получения списка целевых баз данных
+type ListTargetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Targets       []*TargetInfo          `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"` // Список целевых баз данных
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTargetsResponse) Reset() {
+	*x = ListTargetsResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTargetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTargetsResponse) ProtoMessage() {}
+
+func (x *ListTargetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTargetsResponse.ProtoReflect.Descriptor instead.
+func (*ListTargetsResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ListTargetsResponse) GetTargets() []*TargetInfo {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+// Запрос для изменения настроек целевой базы данных
+type UpdateTargetRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TargetId    int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Уникальный идентификатор целевой базы данных
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                          // Новое название
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`            // Новое описание
+	Url         string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`                            // Новая строка подключения
+	// Deprecated: Marked as deprecated in migrator/migrator.proto.
+	UserId        int64             `protobuf:"varint,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                                                  // Устарело и игнорируется: пользователь определяется по токену доступа
+	EnvironmentId int64             `protobuf:"varint,6,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`                                             // Окружение, к которому относится база данных (0 - вне конвейера)
+	Variables     map[string]string `protobuf:"bytes,7,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Новые переменные шаблонов скриптов
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTargetRequest) Reset() {
+	*x = UpdateTargetRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTargetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTargetRequest) ProtoMessage() {}
+
+func (x *UpdateTargetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTargetRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTargetRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *UpdateTargetRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *UpdateTargetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateTargetRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateTargetRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in migrator/migrator.proto.
+func (x *UpdateTargetRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UpdateTargetRequest) GetEnvironmentId() int64 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *UpdateTargetRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+// Ответ на запрос для изменения настроек целевой базы данных
+type UpdateTargetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTargetResponse) Reset() {
+	*x = UpdateTargetResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTargetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTargetResponse) ProtoMessage() {}
+
+func (x *UpdateTargetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTargetResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTargetResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{66}
+}
+
+// Запрос для удаления целевой базы данных
+type DeleteTargetRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TargetId int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Уникальный идентификатор целевой базы данных
+	// Deprecated: Marked as deprecated in migrator/migrator.proto.
+	UserId        int64 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Устарело и игнорируется: пользователь определяется по токену доступа
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTargetRequest) Reset() {
+	*x = DeleteTargetRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTargetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTargetRequest) ProtoMessage() {}
+
+func (x *DeleteTargetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTargetRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTargetRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *DeleteTargetRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in migrator/migrator.proto.
+func (x *DeleteTargetRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// Ответ на запрос для удаления целевой базы данных
+type DeleteTargetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTargetResponse) Reset() {
+	*x = DeleteTargetResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTargetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTargetResponse) ProtoMessage() {}
+
+func (x *DeleteTargetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTargetResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTargetResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{68}
+}
+
+// Окружение конвейера продвижения миграций
+type EnvironmentInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                                                                        // Уникальный идентификатор окружения
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                                                                     // Название окружения
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`                                                                       // Описание окружения
+	PromoteFromId int64                  `protobuf:"varint,4,opt,name=promote_from_id,json=promoteFromId,proto3" json:"promote_from_id,omitempty"`                                           // Окружение, в котором миграция должна быть применена раньше (0 - нет)
+	MinSoakHours  int32                  `protobuf:"varint,5,opt,name=min_soak_hours,json=minSoakHours,proto3" json:"min_soak_hours,omitempty"`                                              // Сколько часов миграция должна пробыть примененной в предыдущем окружении
+	CreatedBy     int64                  `protobuf:"varint,6,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`                                                         // Идентификатор пользователя, создавшего окружение
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                                                          // Дата и время создания
+	UpdatedAt     string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                                                          // Дата и время последнего изменения
+	Variables     map[string]string      `protobuf:"bytes,9,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Переменные шаблонов скриптов целевых баз данных окружения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvironmentInfo) Reset() {
+	*x = EnvironmentInfo{}
+	mi := &file_migrator_migrator_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvironmentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvironmentInfo) ProtoMessage() {}
+
+func (x *EnvironmentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvironmentInfo.ProtoReflect.Descriptor instead.
+func (*EnvironmentInfo) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *EnvironmentInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EnvironmentInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EnvironmentInfo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *EnvironmentInfo) GetPromoteFromId() int64 {
+	if x != nil {
+		return x.PromoteFromId
+	}
+	return 0
+}
+
+func (x *EnvironmentInfo) GetMinSoakHours() int32 {
+	if x != nil {
+		return x.MinSoakHours
+	}
+	return 0
+}
+
+func (x *EnvironmentInfo) GetCreatedBy() int64 {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return 0
+}
+
+func (x *EnvironmentInfo) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *EnvironmentInfo) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *EnvironmentInfo) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+// Запрос для создания окружения
+type CreateEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                                                                     // Уникальное название окружения
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`                                                                       // Описание окружения
+	PromoteFromId int64                  `protobuf:"varint,3,opt,name=promote_from_id,json=promoteFromId,proto3" json:"promote_from_id,omitempty"`                                           // Окружение, в котором миграция должна быть применена раньше (0 - нет)
+	MinSoakHours  int32                  `protobuf:"varint,4,opt,name=min_soak_hours,json=minSoakHours,proto3" json:"min_soak_hours,omitempty"`                                              // Сколько часов миграция должна пробыть примененной в предыдущем окружении
+	Variables     map[string]string      `protobuf:"bytes,5,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Переменные шаблонов скриптов целевых баз данных окружения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEnvironmentRequest) Reset() {
+	*x = CreateEnvironmentRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEnvironmentRequest) ProtoMessage() {}
+
+func (x *CreateEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*CreateEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *CreateEnvironmentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateEnvironmentRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateEnvironmentRequest) GetPromoteFromId() int64 {
+	if x != nil {
+		return x.PromoteFromId
+	}
+	return 0
+}
+
+func (x *CreateEnvironmentRequest) GetMinSoakHours() int32 {
+	if x != nil {
+		return x.MinSoakHours
+	}
+	return 0
+}
+
+func (x *CreateEnvironmentRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+// Ответ на запрос для создания окружения
+type CreateEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId int64                  `protobuf:"varint,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"` // Уникальный идентификатор окружения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEnvironmentResponse) Reset() {
+	*x = CreateEnvironmentResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEnvironmentResponse) ProtoMessage() {}
+
+func (x *CreateEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*CreateEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *CreateEnvironmentResponse) GetEnvironmentId() int64 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+// Запрос для получения окружения
+type GetEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId int64                  `protobuf:"varint,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"` // Уникальный идентификатор окружения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentRequest) Reset() {
+	*x = GetEnvironmentRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentRequest) ProtoMessage() {}
+
+func (x *GetEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetEnvironmentRequest) GetEnvironmentId() int64 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+// Ответ на запрос для получения окружения
+type GetEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *EnvironmentInfo       `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"` // Информация об окружении
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentResponse) Reset() {
+	*x = GetEnvironmentResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentResponse) ProtoMessage() {}
+
+func (x *GetEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *GetEnvironmentResponse) GetEnvironment() *EnvironmentInfo {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+// Запрос для получения списка окружений
+type ListEnvironmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnvironmentsRequest) Reset() {
+	*x = ListEnvironmentsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnvironmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnvironmentsRequest) ProtoMessage() {}
+
+func (x *ListEnvironmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnvironmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListEnvironmentsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{74}
+}
+
+// Ответ на запрос для получения списка окружений
+type ListEnvironmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environments  []*EnvironmentInfo     `protobuf:"bytes,1,rep,name=environments,proto3" json:"environments,omitempty"` // Окружения в порядке конвейера
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnvironmentsResponse) Reset() {
+	*x = ListEnvironmentsResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnvironmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnvironmentsResponse) ProtoMessage() {}
+
+func (x *ListEnvironmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnvironmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListEnvironmentsResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ListEnvironmentsResponse) GetEnvironments() []*EnvironmentInfo {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
+// Запрос для изменения окружения
+type UpdateEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId int64                  `protobuf:"varint,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`                                             // Уникальный идентификатор окружения
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                                                                     // Новое название
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`                                                                       // Новое описание
+	PromoteFromId int64                  `protobuf:"varint,4,opt,name=promote_from_id,json=promoteFromId,proto3" json:"promote_from_id,omitempty"`                                           // Новое предыдущее окружение (0 - нет)
+	MinSoakHours  int32                  `protobuf:"varint,5,opt,name=min_soak_hours,json=minSoakHours,proto3" json:"min_soak_hours,omitempty"`                                              // Новое время выдержки в предыдущем окружении в часах
+	Variables     map[string]string      `protobuf:"bytes,6,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Новые переменные шаблонов скриптов
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEnvironmentRequest) Reset() {
+	*x = UpdateEnvironmentRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEnvironmentRequest) ProtoMessage() {}
+
+func (x *UpdateEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *UpdateEnvironmentRequest) GetEnvironmentId() int64 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *UpdateEnvironmentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateEnvironmentRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateEnvironmentRequest) GetPromoteFromId() int64 {
+	if x != nil {
+		return x.PromoteFromId
+	}
+	return 0
+}
+
+func (x *UpdateEnvironmentRequest) GetMinSoakHours() int32 {
+	if x != nil {
+		return x.MinSoakHours
+	}
+	return 0
+}
+
+func (x *UpdateEnvironmentRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+// Ответ на запрос для изменения окружения
+type UpdateEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEnvironmentResponse) Reset() {
+	*x = UpdateEnvironmentResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEnvironmentResponse) ProtoMessage() {}
+
+func (x *UpdateEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*UpdateEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{77}
+}
+
+// Запрос для удаления окружения
+type DeleteEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId int64                  `protobuf:"varint,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"` // Уникальный идентификатор окружения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteEnvironmentRequest) Reset() {
+	*x = DeleteEnvironmentRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEnvironmentRequest) ProtoMessage() {}
+
+func (x *DeleteEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *DeleteEnvironmentRequest) GetEnvironmentId() int64 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+// Ответ на запрос для удаления окружения
+type DeleteEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteEnvironmentResponse) Reset() {
+	*x = DeleteEnvironmentResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEnvironmentResponse) ProtoMessage() {}
+
+func (x *DeleteEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{79}
+}
+
+// Запрос для получения состояний миграций во всех окружениях
+type ListMigrationEnvironmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetId      int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`                // Только миграции, зарегистрированные для этой целевой базы данных
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                         // Фильтр по названию миграции
+	EnvironmentId int64                  `protobuf:"varint,3,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"` // Только миграции, у которых есть состояние в этом окружении
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                     // Только миграции с этим статусом (в окружении environment_id, если задано)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMigrationEnvironmentsRequest) Reset() {
+	*x = ListMigrationEnvironmentsRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMigrationEnvironmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMigrationEnvironmentsRequest) ProtoMessage() {}
+
+func (x *ListMigrationEnvironmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMigrationEnvironmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListMigrationEnvironmentsRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *ListMigrationEnvironmentsRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *ListMigrationEnvironmentsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListMigrationEnvironmentsRequest) GetEnvironmentId() int64 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *ListMigrationEnvironmentsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// Состояние миграции в целевой базе данных окружения
+type EnvironmentMigrationStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId   int64                  `protobuf:"varint,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`        // Идентификатор окружения
+	EnvironmentName string                 `protobuf:"bytes,2,opt,name=environment_name,json=environmentName,proto3" json:"environment_name,omitempty"`   // Название окружения
+	TargetId        int64                  `protobuf:"varint,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`                       // Идентификатор целевой базы данных
+	TargetName      string                 `protobuf:"bytes,4,opt,name=target_name,json=targetName,proto3" json:"target_name,omitempty"`                  // Название целевой базы данных
+	MigrationId     int64                  `protobuf:"varint,5,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"`              // Идентификатор миграции в этой целевой базе данных
+	Status          string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`                                            // Статус миграции
+	StatusUpdatedAt string                 `protobuf:"bytes,7,opt,name=status_updated_at,json=statusUpdatedAt,proto3" json:"status_updated_at,omitempty"` // Дата и время обновления статуса
+	Checksum        string                 `protobuf:"bytes,8,opt,name=checksum,proto3" json:"checksum,omitempty"`                                        // Контрольная сумма скриптов
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EnvironmentMigrationStatus) Reset() {
+	*x = EnvironmentMigrationStatus{}
+	mi := &file_migrator_migrator_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvironmentMigrationStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvironmentMigrationStatus) ProtoMessage() {}
+
+func (x *EnvironmentMigrationStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvironmentMigrationStatus.ProtoReflect.Descriptor instead.
+func (*EnvironmentMigrationStatus) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *EnvironmentMigrationStatus) GetEnvironmentId() int64 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *EnvironmentMigrationStatus) GetEnvironmentName() string {
+	if x != nil {
+		return x.EnvironmentName
 	}
 	return ""
 }
 
-func (x *MigrationInfo) GetCreatedBy() int64 {
+func (x *EnvironmentMigrationStatus) GetTargetId() int64 {
 	if x != nil {
-		return x.CreatedBy
+		return x.TargetId
 	}
 	return 0
 }
 
-func (x *MigrationInfo) GetStatusUpdatedAt() string {
+func (x *EnvironmentMigrationStatus) GetTargetName() string {
+	if x != nil {
+		return x.TargetName
+	}
+	return ""
+}
+
+func (x *EnvironmentMigrationStatus) GetMigrationId() int64 {
+	if x != nil {
+		return x.MigrationId
+	}
+	return 0
+}
+
+func (x *EnvironmentMigrationStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *EnvironmentMigrationStatus) GetStatusUpdatedAt() string {
 	if x != nil {
 		return x.StatusUpdatedAt
 	}
 	return ""
 }
 
-// Ответ на запрос для получения списка миграций
-type ListMigrationsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Migrations    []*MigrationInfo       `protobuf:"bytes,1,rep,name=migrations,proto3" json:"migrations,omitempty"` // Список миграций
+func (x *EnvironmentMigrationStatus) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+// Состояния одной миграции во всех окружениях
+type MigrationEnvironments struct {
+	state         protoimpl.MessageState        `protogen:"open.v1"`
+	Name          string                        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`         // Название миграции
+	Statuses      []*EnvironmentMigrationStatus `protobuf:"bytes,2,rep,name=statuses,proto3" json:"statuses,omitempty"` // Состояния в порядке конвейера
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListMigrationsResponse) Reset() {
-	*x = ListMigrationsResponse{}
-	mi := &file_migrator_migrator_proto_msgTypes[8]
+func (x *MigrationEnvironments) Reset() {
+	*x = MigrationEnvironments{}
+	mi := &file_migrator_migrator_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListMigrationsResponse) String() string {
+func (x *MigrationEnvironments) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListMigrationsResponse) ProtoMessage() {}
+func (*MigrationEnvironments) ProtoMessage() {}
 
-func (x *ListMigrationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_migrator_migrator_proto_msgTypes[8]
+func (x *MigrationEnvironments) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -520,41 +5425,97 @@ func (x *ListMigrationsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListMigrationsResponse.ProtoReflect.Descriptor instead.
-func (*ListMigrationsResponse) Descriptor() ([]byte, []int) {
-	return file_migrator_migrator_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use MigrationEnvironments.ProtoReflect.Descriptor instead.
+func (*MigrationEnvironments) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *ListMigrationsResponse) GetMigrations() []*MigrationInfo {
+func (x *MigrationEnvironments) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MigrationEnvironments) GetStatuses() []*EnvironmentMigrationStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+// Ответ на запрос для получения состояний миграций во всех окружениях
+type ListMigrationEnvironmentsResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Migrations    []*MigrationEnvironments `protobuf:"bytes,1,rep,name=migrations,proto3" json:"migrations,omitempty"` // Миграции в порядке названий
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMigrationEnvironmentsResponse) Reset() {
+	*x = ListMigrationEnvironmentsResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMigrationEnvironmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMigrationEnvironmentsResponse) ProtoMessage() {}
+
+func (x *ListMigrationEnvironmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMigrationEnvironmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListMigrationEnvironmentsResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *ListMigrationEnvironmentsResponse) GetMigrations() []*MigrationEnvironments {
 	if x != nil {
 		return x.Migrations
 	}
 	return nil
 }
 
-// Запрос для получения миграции
-type GetMigrationRequest struct {
+// Блокировка целевой базы данных
+type LockInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MigrationId   int64                  `protobuf:"varint,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"` // Уникальный идентификатор миграции
+	TargetId      int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`      // Идентификатор целевой базы данных
+	Holder        string                 `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`                           // Реплика сервиса, удерживающая блокировку
+	Operation     string                 `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`                     // Операция, для которой захвачена блокировка
+	AcquiredAt    string                 `protobuf:"bytes,4,opt,name=acquired_at,json=acquiredAt,proto3" json:"acquired_at,omitempty"` // Дата и время захвата блокировки
+	ExpiresAt     string                 `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`    // Срок блокировки, который продлевает удерживающая ее реплика
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMigrationRequest) Reset() {
-	*x = GetMigrationRequest{}
-	mi := &file_migrator_migrator_proto_msgTypes[9]
+func (x *LockInfo) Reset() {
+	*x = LockInfo{}
+	mi := &file_migrator_migrator_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMigrationRequest) String() string {
+func (x *LockInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMigrationRequest) ProtoMessage() {}
+func (*LockInfo) ProtoMessage() {}
 
-func (x *GetMigrationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_migrator_migrator_proto_msgTypes[9]
+func (x *LockInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -565,41 +5526,68 @@ func (x *GetMigrationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMigrationRequest.ProtoReflect.Descriptor instead.
-func (*GetMigrationRequest) Descriptor() ([]byte, []int) {
-	return file_migrator_migrator_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use LockInfo.ProtoReflect.Descriptor instead.
+func (*LockInfo) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *GetMigrationRequest) GetMigrationId() int64 {
+func (x *LockInfo) GetTargetId() int64 {
 	if x != nil {
-		return x.MigrationId
+		return x.TargetId
 	}
 	return 0
 }
 
-// Ответ на запрос для получения миграции
-type GetMigrationResponse struct {
+func (x *LockInfo) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *LockInfo) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *LockInfo) GetAcquiredAt() string {
+	if x != nil {
+		return x.AcquiredAt
+	}
+	return ""
+}
+
+func (x *LockInfo) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+// Запрос для получения списка блокировок
+type ListLocksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Migration     *MigrationInfo         `protobuf:"bytes,1,opt,name=migration,proto3" json:"migration,omitempty"` // Миграция
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMigrationResponse) Reset() {
-	*x = GetMigrationResponse{}
-	mi := &file_migrator_migrator_proto_msgTypes[10]
+func (x *ListLocksRequest) Reset() {
+	*x = ListLocksRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMigrationResponse) String() string {
+func (x *ListLocksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMigrationResponse) ProtoMessage() {}
+func (*ListLocksRequest) ProtoMessage() {}
 
-func (x *GetMigrationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_migrator_migrator_proto_msgTypes[10]
+func (x *ListLocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -610,44 +5598,321 @@ func (x *GetMigrationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMigrationResponse.ProtoReflect.Descriptor instead.
-func (*GetMigrationResponse) Descriptor() ([]byte, []int) {
-	return file_migrator_migrator_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ListLocksRequest.ProtoReflect.Descriptor instead.
+func (*ListLocksRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *GetMigrationResponse) GetMigration() *MigrationInfo {
+// Ответ на запрос для получения списка блокировок
+type ListLocksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locks         []*LockInfo            `protobuf:"bytes,1,rep,name=locks,proto3" json:"locks,omitempty"` // Список удерживаемых блокировок
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLocksResponse) Reset() {
+	*x = ListLocksResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLocksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLocksResponse) ProtoMessage() {}
+
+func (x *ListLocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[86]
 	if x != nil {
-		return x.Migration
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLocksResponse.ProtoReflect.Descriptor instead.
+func (*ListLocksResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *ListLocksResponse) GetLocks() []*LockInfo {
+	if x != nil {
+		return x.Locks
 	}
 	return nil
 }
 
+// Запрос для принудительного освобождения блокировки
+type ReleaseLockRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TargetId int64                  `protobuf:"varint,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // Идентификатор целевой базы данных
+	// Deprecated: Marked as deprecated in migrator/migrator.proto.
+	UserId        int64 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Устарело и игнорируется: пользователь определяется по токену доступа
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseLockRequest) Reset() {
+	*x = ReleaseLockRequest{}
+	mi := &file_migrator_migrator_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseLockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseLockRequest) ProtoMessage() {}
+
+func (x *ReleaseLockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseLockRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseLockRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *ReleaseLockRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in migrator/migrator.proto.
+func (x *ReleaseLockRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// Ответ на запрос для принудительного освобождения блокировки
+type ReleaseLockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseLockResponse) Reset() {
+	*x = ReleaseLockResponse{}
+	mi := &file_migrator_migrator_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseLockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseLockResponse) ProtoMessage() {}
+
+func (x *ReleaseLockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_migrator_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseLockResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseLockResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_migrator_proto_rawDescGZIP(), []int{88}
+}
+
 var File_migrator_migrator_proto protoreflect.FileDescriptor
 
 const file_migrator_migrator_proto_rawDesc = "" +
 	"\n" +
-	"\x17migrator/migrator.proto\x12\tmigration\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xa8\x01\n" +
+	"\x17migrator/migrator.proto\x12\tmigration\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xfe\x03\n" +
 	"\x16CreateMigrationRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
 	"\x06script\x18\x03 \x01(\tR\x06script\x12'\n" +
-	"\x0frollback_script\x18\x04 \x01(\tR\x0erollbackScript\x12\x17\n" +
-	"\auser_id\x18\x05 \x01(\x03R\x06userId\"<\n" +
+	"\x0frollback_script\x18\x04 \x01(\tR\x0erollbackScript\x12\x1b\n" +
+	"\auser_id\x18\x05 \x01(\x03B\x02\x18\x01R\x06userId\x12\x1b\n" +
+	"\ttarget_id\x18\x06 \x01(\x03R\btargetId\x12%\n" +
+	"\x0eexecution_mode\x18\a \x01(\tR\rexecutionMode\x12E\n" +
+	"\x06labels\x18\b \x03(\v2-.migration.CreateMigrationRequest.LabelsEntryR\x06labels\x12#\n" +
+	"\rtenant_scoped\x18\t \x01(\bR\ftenantScoped\x12\x12\n" +
+	"\x04kind\x18\n" +
+	" \x01(\tR\x04kind\x123\n" +
+	"\bbackfill\x18\v \x01(\v2\x17.migration.BackfillSpecR\bbackfill\x12\x1c\n" +
+	"\ttemplated\x18\f \x01(\bR\ttemplated\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"y\n" +
 	"\x17CreateMigrationResponse\x12!\n" +
-	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\"U\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12;\n" +
+	"\rlint_findings\x18\x02 \x03(\v2\x16.migration.LintFindingR\flintFindings\"z\n" +
+	"\x14LintMigrationRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x16\n" +
+	"\x06script\x18\x02 \x01(\tR\x06script\x12'\n" +
+	"\x0frollback_script\x18\x03 \x01(\tR\x0erollbackScript\"e\n" +
+	"\x15LintMigrationResponse\x122\n" +
+	"\bfindings\x18\x01 \x03(\v2\x16.migration.LintFindingR\bfindings\x12\x18\n" +
+	"\ablocked\x18\x02 \x01(\bR\ablocked\"\xc1\x01\n" +
+	"\vLintFinding\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1a\n" +
+	"\brollback\x18\x04 \x01(\bR\brollback\x12\x12\n" +
+	"\x04line\x18\x05 \x01(\x05R\x04line\x12\x1c\n" +
+	"\tstatement\x18\x06 \x01(\tR\tstatement\x12\x1a\n" +
+	"\bblocking\x18\a \x01(\bR\bblocking\"\x92\x01\n" +
 	"\x15ApplyMigrationRequest\x12#\n" +
-	"\rmigration_ids\x18\x01 \x03(\x03R\fmigrationIds\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\x03R\x06userId\"7\n" +
+	"\rmigration_ids\x18\x01 \x03(\x03R\fmigrationIds\x12\x1b\n" +
+	"\auser_id\x18\x02 \x01(\x03B\x02\x18\x01R\x06userId\x12\x1b\n" +
+	"\ttarget_id\x18\x03 \x01(\x03R\btargetId\x12\x1a\n" +
+	"\bselector\x18\x04 \x01(\tR\bselector\"\\\n" +
 	"\x16ApplyMigrationResponse\x12\x1d\n" +
 	"\n" +
-	"applied_at\x18\x01 \x01(\tR\tappliedAt\"V\n" +
+	"applied_at\x18\x01 \x01(\tR\tappliedAt\x12#\n" +
+	"\rmigration_ids\x18\x02 \x03(\x03R\fmigrationIds\"w\n" +
 	"\x18RollbackMigrationRequest\x12!\n" +
-	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\x03R\x06userId\"A\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x1b\n" +
+	"\auser_id\x18\x02 \x01(\x03B\x02\x18\x01R\x06userId\x12\x1b\n" +
+	"\ttarget_id\x18\x03 \x01(\x03R\btargetId\"A\n" +
 	"\x19RollbackMigrationResponse\x12$\n" +
-	"\x0erolled_back_at\x18\x01 \x01(\tR\frolledBackAt\"/\n" +
+	"\x0erolled_back_at\x18\x01 \x01(\tR\frolledBackAt\"y\n" +
+	"\x1aRollbackToMigrationRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x1b\n" +
+	"\auser_id\x18\x02 \x01(\x03B\x02\x18\x01R\x06userId\x12\x1b\n" +
+	"\ttarget_id\x18\x03 \x01(\x03R\btargetId\"k\n" +
+	"\x1bRollbackToMigrationResponse\x12&\n" +
+	"\x0frolled_back_ids\x18\x01 \x03(\x03R\rrolledBackIds\x12$\n" +
+	"\x0erolled_back_at\x18\x02 \x01(\tR\frolledBackAt\"\xb1\x01\n" +
+	"\x19BaselineMigrationsRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\x12#\n" +
+	"\rmigration_ids\x18\x02 \x03(\x03R\fmigrationIds\x12*\n" +
+	"\x11from_migration_id\x18\x03 \x01(\x03R\x0ffromMigrationId\x12&\n" +
+	"\x0fto_migration_id\x18\x04 \x01(\x03R\rtoMigrationId\"d\n" +
+	"\x1aBaselineMigrationsResponse\x12#\n" +
+	"\rbaselined_ids\x18\x01 \x03(\x03R\fbaselinedIds\x12!\n" +
+	"\fbaselined_at\x18\x02 \x01(\tR\vbaselinedAt\"]\n" +
+	" UpdateRepeatableMigrationRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x16\n" +
+	"\x06script\x18\x02 \x01(\tR\x06script\"z\n" +
+	"!UpdateRepeatableMigrationResponse\x12\x18\n" +
+	"\apending\x18\x01 \x01(\bR\apending\x12;\n" +
+	"\rlint_findings\x18\x02 \x03(\v2\x16.migration.LintFindingR\flintFindings\"\xa1\x01\n" +
+	"\x11MigrationRevision\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\x12\x1a\n" +
+	"\bchecksum\x18\x02 \x01(\tR\bchecksum\x12\x16\n" +
+	"\x06script\x18\x03 \x01(\tR\x06script\x12\x1d\n" +
+	"\n" +
+	"applied_by\x18\x04 \x01(\x03R\tappliedBy\x12\x1d\n" +
+	"\n" +
+	"applied_at\x18\x05 \x01(\tR\tappliedAt\"B\n" +
+	"\x1dListMigrationRevisionsRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\"\\\n" +
+	"\x1eListMigrationRevisionsResponse\x12:\n" +
+	"\trevisions\x18\x01 \x03(\v2\x1c.migration.MigrationRevisionR\trevisions\"\x99\x01\n" +
+	"\x1bApplyTenantMigrationRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\x03R\btargetId\x12\x18\n" +
+	"\atenants\x18\x03 \x03(\tR\atenants\x12 \n" +
+	"\vconcurrency\x18\x04 \x01(\x05R\vconcurrency\"s\n" +
+	"\fTenantStatus\x12\x16\n" +
+	"\x06tenant\x18\x01 \x01(\tR\x06tenant\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\"\xa4\x01\n" +
+	"\x1cApplyTenantMigrationResponse\x12\x18\n" +
+	"\aapplied\x18\x01 \x03(\tR\aapplied\x12\x18\n" +
+	"\askipped\x18\x02 \x03(\tR\askipped\x12/\n" +
+	"\x06failed\x18\x03 \x03(\v2\x17.migration.TenantStatusR\x06failed\x12\x1f\n" +
+	"\vfinished_at\x18\x04 \x01(\tR\n" +
+	"finishedAt\"\x9a\x01\n" +
+	"\fBackfillSpec\x12!\n" +
+	"\fcursor_query\x18\x01 \x01(\tR\vcursorQuery\x12%\n" +
+	"\x0eestimate_query\x18\x02 \x01(\tR\restimateQuery\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x03 \x01(\x05R\tbatchSize\x12!\n" +
+	"\fstart_cursor\x18\x04 \x01(\tR\vstartCursor\"\xa6\x03\n" +
+	"\fBackfillInfo\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\x03R\btargetId\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x1b\n" +
+	"\trows_done\x18\x05 \x01(\x03R\browsDone\x12'\n" +
+	"\x0festimated_total\x18\x06 \x01(\x03R\x0eestimatedTotal\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\a \x01(\x05R\tbatchSize\x12\x19\n" +
+	"\bpause_ms\x18\b \x01(\x03R\apauseMs\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12\x14\n" +
+	"\x05owner\x18\n" +
+	" \x01(\tR\x05owner\x12\x1d\n" +
+	"\n" +
+	"started_by\x18\v \x01(\x03R\tstartedBy\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\f \x01(\tR\tstartedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\tR\tupdatedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x0e \x01(\tR\n" +
+	"finishedAt\"9\n" +
+	"\x14StartBackfillRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\"9\n" +
+	"\x14PauseBackfillRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\":\n" +
+	"\x15ResumeBackfillRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\"v\n" +
+	"\x17ThrottleBackfillRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x02 \x01(\x05R\tbatchSize\x12\x19\n" +
+	"\bpause_ms\x18\x03 \x01(\x03R\apauseMs\"7\n" +
+	"\x12GetBackfillRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\"G\n" +
+	"\x10BackfillResponse\x123\n" +
+	"\bbackfill\x18\x01 \x01(\v2\x17.migration.BackfillInfoR\bbackfill\">\n" +
+	"\x19ListTenantStatusesRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\"O\n" +
+	"\x1aListTenantStatusesResponse\x121\n" +
+	"\atenants\x18\x01 \x03(\v2\x17.migration.TenantStatusR\atenants\"\xaf\x02\n" +
+	"\x11MigrationPlanItem\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05order\x18\x03 \x01(\x05R\x05order\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1a\n" +
+	"\brejected\x18\x05 \x01(\bR\brejected\x12#\n" +
+	"\rreject_reason\x18\x06 \x01(\tR\frejectReason\x12\x18\n" +
+	"\asuccess\x18\a \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x1f\n" +
+	"\vduration_ms\x18\t \x01(\x03R\n" +
+	"durationMs\x12#\n" +
+	"\rrows_affected\x18\n" +
+	" \x01(\x03R\frowsAffected\"\x9a\x01\n" +
+	"\x15MigrationPlanResponse\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\x03R\btargetId\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x122\n" +
+	"\x05items\x18\x04 \x03(\v2\x1c.migration.MigrationPlanItemR\x05items\"h\n" +
 	"\x15ListMigrationsRequest\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"\xf9\x01\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\x03R\btargetId\x12\x1a\n" +
+	"\bselector\x18\x03 \x01(\tR\bselector\"\x81\x06\n" +
 	"\rMigrationInfo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -657,7 +5922,32 @@ const file_migrator_migrator_proto_rawDesc = "" +
 	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1d\n" +
 	"\n" +
 	"created_by\x18\a \x01(\x03R\tcreatedBy\x12*\n" +
-	"\x11status_updated_at\x18\b \x01(\tR\x0fstatusUpdatedAt\"R\n" +
+	"\x11status_updated_at\x18\b \x01(\tR\x0fstatusUpdatedAt\x12\x1b\n" +
+	"\ttarget_id\x18\t \x01(\x03R\btargetId\x12\x1a\n" +
+	"\bchecksum\x18\n" +
+	" \x01(\tR\bchecksum\x12)\n" +
+	"\x10applied_checksum\x18\v \x01(\tR\x0fappliedChecksum\x12%\n" +
+	"\x0eexecution_mode\x18\f \x01(\tR\rexecutionMode\x125\n" +
+	"\n" +
+	"last_error\x18\r \x01(\v2\x16.migration.ScriptErrorR\tlastError\x12\x1c\n" +
+	"\tbaselined\x18\x0e \x01(\bR\tbaselined\x12!\n" +
+	"\fbaselined_by\x18\x0f \x01(\x03R\vbaselinedBy\x12<\n" +
+	"\x06labels\x18\x10 \x03(\v2$.migration.MigrationInfo.LabelsEntryR\x06labels\x12#\n" +
+	"\rtenant_scoped\x18\x11 \x01(\bR\ftenantScoped\x12\x12\n" +
+	"\x04kind\x18\x12 \x01(\tR\x04kind\x123\n" +
+	"\bbackfill\x18\x13 \x01(\v2\x17.migration.BackfillSpecR\bbackfill\x12\x1c\n" +
+	"\ttemplated\x18\x14 \x01(\bR\ttemplated\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbd\x01\n" +
+	"\vScriptError\x12\x1a\n" +
+	"\bsqlstate\x18\x01 \x01(\tR\bsqlstate\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detail\x12\x12\n" +
+	"\x04hint\x18\x04 \x01(\tR\x04hint\x12\x1a\n" +
+	"\bposition\x18\x05 \x01(\x05R\bposition\x12\x12\n" +
+	"\x04line\x18\x06 \x01(\x05R\x04line\x12\x1c\n" +
+	"\tstatement\x18\a \x01(\tR\tstatement\"R\n" +
 	"\x16ListMigrationsResponse\x128\n" +
 	"\n" +
 	"migrations\x18\x01 \x03(\v2\x18.migration.MigrationInfoR\n" +
@@ -665,15 +5955,305 @@ const file_migrator_migrator_proto_rawDesc = "" +
 	"\x13GetMigrationRequest\x12!\n" +
 	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\"N\n" +
 	"\x14GetMigrationResponse\x126\n" +
-	"\tmigration\x18\x01 \x01(\v2\x18.migration.MigrationInfoR\tmigration2\xfa\x04\n" +
+	"\tmigration\x18\x01 \x01(\v2\x18.migration.MigrationInfoR\tmigration\"\x85\x01\n" +
+	"\x17ImportMigrationsRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\x12\x1b\n" +
+	"\auser_id\x18\x02 \x01(\x03B\x02\x18\x01R\x06userId\x12\x18\n" +
+	"\aarchive\x18\x03 \x01(\fR\aarchive\x12\x16\n" +
+	"\x06format\x18\x04 \x01(\tR\x06format\"\xa5\x01\n" +
+	"\n" +
+	"ImportItem\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12!\n" +
+	"\fmigration_id\x18\x05 \x01(\x03R\vmigrationId\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\"G\n" +
+	"\x18ImportMigrationsResponse\x12+\n" +
+	"\x05items\x18\x01 \x03(\v2\x15.migration.ImportItemR\x05items\"6\n" +
+	"\x17ExportMigrationsRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\"!\n" +
+	"\vExportChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\xd4\x03\n" +
+	"\fHistoryEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12!\n" +
+	"\fmigration_id\x18\x02 \x01(\x03R\vmigrationId\x12\x1b\n" +
+	"\ttarget_id\x18\x03 \x01(\x03R\btargetId\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x06 \x01(\tR\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\a \x01(\tR\n" +
+	"finishedAt\x12\x1f\n" +
+	"\vduration_ms\x18\b \x01(\x03R\n" +
+	"durationMs\x12\x18\n" +
+	"\aoutcome\x18\t \x01(\tR\aoutcome\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12\x16\n" +
+	"\x06script\x18\v \x01(\tR\x06script\x12D\n" +
+	"\tvariables\x18\f \x03(\v2&.migration.HistoryEntry.VariablesEntryR\tvariables\x12\x16\n" +
+	"\x06tenant\x18\r \x01(\tR\x06tenant\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe4\x01\n" +
+	"\x1bListMigrationHistoryRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\x12!\n" +
+	"\fmigration_id\x18\x02 \x01(\x03R\vmigrationId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x18\n" +
+	"\aoutcome\x18\x05 \x01(\tR\aoutcome\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\"y\n" +
+	"\x1cListMigrationHistoryResponse\x121\n" +
+	"\aentries\x18\x01 \x03(\v2\x17.migration.HistoryEntryR\aentries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"6\n" +
+	"\x17VerifyMigrationsRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\"\xea\x01\n" +
+	"\x11ChecksumViolation\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\x03R\vmigrationId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1a\n" +
+	"\bchecksum\x18\x04 \x01(\tR\bchecksum\x12)\n" +
+	"\x10applied_checksum\x18\x05 \x01(\tR\x0fappliedChecksum\x12'\n" +
+	"\x0factual_checksum\x18\x06 \x01(\tR\x0eactualChecksum\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\"X\n" +
+	"\x18VerifyMigrationsResponse\x12<\n" +
+	"\n" +
+	"violations\x18\x01 \x03(\v2\x1c.migration.ChecksumViolationR\n" +
+	"violations\"V\n" +
+	"\fSchemaObject\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"definition\x18\x03 \x01(\tR\n" +
+	"definition\"\x95\x01\n" +
+	"\x12SchemaSnapshotInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x19\n" +
+	"\btaken_at\x18\x04 \x01(\tR\atakenAt\x12#\n" +
+	"\robjects_count\x18\x05 \x01(\x03R\fobjectsCount\"}\n" +
+	"\vSchemaDrift\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06change\x18\x03 \x01(\tR\x06change\x12\x1a\n" +
+	"\brecorded\x18\x04 \x01(\tR\brecorded\x12\x12\n" +
+	"\x04live\x18\x05 \x01(\tR\x04live\"7\n" +
+	"\x18GetDatabaseStatusRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\"\xee\x01\n" +
+	"\x19GetDatabaseStatusResponse\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\x121\n" +
+	"\aobjects\x18\x02 \x03(\v2\x17.migration.SchemaObjectR\aobjects\x129\n" +
+	"\bsnapshot\x18\x03 \x01(\v2\x1d.migration.SchemaSnapshotInfoR\bsnapshot\x12,\n" +
+	"\x05drift\x18\x04 \x03(\v2\x16.migration.SchemaDriftR\x05drift\x12\x18\n" +
+	"\adrifted\x18\x05 \x01(\bR\adrifted\"\xe9\x01\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\x03R\aactorId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1a\n" +
+	"\bresource\x18\x04 \x01(\tR\bresource\x12\x0e\n" +
+	"\x02ip\x18\x05 \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x06 \x01(\tR\tuserAgent\x12\x18\n" +
+	"\aoutcome\x18\a \x01(\tR\aoutcome\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"\x93\x01\n" +
+	"\x16ListAuditEventsRequest\x12\x19\n" +
+	"\bactor_id\x18\x01 \x01(\x03R\aactorId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"p\n" +
+	"\x17ListAuditEventsResponse\x12-\n" +
+	"\x06events\x18\x01 \x03(\v2\x15.migration.AuditEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xea\x02\n" +
+	"\n" +
+	"TargetInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x05 \x01(\x03R\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\x12%\n" +
+	"\x0eenvironment_id\x18\b \x01(\x03R\renvironmentId\x12B\n" +
+	"\tvariables\x18\t \x03(\v2$.migration.TargetInfo.VariablesEntryR\tvariables\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xac\x02\n" +
+	"\x13CreateTargetRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x1b\n" +
+	"\auser_id\x18\x04 \x01(\x03B\x02\x18\x01R\x06userId\x12%\n" +
+	"\x0eenvironment_id\x18\x05 \x01(\x03R\renvironmentId\x12K\n" +
+	"\tvariables\x18\x06 \x03(\v2-.migration.CreateTargetRequest.VariablesEntryR\tvariables\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"3\n" +
+	"\x14CreateTargetResponse\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\"/\n" +
+	"\x10GetTargetRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\"B\n" +
+	"\x11GetTargetResponse\x12-\n" +
+	"\x06target\x18\x01 \x01(\v2\x15.migration.TargetInfoR\x06target\"\x14\n" +
+	"\x12ListTargetsRequest\"F\n" +
+	"\x13ListTargetsResponse\x12/\n" +
+	"\atargets\x18\x01 \x03(\v2\x15.migration.TargetInfoR\atargets\"\xc9\x02\n" +
+	"\x13UpdateTargetRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x1b\n" +
+	"\auser_id\x18\x05 \x01(\x03B\x02\x18\x01R\x06userId\x12%\n" +
+	"\x0eenvironment_id\x18\x06 \x01(\x03R\renvironmentId\x12K\n" +
+	"\tvariables\x18\a \x03(\v2-.migration.UpdateTargetRequest.VariablesEntryR\tvariables\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x16\n" +
+	"\x14UpdateTargetResponse\"O\n" +
+	"\x13DeleteTargetRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\x12\x1b\n" +
+	"\auser_id\x18\x02 \x01(\x03B\x02\x18\x01R\x06userId\"\x16\n" +
+	"\x14DeleteTargetResponse\"\x89\x03\n" +
+	"\x0fEnvironmentInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12&\n" +
+	"\x0fpromote_from_id\x18\x04 \x01(\x03R\rpromoteFromId\x12$\n" +
+	"\x0emin_soak_hours\x18\x05 \x01(\x05R\fminSoakHours\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x06 \x01(\x03R\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\x12G\n" +
+	"\tvariables\x18\t \x03(\v2).migration.EnvironmentInfo.VariablesEntryR\tvariables\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xae\x02\n" +
+	"\x18CreateEnvironmentRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12&\n" +
+	"\x0fpromote_from_id\x18\x03 \x01(\x03R\rpromoteFromId\x12$\n" +
+	"\x0emin_soak_hours\x18\x04 \x01(\x05R\fminSoakHours\x12P\n" +
+	"\tvariables\x18\x05 \x03(\v22.migration.CreateEnvironmentRequest.VariablesEntryR\tvariables\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"B\n" +
+	"\x19CreateEnvironmentResponse\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\x03R\renvironmentId\">\n" +
+	"\x15GetEnvironmentRequest\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\x03R\renvironmentId\"V\n" +
+	"\x16GetEnvironmentResponse\x12<\n" +
+	"\venvironment\x18\x01 \x01(\v2\x1a.migration.EnvironmentInfoR\venvironment\"\x19\n" +
+	"\x17ListEnvironmentsRequest\"Z\n" +
+	"\x18ListEnvironmentsResponse\x12>\n" +
+	"\fenvironments\x18\x01 \x03(\v2\x1a.migration.EnvironmentInfoR\fenvironments\"\xd5\x02\n" +
+	"\x18UpdateEnvironmentRequest\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\x03R\renvironmentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12&\n" +
+	"\x0fpromote_from_id\x18\x04 \x01(\x03R\rpromoteFromId\x12$\n" +
+	"\x0emin_soak_hours\x18\x05 \x01(\x05R\fminSoakHours\x12P\n" +
+	"\tvariables\x18\x06 \x03(\v22.migration.UpdateEnvironmentRequest.VariablesEntryR\tvariables\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1b\n" +
+	"\x19UpdateEnvironmentResponse\"A\n" +
+	"\x18DeleteEnvironmentRequest\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\x03R\renvironmentId\"\x1b\n" +
+	"\x19DeleteEnvironmentResponse\"\x92\x01\n" +
+	" ListMigrationEnvironmentsRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12%\n" +
+	"\x0eenvironment_id\x18\x03 \x01(\x03R\renvironmentId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\"\xaf\x02\n" +
+	"\x1aEnvironmentMigrationStatus\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\x03R\renvironmentId\x12)\n" +
+	"\x10environment_name\x18\x02 \x01(\tR\x0fenvironmentName\x12\x1b\n" +
+	"\ttarget_id\x18\x03 \x01(\x03R\btargetId\x12\x1f\n" +
+	"\vtarget_name\x18\x04 \x01(\tR\n" +
+	"targetName\x12!\n" +
+	"\fmigration_id\x18\x05 \x01(\x03R\vmigrationId\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12*\n" +
+	"\x11status_updated_at\x18\a \x01(\tR\x0fstatusUpdatedAt\x12\x1a\n" +
+	"\bchecksum\x18\b \x01(\tR\bchecksum\"n\n" +
+	"\x15MigrationEnvironments\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12A\n" +
+	"\bstatuses\x18\x02 \x03(\v2%.migration.EnvironmentMigrationStatusR\bstatuses\"e\n" +
+	"!ListMigrationEnvironmentsResponse\x12@\n" +
+	"\n" +
+	"migrations\x18\x01 \x03(\v2 .migration.MigrationEnvironmentsR\n" +
+	"migrations\"\x9d\x01\n" +
+	"\bLockInfo\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\x12\x16\n" +
+	"\x06holder\x18\x02 \x01(\tR\x06holder\x12\x1c\n" +
+	"\toperation\x18\x03 \x01(\tR\toperation\x12\x1f\n" +
+	"\vacquired_at\x18\x04 \x01(\tR\n" +
+	"acquiredAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\tR\texpiresAt\"\x12\n" +
+	"\x10ListLocksRequest\">\n" +
+	"\x11ListLocksResponse\x12)\n" +
+	"\x05locks\x18\x01 \x03(\v2\x13.migration.LockInfoR\x05locks\"N\n" +
+	"\x12ReleaseLockRequest\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\x03R\btargetId\x12\x1b\n" +
+	"\auser_id\x18\x02 \x01(\x03B\x02\x18\x01R\x06userId\"\x15\n" +
+	"\x13ReleaseLockResponse2\xe8&\n" +
 	"\x10MigrationService\x12s\n" +
-	"\x0fCreateMigration\x12!.migration.CreateMigrationRequest\x1a\".migration.CreateMigrationResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/migrations\x12v\n" +
+	"\x0fCreateMigration\x12!.migration.CreateMigrationRequest\x1a\".migration.CreateMigrationResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/migrations\x12r\n" +
+	"\rLintMigration\x12\x1f.migration.LintMigrationRequest\x1a .migration.LintMigrationResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/migrations/lint\x12v\n" +
 	"\x0eApplyMigration\x12 .migration.ApplyMigrationRequest\x1a!.migration.ApplyMigrationResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/migrations/apply\x12\x91\x01\n" +
-	"\x11RollbackMigration\x12#.migration.RollbackMigrationRequest\x1a$.migration.RollbackMigrationResponse\"1\x82\xd3\xe4\x93\x02+:\x01*\"&/v1/migrations/{migration_id}/rollback\x12m\n" +
+	"\x11RollbackMigration\x12#.migration.RollbackMigrationRequest\x1a$.migration.RollbackMigrationResponse\"1\x82\xd3\xe4\x93\x02+:\x01*\"&/v1/migrations/{migration_id}/rollback\x12\x9a\x01\n" +
+	"\x13RollbackToMigration\x12%.migration.RollbackToMigrationRequest\x1a&.migration.RollbackToMigrationResponse\"4\x82\xd3\xe4\x93\x02.:\x01*\")/v1/migrations/{migration_id}/rollback-to\x12\x85\x01\n" +
+	"\x12BaselineMigrations\x12$.migration.BaselineMigrationsRequest\x1a%.migration.BaselineMigrationsResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/migrations/baseline\x12\x9f\x01\n" +
+	"\x14ApplyTenantMigration\x12&.migration.ApplyTenantMigrationRequest\x1a'.migration.ApplyTenantMigrationResponse\"6\x82\xd3\xe4\x93\x020:\x01*\"+/v1/migrations/{migration_id}/tenants/apply\x12\xa7\x01\n" +
+	"\x19UpdateRepeatableMigration\x12+.migration.UpdateRepeatableMigrationRequest\x1a,.migration.UpdateRepeatableMigrationResponse\"/\x82\xd3\xe4\x93\x02):\x01*\x1a$/v1/migrations/{migration_id}/script\x12\x9e\x01\n" +
+	"\x16ListMigrationRevisions\x12(.migration.ListMigrationRevisionsRequest\x1a).migration.ListMigrationRevisionsResponse\"/\x82\xd3\xe4\x93\x02)\x12'/v1/migrations/{migration_id}/revisions\x12\x90\x01\n" +
+	"\x12ListTenantStatuses\x12$.migration.ListTenantStatusesRequest\x1a%.migration.ListTenantStatusesResponse\"-\x82\xd3\xe4\x93\x02'\x12%/v1/migrations/{migration_id}/tenants\x12\x86\x01\n" +
+	"\rStartBackfill\x12\x1f.migration.StartBackfillRequest\x1a\x1b.migration.BackfillResponse\"7\x82\xd3\xe4\x93\x021:\x01*\",/v1/migrations/{migration_id}/backfill/start\x12\x86\x01\n" +
+	"\rPauseBackfill\x12\x1f.migration.PauseBackfillRequest\x1a\x1b.migration.BackfillResponse\"7\x82\xd3\xe4\x93\x021:\x01*\",/v1/migrations/{migration_id}/backfill/pause\x12\x89\x01\n" +
+	"\x0eResumeBackfill\x12 .migration.ResumeBackfillRequest\x1a\x1b.migration.BackfillResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/v1/migrations/{migration_id}/backfill/resume\x12\x8f\x01\n" +
+	"\x10ThrottleBackfill\x12\".migration.ThrottleBackfillRequest\x1a\x1b.migration.BackfillResponse\":\x82\xd3\xe4\x93\x024:\x01*\"//v1/migrations/{migration_id}/backfill/throttle\x12y\n" +
+	"\vGetBackfill\x12\x1d.migration.GetBackfillRequest\x1a\x1b.migration.BackfillResponse\".\x82\xd3\xe4\x93\x02(\x12&/v1/migrations/{migration_id}/backfill\x12~\n" +
+	"\x12PlanApplyMigration\x12 .migration.ApplyMigrationRequest\x1a .migration.MigrationPlanResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/migrations/apply/plan\x12\x96\x01\n" +
+	"\x15PlanRollbackMigration\x12#.migration.RollbackMigrationRequest\x1a .migration.MigrationPlanResponse\"6\x82\xd3\xe4\x93\x020:\x01*\"+/v1/migrations/{migration_id}/rollback/plan\x12m\n" +
 	"\x0eListMigrations\x12 .migration.ListMigrationsRequest\x1a!.migration.ListMigrationsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/migrations\x12v\n" +
-	"\fGetMigration\x12\x1e.migration.GetMigrationRequest\x1a\x1f.migration.GetMigrationResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/migrations/{migration_id}B\xde\x01\x92A\xc3\x01\n" +
+	"\fGetMigration\x12\x1e.migration.GetMigrationRequest\x1a\x1f.migration.GetMigrationResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/migrations/{migration_id}\x12\x91\x01\n" +
+	"\x10ImportMigrations\x12\".migration.ImportMigrationsRequest\x1a#.migration.ImportMigrationsResponse\"4\x82\xd3\xe4\x93\x02.:\x01*\")/v1/targets/{target_id}/migrations/import\x12P\n" +
+	"\x10ExportMigrations\x12\".migration.ExportMigrationsRequest\x1a\x16.migration.ExportChunk0\x01\x12|\n" +
+	"\x14ListMigrationHistory\x12&.migration.ListMigrationHistoryRequest\x1a'.migration.ListMigrationHistoryResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/history\x12\x8e\x01\n" +
+	"\x10VerifyMigrations\x12\".migration.VerifyMigrationsRequest\x1a#.migration.VerifyMigrationsResponse\"1\x82\xd3\xe4\x93\x02+\x12)/v1/targets/{target_id}/migrations/verify\x12\x86\x01\n" +
+	"\x11GetDatabaseStatus\x12#.migration.GetDatabaseStatusRequest\x1a$.migration.GetDatabaseStatusResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/v1/targets/{target_id}/status\x12k\n" +
+	"\x0fListAuditEvents\x12!.migration.ListAuditEventsRequest\x1a\".migration.ListAuditEventsResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/audit\x12g\n" +
+	"\fCreateTarget\x12\x1e.migration.CreateTargetRequest\x1a\x1f.migration.CreateTargetResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/targets\x12g\n" +
+	"\tGetTarget\x12\x1b.migration.GetTargetRequest\x1a\x1c.migration.GetTargetResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/targets/{target_id}\x12a\n" +
+	"\vListTargets\x12\x1d.migration.ListTargetsRequest\x1a\x1e.migration.ListTargetsResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/targets\x12s\n" +
+	"\fUpdateTarget\x12\x1e.migration.UpdateTargetRequest\x1a\x1f.migration.UpdateTargetResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\x1a\x17/v1/targets/{target_id}\x12p\n" +
+	"\fDeleteTarget\x12\x1e.migration.DeleteTargetRequest\x1a\x1f.migration.DeleteTargetResponse\"\x1f\x82\xd3\xe4\x93\x02\x19*\x17/v1/targets/{target_id}\x12{\n" +
+	"\x11CreateEnvironment\x12#.migration.CreateEnvironmentRequest\x1a$.migration.CreateEnvironmentResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/environments\x12\x80\x01\n" +
+	"\x0eGetEnvironment\x12 .migration.GetEnvironmentRequest\x1a!.migration.GetEnvironmentResponse\")\x82\xd3\xe4\x93\x02#\x12!/v1/environments/{environment_id}\x12u\n" +
+	"\x10ListEnvironments\x12\".migration.ListEnvironmentsRequest\x1a#.migration.ListEnvironmentsResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/environments\x12\x8c\x01\n" +
+	"\x11UpdateEnvironment\x12#.migration.UpdateEnvironmentRequest\x1a$.migration.UpdateEnvironmentResponse\",\x82\xd3\xe4\x93\x02&:\x01*\x1a!/v1/environments/{environment_id}\x12\x89\x01\n" +
+	"\x11DeleteEnvironment\x12#.migration.DeleteEnvironmentRequest\x1a$.migration.DeleteEnvironmentResponse\")\x82\xd3\xe4\x93\x02#*!/v1/environments/{environment_id}\x12\x9b\x01\n" +
+	"\x19ListMigrationEnvironments\x12+.migration.ListMigrationEnvironmentsRequest\x1a,.migration.ListMigrationEnvironmentsResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/environments/migrations\x12Y\n" +
+	"\tListLocks\x12\x1b.migration.ListLocksRequest\x1a\x1c.migration.ListLocksResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/locks\x12}\n" +
+	"\vReleaseLock\x12\x1d.migration.ReleaseLockRequest\x1a\x1e.migration.ReleaseLockResponse\"/\x82\xd3\xe4\x93\x02):\x01*\"$/v1/targets/{target_id}/lock/releaseB\xe8\x02\x92A\xcd\x02\n" +
 	"\x032.0\x12\x84\x01\n" +
-	"\x15Migration Service API\x12fAPI для управления миграциями в реляционных базах данных2\x031.0\x1a\x0elocalhost:8080*\x01\x012\x10application/json:\x10application/jsonZ\x15migrator/api/migratorb\x06proto3"
+	"\x15Migration Service API\x12fAPI для управления миграциями в реляционных базах данных2\x031.0\x1a\x0elocalhost:8080*\x01\x012\x10application/json:\x10application/jsonZz\n" +
+	"x\n" +
+	"\x06Bearer\x12n\b\x02\x12YТокен доступа, выданный Auth.Login, в формате: Bearer <token>\x1a\rAuthorization \x02b\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00Z\x15migrator/api/migratorb\x06proto3"
 
 var (
 	file_migrator_migrator_proto_rawDescOnce sync.Once
@@ -687,38 +6267,225 @@ func file_migrator_migrator_proto_rawDescGZIP() []byte {
 	return file_migrator_migrator_proto_rawDescData
 }
 
-var file_migrator_migrator_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_migrator_migrator_proto_msgTypes = make([]protoimpl.MessageInfo, 98)
 var file_migrator_migrator_proto_goTypes = []any{
-	(*CreateMigrationRequest)(nil),    // 0: migration.CreateMigrationRequest
-	(*CreateMigrationResponse)(nil),   // 1: migration.CreateMigrationResponse
-	(*ApplyMigrationRequest)(nil),     // 2: migration.ApplyMigrationRequest
-	(*ApplyMigrationResponse)(nil),    // 3: migration.ApplyMigrationResponse
-	(*RollbackMigrationRequest)(nil),  // 4: migration.RollbackMigrationRequest
-	(*RollbackMigrationResponse)(nil), // 5: migration.RollbackMigrationResponse
-	(*ListMigrationsRequest)(nil),     // 6: migration.ListMigrationsRequest
-	(*MigrationInfo)(nil),             // 7: migration.MigrationInfo
-	(*ListMigrationsResponse)(nil),    // 8: migration.ListMigrationsResponse
-	(*GetMigrationRequest)(nil),       // 9: migration.GetMigrationRequest
-	(*GetMigrationResponse)(nil),      // 10: migration.GetMigrationResponse
+	(*CreateMigrationRequest)(nil),            // 0: migration.CreateMigrationRequest
+	(*CreateMigrationResponse)(nil),           // 1: migration.CreateMigrationResponse
+	(*LintMigrationRequest)(nil),              // 2: migration.LintMigrationRequest
+	(*LintMigrationResponse)(nil),             // 3: migration.LintMigrationResponse
+	(*LintFinding)(nil),                       // 4: migration.LintFinding
+	(*ApplyMigrationRequest)(nil),             // 5: migration.ApplyMigrationRequest
+	(*ApplyMigrationResponse)(nil),            // 6: migration.ApplyMigrationResponse
+	(*RollbackMigrationRequest)(nil),          // 7: migration.RollbackMigrationRequest
+	(*RollbackMigrationResponse)(nil),         // 8: migration.RollbackMigrationResponse
+	(*RollbackToMigrationRequest)(nil),        // 9: migration.RollbackToMigrationRequest
+	(*RollbackToMigrationResponse)(nil),       // 10: migration.RollbackToMigrationResponse
+	(*BaselineMigrationsRequest)(nil),         // 11: migration.BaselineMigrationsRequest
+	(*BaselineMigrationsResponse)(nil),        // 12: migration.BaselineMigrationsResponse
+	(*UpdateRepeatableMigrationRequest)(nil),  // 13: migration.UpdateRepeatableMigrationRequest
+	(*UpdateRepeatableMigrationResponse)(nil), // 14: migration.UpdateRepeatableMigrationResponse
+	(*MigrationRevision)(nil),                 // 15: migration.MigrationRevision
+	(*ListMigrationRevisionsRequest)(nil),     // 16: migration.ListMigrationRevisionsRequest
+	(*ListMigrationRevisionsResponse)(nil),    // 17: migration.ListMigrationRevisionsResponse
+	(*ApplyTenantMigrationRequest)(nil),       // 18: migration.ApplyTenantMigrationRequest
+	(*TenantStatus)(nil),                      // 19: migration.TenantStatus
+	(*ApplyTenantMigrationResponse)(nil),      // 20: migration.ApplyTenantMigrationResponse
+	(*BackfillSpec)(nil),                      // 21: migration.BackfillSpec
+	(*BackfillInfo)(nil),                      // 22: migration.BackfillInfo
+	(*StartBackfillRequest)(nil),              // 23: migration.StartBackfillRequest
+	(*PauseBackfillRequest)(nil),              // 24: migration.PauseBackfillRequest
+	(*ResumeBackfillRequest)(nil),             // 25: migration.ResumeBackfillRequest
+	(*ThrottleBackfillRequest)(nil),           // 26: migration.ThrottleBackfillRequest
+	(*GetBackfillRequest)(nil),                // 27: migration.GetBackfillRequest
+	(*BackfillResponse)(nil),                  // 28: migration.BackfillResponse
+	(*ListTenantStatusesRequest)(nil),         // 29: migration.ListTenantStatusesRequest
+	(*ListTenantStatusesResponse)(nil),        // 30: migration.ListTenantStatusesResponse
+	(*MigrationPlanItem)(nil),                 // 31: migration.MigrationPlanItem
+	(*MigrationPlanResponse)(nil),             // 32: migration.MigrationPlanResponse
+	(*ListMigrationsRequest)(nil),             // 33: migration.ListMigrationsRequest
+	(*MigrationInfo)(nil),                     // 34: migration.MigrationInfo
+	(*ScriptError)(nil),                       // 35: migration.ScriptError
+	(*ListMigrationsResponse)(nil),            // 36: migration.ListMigrationsResponse
+	(*GetMigrationRequest)(nil),               // 37: migration.GetMigrationRequest
+	(*GetMigrationResponse)(nil),              // 38: migration.GetMigrationResponse
+	(*ImportMigrationsRequest)(nil),           // 39: migration.ImportMigrationsRequest
+	(*ImportItem)(nil),                        // 40: migration.ImportItem
+	(*ImportMigrationsResponse)(nil),          // 41: migration.ImportMigrationsResponse
+	(*ExportMigrationsRequest)(nil),           // 42: migration.ExportMigrationsRequest
+	(*ExportChunk)(nil),                       // 43: migration.ExportChunk
+	(*HistoryEntry)(nil),                      // 44: migration.HistoryEntry
+	(*ListMigrationHistoryRequest)(nil),       // 45: migration.ListMigrationHistoryRequest
+	(*ListMigrationHistoryResponse)(nil),      // 46: migration.ListMigrationHistoryResponse
+	(*VerifyMigrationsRequest)(nil),           // 47: migration.VerifyMigrationsRequest
+	(*ChecksumViolation)(nil),                 // 48: migration.ChecksumViolation
+	(*VerifyMigrationsResponse)(nil),          // 49: migration.VerifyMigrationsResponse
+	(*SchemaObject)(nil),                      // 50: migration.SchemaObject
+	(*SchemaSnapshotInfo)(nil),                // 51: migration.SchemaSnapshotInfo
+	(*SchemaDrift)(nil),                       // 52: migration.SchemaDrift
+	(*GetDatabaseStatusRequest)(nil),          // 53: migration.GetDatabaseStatusRequest
+	(*GetDatabaseStatusResponse)(nil),         // 54: migration.GetDatabaseStatusResponse
+	(*AuditEvent)(nil),                        // 55: migration.AuditEvent
+	(*ListAuditEventsRequest)(nil),            // 56: migration.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil),           // 57: migration.ListAuditEventsResponse
+	(*TargetInfo)(nil),                        // 58: migration.TargetInfo
+	(*CreateTargetRequest)(nil),               // 59: migration.CreateTargetRequest
+	(*CreateTargetResponse)(nil),              // 60: migration.CreateTargetResponse
+	(*GetTargetRequest)(nil),                  // 61: migration.GetTargetRequest
+	(*GetTargetResponse)(nil),                 // 62: migration.GetTargetResponse
+	(*ListTargetsRequest)(nil),                // 63: migration.ListTargetsRequest
+	(*ListTargetsResponse)(nil),               // 64: migration.ListTargetsResponse
+	(*UpdateTargetRequest)(nil),               // 65: migration.UpdateTargetRequest
+	(*UpdateTargetResponse)(nil),              // 66: migration.UpdateTargetResponse
+	(*DeleteTargetRequest)(nil),               // 67: migration.DeleteTargetRequest
+	(*DeleteTargetResponse)(nil),              // 68: migration.DeleteTargetResponse
+	(*EnvironmentInfo)(nil),                   // 69: migration.EnvironmentInfo
+	(*CreateEnvironmentRequest)(nil),          // 70: migration.CreateEnvironmentRequest
+	(*CreateEnvironmentResponse)(nil),         // 71: migration.CreateEnvironmentResponse
+	(*GetEnvironmentRequest)(nil),             // 72: migration.GetEnvironmentRequest
+	(*GetEnvironmentResponse)(nil),            // 73: migration.GetEnvironmentResponse
+	(*ListEnvironmentsRequest)(nil),           // 74: migration.ListEnvironmentsRequest
+	(*ListEnvironmentsResponse)(nil),          // 75: migration.ListEnvironmentsResponse
+	(*UpdateEnvironmentRequest)(nil),          // 76: migration.UpdateEnvironmentRequest
+	(*UpdateEnvironmentResponse)(nil),         // 77: migration.UpdateEnvironmentResponse
+	(*DeleteEnvironmentRequest)(nil),          // 78: migration.DeleteEnvironmentRequest
+	(*DeleteEnvironmentResponse)(nil),         // 79: migration.DeleteEnvironmentResponse
+	(*ListMigrationEnvironmentsRequest)(nil),  // 80: migration.ListMigrationEnvironmentsRequest
+	(*EnvironmentMigrationStatus)(nil),        // 81: migration.EnvironmentMigrationStatus
+	(*MigrationEnvironments)(nil),             // 82: migration.MigrationEnvironments
+	(*ListMigrationEnvironmentsResponse)(nil), // 83: migration.ListMigrationEnvironmentsResponse
+	(*LockInfo)(nil),                          // 84: migration.LockInfo
+	(*ListLocksRequest)(nil),                  // 85: migration.ListLocksRequest
+	(*ListLocksResponse)(nil),                 // 86: migration.ListLocksResponse
+	(*ReleaseLockRequest)(nil),                // 87: migration.ReleaseLockRequest
+	(*ReleaseLockResponse)(nil),               // 88: migration.ReleaseLockResponse
+	nil,                                       // 89: migration.CreateMigrationRequest.LabelsEntry
+	nil,                                       // 90: migration.MigrationInfo.LabelsEntry
+	nil,                                       // 91: migration.HistoryEntry.VariablesEntry
+	nil,                                       // 92: migration.TargetInfo.VariablesEntry
+	nil,                                       // 93: migration.CreateTargetRequest.VariablesEntry
+	nil,                                       // 94: migration.UpdateTargetRequest.VariablesEntry
+	nil,                                       // 95: migration.EnvironmentInfo.VariablesEntry
+	nil,                                       // 96: migration.CreateEnvironmentRequest.VariablesEntry
+	nil,                                       // 97: migration.UpdateEnvironmentRequest.VariablesEntry
 }
 var file_migrator_migrator_proto_depIdxs = []int32{
-	7,  // 0: migration.ListMigrationsResponse.migrations:type_name -> migration.MigrationInfo
-	7,  // 1: migration.GetMigrationResponse.migration:type_name -> migration.MigrationInfo
-	0,  // 2: migration.MigrationService.CreateMigration:input_type -> migration.CreateMigrationRequest
-	2,  // 3: migration.MigrationService.ApplyMigration:input_type -> migration.ApplyMigrationRequest
-	4,  // 4: migration.MigrationService.RollbackMigration:input_type -> migration.RollbackMigrationRequest
-	6,  // 5: migration.MigrationService.ListMigrations:input_type -> migration.ListMigrationsRequest
-	9,  // 6: migration.MigrationService.GetMigration:input_type -> migration.GetMigrationRequest
-	1,  // 7: migration.MigrationService.CreateMigration:output_type -> migration.CreateMigrationResponse
-	3,  // 8: migration.MigrationService.ApplyMigration:output_type -> migration.ApplyMigrationResponse
-	5,  // 9: migration.MigrationService.RollbackMigration:output_type -> migration.RollbackMigrationResponse
-	8,  // 10: migration.MigrationService.ListMigrations:output_type -> migration.ListMigrationsResponse
-	10, // 11: migration.MigrationService.GetMigration:output_type -> migration.GetMigrationResponse
-	7,  // [7:12] is the sub-list for method output_type
-	2,  // [2:7] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	89, // 0: migration.CreateMigrationRequest.labels:type_name -> migration.CreateMigrationRequest.LabelsEntry
+	21, // 1: migration.CreateMigrationRequest.backfill:type_name -> migration.BackfillSpec
+	4,  // 2: migration.CreateMigrationResponse.lint_findings:type_name -> migration.LintFinding
+	4,  // 3: migration.LintMigrationResponse.findings:type_name -> migration.LintFinding
+	4,  // 4: migration.UpdateRepeatableMigrationResponse.lint_findings:type_name -> migration.LintFinding
+	15, // 5: migration.ListMigrationRevisionsResponse.revisions:type_name -> migration.MigrationRevision
+	19, // 6: migration.ApplyTenantMigrationResponse.failed:type_name -> migration.TenantStatus
+	22, // 7: migration.BackfillResponse.backfill:type_name -> migration.BackfillInfo
+	19, // 8: migration.ListTenantStatusesResponse.tenants:type_name -> migration.TenantStatus
+	31, // 9: migration.MigrationPlanResponse.items:type_name -> migration.MigrationPlanItem
+	35, // 10: migration.MigrationInfo.last_error:type_name -> migration.ScriptError
+	90, // 11: migration.MigrationInfo.labels:type_name -> migration.MigrationInfo.LabelsEntry
+	21, // 12: migration.MigrationInfo.backfill:type_name -> migration.BackfillSpec
+	34, // 13: migration.ListMigrationsResponse.migrations:type_name -> migration.MigrationInfo
+	34, // 14: migration.GetMigrationResponse.migration:type_name -> migration.MigrationInfo
+	40, // 15: migration.ImportMigrationsResponse.items:type_name -> migration.ImportItem
+	91, // 16: migration.HistoryEntry.variables:type_name -> migration.HistoryEntry.VariablesEntry
+	44, // 17: migration.ListMigrationHistoryResponse.entries:type_name -> migration.HistoryEntry
+	48, // 18: migration.VerifyMigrationsResponse.violations:type_name -> migration.ChecksumViolation
+	50, // 19: migration.GetDatabaseStatusResponse.objects:type_name -> migration.SchemaObject
+	51, // 20: migration.GetDatabaseStatusResponse.snapshot:type_name -> migration.SchemaSnapshotInfo
+	52, // 21: migration.GetDatabaseStatusResponse.drift:type_name -> migration.SchemaDrift
+	55, // 22: migration.ListAuditEventsResponse.events:type_name -> migration.AuditEvent
+	92, // 23: migration.TargetInfo.variables:type_name -> migration.TargetInfo.VariablesEntry
+	93, // 24: migration.CreateTargetRequest.variables:type_name -> migration.CreateTargetRequest.VariablesEntry
+	58, // 25: migration.GetTargetResponse.target:type_name -> migration.TargetInfo
+	58, // 26: migration.ListTargetsResponse.targets:type_name -> migration.TargetInfo
+	94, // 27: migration.UpdateTargetRequest.variables:type_name -> migration.UpdateTargetRequest.VariablesEntry
+	95, // 28: migration.EnvironmentInfo.variables:type_name -> migration.EnvironmentInfo.VariablesEntry
+	96, // 29: migration.CreateEnvironmentRequest.variables:type_name -> migration.CreateEnvironmentRequest.VariablesEntry
+	69, // 30: migration.GetEnvironmentResponse.environment:type_name -> migration.EnvironmentInfo
+	69, // 31: migration.ListEnvironmentsResponse.environments:type_name -> migration.EnvironmentInfo
+	97, // 32: migration.UpdateEnvironmentRequest.variables:type_name -> migration.UpdateEnvironmentRequest.VariablesEntry
+	81, // 33: migration.MigrationEnvironments.statuses:type_name -> migration.EnvironmentMigrationStatus
+	82, // 34: migration.ListMigrationEnvironmentsResponse.migrations:type_name -> migration.MigrationEnvironments
+	84, // 35: migration.ListLocksResponse.locks:type_name -> migration.LockInfo
+	0,  // 36: migration.MigrationService.CreateMigration:input_type -> migration.CreateMigrationRequest
+	2,  // 37: migration.MigrationService.LintMigration:input_type -> migration.LintMigrationRequest
+	5,  // 38: migration.MigrationService.ApplyMigration:input_type -> migration.ApplyMigrationRequest
+	7,  // 39: migration.MigrationService.RollbackMigration:input_type -> migration.RollbackMigrationRequest
+	9,  // 40: migration.MigrationService.RollbackToMigration:input_type -> migration.RollbackToMigrationRequest
+	11, // 41: migration.MigrationService.BaselineMigrations:input_type -> migration.BaselineMigrationsRequest
+	18, // 42: migration.MigrationService.ApplyTenantMigration:input_type -> migration.ApplyTenantMigrationRequest
+	13, // 43: migration.MigrationService.UpdateRepeatableMigration:input_type -> migration.UpdateRepeatableMigrationRequest
+	16, // 44: migration.MigrationService.ListMigrationRevisions:input_type -> migration.ListMigrationRevisionsRequest
+	29, // 45: migration.MigrationService.ListTenantStatuses:input_type -> migration.ListTenantStatusesRequest
+	23, // 46: migration.MigrationService.StartBackfill:input_type -> migration.StartBackfillRequest
+	24, // 47: migration.MigrationService.PauseBackfill:input_type -> migration.PauseBackfillRequest
+	25, // 48: migration.MigrationService.ResumeBackfill:input_type -> migration.ResumeBackfillRequest
+	26, // 49: migration.MigrationService.ThrottleBackfill:input_type -> migration.ThrottleBackfillRequest
+	27, // 50: migration.MigrationService.GetBackfill:input_type -> migration.GetBackfillRequest
+	5,  // 51: migration.MigrationService.PlanApplyMigration:input_type -> migration.ApplyMigrationRequest
+	7,  // 52: migration.MigrationService.PlanRollbackMigration:input_type -> migration.RollbackMigrationRequest
+	33, // 53: migration.MigrationService.ListMigrations:input_type -> migration.ListMigrationsRequest
+	37, // 54: migration.MigrationService.GetMigration:input_type -> migration.GetMigrationRequest
+	39, // 55: migration.MigrationService.ImportMigrations:input_type -> migration.ImportMigrationsRequest
+	42, // 56: migration.MigrationService.ExportMigrations:input_type -> migration.ExportMigrationsRequest
+	45, // 57: migration.MigrationService.ListMigrationHistory:input_type -> migration.ListMigrationHistoryRequest
+	47, // 58: migration.MigrationService.VerifyMigrations:input_type -> migration.VerifyMigrationsRequest
+	53, // 59: migration.MigrationService.GetDatabaseStatus:input_type -> migration.GetDatabaseStatusRequest
+	56, // 60: migration.MigrationService.ListAuditEvents:input_type -> migration.ListAuditEventsRequest
+	59, // 61: migration.MigrationService.CreateTarget:input_type -> migration.CreateTargetRequest
+	61, // 62: migration.MigrationService.GetTarget:input_type -> migration.GetTargetRequest
+	63, // 63: migration.MigrationService.ListTargets:input_type -> migration.ListTargetsRequest
+	65, // 64: migration.MigrationService.UpdateTarget:input_type -> migration.UpdateTargetRequest
+	67, // 65: migration.MigrationService.DeleteTarget:input_type -> migration.DeleteTargetRequest
+	70, // 66: migration.MigrationService.CreateEnvironment:input_type -> migration.CreateEnvironmentRequest
+	72, // 67: migration.MigrationService.GetEnvironment:input_type -> migration.GetEnvironmentRequest
+	74, // 68: migration.MigrationService.ListEnvironments:input_type -> migration.ListEnvironmentsRequest
+	76, // 69: migration.MigrationService.UpdateEnvironment:input_type -> migration.UpdateEnvironmentRequest
+	78, // 70: migration.MigrationService.DeleteEnvironment:input_type -> migration.DeleteEnvironmentRequest
+	80, // 71: migration.MigrationService.ListMigrationEnvironments:input_type -> migration.ListMigrationEnvironmentsRequest
+	85, // 72: migration.MigrationService.ListLocks:input_type -> migration.ListLocksRequest
+	87, // 73: migration.MigrationService.ReleaseLock:input_type -> migration.ReleaseLockRequest
+	1,  // 74: migration.MigrationService.CreateMigration:output_type -> migration.CreateMigrationResponse
+	3,  // 75: migration.MigrationService.LintMigration:output_type -> migration.LintMigrationResponse
+	6,  // 76: migration.MigrationService.ApplyMigration:output_type -> migration.ApplyMigrationResponse
+	8,  // 77: migration.MigrationService.RollbackMigration:output_type -> migration.RollbackMigrationResponse
+	10, // 78: migration.MigrationService.RollbackToMigration:output_type -> migration.RollbackToMigrationResponse
+	12, // 79: migration.MigrationService.BaselineMigrations:output_type -> migration.BaselineMigrationsResponse
+	20, // 80: migration.MigrationService.ApplyTenantMigration:output_type -> migration.ApplyTenantMigrationResponse
+	14, // 81: migration.MigrationService.UpdateRepeatableMigration:output_type -> migration.UpdateRepeatableMigrationResponse
+	17, // 82: migration.MigrationService.ListMigrationRevisions:output_type -> migration.ListMigrationRevisionsResponse
+	30, // 83: migration.MigrationService.ListTenantStatuses:output_type -> migration.ListTenantStatusesResponse
+	28, // 84: migration.MigrationService.StartBackfill:output_type -> migration.BackfillResponse
+	28, // 85: migration.MigrationService.PauseBackfill:output_type -> migration.BackfillResponse
+	28, // 86: migration.MigrationService.ResumeBackfill:output_type -> migration.BackfillResponse
+	28, // 87: migration.MigrationService.ThrottleBackfill:output_type -> migration.BackfillResponse
+	28, // 88: migration.MigrationService.GetBackfill:output_type -> migration.BackfillResponse
+	32, // 89: migration.MigrationService.PlanApplyMigration:output_type -> migration.MigrationPlanResponse
+	32, // 90: migration.MigrationService.PlanRollbackMigration:output_type -> migration.MigrationPlanResponse
+	36, // 91: migration.MigrationService.ListMigrations:output_type -> migration.ListMigrationsResponse
+	38, // 92: migration.MigrationService.GetMigration:output_type -> migration.GetMigrationResponse
+	41, // 93: migration.MigrationService.ImportMigrations:output_type -> migration.ImportMigrationsResponse
+	43, // 94: migration.MigrationService.ExportMigrations:output_type -> migration.ExportChunk
+	46, // 95: migration.MigrationService.ListMigrationHistory:output_type -> migration.ListMigrationHistoryResponse
+	49, // 96: migration.MigrationService.VerifyMigrations:output_type -> migration.VerifyMigrationsResponse
+	54, // 97: migration.MigrationService.GetDatabaseStatus:output_type -> migration.GetDatabaseStatusResponse
+	57, // 98: migration.MigrationService.ListAuditEvents:output_type -> migration.ListAuditEventsResponse
+	60, // 99: migration.MigrationService.CreateTarget:output_type -> migration.CreateTargetResponse
+	62, // 100: migration.MigrationService.GetTarget:output_type -> migration.GetTargetResponse
+	64, // 101: migration.MigrationService.ListTargets:output_type -> migration.ListTargetsResponse
+	66, // 102: migration.MigrationService.UpdateTarget:output_type -> migration.UpdateTargetResponse
+	68, // 103: migration.MigrationService.DeleteTarget:output_type -> migration.DeleteTargetResponse
+	71, // 104: migration.MigrationService.CreateEnvironment:output_type -> migration.CreateEnvironmentResponse
+	73, // 105: migration.MigrationService.GetEnvironment:output_type -> migration.GetEnvironmentResponse
+	75, // 106: migration.MigrationService.ListEnvironments:output_type -> migration.ListEnvironmentsResponse
+	77, // 107: migration.MigrationService.UpdateEnvironment:output_type -> migration.UpdateEnvironmentResponse
+	79, // 108: migration.MigrationService.DeleteEnvironment:output_type -> migration.DeleteEnvironmentResponse
+	83, // 109: migration.MigrationService.ListMigrationEnvironments:output_type -> migration.ListMigrationEnvironmentsResponse
+	86, // 110: migration.MigrationService.ListLocks:output_type -> migration.ListLocksResponse
+	88, // 111: migration.MigrationService.ReleaseLock:output_type -> migration.ReleaseLockResponse
+	74, // [74:112] is the sub-list for method output_type
+	36, // [36:74] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_migrator_migrator_proto_init() }
@@ -732,7 +6499,7 @@ func file_migrator_migrator_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_migrator_migrator_proto_rawDesc), len(file_migrator_migrator_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   98,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
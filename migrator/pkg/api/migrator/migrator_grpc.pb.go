@@ -19,11 +19,44 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MigrationService_CreateMigration_FullMethodName   = "/migration.MigrationService/CreateMigration"
-	MigrationService_ApplyMigration_FullMethodName    = "/migration.MigrationService/ApplyMigration"
-	MigrationService_RollbackMigration_FullMethodName = "/migration.MigrationService/RollbackMigration"
-	MigrationService_ListMigrations_FullMethodName    = "/migration.MigrationService/ListMigrations"
-	MigrationService_GetMigration_FullMethodName      = "/migration.MigrationService/GetMigration"
+	MigrationService_CreateMigration_FullMethodName           = "/migration.MigrationService/CreateMigration"
+	MigrationService_LintMigration_FullMethodName             = "/migration.MigrationService/LintMigration"
+	MigrationService_ApplyMigration_FullMethodName            = "/migration.MigrationService/ApplyMigration"
+	MigrationService_RollbackMigration_FullMethodName         = "/migration.MigrationService/RollbackMigration"
+	MigrationService_RollbackToMigration_FullMethodName       = "/migration.MigrationService/RollbackToMigration"
+	MigrationService_BaselineMigrations_FullMethodName        = "/migration.MigrationService/BaselineMigrations"
+	MigrationService_ApplyTenantMigration_FullMethodName      = "/migration.MigrationService/ApplyTenantMigration"
+	MigrationService_UpdateRepeatableMigration_FullMethodName = "/migration.MigrationService/UpdateRepeatableMigration"
+	MigrationService_ListMigrationRevisions_FullMethodName    = "/migration.MigrationService/ListMigrationRevisions"
+	MigrationService_ListTenantStatuses_FullMethodName        = "/migration.MigrationService/ListTenantStatuses"
+	MigrationService_StartBackfill_FullMethodName             = "/migration.MigrationService/StartBackfill"
+	MigrationService_PauseBackfill_FullMethodName             = "/migration.MigrationService/PauseBackfill"
+	MigrationService_ResumeBackfill_FullMethodName            = "/migration.MigrationService/ResumeBackfill"
+	MigrationService_ThrottleBackfill_FullMethodName          = "/migration.MigrationService/ThrottleBackfill"
+	MigrationService_GetBackfill_FullMethodName               = "/migration.MigrationService/GetBackfill"
+	MigrationService_PlanApplyMigration_FullMethodName        = "/migration.MigrationService/PlanApplyMigration"
+	MigrationService_PlanRollbackMigration_FullMethodName     = "/migration.MigrationService/PlanRollbackMigration"
+	MigrationService_ListMigrations_FullMethodName            = "/migration.MigrationService/ListMigrations"
+	MigrationService_GetMigration_FullMethodName              = "/migration.MigrationService/GetMigration"
+	MigrationService_ImportMigrations_FullMethodName          = "/migration.MigrationService/ImportMigrations"
+	MigrationService_ExportMigrations_FullMethodName          = "/migration.MigrationService/ExportMigrations"
+	MigrationService_ListMigrationHistory_FullMethodName      = "/migration.MigrationService/ListMigrationHistory"
+	MigrationService_VerifyMigrations_FullMethodName          = "/migration.MigrationService/VerifyMigrations"
+	MigrationService_GetDatabaseStatus_FullMethodName         = "/migration.MigrationService/GetDatabaseStatus"
+	MigrationService_ListAuditEvents_FullMethodName           = "/migration.MigrationService/ListAuditEvents"
+	MigrationService_CreateTarget_FullMethodName              = "/migration.MigrationService/CreateTarget"
+	MigrationService_GetTarget_FullMethodName                 = "/migration.MigrationService/GetTarget"
+	MigrationService_ListTargets_FullMethodName               = "/migration.MigrationService/ListTargets"
+	MigrationService_UpdateTarget_FullMethodName              = "/migration.MigrationService/UpdateTarget"
+	MigrationService_DeleteTarget_FullMethodName              = "/migration.MigrationService/DeleteTarget"
+	MigrationService_CreateEnvironment_FullMethodName         = "/migration.MigrationService/CreateEnvironment"
+	MigrationService_GetEnvironment_FullMethodName            = "/migration.MigrationService/GetEnvironment"
+	MigrationService_ListEnvironments_FullMethodName          = "/migration.MigrationService/ListEnvironments"
+	MigrationService_UpdateEnvironment_FullMethodName         = "/migration.MigrationService/UpdateEnvironment"
+	MigrationService_DeleteEnvironment_FullMethodName         = "/migration.MigrationService/DeleteEnvironment"
+	MigrationService_ListMigrationEnvironments_FullMethodName = "/migration.MigrationService/ListMigrationEnvironments"
+	MigrationService_ListLocks_FullMethodName                 = "/migration.MigrationService/ListLocks"
+	MigrationService_ReleaseLock_FullMethodName               = "/migration.MigrationService/ReleaseLock"
 )
 
 // MigrationServiceClient is the client API for MigrationService service.
@@ -32,16 +65,87 @@ const (
 //
 // MigrationService - сервис для управления миграциями
 type MigrationServiceClient interface {
-	// Создание новой миграции
+	// Создание новой миграции.
+	// Повтор запроса с тем же ключом идемпотентности (метаданные idempotency-key,
+	// HTTP-заголовок Idempotency-Key) возвращает ответ первого запроса
 	CreateMigration(ctx context.Context, in *CreateMigrationRequest, opts ...grpc.CallOption) (*CreateMigrationResponse, error)
-	// Применение миграций
+	// Проверка скриптов миграции линтером SQL
+	LintMigration(ctx context.Context, in *LintMigrationRequest, opts ...grpc.CallOption) (*LintMigrationResponse, error)
+	// Применение миграций.
+	// Повтор запроса с тем же ключом идемпотентности (метаданные idempotency-key,
+	// HTTP-заголовок Idempotency-Key) возвращает ответ первого запроса
 	ApplyMigration(ctx context.Context, in *ApplyMigrationRequest, opts ...grpc.CallOption) (*ApplyMigrationResponse, error)
 	// Откат миграции
 	RollbackMigration(ctx context.Context, in *RollbackMigrationRequest, opts ...grpc.CallOption) (*RollbackMigrationResponse, error)
+	// Откат всех миграций, примененных после указанной, в одной транзакции
+	RollbackToMigration(ctx context.Context, in *RollbackToMigrationRequest, opts ...grpc.CallOption) (*RollbackToMigrationResponse, error)
+	// Отметка миграций примененными без выполнения скриптов для базы данных, схема которой уже создана
+	BaselineMigrations(ctx context.Context, in *BaselineMigrationsRequest, opts ...grpc.CallOption) (*BaselineMigrationsResponse, error)
+	// Применение миграции арендаторов к схемам арендаторов целевой базы данных
+	ApplyTenantMigration(ctx context.Context, in *ApplyTenantMigrationRequest, opts ...grpc.CallOption) (*ApplyTenantMigrationResponse, error)
+	// Замена скрипта повторяемой миграции; измененный скрипт выполняется следующим применением
+	UpdateRepeatableMigration(ctx context.Context, in *UpdateRepeatableMigrationRequest, opts ...grpc.CallOption) (*UpdateRepeatableMigrationResponse, error)
+	// Выполненные ревизии скрипта повторяемой миграции
+	ListMigrationRevisions(ctx context.Context, in *ListMigrationRevisionsRequest, opts ...grpc.CallOption) (*ListMigrationRevisionsResponse, error)
+	// Состояние миграции арендаторов в каждой схеме арендатора
+	ListTenantStatuses(ctx context.Context, in *ListTenantStatusesRequest, opts ...grpc.CallOption) (*ListTenantStatusesResponse, error)
+	// Запуск миграции данных: пачки выполняются в фоне с сохранением курсора
+	StartBackfill(ctx context.Context, in *StartBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error)
+	// Приостановка миграции данных после выполняемой пачки
+	PauseBackfill(ctx context.Context, in *PauseBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error)
+	// Продолжение приостановленной или завершившейся ошибкой миграции данных с сохраненного курсора
+	ResumeBackfill(ctx context.Context, in *ResumeBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error)
+	// Изменение размера пачки и паузы между пачками миграции данных
+	ThrottleBackfill(ctx context.Context, in *ThrottleBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error)
+	// Ход выполнения миграции данных
+	GetBackfill(ctx context.Context, in *GetBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error)
+	// Пробное применение миграций: скрипты выполняются в транзакции, которая всегда откатывается
+	PlanApplyMigration(ctx context.Context, in *ApplyMigrationRequest, opts ...grpc.CallOption) (*MigrationPlanResponse, error)
+	// Пробный откат миграции: скрипт отката выполняется в транзакции, которая всегда откатывается
+	PlanRollbackMigration(ctx context.Context, in *RollbackMigrationRequest, opts ...grpc.CallOption) (*MigrationPlanResponse, error)
 	// Получение списка миграций
 	ListMigrations(ctx context.Context, in *ListMigrationsRequest, opts ...grpc.CallOption) (*ListMigrationsResponse, error)
 	// Получение конкретной миграции
 	GetMigration(ctx context.Context, in *GetMigrationRequest, opts ...grpc.CallOption) (*GetMigrationResponse, error)
+	// Импорт миграций из архива файлов в раскладке golang-migrate, goose или Flyway
+	ImportMigrations(ctx context.Context, in *ImportMigrationsRequest, opts ...grpc.CallOption) (*ImportMigrationsResponse, error)
+	// Выгрузка реестра миграций архивом tar.gz, передаваемым частями.
+	// Через HTTP архив доступен по GET /v1/export?target_id=...
+	ExportMigrations(ctx context.Context, in *ExportMigrationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
+	// Получение журнала выполнения миграций
+	ListMigrationHistory(ctx context.Context, in *ListMigrationHistoryRequest, opts ...grpc.CallOption) (*ListMigrationHistoryResponse, error)
+	// Проверка контрольных сумм скриптов миграций целевой базы данных
+	VerifyMigrations(ctx context.Context, in *VerifyMigrationsRequest, opts ...grpc.CallOption) (*VerifyMigrationsResponse, error)
+	// Сравнение схемы целевой базы данных с последним снимком, сохраненным после применения или отката
+	GetDatabaseStatus(ctx context.Context, in *GetDatabaseStatusRequest, opts ...grpc.CallOption) (*GetDatabaseStatusResponse, error)
+	// Получение журнала аудита вызовов API
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	// Регистрация целевой базы данных
+	CreateTarget(ctx context.Context, in *CreateTargetRequest, opts ...grpc.CallOption) (*CreateTargetResponse, error)
+	// Получение целевой базы данных
+	GetTarget(ctx context.Context, in *GetTargetRequest, opts ...grpc.CallOption) (*GetTargetResponse, error)
+	// Получение списка целевых баз данных
+	ListTargets(ctx context.Context, in *ListTargetsRequest, opts ...grpc.CallOption) (*ListTargetsResponse, error)
+	// Изменение настроек целевой базы данных
+	UpdateTarget(ctx context.Context, in *UpdateTargetRequest, opts ...grpc.CallOption) (*UpdateTargetResponse, error)
+	// Удаление целевой базы данных из реестра
+	DeleteTarget(ctx context.Context, in *DeleteTargetRequest, opts ...grpc.CallOption) (*DeleteTargetResponse, error)
+	// Создание окружения конвейера продвижения миграций
+	CreateEnvironment(ctx context.Context, in *CreateEnvironmentRequest, opts ...grpc.CallOption) (*CreateEnvironmentResponse, error)
+	// Получение окружения
+	GetEnvironment(ctx context.Context, in *GetEnvironmentRequest, opts ...grpc.CallOption) (*GetEnvironmentResponse, error)
+	// Получение списка окружений в порядке конвейера
+	ListEnvironments(ctx context.Context, in *ListEnvironmentsRequest, opts ...grpc.CallOption) (*ListEnvironmentsResponse, error)
+	// Изменение окружения и его правила продвижения
+	UpdateEnvironment(ctx context.Context, in *UpdateEnvironmentRequest, opts ...grpc.CallOption) (*UpdateEnvironmentResponse, error)
+	// Удаление окружения
+	DeleteEnvironment(ctx context.Context, in *DeleteEnvironmentRequest, opts ...grpc.CallOption) (*DeleteEnvironmentResponse, error)
+	// Состояния миграций во всех окружениях
+	ListMigrationEnvironments(ctx context.Context, in *ListMigrationEnvironmentsRequest, opts ...grpc.CallOption) (*ListMigrationEnvironmentsResponse, error)
+	// Получение списка удерживаемых блокировок целевых баз данных
+	ListLocks(ctx context.Context, in *ListLocksRequest, opts ...grpc.CallOption) (*ListLocksResponse, error)
+	// Принудительное освобождение блокировки целевой базы данных
+	ReleaseLock(ctx context.Context, in *ReleaseLockRequest, opts ...grpc.CallOption) (*ReleaseLockResponse, error)
 }
 
 type migrationServiceClient struct {
@@ -62,6 +166,16 @@ func (c *migrationServiceClient) CreateMigration(ctx context.Context, in *Create
 	return out, nil
 }
 
+func (c *migrationServiceClient) LintMigration(ctx context.Context, in *LintMigrationRequest, opts ...grpc.CallOption) (*LintMigrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LintMigrationResponse)
+	err := c.cc.Invoke(ctx, MigrationService_LintMigration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *migrationServiceClient) ApplyMigration(ctx context.Context, in *ApplyMigrationRequest, opts ...grpc.CallOption) (*ApplyMigrationResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ApplyMigrationResponse)
@@ -69,187 +183,1273 @@ func (c *migrationServiceClient) ApplyMigration(ctx context.Context, in *ApplyMi
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *migrationServiceClient) RollbackMigration(ctx context.Context, in *RollbackMigrationRequest, opts ...grpc.CallOption) (*RollbackMigrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RollbackMigrationResponse)
+	err := c.cc.Invoke(ctx, MigrationService_RollbackMigration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) RollbackToMigration(ctx context.Context, in *RollbackToMigrationRequest, opts ...grpc.CallOption) (*RollbackToMigrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RollbackToMigrationResponse)
+	err := c.cc.Invoke(ctx, MigrationService_RollbackToMigration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) BaselineMigrations(ctx context.Context, in *BaselineMigrationsRequest, opts ...grpc.CallOption) (*BaselineMigrationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BaselineMigrationsResponse)
+	err := c.cc.Invoke(ctx, MigrationService_BaselineMigrations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ApplyTenantMigration(ctx context.Context, in *ApplyTenantMigrationRequest, opts ...grpc.CallOption) (*ApplyTenantMigrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyTenantMigrationResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ApplyTenantMigration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) UpdateRepeatableMigration(ctx context.Context, in *UpdateRepeatableMigrationRequest, opts ...grpc.CallOption) (*UpdateRepeatableMigrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateRepeatableMigrationResponse)
+	err := c.cc.Invoke(ctx, MigrationService_UpdateRepeatableMigration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ListMigrationRevisions(ctx context.Context, in *ListMigrationRevisionsRequest, opts ...grpc.CallOption) (*ListMigrationRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMigrationRevisionsResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ListMigrationRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ListTenantStatuses(ctx context.Context, in *ListTenantStatusesRequest, opts ...grpc.CallOption) (*ListTenantStatusesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTenantStatusesResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ListTenantStatuses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) StartBackfill(ctx context.Context, in *StartBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackfillResponse)
+	err := c.cc.Invoke(ctx, MigrationService_StartBackfill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) PauseBackfill(ctx context.Context, in *PauseBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackfillResponse)
+	err := c.cc.Invoke(ctx, MigrationService_PauseBackfill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ResumeBackfill(ctx context.Context, in *ResumeBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackfillResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ResumeBackfill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ThrottleBackfill(ctx context.Context, in *ThrottleBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackfillResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ThrottleBackfill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) GetBackfill(ctx context.Context, in *GetBackfillRequest, opts ...grpc.CallOption) (*BackfillResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackfillResponse)
+	err := c.cc.Invoke(ctx, MigrationService_GetBackfill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) PlanApplyMigration(ctx context.Context, in *ApplyMigrationRequest, opts ...grpc.CallOption) (*MigrationPlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MigrationPlanResponse)
+	err := c.cc.Invoke(ctx, MigrationService_PlanApplyMigration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) PlanRollbackMigration(ctx context.Context, in *RollbackMigrationRequest, opts ...grpc.CallOption) (*MigrationPlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MigrationPlanResponse)
+	err := c.cc.Invoke(ctx, MigrationService_PlanRollbackMigration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ListMigrations(ctx context.Context, in *ListMigrationsRequest, opts ...grpc.CallOption) (*ListMigrationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMigrationsResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ListMigrations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) GetMigration(ctx context.Context, in *GetMigrationRequest, opts ...grpc.CallOption) (*GetMigrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMigrationResponse)
+	err := c.cc.Invoke(ctx, MigrationService_GetMigration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ImportMigrations(ctx context.Context, in *ImportMigrationsRequest, opts ...grpc.CallOption) (*ImportMigrationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportMigrationsResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ImportMigrations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ExportMigrations(ctx context.Context, in *ExportMigrationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MigrationService_ServiceDesc.Streams[0], MigrationService_ExportMigrations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportMigrationsRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MigrationService_ExportMigrationsClient = grpc.ServerStreamingClient[ExportChunk]
+
+func (c *migrationServiceClient) ListMigrationHistory(ctx context.Context, in *ListMigrationHistoryRequest, opts ...grpc.CallOption) (*ListMigrationHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMigrationHistoryResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ListMigrationHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) VerifyMigrations(ctx context.Context, in *VerifyMigrationsRequest, opts ...grpc.CallOption) (*VerifyMigrationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyMigrationsResponse)
+	err := c.cc.Invoke(ctx, MigrationService_VerifyMigrations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) GetDatabaseStatus(ctx context.Context, in *GetDatabaseStatusRequest, opts ...grpc.CallOption) (*GetDatabaseStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDatabaseStatusResponse)
+	err := c.cc.Invoke(ctx, MigrationService_GetDatabaseStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ListAuditEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) CreateTarget(ctx context.Context, in *CreateTargetRequest, opts ...grpc.CallOption) (*CreateTargetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTargetResponse)
+	err := c.cc.Invoke(ctx, MigrationService_CreateTarget_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) GetTarget(ctx context.Context, in *GetTargetRequest, opts ...grpc.CallOption) (*GetTargetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTargetResponse)
+	err := c.cc.Invoke(ctx, MigrationService_GetTarget_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ListTargets(ctx context.Context, in *ListTargetsRequest, opts ...grpc.CallOption) (*ListTargetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTargetsResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ListTargets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) UpdateTarget(ctx context.Context, in *UpdateTargetRequest, opts ...grpc.CallOption) (*UpdateTargetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateTargetResponse)
+	err := c.cc.Invoke(ctx, MigrationService_UpdateTarget_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) DeleteTarget(ctx context.Context, in *DeleteTargetRequest, opts ...grpc.CallOption) (*DeleteTargetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTargetResponse)
+	err := c.cc.Invoke(ctx, MigrationService_DeleteTarget_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) CreateEnvironment(ctx context.Context, in *CreateEnvironmentRequest, opts ...grpc.CallOption) (*CreateEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateEnvironmentResponse)
+	err := c.cc.Invoke(ctx, MigrationService_CreateEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) GetEnvironment(ctx context.Context, in *GetEnvironmentRequest, opts ...grpc.CallOption) (*GetEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEnvironmentResponse)
+	err := c.cc.Invoke(ctx, MigrationService_GetEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ListEnvironments(ctx context.Context, in *ListEnvironmentsRequest, opts ...grpc.CallOption) (*ListEnvironmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEnvironmentsResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ListEnvironments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) UpdateEnvironment(ctx context.Context, in *UpdateEnvironmentRequest, opts ...grpc.CallOption) (*UpdateEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateEnvironmentResponse)
+	err := c.cc.Invoke(ctx, MigrationService_UpdateEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) DeleteEnvironment(ctx context.Context, in *DeleteEnvironmentRequest, opts ...grpc.CallOption) (*DeleteEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteEnvironmentResponse)
+	err := c.cc.Invoke(ctx, MigrationService_DeleteEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ListMigrationEnvironments(ctx context.Context, in *ListMigrationEnvironmentsRequest, opts ...grpc.CallOption) (*ListMigrationEnvironmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMigrationEnvironmentsResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ListMigrationEnvironments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ListLocks(ctx context.Context, in *ListLocksRequest, opts ...grpc.CallOption) (*ListLocksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLocksResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ListLocks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migrationServiceClient) ReleaseLock(ctx context.Context, in *ReleaseLockRequest, opts ...grpc.CallOption) (*ReleaseLockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseLockResponse)
+	err := c.cc.Invoke(ctx, MigrationService_ReleaseLock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MigrationServiceServer is the server API for MigrationService service.
+// All implementations must embed UnimplementedMigrationServiceServer
+// for forward compatibility.
+//
+// MigrationService - сервис для управления миграциями
+type MigrationServiceServer interface {
+	// Создание новой миграции.
+	// Повтор запроса с тем же ключом идемпотентности (метаданные idempotency-key,
+	// HTTP-заголовок Idempotency-Key) возвращает ответ первого запроса
+	CreateMigration(context.Context, *CreateMigrationRequest) (*CreateMigrationResponse, error)
+	// Проверка скриптов миграции линтером SQL
+	LintMigration(context.Context, *LintMigrationRequest) (*LintMigrationResponse, error)
+	// Применение миграций.
+	// Повтор запроса с тем же ключом идемпотентности (метаданные idempotency-key,
+	// HTTP-заголовок Idempotency-Key) возвращает ответ первого запроса
+	ApplyMigration(context.Context, *ApplyMigrationRequest) (*ApplyMigrationResponse, error)
+	// Откат миграции
+	RollbackMigration(context.Context, *RollbackMigrationRequest) (*RollbackMigrationResponse, error)
+	// Откат всех миграций, примененных после указанной, в одной транзакции
+	RollbackToMigration(context.Context, *RollbackToMigrationRequest) (*RollbackToMigrationResponse, error)
+	// Отметка миграций примененными без выполнения скриптов для базы данных, схема которой уже создана
+	BaselineMigrations(context.Context, *BaselineMigrationsRequest) (*BaselineMigrationsResponse, error)
+	// Применение миграции арендаторов к схемам арендаторов целевой базы данных
+	ApplyTenantMigration(context.Context, *ApplyTenantMigrationRequest) (*ApplyTenantMigrationResponse, error)
+	// Замена скрипта повторяемой миграции; измененный скрипт выполняется следующим применением
+	UpdateRepeatableMigration(context.Context, *UpdateRepeatableMigrationRequest) (*UpdateRepeatableMigrationResponse, error)
+	// Выполненные ревизии скрипта повторяемой миграции
+	ListMigrationRevisions(context.Context, *ListMigrationRevisionsRequest) (*ListMigrationRevisionsResponse, error)
+	// Состояние миграции арендаторов в каждой схеме арендатора
+	ListTenantStatuses(context.Context, *ListTenantStatusesRequest) (*ListTenantStatusesResponse, error)
+	// Запуск миграции данных: пачки выполняются в фоне с сохранением курсора
+	StartBackfill(context.Context, *StartBackfillRequest) (*BackfillResponse, error)
+	// Приостановка миграции данных после выполняемой пачки
+	PauseBackfill(context.Context, *PauseBackfillRequest) (*BackfillResponse, error)
+	// Продолжение приостановленной или завершившейся ошибкой миграции данных с сохраненного курсора
+	ResumeBackfill(context.Context, *ResumeBackfillRequest) (*BackfillResponse, error)
+	// Изменение размера пачки и паузы между пачками миграции данных
+	ThrottleBackfill(context.Context, *ThrottleBackfillRequest) (*BackfillResponse, error)
+	// Ход выполнения миграции данных
+	GetBackfill(context.Context, *GetBackfillRequest) (*BackfillResponse, error)
+	// Пробное применение миграций: скрипты выполняются в транзакции, которая всегда откатывается
+	PlanApplyMigration(context.Context, *ApplyMigrationRequest) (*MigrationPlanResponse, error)
+	// Пробный откат миграции: скрипт отката выполняется в транзакции, которая всегда откатывается
+	PlanRollbackMigration(context.Context, *RollbackMigrationRequest) (*MigrationPlanResponse, error)
+	// Получение списка миграций
+	ListMigrations(context.Context, *ListMigrationsRequest) (*ListMigrationsResponse, error)
+	// Получение конкретной миграции
+	GetMigration(context.Context, *GetMigrationRequest) (*GetMigrationResponse, error)
+	// Импорт миграций из архива файлов в раскладке golang-migrate, goose или Flyway
+	ImportMigrations(context.Context, *ImportMigrationsRequest) (*ImportMigrationsResponse, error)
+	// Выгрузка реестра миграций архивом tar.gz, передаваемым частями.
+	// Через HTTP архив доступен по GET /v1/export?target_id=...
+	ExportMigrations(*ExportMigrationsRequest, grpc.ServerStreamingServer[ExportChunk]) error
+	// Получение журнала выполнения миграций
+	ListMigrationHistory(context.Context, *ListMigrationHistoryRequest) (*ListMigrationHistoryResponse, error)
+	// Проверка контрольных сумм скриптов миграций целевой базы данных
+	VerifyMigrations(context.Context, *VerifyMigrationsRequest) (*VerifyMigrationsResponse, error)
+	// Сравнение схемы целевой базы данных с последним снимком, сохраненным после применения или отката
+	GetDatabaseStatus(context.Context, *GetDatabaseStatusRequest) (*GetDatabaseStatusResponse, error)
+	// Получение журнала аудита вызовов API
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	// Регистрация целевой базы данных
+	CreateTarget(context.Context, *CreateTargetRequest) (*CreateTargetResponse, error)
+	// Получение целевой базы данных
+	GetTarget(context.Context, *GetTargetRequest) (*GetTargetResponse, error)
+	// Получение списка целевых баз данных
+	ListTargets(context.Context, *ListTargetsRequest) (*ListTargetsResponse, error)
+	// Изменение настроек целевой базы данных
+	UpdateTarget(context.Context, *UpdateTargetRequest) (*UpdateTargetResponse, error)
+	// Удаление целевой базы данных из реестра
+	DeleteTarget(context.Context, *DeleteTargetRequest) (*DeleteTargetResponse, error)
+	// Создание окружения конвейера продвижения миграций
+	CreateEnvironment(context.Context, *CreateEnvironmentRequest) (*CreateEnvironmentResponse, error)
+	// Получение окружения
+	GetEnvironment(context.Context, *GetEnvironmentRequest) (*GetEnvironmentResponse, error)
+	// Получение списка окружений в порядке конвейера
+	ListEnvironments(context.Context, *ListEnvironmentsRequest) (*ListEnvironmentsResponse, error)
+	// Изменение окружения и его правила продвижения
+	UpdateEnvironment(context.Context, *UpdateEnvironmentRequest) (*UpdateEnvironmentResponse, error)
+	// Удаление окружения
+	DeleteEnvironment(context.Context, *DeleteEnvironmentRequest) (*DeleteEnvironmentResponse, error)
+	// Состояния миграций во всех окружениях
+	ListMigrationEnvironments(context.Context, *ListMigrationEnvironmentsRequest) (*ListMigrationEnvironmentsResponse, error)
+	// Получение списка удерживаемых блокировок целевых баз данных
+	ListLocks(context.Context, *ListLocksRequest) (*ListLocksResponse, error)
+	// Принудительное освобождение блокировки целевой базы данных
+	ReleaseLock(context.Context, *ReleaseLockRequest) (*ReleaseLockResponse, error)
+	mustEmbedUnimplementedMigrationServiceServer()
+}
+
+// UnimplementedMigrationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMigrationServiceServer struct{}
+
+func (UnimplementedMigrationServiceServer) CreateMigration(context.Context, *CreateMigrationRequest) (*CreateMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) LintMigration(context.Context, *LintMigrationRequest) (*LintMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LintMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) ApplyMigration(context.Context, *ApplyMigrationRequest) (*ApplyMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) RollbackMigration(context.Context, *RollbackMigrationRequest) (*RollbackMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) RollbackToMigration(context.Context, *RollbackToMigrationRequest) (*RollbackToMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackToMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) BaselineMigrations(context.Context, *BaselineMigrationsRequest) (*BaselineMigrationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BaselineMigrations not implemented")
+}
+func (UnimplementedMigrationServiceServer) ApplyTenantMigration(context.Context, *ApplyTenantMigrationRequest) (*ApplyTenantMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyTenantMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) UpdateRepeatableMigration(context.Context, *UpdateRepeatableMigrationRequest) (*UpdateRepeatableMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRepeatableMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) ListMigrationRevisions(context.Context, *ListMigrationRevisionsRequest) (*ListMigrationRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMigrationRevisions not implemented")
+}
+func (UnimplementedMigrationServiceServer) ListTenantStatuses(context.Context, *ListTenantStatusesRequest) (*ListTenantStatusesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenantStatuses not implemented")
+}
+func (UnimplementedMigrationServiceServer) StartBackfill(context.Context, *StartBackfillRequest) (*BackfillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartBackfill not implemented")
+}
+func (UnimplementedMigrationServiceServer) PauseBackfill(context.Context, *PauseBackfillRequest) (*BackfillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseBackfill not implemented")
+}
+func (UnimplementedMigrationServiceServer) ResumeBackfill(context.Context, *ResumeBackfillRequest) (*BackfillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeBackfill not implemented")
+}
+func (UnimplementedMigrationServiceServer) ThrottleBackfill(context.Context, *ThrottleBackfillRequest) (*BackfillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ThrottleBackfill not implemented")
+}
+func (UnimplementedMigrationServiceServer) GetBackfill(context.Context, *GetBackfillRequest) (*BackfillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBackfill not implemented")
+}
+func (UnimplementedMigrationServiceServer) PlanApplyMigration(context.Context, *ApplyMigrationRequest) (*MigrationPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanApplyMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) PlanRollbackMigration(context.Context, *RollbackMigrationRequest) (*MigrationPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanRollbackMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) ListMigrations(context.Context, *ListMigrationsRequest) (*ListMigrationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMigrations not implemented")
+}
+func (UnimplementedMigrationServiceServer) GetMigration(context.Context, *GetMigrationRequest) (*GetMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMigration not implemented")
+}
+func (UnimplementedMigrationServiceServer) ImportMigrations(context.Context, *ImportMigrationsRequest) (*ImportMigrationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMigrations not implemented")
+}
+func (UnimplementedMigrationServiceServer) ExportMigrations(*ExportMigrationsRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportMigrations not implemented")
+}
+func (UnimplementedMigrationServiceServer) ListMigrationHistory(context.Context, *ListMigrationHistoryRequest) (*ListMigrationHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMigrationHistory not implemented")
+}
+func (UnimplementedMigrationServiceServer) VerifyMigrations(context.Context, *VerifyMigrationsRequest) (*VerifyMigrationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMigrations not implemented")
+}
+func (UnimplementedMigrationServiceServer) GetDatabaseStatus(context.Context, *GetDatabaseStatusRequest) (*GetDatabaseStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDatabaseStatus not implemented")
+}
+func (UnimplementedMigrationServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedMigrationServiceServer) CreateTarget(context.Context, *CreateTargetRequest) (*CreateTargetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTarget not implemented")
+}
+func (UnimplementedMigrationServiceServer) GetTarget(context.Context, *GetTargetRequest) (*GetTargetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTarget not implemented")
+}
+func (UnimplementedMigrationServiceServer) ListTargets(context.Context, *ListTargetsRequest) (*ListTargetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTargets not implemented")
+}
+func (UnimplementedMigrationServiceServer) UpdateTarget(context.Context, *UpdateTargetRequest) (*UpdateTargetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTarget not implemented")
+}
+func (UnimplementedMigrationServiceServer) DeleteTarget(context.Context, *DeleteTargetRequest) (*DeleteTargetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTarget not implemented")
+}
+func (UnimplementedMigrationServiceServer) CreateEnvironment(context.Context, *CreateEnvironmentRequest) (*CreateEnvironmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEnvironment not implemented")
+}
+func (UnimplementedMigrationServiceServer) GetEnvironment(context.Context, *GetEnvironmentRequest) (*GetEnvironmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEnvironment not implemented")
+}
+func (UnimplementedMigrationServiceServer) ListEnvironments(context.Context, *ListEnvironmentsRequest) (*ListEnvironmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEnvironments not implemented")
+}
+func (UnimplementedMigrationServiceServer) UpdateEnvironment(context.Context, *UpdateEnvironmentRequest) (*UpdateEnvironmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEnvironment not implemented")
+}
+func (UnimplementedMigrationServiceServer) DeleteEnvironment(context.Context, *DeleteEnvironmentRequest) (*DeleteEnvironmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEnvironment not implemented")
+}
+func (UnimplementedMigrationServiceServer) ListMigrationEnvironments(context.Context, *ListMigrationEnvironmentsRequest) (*ListMigrationEnvironmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMigrationEnvironments not implemented")
+}
+func (UnimplementedMigrationServiceServer) ListLocks(context.Context, *ListLocksRequest) (*ListLocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLocks not implemented")
+}
+func (UnimplementedMigrationServiceServer) ReleaseLock(context.Context, *ReleaseLockRequest) (*ReleaseLockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseLock not implemented")
+}
+func (UnimplementedMigrationServiceServer) mustEmbedUnimplementedMigrationServiceServer() {}
+func (UnimplementedMigrationServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeMigrationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MigrationServiceServer will
+// result in compilation errors.
+type UnsafeMigrationServiceServer interface {
+	mustEmbedUnimplementedMigrationServiceServer()
+}
+
+func RegisterMigrationServiceServer(s grpc.ServiceRegistrar, srv MigrationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMigrationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MigrationService_ServiceDesc, srv)
+}
+
+func _MigrationService_CreateMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).CreateMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_CreateMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).CreateMigration(ctx, req.(*CreateMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_LintMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LintMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).LintMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_LintMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).LintMigration(ctx, req.(*LintMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_ApplyMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ApplyMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ApplyMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ApplyMigration(ctx, req.(*ApplyMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_RollbackMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).RollbackMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_RollbackMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).RollbackMigration(ctx, req.(*RollbackMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_RollbackToMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackToMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).RollbackToMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_RollbackToMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).RollbackToMigration(ctx, req.(*RollbackToMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_BaselineMigrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BaselineMigrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).BaselineMigrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_BaselineMigrations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).BaselineMigrations(ctx, req.(*BaselineMigrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_ApplyTenantMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyTenantMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ApplyTenantMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ApplyTenantMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ApplyTenantMigration(ctx, req.(*ApplyTenantMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_UpdateRepeatableMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRepeatableMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).UpdateRepeatableMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_UpdateRepeatableMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).UpdateRepeatableMigration(ctx, req.(*UpdateRepeatableMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_ListMigrationRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMigrationRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ListMigrationRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ListMigrationRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ListMigrationRevisions(ctx, req.(*ListMigrationRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_ListTenantStatuses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTenantStatusesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ListTenantStatuses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ListTenantStatuses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ListTenantStatuses(ctx, req.(*ListTenantStatusesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_StartBackfill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartBackfillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).StartBackfill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_StartBackfill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).StartBackfill(ctx, req.(*StartBackfillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_PauseBackfill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseBackfillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).PauseBackfill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_PauseBackfill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).PauseBackfill(ctx, req.(*PauseBackfillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_ResumeBackfill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeBackfillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ResumeBackfill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ResumeBackfill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ResumeBackfill(ctx, req.(*ResumeBackfillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_ThrottleBackfill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThrottleBackfillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ThrottleBackfill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ThrottleBackfill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ThrottleBackfill(ctx, req.(*ThrottleBackfillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_GetBackfill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBackfillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).GetBackfill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_GetBackfill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).GetBackfill(ctx, req.(*GetBackfillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_PlanApplyMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).PlanApplyMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_PlanApplyMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).PlanApplyMigration(ctx, req.(*ApplyMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_PlanRollbackMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).PlanRollbackMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_PlanRollbackMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).PlanRollbackMigration(ctx, req.(*RollbackMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_ListMigrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMigrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ListMigrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ListMigrations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ListMigrations(ctx, req.(*ListMigrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_GetMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).GetMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_GetMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).GetMigration(ctx, req.(*GetMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_ImportMigrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMigrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ImportMigrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ImportMigrations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ImportMigrations(ctx, req.(*ImportMigrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_ExportMigrations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportMigrationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MigrationServiceServer).ExportMigrations(m, &grpc.GenericServerStream[ExportMigrationsRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MigrationService_ExportMigrationsServer = grpc.ServerStreamingServer[ExportChunk]
+
+func _MigrationService_ListMigrationHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMigrationHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ListMigrationHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ListMigrationHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ListMigrationHistory(ctx, req.(*ListMigrationHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_VerifyMigrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMigrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).VerifyMigrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_VerifyMigrations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).VerifyMigrations(ctx, req.(*VerifyMigrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MigrationService_GetDatabaseStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDatabaseStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).GetDatabaseStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_GetDatabaseStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).GetDatabaseStatus(ctx, req.(*GetDatabaseStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *migrationServiceClient) RollbackMigration(ctx context.Context, in *RollbackMigrationRequest, opts ...grpc.CallOption) (*RollbackMigrationResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(RollbackMigrationResponse)
-	err := c.cc.Invoke(ctx, MigrationService_RollbackMigration_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _MigrationService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *migrationServiceClient) ListMigrations(ctx context.Context, in *ListMigrationsRequest, opts ...grpc.CallOption) (*ListMigrationsResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ListMigrationsResponse)
-	err := c.cc.Invoke(ctx, MigrationService_ListMigrations_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _MigrationService_CreateTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTargetRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).CreateTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_CreateTarget_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).CreateTarget(ctx, req.(*CreateTargetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *migrationServiceClient) GetMigration(ctx context.Context, in *GetMigrationRequest, opts ...grpc.CallOption) (*GetMigrationResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(GetMigrationResponse)
-	err := c.cc.Invoke(ctx, MigrationService_GetMigration_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _MigrationService_GetTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).GetTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_GetTarget_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).GetTarget(ctx, req.(*GetTargetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// MigrationServiceServer is the server API for MigrationService service.
-// All implementations must embed UnimplementedMigrationServiceServer
-// for forward compatibility.
-//
-// MigrationService - сервис для управления миграциями
-type MigrationServiceServer interface {
-	// Создание новой миграции
-	CreateMigration(context.Context, *CreateMigrationRequest) (*CreateMigrationResponse, error)
-	// Применение миграций
-	ApplyMigration(context.Context, *ApplyMigrationRequest) (*ApplyMigrationResponse, error)
-	// Откат миграции
-	RollbackMigration(context.Context, *RollbackMigrationRequest) (*RollbackMigrationResponse, error)
-	// Получение списка миграций
-	ListMigrations(context.Context, *ListMigrationsRequest) (*ListMigrationsResponse, error)
-	// Получение конкретной миграции
-	GetMigration(context.Context, *GetMigrationRequest) (*GetMigrationResponse, error)
-	mustEmbedUnimplementedMigrationServiceServer()
+func _MigrationService_ListTargets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTargetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ListTargets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ListTargets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ListTargets(ctx, req.(*ListTargetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedMigrationServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedMigrationServiceServer struct{}
-
-func (UnimplementedMigrationServiceServer) CreateMigration(context.Context, *CreateMigrationRequest) (*CreateMigrationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateMigration not implemented")
-}
-func (UnimplementedMigrationServiceServer) ApplyMigration(context.Context, *ApplyMigrationRequest) (*ApplyMigrationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ApplyMigration not implemented")
-}
-func (UnimplementedMigrationServiceServer) RollbackMigration(context.Context, *RollbackMigrationRequest) (*RollbackMigrationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RollbackMigration not implemented")
+func _MigrationService_UpdateTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTargetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).UpdateTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_UpdateTarget_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).UpdateTarget(ctx, req.(*UpdateTargetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMigrationServiceServer) ListMigrations(context.Context, *ListMigrationsRequest) (*ListMigrationsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListMigrations not implemented")
+
+func _MigrationService_DeleteTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTargetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).DeleteTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_DeleteTarget_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).DeleteTarget(ctx, req.(*DeleteTargetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMigrationServiceServer) GetMigration(context.Context, *GetMigrationRequest) (*GetMigrationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetMigration not implemented")
+
+func _MigrationService_CreateEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).CreateEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_CreateEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).CreateEnvironment(ctx, req.(*CreateEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMigrationServiceServer) mustEmbedUnimplementedMigrationServiceServer() {}
-func (UnimplementedMigrationServiceServer) testEmbeddedByValue()                          {}
 
-// UnsafeMigrationServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to MigrationServiceServer will
-// result in compilation errors.
-type UnsafeMigrationServiceServer interface {
-	mustEmbedUnimplementedMigrationServiceServer()
+func _MigrationService_GetEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).GetEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_GetEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).GetEnvironment(ctx, req.(*GetEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterMigrationServiceServer(s grpc.ServiceRegistrar, srv MigrationServiceServer) {
-	// If the following call pancis, it indicates UnimplementedMigrationServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _MigrationService_ListEnvironments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEnvironmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&MigrationService_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(MigrationServiceServer).ListEnvironments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MigrationService_ListEnvironments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigrationServiceServer).ListEnvironments(ctx, req.(*ListEnvironmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _MigrationService_CreateMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateMigrationRequest)
+func _MigrationService_UpdateEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEnvironmentRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MigrationServiceServer).CreateMigration(ctx, in)
+		return srv.(MigrationServiceServer).UpdateEnvironment(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MigrationService_CreateMigration_FullMethodName,
+		FullMethod: MigrationService_UpdateEnvironment_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MigrationServiceServer).CreateMigration(ctx, req.(*CreateMigrationRequest))
+		return srv.(MigrationServiceServer).UpdateEnvironment(ctx, req.(*UpdateEnvironmentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MigrationService_ApplyMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ApplyMigrationRequest)
+func _MigrationService_DeleteEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEnvironmentRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MigrationServiceServer).ApplyMigration(ctx, in)
+		return srv.(MigrationServiceServer).DeleteEnvironment(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MigrationService_ApplyMigration_FullMethodName,
+		FullMethod: MigrationService_DeleteEnvironment_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MigrationServiceServer).ApplyMigration(ctx, req.(*ApplyMigrationRequest))
+		return srv.(MigrationServiceServer).DeleteEnvironment(ctx, req.(*DeleteEnvironmentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MigrationService_RollbackMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RollbackMigrationRequest)
+func _MigrationService_ListMigrationEnvironments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMigrationEnvironmentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MigrationServiceServer).RollbackMigration(ctx, in)
+		return srv.(MigrationServiceServer).ListMigrationEnvironments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MigrationService_RollbackMigration_FullMethodName,
+		FullMethod: MigrationService_ListMigrationEnvironments_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MigrationServiceServer).RollbackMigration(ctx, req.(*RollbackMigrationRequest))
+		return srv.(MigrationServiceServer).ListMigrationEnvironments(ctx, req.(*ListMigrationEnvironmentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MigrationService_ListMigrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListMigrationsRequest)
+func _MigrationService_ListLocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLocksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MigrationServiceServer).ListMigrations(ctx, in)
+		return srv.(MigrationServiceServer).ListLocks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MigrationService_ListMigrations_FullMethodName,
+		FullMethod: MigrationService_ListLocks_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MigrationServiceServer).ListMigrations(ctx, req.(*ListMigrationsRequest))
+		return srv.(MigrationServiceServer).ListLocks(ctx, req.(*ListLocksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MigrationService_GetMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetMigrationRequest)
+func _MigrationService_ReleaseLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseLockRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MigrationServiceServer).GetMigration(ctx, in)
+		return srv.(MigrationServiceServer).ReleaseLock(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MigrationService_GetMigration_FullMethodName,
+		FullMethod: MigrationService_ReleaseLock_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MigrationServiceServer).GetMigration(ctx, req.(*GetMigrationRequest))
+		return srv.(MigrationServiceServer).ReleaseLock(ctx, req.(*ReleaseLockRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -265,6 +1465,10 @@ var MigrationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateMigration",
 			Handler:    _MigrationService_CreateMigration_Handler,
 		},
+		{
+			MethodName: "LintMigration",
+			Handler:    _MigrationService_LintMigration_Handler,
+		},
 		{
 			MethodName: "ApplyMigration",
 			Handler:    _MigrationService_ApplyMigration_Handler,
@@ -273,6 +1477,58 @@ var MigrationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RollbackMigration",
 			Handler:    _MigrationService_RollbackMigration_Handler,
 		},
+		{
+			MethodName: "RollbackToMigration",
+			Handler:    _MigrationService_RollbackToMigration_Handler,
+		},
+		{
+			MethodName: "BaselineMigrations",
+			Handler:    _MigrationService_BaselineMigrations_Handler,
+		},
+		{
+			MethodName: "ApplyTenantMigration",
+			Handler:    _MigrationService_ApplyTenantMigration_Handler,
+		},
+		{
+			MethodName: "UpdateRepeatableMigration",
+			Handler:    _MigrationService_UpdateRepeatableMigration_Handler,
+		},
+		{
+			MethodName: "ListMigrationRevisions",
+			Handler:    _MigrationService_ListMigrationRevisions_Handler,
+		},
+		{
+			MethodName: "ListTenantStatuses",
+			Handler:    _MigrationService_ListTenantStatuses_Handler,
+		},
+		{
+			MethodName: "StartBackfill",
+			Handler:    _MigrationService_StartBackfill_Handler,
+		},
+		{
+			MethodName: "PauseBackfill",
+			Handler:    _MigrationService_PauseBackfill_Handler,
+		},
+		{
+			MethodName: "ResumeBackfill",
+			Handler:    _MigrationService_ResumeBackfill_Handler,
+		},
+		{
+			MethodName: "ThrottleBackfill",
+			Handler:    _MigrationService_ThrottleBackfill_Handler,
+		},
+		{
+			MethodName: "GetBackfill",
+			Handler:    _MigrationService_GetBackfill_Handler,
+		},
+		{
+			MethodName: "PlanApplyMigration",
+			Handler:    _MigrationService_PlanApplyMigration_Handler,
+		},
+		{
+			MethodName: "PlanRollbackMigration",
+			Handler:    _MigrationService_PlanRollbackMigration_Handler,
+		},
 		{
 			MethodName: "ListMigrations",
 			Handler:    _MigrationService_ListMigrations_Handler,
@@ -281,7 +1537,85 @@ var MigrationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMigration",
 			Handler:    _MigrationService_GetMigration_Handler,
 		},
+		{
+			MethodName: "ImportMigrations",
+			Handler:    _MigrationService_ImportMigrations_Handler,
+		},
+		{
+			MethodName: "ListMigrationHistory",
+			Handler:    _MigrationService_ListMigrationHistory_Handler,
+		},
+		{
+			MethodName: "VerifyMigrations",
+			Handler:    _MigrationService_VerifyMigrations_Handler,
+		},
+		{
+			MethodName: "GetDatabaseStatus",
+			Handler:    _MigrationService_GetDatabaseStatus_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _MigrationService_ListAuditEvents_Handler,
+		},
+		{
+			MethodName: "CreateTarget",
+			Handler:    _MigrationService_CreateTarget_Handler,
+		},
+		{
+			MethodName: "GetTarget",
+			Handler:    _MigrationService_GetTarget_Handler,
+		},
+		{
+			MethodName: "ListTargets",
+			Handler:    _MigrationService_ListTargets_Handler,
+		},
+		{
+			MethodName: "UpdateTarget",
+			Handler:    _MigrationService_UpdateTarget_Handler,
+		},
+		{
+			MethodName: "DeleteTarget",
+			Handler:    _MigrationService_DeleteTarget_Handler,
+		},
+		{
+			MethodName: "CreateEnvironment",
+			Handler:    _MigrationService_CreateEnvironment_Handler,
+		},
+		{
+			MethodName: "GetEnvironment",
+			Handler:    _MigrationService_GetEnvironment_Handler,
+		},
+		{
+			MethodName: "ListEnvironments",
+			Handler:    _MigrationService_ListEnvironments_Handler,
+		},
+		{
+			MethodName: "UpdateEnvironment",
+			Handler:    _MigrationService_UpdateEnvironment_Handler,
+		},
+		{
+			MethodName: "DeleteEnvironment",
+			Handler:    _MigrationService_DeleteEnvironment_Handler,
+		},
+		{
+			MethodName: "ListMigrationEnvironments",
+			Handler:    _MigrationService_ListMigrationEnvironments_Handler,
+		},
+		{
+			MethodName: "ListLocks",
+			Handler:    _MigrationService_ListLocks_Handler,
+		},
+		{
+			MethodName: "ReleaseLock",
+			Handler:    _MigrationService_ReleaseLock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportMigrations",
+			Handler:       _MigrationService_ExportMigrations_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "migrator/migrator.proto",
 }
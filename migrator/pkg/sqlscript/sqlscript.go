@@ -0,0 +1,333 @@
+// Package sqlscript разбирает SQL-скрипты на отдельные операторы.
+package sqlscript
+
+import (
+	"strings"
+)
+
+// Syntax - диалект SQL, по правилам которого разбирается скрипт.
+type Syntax int
+
+const (
+	// PostgreSQL - строки в долларовых кавычках и строки E'...' со спецпоследовательностями.
+	PostgreSQL Syntax = iota
+	// MySQL - идентификаторы в обратных кавычках, комментарии #, спецпоследовательности
+	// в строках и составные операторы BEGIN ... END в телах процедур и триггеров.
+	MySQL
+	// SQLite - идентификаторы в обратных и квадратных скобках и тела триггеров BEGIN ... END.
+	SQLite
+)
+
+// Statement - оператор скрипта.
+type Statement struct {
+	// Text - текст оператора без завершающей точки с запятой.
+	Text string
+	// Offset - смещение начала оператора от начала скрипта в байтах.
+	Offset int
+}
+
+// Split разбивает скрипт на операторы по точке с запятой, учитывая строки,
+// идентификаторы в кавычках, строки в долларовых кавычках и комментарии.
+// Операторы возвращаются без завершающей точки с запятой; пустые операторы
+// и операторы, состоящие только из комментариев, пропускаются.
+func Split(script string) []string {
+	statements := Parse(script)
+	result := make([]string, len(statements))
+	for i, statement := range statements {
+		result[i] = statement.Text
+	}
+	return result
+}
+
+// Parse разбивает скрипт на операторы так же, как Split, сохраняя их положение в скрипте.
+func Parse(script string) []Statement {
+	return ParseSyntax(script, PostgreSQL)
+}
+
+// ParseSyntax разбивает скрипт на операторы по правилам диалекта syntax.
+// В MySQL и SQLite точка с запятой внутри BEGIN ... END оператора CREATE
+// (тела процедуры или триггера) не завершает оператор.
+func ParseSyntax(script string, syntax Syntax) []Statement {
+	var (
+		statements []Statement
+		start      int
+		hasCode    bool
+		// compound - оператор начинается с CREATE и может содержать блоки BEGIN ... END;
+		// в остальных операторах BEGIN начинает транзакцию.
+		compound bool
+		depth    int
+	)
+
+	flush := func(end int) {
+		if hasCode {
+			text := strings.TrimLeft(script[start:end], " \t\r\n")
+			statements = append(statements, Statement{
+				Text:   strings.TrimSpace(text),
+				Offset: end - len(text),
+			})
+		}
+		start = end + 1
+		hasCode = false
+		compound = false
+		depth = 0
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		switch {
+		case c == ';':
+			if depth > 0 {
+				continue
+			}
+			flush(i)
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			i = skipLineComment(script, i)
+		case c == '#' && syntax == MySQL:
+			i = skipLineComment(script, i)
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			i = skipBlockComment(script, i)
+		case c == '\'':
+			hasCode = true
+			escapes := syntax == MySQL || i > 0 && (script[i-1] == 'E' || script[i-1] == 'e')
+			i = skipQuoted(script, i, '\'', escapes)
+		case c == '"':
+			hasCode = true
+			i = skipQuoted(script, i, '"', syntax == MySQL)
+		case c == '`' && syntax != PostgreSQL:
+			hasCode = true
+			i = skipQuoted(script, i, '`', false)
+		case c == '[' && syntax == SQLite:
+			hasCode = true
+			i = skipBracketed(script, i)
+		case c == '$' && syntax == PostgreSQL:
+			hasCode = true
+			if tag, ok := dollarTag(script[i:]); ok {
+				i = skipDollarQuoted(script, i, tag)
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case syntax != PostgreSQL && isWordChar(c):
+			end := wordEnd(script, i)
+			word := strings.ToUpper(script[i:end])
+			if !hasCode {
+				compound = word == "CREATE"
+			}
+			hasCode = true
+			if compound {
+				depth = blockDepth(depth, word, script[end:])
+			}
+			i = end - 1
+		default:
+			hasCode = true
+		}
+	}
+	flush(len(script))
+
+	return statements
+}
+
+// Normalize возвращает текст оператора PostgreSQL без комментариев, с содержимым строк
+// и строк в долларовых кавычках, замененным пустыми строками в тех же кавычках,
+// и с пробельными символами, сжатыми до одного пробела. Идентификаторы
+// в кавычках сохраняются. Нормализованный текст удобно проверять регулярными выражениями.
+func Normalize(text string) string {
+	var (
+		b            strings.Builder
+		pendingSpace bool
+	)
+
+	emit := func(s string) {
+		if pendingSpace && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		b.WriteString(s)
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case c == '-' && strings.HasPrefix(text[i:], "--"):
+			i = skipLineComment(text, i)
+			pendingSpace = true
+		case c == '/' && strings.HasPrefix(text[i:], "/*"):
+			i = skipBlockComment(text, i)
+			pendingSpace = true
+		case c == '\'':
+			escapes := i > 0 && (text[i-1] == 'E' || text[i-1] == 'e')
+			i = skipQuoted(text, i, '\'', escapes)
+			emit("''")
+		case c == '"':
+			end := skipQuoted(text, i, '"', false)
+			emit(text[i : end+1])
+			i = end
+		case c == '$':
+			if tag, ok := dollarTag(text[i:]); ok {
+				i = skipDollarQuoted(text, i, tag)
+				emit("$$")
+				continue
+			}
+			emit("$")
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pendingSpace = true
+		default:
+			emit(text[i : i+1])
+		}
+	}
+
+	return b.String()
+}
+
+// Locate возвращает номер строки (с единицы) для смещения в байтах
+// и оператор, в который попадает это смещение.
+func Locate(script string, offset int) (int, string) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(script) {
+		offset = len(script)
+	}
+
+	line := strings.Count(script[:offset], "\n") + 1
+
+	var statement string
+	for _, s := range Parse(script) {
+		if s.Offset > offset {
+			break
+		}
+		statement = s.Text
+	}
+
+	return line, statement
+}
+
+// ByteOffset переводит позицию в символах (с единицы), которую сообщает
+// PostgreSQL, в смещение в байтах.
+func ByteOffset(s string, position int) int {
+	if position <= 1 {
+		return 0
+	}
+	chars := 0
+	for i := range s {
+		if chars == position-1 {
+			return i
+		}
+		chars++
+	}
+	return len(s)
+}
+
+// skipLineComment возвращает индекс последнего символа однострочного комментария.
+func skipLineComment(s string, i int) int {
+	end := strings.IndexByte(s[i:], '\n')
+	if end < 0 {
+		return len(s) - 1
+	}
+	return i + end
+}
+
+// skipBlockComment возвращает индекс последнего символа блочного комментария
+// с учетом вложенных комментариев.
+func skipBlockComment(s string, i int) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "/*"):
+			depth++
+			i++
+		case strings.HasPrefix(s[i:], "*/"):
+			depth--
+			i++
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s) - 1
+}
+
+// skipQuoted возвращает индекс закрывающей кавычки.
+// Удвоенная кавычка считается экранированной.
+func skipQuoted(s string, i int, quote byte, backslashEscapes bool) int {
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if backslashEscapes {
+				i++
+			}
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(s) - 1
+}
+
+// skipBracketed возвращает индекс закрывающей скобки идентификатора [...] SQLite.
+func skipBracketed(s string, i int) int {
+	end := strings.IndexByte(s[i:], ']')
+	if end < 0 {
+		return len(s) - 1
+	}
+	return i + end
+}
+
+// blockDepth возвращает глубину вложенности блоков после слова word.
+// BEGIN и CASE открывают блок, END закрывает его, кроме END IF, END LOOP,
+// END WHILE и END REPEAT, которым не соответствует открывающее слово.
+func blockDepth(depth int, word, rest string) int {
+	switch word {
+	case "BEGIN", "CASE":
+		return depth + 1
+	case "END":
+		rest = strings.TrimLeft(rest, " \t\r\n")
+		switch strings.ToUpper(rest[:wordEnd(rest, 0)]) {
+		case "IF", "LOOP", "WHILE", "REPEAT":
+			return depth
+		}
+		if depth > 0 {
+			return depth - 1
+		}
+	}
+	return depth
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// wordEnd возвращает индекс первого символа после слова, начинающегося с индекса i.
+func wordEnd(s string, i int) int {
+	for i < len(s) && isWordChar(s[i]) {
+		i++
+	}
+	return i
+}
+
+// dollarTag возвращает открывающий тег строки в долларовых кавычках ($$ или $tag$).
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '$':
+			return s[:j+1], true
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || j > 1 && c >= '0' && c <= '9':
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// skipDollarQuoted возвращает индекс последнего символа закрывающего тега.
+func skipDollarQuoted(s string, i int, tag string) int {
+	end := strings.Index(s[i+len(tag):], tag)
+	if end < 0 {
+		return len(s) - 1
+	}
+	return i + len(tag) + end + len(tag) - 1
+}
@@ -0,0 +1,234 @@
+package sqlscript
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "empty script",
+			script: "  \n\t",
+			want:   []string{},
+		},
+		{
+			name:   "statements",
+			script: "CREATE TABLE a (id INT);\nINSERT INTO a VALUES (1);",
+			want:   []string{"CREATE TABLE a (id INT)", "INSERT INTO a VALUES (1)"},
+		},
+		{
+			name:   "empty statements and trailing semicolons",
+			script: ";;SELECT 1;;\n;",
+			want:   []string{"SELECT 1"},
+		},
+		{
+			name:   "only comments",
+			script: "-- first; comment\n/* second; comment */;",
+			want:   []string{},
+		},
+		{
+			name:   "semicolon in comments",
+			script: "SELECT 1 -- not; the end\n; /* also; not */ SELECT 2",
+			want:   []string{"SELECT 1 -- not; the end", "/* also; not */ SELECT 2"},
+		},
+		{
+			name:   "semicolon in strings and identifiers",
+			script: `INSERT INTO "a;b" VALUES ('x;y', 'it''s; fine'); SELECT 2`,
+			want:   []string{`INSERT INTO "a;b" VALUES ('x;y', 'it''s; fine')`, "SELECT 2"},
+		},
+		{
+			name:   "escape string",
+			script: `SELECT E'a\';b'; SELECT 2`,
+			want:   []string{`SELECT E'a\';b'`, "SELECT 2"},
+		},
+		{
+			name:   "backslash in standard string",
+			script: `SELECT 'a\'; SELECT 2`,
+			want:   []string{`SELECT 'a\'`, "SELECT 2"},
+		},
+		{
+			name: "dollar quoted function body",
+			script: `CREATE FUNCTION f() RETURNS int AS $$
+BEGIN
+	RETURN 1;
+END;
+$$ LANGUAGE plpgsql;
+SELECT f();`,
+			want: []string{
+				"CREATE FUNCTION f() RETURNS int AS $$\nBEGIN\n\tRETURN 1;\nEND;\n$$ LANGUAGE plpgsql",
+				"SELECT f()",
+			},
+		},
+		{
+			name:   "tagged dollar quotes",
+			script: "DO $body$ BEGIN PERFORM '$$;'; END $body$; SELECT 2",
+			want:   []string{"DO $body$ BEGIN PERFORM '$$;'; END $body$", "SELECT 2"},
+		},
+		{
+			name:   "positional parameter is not a dollar quote",
+			script: "PREPARE p AS SELECT $1; SELECT 2",
+			want:   []string{"PREPARE p AS SELECT $1", "SELECT 2"},
+		},
+		{
+			name:   "unterminated string",
+			script: "SELECT 'abc; SELECT 2",
+			want:   []string{"SELECT 'abc; SELECT 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Split(tt.script)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSyntax(t *testing.T) {
+	tests := []struct {
+		name   string
+		syntax Syntax
+		script string
+		want   []string
+	}{
+		{
+			name:   "mysql hash comment",
+			syntax: MySQL,
+			script: "SELECT 1 # not; the end\n; SELECT 2",
+			want:   []string{"SELECT 1 # not; the end", "SELECT 2"},
+		},
+		{
+			name:   "hash is not a comment in postgres",
+			syntax: PostgreSQL,
+			script: "SELECT '{}'::jsonb #> '{a}'; SELECT 2",
+			want:   []string{"SELECT '{}'::jsonb #> '{a}'", "SELECT 2"},
+		},
+		{
+			name:   "mysql backticks and escapes",
+			syntax: MySQL,
+			script: "INSERT INTO `a;b` VALUES ('x\\';y'); SELECT 2",
+			want:   []string{"INSERT INTO `a;b` VALUES ('x\\';y')", "SELECT 2"},
+		},
+		{
+			name:   "mysql procedure body",
+			syntax: MySQL,
+			script: "CREATE PROCEDURE p() BEGIN IF 1 THEN SELECT 1; END IF; SELECT 2; END; CALL p()",
+			want:   []string{"CREATE PROCEDURE p() BEGIN IF 1 THEN SELECT 1; END IF; SELECT 2; END", "CALL p()"},
+		},
+		{
+			name:   "mysql case inside trigger",
+			syntax: MySQL,
+			script: "CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW BEGIN SET NEW.x = CASE WHEN 1 THEN 2 END; END; SELECT 1",
+			want: []string{
+				"CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW BEGIN SET NEW.x = CASE WHEN 1 THEN 2 END; END",
+				"SELECT 1",
+			},
+		},
+		{
+			name:   "mysql transaction begin",
+			syntax: MySQL,
+			script: "BEGIN; UPDATE a SET x = 1 WHERE id = 1; COMMIT",
+			want:   []string{"BEGIN", "UPDATE a SET x = 1 WHERE id = 1", "COMMIT"},
+		},
+		{
+			name:   "sqlite trigger and brackets",
+			syntax: SQLite,
+			script: "CREATE TRIGGER t AFTER INSERT ON [a;b] BEGIN UPDATE c SET n = n + 1; END; SELECT 1",
+			want:   []string{"CREATE TRIGGER t AFTER INSERT ON [a;b] BEGIN UPDATE c SET n = n + 1; END", "SELECT 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, statement := range ParseSyntax(tt.script, tt.syntax) {
+				got = append(got, statement.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSyntax() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOffsets(t *testing.T) {
+	script := "SELECT 1;\n\n  -- next\n  SELECT 2;"
+	statements := Parse(script)
+
+	want := []Statement{
+		{Text: "SELECT 1", Offset: 0},
+		{Text: "-- next\n  SELECT 2", Offset: 13},
+	}
+	if !reflect.DeepEqual(statements, want) {
+		t.Fatalf("Parse() = %+v, want %+v", statements, want)
+	}
+
+	line, statement := Locate(script, len(script)-2)
+	if line != 4 || statement != want[1].Text {
+		t.Errorf("Locate() = %d, %q, want 4, %q", line, statement, want[1].Text)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "whitespace and comments",
+			text: "ALTER  TABLE\n\ta -- comment\n/* block */ DROP COLUMN b",
+			want: "ALTER TABLE a DROP COLUMN b",
+		},
+		{
+			name: "strings are emptied",
+			text: "UPDATE a SET note = 'WHERE x' , e = E'it\\'s'",
+			want: "UPDATE a SET note = '' , e = E''",
+		},
+		{
+			name: "quoted identifiers are kept",
+			text: `DROP TABLE "Some  Table"`,
+			want: `DROP TABLE "Some  Table"`,
+		},
+		{
+			name: "dollar quoted body",
+			text: "CREATE FUNCTION f() AS $fn$ SELECT 'WHERE' $fn$ LANGUAGE sql",
+			want: "CREATE FUNCTION f() AS $$ LANGUAGE sql",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.text); got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteOffset(t *testing.T) {
+	tests := []struct {
+		s        string
+		position int
+		want     int
+	}{
+		{s: "SELECT", position: 0, want: 0},
+		{s: "SELECT", position: 1, want: 0},
+		{s: "SELECT", position: 3, want: 2},
+		{s: "SELECT 'ё' x", position: 11, want: 11},
+		{s: "abc", position: 10, want: 3},
+	}
+
+	for _, tt := range tests {
+		if got := ByteOffset(tt.s, tt.position); got != tt.want {
+			t.Errorf("ByteOffset(%q, %d) = %d, want %d", tt.s, tt.position, got, tt.want)
+		}
+	}
+}